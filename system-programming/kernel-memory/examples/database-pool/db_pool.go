@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
 	
 	_ "github.com/mattn/go-sqlite3"
@@ -16,13 +21,36 @@ type BuddyConnectionPool struct {
 	dbSource string
 	mutex    sync.RWMutex
 	stats    *PoolStats
+
+	// 드레인을 위해 풀이 만든 모든 커넥션과 사용 중인 커넥션을 추적
+	connMutex sync.Mutex
+	conns     map[*PooledConnection]struct{}
+	inUse     map[*PooledConnection]struct{}
+	draining  bool
+	drained   chan struct{} // 드레인 중 마지막 사용 커넥션이 반환되면 닫힘
+
+	// 워밍업으로 미리 만들어 둔 커넥션 (GC에 비워지는 sync.Pool과 달리 유지됨)
+	warmup WarmupConfig
+	idle   map[int][]*PooledConnection
+}
+
+// WarmupConfig는 시작 시(그리고 드레인 후 재개 시) 크기 클래스별로 미리 만들
+// 커넥션 수를 지정한다. SizeClasses가 비어 있으면 모든 크기 클래스를 워밍업한다.
+type WarmupConfig struct {
+	ConnectionsPerClass int
+	SizeClasses         []int
 }
 
+// ErrPoolDraining은 드레인 중인 풀에 커넥션을 요청했을 때 반환
+var ErrPoolDraining = errors.New("커넥션 풀이 드레인 중입니다")
+
 type PoolStats struct {
 	ActiveConnections int64
 	TotalRequests     int64
 	PoolHits          int64
 	PoolMisses        int64
+	WarmedConnections int64
+	WarmupDuration    time.Duration
 	mutex             sync.RWMutex
 }
 
@@ -44,6 +72,20 @@ func (ps *PoolStats) IncrementMiss() {
 	ps.mutex.Unlock()
 }
 
+func (ps *PoolStats) RecordWarmup(count int, duration time.Duration) {
+	ps.mutex.Lock()
+	ps.WarmedConnections += int64(count)
+	ps.WarmupDuration += duration
+	ps.mutex.Unlock()
+}
+
+// GetWarmupStats는 누적 워밍업 커넥션 수와 소요 시간을 반환
+func (ps *PoolStats) GetWarmupStats() (int64, time.Duration) {
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+	return ps.WarmedConnections, ps.WarmupDuration
+}
+
 func (ps *PoolStats) GetStats() (int64, int64, int64, int64) {
 	ps.mutex.RLock()
 	defer ps.mutex.RUnlock()
@@ -64,40 +106,101 @@ func NewBuddyConnectionPool(dbSource string) *BuddyConnectionPool {
 		pools:    make(map[int]*sync.Pool),
 		dbSource: dbSource,
 		stats:    &PoolStats{},
+		conns:    make(map[*PooledConnection]struct{}),
+		inUse:    make(map[*PooledConnection]struct{}),
+		idle:     make(map[int][]*PooledConnection),
 	}
 	
 	// 2의 거듭제곱 크기별 풀 초기화 (1, 2, 4, 8, 16, 32 커넥션)
+	// New를 두지 않아 비어 있는 풀은 미스로 집계되고 GetConnection이 직접 생성
 	for size := 1; size <= 32; size *= 2 {
-		currentSize := size
-		bcp.pools[size] = &sync.Pool{
-			New: func() interface{} {
-				return bcp.createConnectionGroup(currentSize)
-			},
-		}
+		bcp.pools[size] = &sync.Pool{}
 	}
 	
 	return bcp
 }
 
-func (bcp *BuddyConnectionPool) createConnectionGroup(size int) []*PooledConnection {
-	connections := make([]*PooledConnection, size)
+// NewWarmBuddyConnectionPool은 워밍업을 마친 풀을 만든다
+func NewWarmBuddyConnectionPool(dbSource string, warmup WarmupConfig) *BuddyConnectionPool {
+	bcp := NewBuddyConnectionPool(dbSource)
+	bcp.warmup = warmup
+	bcp.WarmUp()
+	return bcp
+}
+
+// WarmUp은 설정된 크기 클래스마다 커넥션을 미리 만들어 첫 요청이 동기적으로
+// 커넥션을 생성하지 않도록 한다. 새로 만든 커넥션 수를 반환한다.
+func (bcp *BuddyConnectionPool) WarmUp() int {
+	if bcp.warmup.ConnectionsPerClass <= 0 || bcp.isDraining() {
+		return 0
+	}
 	
-	for i := 0; i < size; i++ {
-		db, err := sql.Open("sqlite3", bcp.dbSource)
-		if err != nil {
-			log.Printf("커넥션 생성 실패: %v", err)
-			continue
+	start := time.Now()
+	classes := bcp.warmup.SizeClasses
+	if len(classes) == 0 {
+		for size := 1; size <= 32; size *= 2 {
+			classes = append(classes, size)
 		}
-		
-		connections[i] = &PooledConnection{
-			DB:       db,
-			poolSize: size,
-			created:  time.Now(),
-			lastUsed: time.Now(),
+	}
+	
+	created := 0
+	for _, class := range classes {
+		size := bcp.nextPowerOf2(class)
+		for i := 0; i < bcp.warmup.ConnectionsPerClass; i++ {
+			conn := bcp.newConnection(size)
+			if conn == nil {
+				continue
+			}
+			bcp.connMutex.Lock()
+			bcp.idle[size] = append(bcp.idle[size], conn)
+			bcp.connMutex.Unlock()
+			created++
 		}
 	}
 	
-	return connections
+	elapsed := time.Since(start)
+	bcp.stats.RecordWarmup(created, elapsed)
+	log.Printf("커넥션 풀 워밍업 완료: %d개 생성 (소요시간: %v)", created, elapsed)
+	return created
+}
+
+// Reopen은 드레인된 풀을 다시 열고 워밍업을 재실행한다
+func (bcp *BuddyConnectionPool) Reopen() int {
+	bcp.connMutex.Lock()
+	bcp.draining = false
+	bcp.connMutex.Unlock()
+	return bcp.WarmUp()
+}
+
+func (bcp *BuddyConnectionPool) newConnection(size int) *PooledConnection {
+	db, err := sql.Open("sqlite3", bcp.dbSource)
+	if err != nil {
+		log.Printf("커넥션 생성 실패: %v", err)
+		return nil
+	}
+	
+	conn := &PooledConnection{
+		DB:       db,
+		poolSize: size,
+		created:  time.Now(),
+		lastUsed: time.Now(),
+	}
+	bcp.track(conn)
+	return conn
+}
+
+// takeIdle은 워밍업된 커넥션이 남아 있으면 하나 꺼낸다
+func (bcp *BuddyConnectionPool) takeIdle(size int) *PooledConnection {
+	bcp.connMutex.Lock()
+	defer bcp.connMutex.Unlock()
+	
+	conns := bcp.idle[size]
+	if len(conns) == 0 {
+		return nil
+	}
+	conn := conns[len(conns)-1]
+	bcp.idle[size] = conns[:len(conns)-1]
+	return conn
 }
 
 func (bcp *BuddyConnectionPool) nextPowerOf2(size int) int {
@@ -114,6 +217,11 @@ func (bcp *BuddyConnectionPool) nextPowerOf2(size int) int {
 func (bcp *BuddyConnectionPool) GetConnection(requestedSize int) *PooledConnection {
 	bcp.stats.IncrementRequest()
 	
+	if bcp.isDraining() {
+		log.Printf("커넥션 요청 거부: %v", ErrPoolDraining)
+		return nil
+	}
+	
 	poolSize := bcp.nextPowerOf2(requestedSize)
 	
 	bcp.mutex.RLock()
@@ -129,32 +237,45 @@ func (bcp *BuddyConnectionPool) GetConnection(requestedSize int) *PooledConnecti
 			return nil
 		}
 		
-		return &PooledConnection{
+		conn := &PooledConnection{
 			DB:       db,
 			poolSize: poolSize,
 			created:  time.Now(),
 			lastUsed: time.Now(),
 			inUse:    true,
 		}
+		bcp.track(conn)
+		bcp.markInUse(conn)
+		return conn
+	}
+	
+	// 워밍업된 커넥션, 그다음 반환된 커넥션 순으로 재사용
+	conn := bcp.takeIdle(poolSize)
+	if conn == nil {
+		if connectionGroup, ok := pool.Get().([]*PooledConnection); ok && len(connectionGroup) > 0 {
+			conn = connectionGroup[0]
+		}
 	}
 	
-	// 풀에서 커넥션 그룹 가져오기
-	connectionGroup := pool.Get().([]*PooledConnection)
-	if len(connectionGroup) > 0 {
+	if conn != nil {
 		bcp.stats.IncrementHit()
-		conn := connectionGroup[0]
-		conn.inUse = true
-		conn.lastUsed = time.Now()
-		
-		bcp.stats.mutex.Lock()
-		bcp.stats.ActiveConnections++
-		bcp.stats.mutex.Unlock()
-		
-		return conn
+	} else {
+		// 풀이 비어 있으면 요청 경로에서 동기적으로 생성
+		bcp.stats.IncrementMiss()
+		if conn = bcp.newConnection(poolSize); conn == nil {
+			return nil
+		}
 	}
 	
-	bcp.stats.IncrementMiss()
-	return nil
+	conn.inUse = true
+	conn.lastUsed = time.Now()
+	bcp.markInUse(conn)
+	
+	bcp.stats.mutex.Lock()
+	bcp.stats.ActiveConnections++
+	bcp.stats.mutex.Unlock()
+	
+	return conn
 }
 
 func (bcp *BuddyConnectionPool) PutConnection(conn *PooledConnection) {
@@ -169,8 +290,15 @@ func (bcp *BuddyConnectionPool) PutConnection(conn *PooledConnection) {
 	bcp.stats.ActiveConnections--
 	bcp.stats.mutex.Unlock()
 	
+	// 드레인 중이면 풀로 돌려보내지 않고 바로 닫음
+	if bcp.release(conn) {
+		conn.Close()
+		return
+	}
+	
 	// 커넥션이 너무 오래된 경우 폐기
 	if time.Since(conn.created) > 30*time.Minute {
+		bcp.untrack(conn)
 		conn.Close()
 		return
 	}
@@ -183,10 +311,124 @@ func (bcp *BuddyConnectionPool) PutConnection(conn *PooledConnection) {
 	if exists {
 		pool.Put([]*PooledConnection{conn})
 	} else {
+		bcp.untrack(conn)
 		conn.Close()
 	}
 }
 
+func (bcp *BuddyConnectionPool) track(conn *PooledConnection) {
+	bcp.connMutex.Lock()
+	bcp.conns[conn] = struct{}{}
+	bcp.connMutex.Unlock()
+}
+
+func (bcp *BuddyConnectionPool) untrack(conn *PooledConnection) {
+	bcp.connMutex.Lock()
+	delete(bcp.conns, conn)
+	bcp.connMutex.Unlock()
+}
+
+func (bcp *BuddyConnectionPool) markInUse(conn *PooledConnection) {
+	bcp.connMutex.Lock()
+	bcp.inUse[conn] = struct{}{}
+	bcp.connMutex.Unlock()
+}
+
+func (bcp *BuddyConnectionPool) isDraining() bool {
+	bcp.connMutex.Lock()
+	defer bcp.connMutex.Unlock()
+	return bcp.draining
+}
+
+// release는 반환된 커넥션을 사용 중 목록에서 빼고, 드레인 중이면 true를 반환
+func (bcp *BuddyConnectionPool) release(conn *PooledConnection) bool {
+	bcp.connMutex.Lock()
+	defer bcp.connMutex.Unlock()
+	
+	delete(bcp.inUse, conn)
+	if !bcp.draining {
+		return false
+	}
+	
+	delete(bcp.conns, conn)
+	if len(bcp.inUse) == 0 && bcp.drained != nil {
+		close(bcp.drained)
+		bcp.drained = nil
+	}
+	return true
+}
+
+// Drain은 새 커넥션 발급을 멈추고, 사용 중인 커넥션이 반환되기를 ctx 기한까지
+// 기다린 뒤 남은 커넥션을 모두 닫는다. 기한 내에 반환되지 않아 강제로 닫은
+// 커넥션 수를 반환하며, 이 경우 ctx의 에러를 함께 돌려준다.
+func (bcp *BuddyConnectionPool) Drain(ctx context.Context) (int, error) {
+	bcp.connMutex.Lock()
+	bcp.draining = true
+	var drained chan struct{}
+	if len(bcp.inUse) > 0 {
+		if bcp.drained == nil {
+			bcp.drained = make(chan struct{})
+		}
+		drained = bcp.drained
+	}
+	bcp.connMutex.Unlock()
+	
+	var waitErr error
+	if drained != nil {
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			waitErr = ctx.Err()
+		}
+	}
+	
+	bcp.connMutex.Lock()
+	forced := len(bcp.inUse)
+	conns := make([]*PooledConnection, 0, len(bcp.conns))
+	for conn := range bcp.conns {
+		conns = append(conns, conn)
+	}
+	bcp.conns = make(map[*PooledConnection]struct{})
+	bcp.inUse = make(map[*PooledConnection]struct{})
+	bcp.idle = make(map[int][]*PooledConnection)
+	bcp.drained = nil
+	bcp.connMutex.Unlock()
+	
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil {
+			log.Printf("드레인 중 커넥션 종료 실패: %v", err)
+		}
+	}
+	
+	if forced > 0 {
+		log.Printf("드레인 기한 초과: 사용 중인 커넥션 %d개 강제 종료", forced)
+	}
+	
+	return forced, waitErr
+}
+
+// drainOnSignal은 SIGTERM/SIGINT를 받으면 풀을 드레인하고 프로세스를 종료
+func drainOnSignal(pool *BuddyConnectionPool, timeout time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	
+	go func() {
+		sig := <-signals
+		log.Printf("종료 시그널 수신 (%v), 커넥션 드레인 시작", sig)
+		
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		
+		forced, err := pool.Drain(ctx)
+		if err != nil {
+			log.Printf("드레인 완료 (강제 종료 %d개): %v", forced, err)
+			os.Exit(1)
+		}
+		log.Printf("드레인 완료")
+		os.Exit(0)
+	}()
+}
+
 // 실무용 데이터베이스 서비스
 type UserService struct {
 	pool *BuddyConnectionPool
@@ -200,7 +442,11 @@ type User struct {
 }
 
 func NewUserService(dbSource string) *UserService {
-	pool := NewBuddyConnectionPool(dbSource)
+	// 자주 쓰는 크기 클래스(1, 4, 8)를 미리 채워 첫 요청의 지연을 줄임
+	pool := NewWarmBuddyConnectionPool(dbSource, WarmupConfig{
+		ConnectionsPerClass: 2,
+		SizeClasses:         []int{1, 4, 8},
+	})
 	
 	// 테이블 초기화
 	conn := pool.GetConnection(1)
@@ -331,6 +577,7 @@ func (us *UserService) GetPoolStats() (int64, int64, int64, int64) {
 // 실무 시나리오 테스트
 func main() {
 	userService := NewUserService(":memory:")
+	drainOnSignal(userService.pool, 10*time.Second)
 	
 	// 1. 단일 사용자 생성
 	log.Println("=== 단일 사용자 생성 ===")
@@ -377,6 +624,8 @@ func main() {
 	log.Printf("총 요청: %d", total)
 	log.Printf("풀 적중: %d", hits)
 	log.Printf("풀 실패: %d", misses)
+	warmed, warmupTime := userService.pool.stats.GetWarmupStats()
+	log.Printf("워밍업 커넥션: %d (소요시간: %v)", warmed, warmupTime)
 	if total > 0 {
 		log.Printf("적중률: %.2f%%", float64(hits)/float64(total)*100)
 	}
@@ -415,4 +664,11 @@ func main() {
 	log.Printf("\n=== 최종 통계 ===")
 	log.Printf("총 요청: %d", total)
 	log.Printf("풀 적중률: %.2f%%", float64(hits)/float64(total)*100)
+	
+	// 정상 종료 시에도 커넥션을 드레인
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if forced, err := userService.pool.Drain(ctx); err != nil {
+		log.Printf("드레인 실패 (강제 종료 %d개): %v", forced, err)
+	}
 }
\ No newline at end of file
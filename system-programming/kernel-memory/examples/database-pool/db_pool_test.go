@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrainForceClosesHeldConnectionAfterDeadline(t *testing.T) {
+	pool := NewBuddyConnectionPool(":memory:")
+
+	held := pool.GetConnection(1)
+	if held == nil {
+		t.Fatal("expected a connection from the pool")
+	}
+	if err := held.Ping(); err != nil {
+		t.Fatalf("held connection should be usable before drain: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	forced, err := pool.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("drain returned before the deadline: %v", elapsed)
+	}
+	if forced != 1 {
+		t.Errorf("expected 1 forcibly closed connection, got %d", forced)
+	}
+	if err := held.Ping(); err == nil {
+		t.Error("expected held connection to be closed after drain")
+	}
+
+	if conn := pool.GetConnection(1); conn != nil {
+		t.Error("expected no new connections while draining")
+	}
+
+	// 늦게 반환된 커넥션도 안전하게 처리되어야 함
+	pool.PutConnection(held)
+}
+
+func TestDrainWaitsForReturnedConnection(t *testing.T) {
+	pool := NewBuddyConnectionPool(":memory:")
+
+	held := pool.GetConnection(1)
+	if held == nil {
+		t.Fatal("expected a connection from the pool")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.PutConnection(held)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	forced, err := pool.Drain(ctx)
+	if err != nil {
+		t.Fatalf("expected clean drain, got %v", err)
+	}
+	if forced != 0 {
+		t.Errorf("expected no forcibly closed connections, got %d", forced)
+	}
+}
+
+func TestWarmUpServesInitialRequestsFromPool(t *testing.T) {
+	warmup := WarmupConfig{ConnectionsPerClass: 2, SizeClasses: []int{1, 4}}
+	pool := NewWarmBuddyConnectionPool(":memory:", warmup)
+
+	warmed, duration := pool.stats.GetWarmupStats()
+	if warmed != 4 {
+		t.Fatalf("expected 4 warmed connections, got %d", warmed)
+	}
+	if duration <= 0 {
+		t.Errorf("expected warm-up duration to be recorded, got %v", duration)
+	}
+
+	var held []*PooledConnection
+	for _, size := range []int{1, 1, 3, 4} {
+		conn := pool.GetConnection(size)
+		if conn == nil {
+			t.Fatalf("expected a connection for size %d", size)
+		}
+		held = append(held, conn)
+	}
+
+	_, total, hits, misses := pool.stats.GetStats()
+	if total != 4 || hits != 4 || misses != 0 {
+		t.Errorf("expected 4 hits and no misses after warm-up, got total=%d hits=%d misses=%d", total, hits, misses)
+	}
+
+	// 워밍업 분량을 넘어서면 동기 생성(미스)으로 처리
+	extra := pool.GetConnection(1)
+	if _, _, _, misses := pool.stats.GetStats(); misses != 1 {
+		t.Errorf("expected a miss once warmed connections are exhausted, got %d", misses)
+	}
+	held = append(held, extra)
+
+	for _, conn := range held {
+		pool.PutConnection(conn)
+	}
+}
+
+func TestColdPoolFirstRequestIsMiss(t *testing.T) {
+	pool := NewBuddyConnectionPool(":memory:")
+
+	conn := pool.GetConnection(1)
+	if conn == nil {
+		t.Fatal("expected a connection from the pool")
+	}
+	defer pool.PutConnection(conn)
+
+	if _, _, hits, misses := pool.stats.GetStats(); hits != 0 || misses != 1 {
+		t.Errorf("expected cold pool to miss, got hits=%d misses=%d", hits, misses)
+	}
+}
+
+func TestReopenAfterDrainWarmsUpAgain(t *testing.T) {
+	pool := NewWarmBuddyConnectionPool(":memory:", WarmupConfig{ConnectionsPerClass: 1, SizeClasses: []int{1}})
+
+	if _, err := pool.Drain(context.Background()); err != nil {
+		t.Fatalf("drain failed: %v", err)
+	}
+	if created := pool.Reopen(); created != 1 {
+		t.Fatalf("expected reopen to warm 1 connection, got %d", created)
+	}
+
+	conn := pool.GetConnection(1)
+	if conn == nil {
+		t.Fatal("expected a connection after reopen")
+	}
+	defer pool.PutConnection(conn)
+	if err := conn.Ping(); err != nil {
+		t.Fatalf("warmed connection should be usable: %v", err)
+	}
+
+	if warmed, _ := pool.stats.GetWarmupStats(); warmed != 2 {
+		t.Errorf("expected warm-up counts to accumulate to 2, got %d", warmed)
+	}
+	if _, _, hits, misses := pool.stats.GetStats(); hits != 1 || misses != 0 {
+		t.Errorf("expected reopened pool to hit, got hits=%d misses=%d", hits, misses)
+	}
+}
@@ -127,9 +127,22 @@ func (ba *BuddyAllocator) DeallocateBuffer(buf []byte) {
 	}
 }
 
-// 성능 테스트
-func benchmarkAllocator(name string, allocFunc func(int) []byte, deallocFunc func([]byte), iterations int) {
-	start := time.Now()
+// Clock은 벤치마크의 시간 측정 소스
+// 보고용 수치는 realClock을 사용하고, 테스트에서는 결정적인 시계로 교체 가능
+type Clock interface {
+	Now() time.Time
+	Since(start time.Time) time.Duration
+}
+
+// realClock은 벽시계(wall-clock) 시간을 사용
+type realClock struct{}
+
+func (realClock) Now() time.Time                      { return time.Now() }
+func (realClock) Since(start time.Time) time.Duration { return time.Since(start) }
+
+// 성능 테스트 - 측정한 소요 시간을 반환
+func benchmarkAllocator(clock Clock, name string, allocFunc func(int) []byte, deallocFunc func([]byte), iterations int) time.Duration {
+	start := clock.Now()
 	var memBefore runtime.MemStats
 	runtime.GC()
 	runtime.ReadMemStats(&memBefore)
@@ -144,34 +157,36 @@ func benchmarkAllocator(name string, allocFunc func(int) []byte, deallocFunc fun
 	runtime.GC()
 	runtime.ReadMemStats(&memAfter)
 	
-	duration := time.Since(start)
+	duration := clock.Since(start)
 	fmt.Printf("%s:\n", name)
 	fmt.Printf("  시간: %v\n", duration)
 	fmt.Printf("  할당된 메모리: %d KB\n", (memAfter.TotalAlloc-memBefore.TotalAlloc)/1024)
 	fmt.Printf("  GC 횟수: %d\n", memAfter.NumGC-memBefore.NumGC)
 	fmt.Println()
+	return duration
 }
 
 func main() {
 	iterations := 100000
+	clock := realClock{}
 	
 	// 1. 일반적인 할당
 	simple := &SimpleAllocator{}
-	benchmarkAllocator("일반 할당 (커널 개념 미반영)", 
+	benchmarkAllocator(clock, "일반 할당 (커널 개념 미반영)", 
 		simple.AllocateBuffer,
 		func(buf []byte) { simple.DeallocateBuffer(buf) },
 		iterations)
 	
 	// 2. 슬랩 풀 할당
 	slabPool := NewSlabPool()
-	benchmarkAllocator("슬랩 풀 할당 (SLAB/SLOB 개념 반영)",
+	benchmarkAllocator(clock, "슬랩 풀 할당 (SLAB/SLOB 개념 반영)",
 		slabPool.AllocateBuffer,
 		func(buf []byte) { slabPool.DeallocateBuffer(buf, len(buf)) },
 		iterations)
 	
 	// 3. 버디 시스템 할당
 	buddyAlloc := NewBuddyAllocator()
-	benchmarkAllocator("버디 시스템 할당 (Buddy System 개념 반영)",
+	benchmarkAllocator(clock, "버디 시스템 할당 (Buddy System 개념 반영)",
 		buddyAlloc.AllocateBuffer,
 		func(buf []byte) { buddyAlloc.DeallocateBuffer(buf) },
 		iterations)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// stepClock은 테스트가 명시적으로 Advance할 때만 시간이 흐르는 결정적인 시계
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time                      { return c.now }
+func (c *stepClock) Since(start time.Time) time.Duration { return c.now.Sub(start) }
+func (c *stepClock) Advance(d time.Duration)             { c.now = c.now.Add(d) }
+
+// chargeCapacity는 할당마다 실제로 잡힌 용량(cap) 1바이트당 1ns씩 시계를 진행
+func chargeCapacity(clock *stepClock, allocFunc func(int) []byte) func(int) []byte {
+	return func(size int) []byte {
+		buf := allocFunc(size)
+		clock.Advance(time.Duration(cap(buf)) * time.Nanosecond)
+		return buf
+	}
+}
+
+func compareAllocators(iterations int) (simple, slab, buddy time.Duration) {
+	simpleAlloc := &SimpleAllocator{}
+	clock := &stepClock{now: time.Unix(0, 0)}
+	simple = benchmarkAllocator(clock, "simple",
+		chargeCapacity(clock, simpleAlloc.AllocateBuffer),
+		simpleAlloc.DeallocateBuffer,
+		iterations)
+
+	slabPool := NewSlabPool()
+	clock = &stepClock{now: time.Unix(0, 0)}
+	slab = benchmarkAllocator(clock, "slab",
+		chargeCapacity(clock, slabPool.AllocateBuffer),
+		func(buf []byte) { slabPool.DeallocateBuffer(buf, len(buf)) },
+		iterations)
+
+	buddyAlloc := NewBuddyAllocator()
+	clock = &stepClock{now: time.Unix(0, 0)}
+	buddy = benchmarkAllocator(clock, "buddy",
+		chargeCapacity(clock, buddyAlloc.AllocateBuffer),
+		buddyAlloc.DeallocateBuffer,
+		iterations)
+	return simple, slab, buddy
+}
+
+func TestBenchmarkAllocator_UsesInjectedClock(t *testing.T) {
+	iterations := 1000
+	simple, _, _ := compareAllocators(iterations)
+
+	// 일반 할당은 요청 크기만큼만 잡으므로 100..599 바이트를 두 번 순회한 합
+	var want time.Duration
+	for i := 0; i < iterations; i++ {
+		want += time.Duration(100+(i%500)) * time.Nanosecond
+	}
+	if simple != want {
+		t.Fatalf("simple allocator took %v on the injected clock, want %v", simple, want)
+	}
+}
+
+func TestCompareAllocators_DeterministicOrdering(t *testing.T) {
+	iterations := 1000
+	simple, slab, buddy := compareAllocators(iterations)
+
+	// 내부 단편화가 적을수록 잡힌 용량이 작다: 일반 < 버디 < 슬랩
+	if !(simple < buddy && buddy < slab) {
+		t.Fatalf("expected simple < buddy < slab, got simple=%v buddy=%v slab=%v", simple, buddy, slab)
+	}
+
+	simple2, slab2, buddy2 := compareAllocators(iterations)
+	if simple2 != simple || slab2 != slab || buddy2 != buddy {
+		t.Fatalf("comparison is not deterministic: first (%v, %v, %v), second (%v, %v, %v)",
+			simple, slab, buddy, simple2, slab2, buddy2)
+	}
+}
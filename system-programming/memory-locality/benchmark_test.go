@@ -309,3 +309,27 @@ func BenchmarkMemoryAllocation(b *testing.B) {
 		}
 	})
 }
+
+// 시뮬레이션 시계에서의 결정적 비교 테스트
+func TestCompareMatrixMultiplication_SimulatedClockOrdering(t *testing.T) {
+	size, blockSize := 128, 16
+	A := NewMatrix(size, size)
+	B := NewMatrix(size, size)
+	A.RandomFill()
+	B.RandomFill()
+
+	first := CompareMatrixMultiplication(A, B, blockSize, NewSimulatedClock(DefaultCacheModel()))
+	if !first.ImprovedMatches || !first.BlockedMatches {
+		t.Fatalf("Expected all strategies to agree, got %+v", first)
+	}
+	if !(first.Blocked < first.Improved && first.Improved < first.Naive) {
+		t.Errorf("Expected blocked < improved < naive, got blocked=%v improved=%v naive=%v",
+			first.Blocked, first.Improved, first.Naive)
+	}
+
+	// The same access pattern must produce the same simulated timings
+	second := CompareMatrixMultiplication(A, B, blockSize, NewSimulatedClock(DefaultCacheModel()))
+	if first != second {
+		t.Errorf("Expected repeatable simulated timings, got %+v and %+v", first, second)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,8 +13,15 @@ type Matrix struct {
 	data [][]int
 	rows int
 	cols int
+
+	// base is the matrix's simulated address, used only by recorder
+	base     uint64
+	recorder AccessRecorder
 }
 
+// matrixCount gives every matrix a distinct simulated address range
+var matrixCount atomic.Uint64
+
 // NewMatrix creates a new matrix with given dimensions
 func NewMatrix(rows, cols int) *Matrix {
 	data := make([][]int, rows)
@@ -24,6 +32,7 @@ func NewMatrix(rows, cols int) *Matrix {
 		data: data,
 		rows: rows,
 		cols: cols,
+		base: matrixCount.Add(1) << 32,
 	}
 }
 
@@ -39,14 +48,32 @@ func (m *Matrix) RandomFill() {
 
 // Get returns the value at position (i, j)
 func (m *Matrix) Get(i, j int) int {
+	if m.recorder != nil {
+		m.record(i, j)
+	}
 	return m.data[i][j]
 }
 
 // Set sets the value at position (i, j)
 func (m *Matrix) Set(i, j, value int) {
+	if m.recorder != nil {
+		m.record(i, j)
+	}
 	m.data[i][j] = value
 }
 
+// record reports the access as if the matrix were laid out row-major
+func (m *Matrix) record(i, j int) {
+	m.recorder.Access(m.base + uint64(i*m.cols+j)*8)
+}
+
+// newProductMatrix creates the result of A x B, reporting accesses to A's recorder
+func newProductMatrix(A, B *Matrix) *Matrix {
+	C := NewMatrix(A.rows, B.cols)
+	C.recorder = A.recorder
+	return C
+}
+
 // 2D 배열을 위한 구조체
 type Array2D struct {
 	data [][]int
@@ -106,7 +133,7 @@ func NaiveMatrixMultiply(A, B *Matrix) *Matrix {
 		panic("Matrix dimensions don't match for multiplication")
 	}
 
-	C := NewMatrix(A.rows, B.cols)
+	C := newProductMatrix(A, B)
 
 	// 전형적인 3중 루프 (i-j-k 순서)
 	// 문제점: B 행렬과 C 행렬에서 캐시 미스가 빈번하게 발생
@@ -131,7 +158,7 @@ func ImprovedMatrixMultiply(A, B *Matrix) *Matrix {
 		panic("Matrix dimensions don't match for multiplication")
 	}
 
-	C := NewMatrix(A.rows, B.cols)
+	C := newProductMatrix(A, B)
 
 	// i-k-j 순서로 루프 재정렬
 	// 장점: C 행렬의 행 단위 접근으로 캐시 효율성 향상
@@ -156,7 +183,7 @@ func BlockedMatrixMultiply(A, B *Matrix, blockSize int) *Matrix {
 		panic("Matrix dimensions don't match for multiplication")
 	}
 
-	C := NewMatrix(A.rows, B.cols)
+	C := newProductMatrix(A, B)
 
 	// 행렬을 blockSize x blockSize 블록으로 나누어 처리
 	// 각 블록이 캐시에 맞도록 크기 조정
@@ -250,6 +277,47 @@ func MatricesEqual(A, B *Matrix) bool {
 	return true
 }
 
+// MatrixComparison is the outcome of timing the three multiplication strategies
+type MatrixComparison struct {
+	Size      int
+	BlockSize int
+	Naive     time.Duration
+	Improved  time.Duration
+	Blocked   time.Duration
+	// ImprovedMatches and BlockedMatches report whether each result equals the naive one
+	ImprovedMatches bool
+	BlockedMatches  bool
+}
+
+// CompareMatrixMultiplication times naive, improved and blocked A x B with clock.
+// A clock that is also an AccessRecorder observes every matrix access.
+func CompareMatrixMultiplication(A, B *Matrix, blockSize int, clock Clock) MatrixComparison {
+	if recorder, ok := clock.(AccessRecorder); ok {
+		A.recorder, B.recorder = recorder, recorder
+		defer func() { A.recorder, B.recorder = nil, nil }()
+	}
+
+	comparison := MatrixComparison{Size: A.rows, BlockSize: blockSize}
+
+	start := clock.Now()
+	result1 := NaiveMatrixMultiply(A, B)
+	comparison.Naive = clock.Since(start)
+
+	start = clock.Now()
+	result2 := ImprovedMatrixMultiply(A, B)
+	comparison.Improved = clock.Since(start)
+
+	start = clock.Now()
+	result3 := BlockedMatrixMultiply(A, B, blockSize)
+	comparison.Blocked = clock.Since(start)
+
+	// 검증은 측정에서 제외
+	result1.recorder, result2.recorder, result3.recorder = nil, nil, nil
+	comparison.ImprovedMatches = MatricesEqual(result1, result2)
+	comparison.BlockedMatches = MatricesEqual(result1, result3)
+	return comparison
+}
+
 // 성능 측정 함수
 func BenchmarkMatrixMultiplication(size int) {
 	fmt.Printf("\n=== Matrix Multiplication Benchmark (Size: %dx%d) ===\n", size, size)
@@ -260,41 +328,34 @@ func BenchmarkMatrixMultiplication(size int) {
 	A.RandomFill()
 	B.RandomFill()
 
+	blockSize := 64 // L1 캐시에 맞는 크기
+	result := CompareMatrixMultiplication(A, B, blockSize, RealClock{})
+
 	// 1. Naive 방법
 	fmt.Println("\n1. Naive Matrix Multiplication:")
 	fmt.Println("   - Cache locality: Poor")
 	fmt.Println("   - Memory access pattern: Random (B matrix column access)")
-	start := time.Now()
-	result1 := NaiveMatrixMultiply(A, B)
-	naive_time := time.Since(start)
-	fmt.Printf("   - Time: %v\n", naive_time)
+	fmt.Printf("   - Time: %v\n", result.Naive)
 
 	// 2. Improved 방법 (루프 재정렬)
 	fmt.Println("\n2. Improved Matrix Multiplication (Loop Reordering):")
 	fmt.Println("   - Cache locality: Better")
 	fmt.Println("   - Memory access pattern: Sequential (C matrix row access)")
-	start = time.Now()
-	result2 := ImprovedMatrixMultiply(A, B)
-	improved_time := time.Since(start)
-	fmt.Printf("   - Time: %v\n", improved_time)
-	fmt.Printf("   - Speedup: %.2fx\n", float64(naive_time)/float64(improved_time))
+	fmt.Printf("   - Time: %v\n", result.Improved)
+	fmt.Printf("   - Speedup: %.2fx\n", float64(result.Naive)/float64(result.Improved))
 
 	// 3. Blocked 방법
-	blockSize := 64 // L1 캐시에 맞는 크기
 	fmt.Printf("\n3. Blocked Matrix Multiplication (Block size: %d):\n", blockSize)
 	fmt.Println("   - Cache locality: Excellent")
 	fmt.Println("   - Memory access pattern: Block-wise, cache-friendly")
-	start = time.Now()
-	result3 := BlockedMatrixMultiply(A, B, blockSize)
-	blocked_time := time.Since(start)
-	fmt.Printf("   - Time: %v\n", blocked_time)
-	fmt.Printf("   - Speedup vs Naive: %.2fx\n", float64(naive_time)/float64(blocked_time))
-	fmt.Printf("   - Speedup vs Improved: %.2fx\n", float64(improved_time)/float64(blocked_time))
+	fmt.Printf("   - Time: %v\n", result.Blocked)
+	fmt.Printf("   - Speedup vs Naive: %.2fx\n", float64(result.Naive)/float64(result.Blocked))
+	fmt.Printf("   - Speedup vs Improved: %.2fx\n", float64(result.Improved)/float64(result.Blocked))
 
 	// 결과 검증
 	fmt.Println("\n4. Result Verification:")
-	fmt.Printf("   - Naive vs Improved: %t\n", MatricesEqual(result1, result2))
-	fmt.Printf("   - Naive vs Blocked: %t\n", MatricesEqual(result1, result3))
+	fmt.Printf("   - Naive vs Improved: %t\n", result.ImprovedMatches)
+	fmt.Printf("   - Naive vs Blocked: %t\n", result.BlockedMatches)
 
 	// 캐시 성능 분석
 	fmt.Println("\n5. Cache Performance Analysis:")
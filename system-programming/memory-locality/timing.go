@@ -0,0 +1,113 @@
+package main
+
+import (
+	"time"
+)
+
+// Clock is the timing source used by the benchmark comparisons.
+// RealClock reports wall-clock numbers; SimulatedClock gives deterministic
+// relative comparisons for tests.
+type Clock interface {
+	Now() time.Time
+	Since(start time.Time) time.Duration
+}
+
+// AccessRecorder observes matrix element accesses. A Clock that also
+// implements it is attached to the matrices under test.
+type AccessRecorder interface {
+	Access(addr uint64)
+}
+
+// RealClock measures wall-clock time
+type RealClock struct{}
+
+func (RealClock) Now() time.Time                      { return time.Now() }
+func (RealClock) Since(start time.Time) time.Duration { return time.Since(start) }
+
+// CacheModel configures the set-associative LRU cache a SimulatedClock models
+type CacheModel struct {
+	LineSize    int
+	Sets        int
+	Ways        int
+	HitLatency  time.Duration
+	MissLatency time.Duration
+}
+
+// DefaultCacheModel is a 32KB, 8-way L1 data cache with 64-byte lines
+func DefaultCacheModel() CacheModel {
+	return CacheModel{
+		LineSize:    CacheLineSize,
+		Sets:        64,
+		Ways:        8,
+		HitLatency:  1 * time.Nanosecond,
+		MissLatency: 100 * time.Nanosecond,
+	}
+}
+
+// SimulatedClock advances only when memory is accessed, by the hit or miss
+// latency of a modelled cache. The same access pattern always yields the
+// same elapsed time, independent of the machine or its load.
+type SimulatedClock struct {
+	model CacheModel
+	now   time.Time
+	// sets holds the cached line tags of each set, most recently used first
+	sets   [][]uint64
+	hits   int
+	misses int
+}
+
+func NewSimulatedClock(model CacheModel) *SimulatedClock {
+	if model.LineSize <= 0 {
+		model.LineSize = CacheLineSize
+	}
+	if model.Sets <= 0 {
+		model.Sets = 1
+	}
+	if model.Ways <= 0 {
+		model.Ways = 1
+	}
+	return &SimulatedClock{
+		model: model,
+		now:   time.Unix(0, 0),
+		sets:  make([][]uint64, model.Sets),
+	}
+}
+
+func (c *SimulatedClock) Now() time.Time {
+	return c.now
+}
+
+func (c *SimulatedClock) Since(start time.Time) time.Duration {
+	return c.now.Sub(start)
+}
+
+// Access charges one memory access to the clock
+func (c *SimulatedClock) Access(addr uint64) {
+	line := addr / uint64(c.model.LineSize)
+	set := c.sets[line%uint64(len(c.sets))]
+
+	for i, tag := range set {
+		if tag == line {
+			// Move to the front to keep LRU order
+			copy(set[1:i+1], set[:i])
+			set[0] = line
+			c.hits++
+			c.now = c.now.Add(c.model.HitLatency)
+			return
+		}
+	}
+
+	if len(set) < c.model.Ways {
+		set = append(set, 0)
+	}
+	copy(set[1:], set[:len(set)-1])
+	set[0] = line
+	c.sets[line%uint64(len(c.sets))] = set
+	c.misses++
+	c.now = c.now.Add(c.model.MissLatency)
+}
+
+// Stats returns the modelled cache hits and misses so far
+func (c *SimulatedClock) Stats() (hits, misses int) {
+	return c.hits, c.misses
+}
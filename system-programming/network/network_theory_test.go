@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"math/rand"
 	"net"
 	"strings"
 	"sync"
@@ -612,4 +613,98 @@ func TestComprehensiveNetworkAnalysis(t *testing.T) {
 	t.Log("3. Application-level features often overshadow protocol-level optimizations")
 	t.Log("4. Proper testing must account for network variability and failure scenarios")
 	t.Log("5. Performance optimization requires understanding both theory and practical limitations")
-}
\ No newline at end of file
+}
+
+func TestUDPStatisticsRTTPercentiles(t *testing.T) {
+	conn := NewPracticalUDPConnection(1024, time.Second)
+
+	// Known distribution: 1ms..10000ms uniformly, fed in a shuffled order
+	const samples = 10000
+	rtts := make([]time.Duration, samples)
+	for i := range rtts {
+		rtts[i] = time.Duration(i+1) * time.Millisecond
+	}
+	rng := rand.New(rand.NewSource(42))
+	rng.Shuffle(len(rtts), func(i, j int) { rtts[i], rtts[j] = rtts[j], rtts[i] })
+
+	for _, rtt := range rtts {
+		conn.RecordRTT(rtt)
+	}
+
+	stats := conn.GetStatistics()
+
+	checks := []struct {
+		name     string
+		got      time.Duration
+		expected time.Duration
+	}{
+		{"p50", stats.RTTP50, 5000 * time.Millisecond},
+		{"p95", stats.RTTP95, 9500 * time.Millisecond},
+		{"p99", stats.RTTP99, 9900 * time.Millisecond},
+		{"average", stats.AverageRTT, 5000500 * time.Microsecond},
+	}
+
+	for _, check := range checks {
+		tolerance := float64(check.expected) * 0.02
+		diff := float64(check.got - check.expected)
+		if diff < -tolerance || diff > tolerance {
+			t.Errorf("%s RTT = %v, want %v (±2%%)", check.name, check.got, check.expected)
+		}
+	}
+}
+
+func TestUDPReorderBufferBounded(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxPackets  int
+		maxBytes    int
+		payloadSize int
+		wantKept    int
+	}{
+		{"count limit", 8, 1 << 20, 100, 8},
+		{"byte limit", 1024, 4096, 1000, 4},
+		{"payload larger than byte limit", 1024, 512, 1000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := NewPracticalUDPConnection(2048, time.Second)
+			conn.SetReorderBufferLimits(tt.maxPackets, tt.maxBytes)
+
+			// Packet #1 never arrives, so every later packet is out of order
+			const sent = 1000
+			for seq := uint32(sent + 1); seq >= 2; seq-- {
+				conn.mu.Lock()
+				conn.sequencePacket(&UDPPacket{SequenceNumber: seq, Data: make([]byte, tt.payloadSize)})
+				conn.mu.Unlock()
+
+				stats := conn.GetStatistics()
+				if stats.BufferedPackets > tt.maxPackets || stats.BufferedBytes > tt.maxBytes {
+					t.Fatalf("buffer exceeded limits: %d packets, %d bytes", stats.BufferedPackets, stats.BufferedBytes)
+				}
+			}
+
+			stats := conn.GetStatistics()
+			if stats.BufferedPackets != tt.wantKept || stats.BufferedBytes != tt.wantKept*tt.payloadSize {
+				t.Errorf("buffered %d packets (%d bytes), want %d", stats.BufferedPackets, stats.BufferedBytes, tt.wantKept)
+			}
+			if stats.ReorderDrops != int64(sent-tt.wantKept) {
+				t.Errorf("ReorderDrops = %d, want %d", stats.ReorderDrops, sent-tt.wantKept)
+			}
+
+			// The packets kept are the ones right after the gap
+			conn.mu.Lock()
+			defer conn.mu.Unlock()
+			conn.sequencePacket(&UDPPacket{SequenceNumber: 1})
+			for want := uint32(2); want < uint32(2+tt.wantKept); want++ {
+				packet, err := conn.checkBufferedPackets()
+				if err != nil || packet.SequenceNumber != want {
+					t.Fatalf("expected buffered packet #%d, got %v (%v)", want, packet, err)
+				}
+			}
+			if conn.bufferedBytes != 0 || len(conn.packetBuffer) != 0 {
+				t.Errorf("buffer not drained: %d packets, %d bytes", len(conn.packetBuffer), conn.bufferedBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// P2QuantileEstimator tracks a single quantile of a stream using the P² algorithm
+// (Jain & Chlamtac, 1985).
+// Theory: percentiles need every sample sorted
+// Practice: five markers adjusted per observation give a close estimate in O(1) memory
+type P2QuantileEstimator struct {
+	quantile  float64
+	count     int
+	heights   [5]float64 // marker heights (estimated values)
+	positions [5]float64 // actual marker positions
+	desired   [5]float64 // desired marker positions
+	increment [5]float64 // desired position increments per observation
+}
+
+func NewP2QuantileEstimator(quantile float64) *P2QuantileEstimator {
+	return &P2QuantileEstimator{
+		quantile:  quantile,
+		desired:   [5]float64{1, 1 + 2*quantile, 1 + 4*quantile, 3 + 2*quantile, 5},
+		increment: [5]float64{0, quantile / 2, quantile, (1 + quantile) / 2, 1},
+	}
+}
+
+// Add folds one observation into the estimate
+func (e *P2QuantileEstimator) Add(x float64) {
+	if e.count < 5 {
+		e.heights[e.count] = x
+		e.count++
+		if e.count == 5 {
+			sort.Float64s(e.heights[:])
+			for i := range e.positions {
+				e.positions[i] = float64(i + 1)
+			}
+		}
+		return
+	}
+	e.count++
+
+	// Find the cell containing x, widening the extremes if needed
+	var k int
+	switch {
+	case x < e.heights[0]:
+		e.heights[0] = x
+		k = 0
+	case x >= e.heights[4]:
+		e.heights[4] = x
+		k = 3
+	default:
+		for k = 0; k < 3; k++ {
+			if x < e.heights[k+1] {
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.positions[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.increment[i]
+	}
+
+	// Nudge the middle markers toward their desired positions
+	for i := 1; i <= 3; i++ {
+		d := e.desired[i] - e.positions[i]
+		if (d >= 1 && e.positions[i+1]-e.positions[i] > 1) || (d <= -1 && e.positions[i-1]-e.positions[i] < -1) {
+			sign := math.Copysign(1, d)
+			height := e.parabolic(i, sign)
+			if e.heights[i-1] < height && height < e.heights[i+1] {
+				e.heights[i] = height
+			} else {
+				e.heights[i] = e.linear(i, sign)
+			}
+			e.positions[i] += sign
+		}
+	}
+}
+
+func (e *P2QuantileEstimator) parabolic(i int, d float64) float64 {
+	n := e.positions
+	q := e.heights
+	return q[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+func (e *P2QuantileEstimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.heights[i] + d*(e.heights[j]-e.heights[i])/(e.positions[j]-e.positions[i])
+}
+
+// Value returns the current estimate; with fewer than five samples it falls
+// back to the exact quantile of what has been seen
+func (e *P2QuantileEstimator) Value() float64 {
+	if e.count == 0 {
+		return 0
+	}
+	if e.count < 5 {
+		samples := append([]float64(nil), e.heights[:e.count]...)
+		sort.Float64s(samples)
+		index := int(math.Round(e.quantile * float64(len(samples)-1)))
+		return samples[index]
+	}
+	return e.heights[2]
+}
+
+// Count returns the number of observations seen
+func (e *P2QuantileEstimator) Count() int {
+	return e.count
+}
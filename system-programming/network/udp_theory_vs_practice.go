@@ -33,6 +33,15 @@ type PracticalUDPConnection struct {
 	expectedSequence    uint32
 	mu                  sync.RWMutex
 	packetBuffer        map[uint32][]byte // Buffer for out-of-order packets
+	bufferedBytes       int
+	maxBufferedPackets  int
+	maxBufferedBytes    int
+	reorderDrops        int64
+	rttSamples          int64
+	rttTotal            time.Duration
+	rttP50              *P2QuantileEstimator // Streaming percentiles - no sample storage
+	rttP95              *P2QuantileEstimator
+	rttP99              *P2QuantileEstimator
 }
 
 // UDPPacket represents a practical UDP packet with metadata
@@ -51,22 +60,50 @@ type UDPStatistics struct {
 	PacketsLost        int64
 	DuplicatePackets   int64
 	OutOfOrderPackets  int64
+	ReorderDrops       int64
+	BufferedPackets    int
+	BufferedBytes      int
 	AverageRTT         time.Duration
+	RTTP50             time.Duration
+	RTTP95             time.Duration
+	RTTP99             time.Duration
 	PacketLossRate     float64
 	JitterVariance     time.Duration
 }
 
+// Default reorder buffer limits; see SetReorderBufferLimits
+const (
+	DefaultMaxBufferedPackets = 1024
+	DefaultMaxBufferedBytes   = 4 << 20
+)
+
 func NewPracticalUDPConnection(maxPacketSize int, timeout time.Duration) *PracticalUDPConnection {
 	return &PracticalUDPConnection{
-		maxPacketSize:    maxPacketSize,
-		readTimeout:      timeout,
-		writeTimeout:     timeout,
-		packetBuffer:     make(map[uint32][]byte),
+		maxPacketSize:      maxPacketSize,
+		readTimeout:        timeout,
+		writeTimeout:       timeout,
+		packetBuffer:       make(map[uint32][]byte),
+		maxBufferedPackets: DefaultMaxBufferedPackets,
+		maxBufferedBytes:   DefaultMaxBufferedBytes,
 		expectedSequence: 1,
 		sequenceNumber:   1,
+		rttP50:           NewP2QuantileEstimator(0.50),
+		rttP95:           NewP2QuantileEstimator(0.95),
+		rttP99:           NewP2QuantileEstimator(0.99),
 	}
 }
 
+// SetReorderBufferLimits caps the out-of-order buffer by packet count and total
+// payload bytes. A limit of zero or less leaves that dimension unbounded.
+func (p *PracticalUDPConnection) SetReorderBufferLimits(maxPackets, maxBytes int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.maxBufferedPackets = maxPackets
+	p.maxBufferedBytes = maxBytes
+	p.enforceBufferLimits()
+}
+
 // Listen demonstrates UDP server setup complexities
 func (p *PracticalUDPConnection) Listen(address string) error {
 	p.mu.Lock()
@@ -208,9 +245,15 @@ func (p *PracticalUDPConnection) ReceivePacket() (*UDPPacket, error) {
 	
 	p.packetsReceived++
 	
+	return p.sequencePacket(packet)
+}
+
+// sequencePacket delivers packet if it is the next expected one, otherwise
+// drops it as a duplicate or holds it in the reorder buffer. Caller holds p.mu.
+func (p *PracticalUDPConnection) sequencePacket(packet *UDPPacket) (*UDPPacket, error) {
 	// Practical consideration: Handle out-of-order packets
 	if packet.SequenceNumber != p.expectedSequence {
-		if packet.SequenceNumber < p.expectedSequence {
+		if _, buffered := p.packetBuffer[packet.SequenceNumber]; packet.SequenceNumber < p.expectedSequence || buffered {
 			// Duplicate packet
 			p.duplicatePackets++
 			log.Printf("Received duplicate packet #%d", packet.SequenceNumber)
@@ -218,10 +261,18 @@ func (p *PracticalUDPConnection) ReceivePacket() (*UDPPacket, error) {
 		} else {
 			// Out of order packet - buffer it
 			p.outOfOrderPackets++
-			p.packetBuffer[packet.SequenceNumber] = packet.Data
+			// Copy out of the receive buffer so only the payload is retained
+			data := append([]byte(nil), packet.Data...)
+			p.packetBuffer[packet.SequenceNumber] = data
+			p.bufferedBytes += len(data)
 			log.Printf("Received out-of-order packet #%d (expected #%d)", 
 				packet.SequenceNumber, p.expectedSequence)
 			
+			// Theory: buffer until the gap fills
+			// Practice: a lost packet or a peer sending huge sequence numbers would
+			// grow the buffer forever, so memory has to be capped
+			p.enforceBufferLimits()
+			
 			// Check if we can deliver buffered packets
 			return p.checkBufferedPackets()
 		}
@@ -233,10 +284,38 @@ func (p *PracticalUDPConnection) ReceivePacket() (*UDPPacket, error) {
 	return packet, nil
 }
 
+// enforceBufferLimits drops the highest-sequence buffered packets until the
+// buffer fits its limits. Those are the furthest from delivery, so the packets
+// needed to close the current gap are kept. Caller holds p.mu.
+func (p *PracticalUDPConnection) enforceBufferLimits() {
+	for p.bufferOverLimit() {
+		var highest uint32
+		for seq := range p.packetBuffer {
+			if seq > highest {
+				highest = seq
+			}
+		}
+		
+		p.bufferedBytes -= len(p.packetBuffer[highest])
+		delete(p.packetBuffer, highest)
+		p.reorderDrops++
+		log.Printf("Reorder buffer full, dropped packet #%d", highest)
+	}
+}
+
+func (p *PracticalUDPConnection) bufferOverLimit() bool {
+	if len(p.packetBuffer) == 0 {
+		return false
+	}
+	return (p.maxBufferedPackets > 0 && len(p.packetBuffer) > p.maxBufferedPackets) ||
+		(p.maxBufferedBytes > 0 && p.bufferedBytes > p.maxBufferedBytes)
+}
+
 // checkBufferedPackets tries to deliver consecutive buffered packets
 func (p *PracticalUDPConnection) checkBufferedPackets() (*UDPPacket, error) {
 	if data, exists := p.packetBuffer[p.expectedSequence]; exists {
 		delete(p.packetBuffer, p.expectedSequence)
+		p.bufferedBytes -= len(data)
 		
 		packet := &UDPPacket{
 			SequenceNumber: p.expectedSequence,
@@ -252,6 +331,20 @@ func (p *PracticalUDPConnection) checkBufferedPackets() (*UDPPacket, error) {
 	return nil, fmt.Errorf("waiting for packet #%d", p.expectedSequence)
 }
 
+// RecordRTT adds a round-trip sample, e.g. when an echo or ack for a packet arrives
+func (p *PracticalUDPConnection) RecordRTT(rtt time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Theory: average RTT describes the link
+	// Practice: tail latency (p99) is what users feel, but storing every sample is unbounded
+	p.rttSamples++
+	p.rttTotal += rtt
+	p.rttP50.Add(float64(rtt))
+	p.rttP95.Add(float64(rtt))
+	p.rttP99.Add(float64(rtt))
+}
+
 // GetStatistics returns detailed UDP connection statistics
 func (p *PracticalUDPConnection) GetStatistics() UDPStatistics {
 	p.mu.RLock()
@@ -263,12 +356,24 @@ func (p *PracticalUDPConnection) GetStatistics() UDPStatistics {
 		packetLossRate = float64(p.packetsLost) / float64(p.packetsSent) * 100
 	}
 	
+	var averageRTT time.Duration
+	if p.rttSamples > 0 {
+		averageRTT = p.rttTotal / time.Duration(p.rttSamples)
+	}
+	
 	return UDPStatistics{
 		PacketsSent:       p.packetsSent,
 		PacketsReceived:   p.packetsReceived,
 		PacketsLost:       p.packetsLost,
 		DuplicatePackets:  p.duplicatePackets,
 		OutOfOrderPackets: p.outOfOrderPackets,
+		ReorderDrops:      p.reorderDrops,
+		BufferedPackets:   len(p.packetBuffer),
+		BufferedBytes:     p.bufferedBytes,
+		AverageRTT:        averageRTT,
+		RTTP50:            time.Duration(p.rttP50.Value()),
+		RTTP95:            time.Duration(p.rttP95.Value()),
+		RTTP99:            time.Duration(p.rttP99.Value()),
 		PacketLossRate:    packetLossRate,
 	}
 }
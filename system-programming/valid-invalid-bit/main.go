@@ -14,7 +14,8 @@ import (
 func main() {
 	rand.Seed(time.Now().UnixNano())
 	
-	fmt.Println("=== 유효-무효 비트 기반 메모리 관리 시뮬레이터 ===\n")
+	fmt.Println("=== 유효-무효 비트 기반 메모리 관리 시뮬레이터 ===")
+	fmt.Println()
 	
 	// 시뮬레이션 파라미터 설정
 	pageTableSize := 8  // 페이지 테이블 크기 (8개 페이지)
@@ -40,6 +41,10 @@ func main() {
 	testScenario3(mm)
 	
 	mm.PrintStatistics()
+	
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	
+	testScenario4(totalFrames)
 }
 
 // testScenario1 demonstrates basic memory access and page faults
@@ -100,4 +105,16 @@ func testScenario3(mm *MemoryManager) {
 	}
 	
 	mm.PrintPageTable()
-}
\ No newline at end of file
+}
+
+// testScenario4 compares FIFO, LRU and Clock on a reference string that triggers Belady's anomaly
+// 벨라디의 모순을 일으키는 참조열로 FIFO, LRU, Clock 정책을 비교합니다
+func testScenario4(frames int) {
+	fmt.Println("=== 시나리오 4: 페이지 교체 정책 비교 ===")
+	
+	trace := []int{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	fmt.Printf("참조열: %v\n", trace)
+	
+	PrintPolicyComparison(ComparePolicies(trace, frames-1))
+	PrintPolicyComparison(ComparePolicies(trace, frames))
+}
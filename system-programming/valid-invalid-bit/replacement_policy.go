@@ -0,0 +1,213 @@
+package main
+
+import "fmt"
+
+// Page replacement policy names used as ComparePolicies keys
+// ComparePolicies 결과 맵의 키로 사용되는 페이지 교체 정책 이름
+const (
+	PolicyFIFO  = "FIFO"
+	PolicyLRU   = "LRU"
+	PolicyClock = "Clock"
+)
+
+// SequenceResult holds the outcome of running a page reference string through one policy
+// 하나의 교체 정책으로 페이지 참조열을 실행한 결과를 나타내는 구조체
+type SequenceResult struct {
+	Policy    string  // 교체 정책 이름
+	Frames    int     // 물리 프레임 수
+	Accesses  int     // 총 참조 횟수
+	Hits      int     // 프레임에 이미 있던 참조 수
+	Faults    int     // 페이지 폴트 수
+	Evictions int     // 축출된 페이지 수
+	FaultRate float64 // 페이지 폴트 비율 (0~1)
+
+	// BeladyAnomaly is set when some frame count k <= Frames faults less than k+1
+	// 프레임 수를 늘렸는데 폴트가 증가하는 벨라디의 모순 발생 여부
+	BeladyAnomaly bool
+	AnomalyFrames int // 모순이 처음 관찰된 프레임 수 k (k+1에서 폴트 증가)
+}
+
+// pageReplacer simulates the resident set of one replacement policy
+// 하나의 교체 정책에 대한 상주 페이지 집합을 시뮬레이션하는 인터페이스
+type pageReplacer interface {
+	// access references page and reports whether it hit and whether a page was evicted
+	access(page int) (hit, evicted bool)
+}
+
+// fifoReplacer evicts the page that was loaded earliest
+// 가장 먼저 적재된 페이지를 축출합니다
+type fifoReplacer struct {
+	frames   int
+	queue    []int
+	resident map[int]bool
+}
+
+func (r *fifoReplacer) access(page int) (bool, bool) {
+	if r.resident[page] {
+		return true, false
+	}
+
+	evicted := false
+	if len(r.queue) == r.frames {
+		delete(r.resident, r.queue[0])
+		r.queue = r.queue[1:]
+		evicted = true
+	}
+	r.queue = append(r.queue, page)
+	r.resident[page] = true
+	return false, evicted
+}
+
+// lruReplacer evicts the page that was referenced least recently
+// 가장 오래 전에 참조된 페이지를 축출합니다
+type lruReplacer struct {
+	frames int
+	order  []int // 앞쪽일수록 오래 전에 참조된 페이지
+}
+
+func (r *lruReplacer) access(page int) (bool, bool) {
+	for i, p := range r.order {
+		if p == page {
+			r.order = append(append(r.order[:i:i], r.order[i+1:]...), page)
+			return true, false
+		}
+	}
+
+	evicted := false
+	if len(r.order) == r.frames {
+		r.order = r.order[1:]
+		evicted = true
+	}
+	r.order = append(r.order, page)
+	return false, evicted
+}
+
+// clockReplacer approximates LRU with a reference bit per frame and a sweeping hand
+// 프레임마다 참조 비트를 두고 시계 바늘을 돌려 LRU를 근사합니다 (second chance)
+type clockReplacer struct {
+	pages      []int
+	referenced []bool
+	slot       map[int]int // 페이지 번호 -> 프레임 위치
+	hand       int
+}
+
+func (r *clockReplacer) access(page int) (bool, bool) {
+	if i, ok := r.slot[page]; ok {
+		r.referenced[i] = true
+		return true, false
+	}
+
+	// 빈 프레임이 있으면 바로 적재합니다
+	for i, p := range r.pages {
+		if p == -1 {
+			r.pages[i] = page
+			r.referenced[i] = true
+			r.slot[page] = i
+			return false, false
+		}
+	}
+
+	// 참조 비트가 꺼진 프레임을 만날 때까지 비트를 지우며 바늘을 이동합니다
+	for r.referenced[r.hand] {
+		r.referenced[r.hand] = false
+		r.hand = (r.hand + 1) % len(r.pages)
+	}
+	delete(r.slot, r.pages[r.hand])
+	r.pages[r.hand] = page
+	r.referenced[r.hand] = true
+	r.slot[page] = r.hand
+	r.hand = (r.hand + 1) % len(r.pages)
+	return false, true
+}
+
+// newReplacer creates an empty replacer for policy with the given number of frames
+// 지정된 정책과 프레임 수로 비어 있는 교체기를 생성합니다
+func newReplacer(policy string, frames int) pageReplacer {
+	switch policy {
+	case PolicyLRU:
+		return &lruReplacer{frames: frames}
+	case PolicyClock:
+		pages := make([]int, frames)
+		for i := range pages {
+			pages[i] = -1
+		}
+		return &clockReplacer{pages: pages, referenced: make([]bool, frames), slot: make(map[int]int)}
+	default:
+		return &fifoReplacer{frames: frames, resident: make(map[int]bool)}
+	}
+}
+
+// runSequence runs trace through policy with the given number of frames
+// 참조열을 지정된 정책과 프레임 수로 실행합니다
+func runSequence(policy string, trace []int, frames int) SequenceResult {
+	replacer := newReplacer(policy, frames)
+	result := SequenceResult{Policy: policy, Frames: frames, Accesses: len(trace)}
+
+	for _, page := range trace {
+		hit, evicted := replacer.access(page)
+		if hit {
+			result.Hits++
+		} else {
+			result.Faults++
+		}
+		if evicted {
+			result.Evictions++
+		}
+	}
+
+	if result.Accesses > 0 {
+		result.FaultRate = float64(result.Faults) / float64(result.Accesses)
+	}
+	return result
+}
+
+// ComparePolicies runs the same reference string through FIFO, LRU and Clock with
+// frames frames and returns each policy's statistics keyed by policy name.
+// Every frame count from 1 to frames is also compared against one more frame to
+// detect Belady's anomaly. Returns nil if frames is not positive.
+// 동일한 참조열을 FIFO, LRU, Clock 정책으로 실행하여 결과를 나란히 비교합니다
+func ComparePolicies(trace []int, frames int) map[string]SequenceResult {
+	if frames <= 0 {
+		return nil
+	}
+
+	results := make(map[string]SequenceResult, 3)
+	for _, policy := range []string{PolicyFIFO, PolicyLRU, PolicyClock} {
+		result := runSequence(policy, trace, frames)
+
+		// 프레임 수 k와 k+1의 폴트 수를 비교하여 벨라디의 모순을 찾습니다
+		faults := runSequence(policy, trace, 1).Faults
+		for k := 1; k <= frames; k++ {
+			next := runSequence(policy, trace, k+1).Faults
+			if next > faults {
+				result.BeladyAnomaly = true
+				result.AnomalyFrames = k
+				break
+			}
+			faults = next
+		}
+
+		results[policy] = result
+	}
+	return results
+}
+
+// PrintPolicyComparison prints ComparePolicies results as a table
+// ComparePolicies 결과를 표 형태로 출력합니다
+func PrintPolicyComparison(results map[string]SequenceResult) {
+	fmt.Printf("\n=== 페이지 교체 정책 비교 ===\n")
+	fmt.Printf("정책\t프레임\t폴트\t축출\t폴트율\t벨라디 모순\n")
+	for _, policy := range []string{PolicyFIFO, PolicyLRU, PolicyClock} {
+		result, ok := results[policy]
+		if !ok {
+			continue
+		}
+
+		anomaly := "-"
+		if result.BeladyAnomaly {
+			anomaly = fmt.Sprintf("%d→%d 프레임", result.AnomalyFrames, result.AnomalyFrames+1)
+		}
+		fmt.Printf("%s\t%d\t%d\t%d\t%.2f%%\t%s\n", result.Policy, result.Frames,
+			result.Faults, result.Evictions, result.FaultRate*100, anomaly)
+	}
+}
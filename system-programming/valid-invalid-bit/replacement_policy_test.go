@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+// 벨라디의 모순을 일으키는 고전적인 참조열
+var beladyTrace = []int{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+
+func TestComparePolicies_BeladyAnomaly(t *testing.T) {
+	three := ComparePolicies(beladyTrace, 3)
+	four := ComparePolicies(beladyTrace, 4)
+
+	// FIFO: 3 프레임에서 9번, 4 프레임에서 10번 폴트
+	if three[PolicyFIFO].Faults != 9 || four[PolicyFIFO].Faults != 10 {
+		t.Errorf("Expected FIFO faults 9 and 10, got %d and %d",
+			three[PolicyFIFO].Faults, four[PolicyFIFO].Faults)
+	}
+	if !three[PolicyFIFO].BeladyAnomaly || three[PolicyFIFO].AnomalyFrames != 3 {
+		t.Errorf("Expected FIFO to show the anomaly going from 3 to 4 frames, got %+v", three[PolicyFIFO])
+	}
+
+	// LRU는 스택 알고리즘이므로 프레임이 늘어도 폴트가 늘지 않습니다
+	if three[PolicyLRU].Faults != 10 || four[PolicyLRU].Faults != 8 {
+		t.Errorf("Expected LRU faults 10 and 8, got %d and %d",
+			three[PolicyLRU].Faults, four[PolicyLRU].Faults)
+	}
+	if four[PolicyLRU].BeladyAnomaly {
+		t.Errorf("Expected LRU not to show the anomaly, got %+v", four[PolicyLRU])
+	}
+}
+
+func TestComparePolicies_CountsHitsAndEvictions(t *testing.T) {
+	results := ComparePolicies(beladyTrace, 3)
+
+	for policy, result := range results {
+		if result.Hits+result.Faults != len(beladyTrace) {
+			t.Errorf("%s: hits %d + faults %d != %d accesses", policy, result.Hits, result.Faults, len(beladyTrace))
+		}
+		// 처음 3번의 폴트는 빈 프레임을 채우므로 축출이 없습니다
+		if result.Evictions != result.Faults-3 {
+			t.Errorf("%s: expected %d evictions, got %d", policy, result.Faults-3, result.Evictions)
+		}
+	}
+
+	if ComparePolicies(beladyTrace, 0) != nil {
+		t.Error("Expected nil results for zero frames")
+	}
+}
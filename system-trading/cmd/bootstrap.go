@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// defaultPortfolioID is the portfolio the services trade when none is specified
+const defaultPortfolioID = entities.DefaultPortfolioID
+
+// bootstrapDefaultPortfolio creates the default portfolio on first startup. It
+// is idempotent: an existing portfolio keeps its state across restarts.
+func bootstrapDefaultPortfolio(ctx context.Context, portfolios *usecases.PortfolioService, initialCash float64, logger interfaces.Logger) error {
+	portfolio, created, err := portfolios.EnsurePortfolio(ctx, defaultPortfolioID, initialCash)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap default portfolio: %w", err)
+	}
+
+	if !created {
+		logger.Info("Using existing default portfolio",
+			interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+			interfaces.Field{Key: "total_value", Value: portfolio.TotalValue},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/usecases"
+)
+
+func TestBootstrapDefaultPortfolio_CreatesOnceAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// The repository outlives each startup, as a persistent store would
+	repo := repositories.NewInMemoryPortfolioRepository()
+	startup := func(initialCash float64) {
+		service := usecases.NewPortfolioService(repo, messagebus.NewMockMessageBus(), testLogger, &recordingMetrics{})
+		if err := bootstrapDefaultPortfolio(ctx, service, initialCash, testLogger); err != nil {
+			t.Fatalf("Bootstrap failed: %v", err)
+		}
+	}
+
+	startup(250000)
+	portfolio, err := repo.GetByID(ctx, defaultPortfolioID)
+	if err != nil {
+		t.Fatalf("Expected default portfolio after first startup: %v", err)
+	}
+	if portfolio.Cash != 250000 || portfolio.TotalValue != 250000 {
+		t.Fatalf("Expected configured initial cash 250000, got cash=%v value=%v", portfolio.Cash, portfolio.TotalValue)
+	}
+
+	// Trading moves the cash; a restart must not reset or replace the portfolio
+	portfolio.Cash = 180000
+	if err := repo.Save(ctx, portfolio); err != nil {
+		t.Fatalf("Failed to save portfolio: %v", err)
+	}
+
+	startup(250000)
+	restarted, err := repo.GetByID(ctx, defaultPortfolioID)
+	if err != nil {
+		t.Fatalf("Expected default portfolio after second startup: %v", err)
+	}
+	if restarted.ID != portfolio.ID || restarted.Cash != 180000 {
+		t.Errorf("Second startup replaced the existing portfolio: cash=%v", restarted.Cash)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// readinessHandler serves the aggregate health report, answering 503 while any
+// check is unhealthy. Degraded components still accept traffic.
+func readinessHandler(health interfaces.HealthAggregator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := health.Check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status == interfaces.HealthStatusUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// connectionCheck reports a component unhealthy while it is disconnected
+func connectionCheck(component string, connected func() bool) interfaces.CheckFunc {
+	return func(ctx context.Context) (interfaces.HealthStatus, string) {
+		if !connected() {
+			return interfaces.HealthStatusUnhealthy, component + " disconnected"
+		}
+		return interfaces.HealthStatusHealthy, component + " connected"
+	}
+}
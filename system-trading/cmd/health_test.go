@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func TestReadinessHandler_ReflectsRegisteredChecks(t *testing.T) {
+	busConnected := true
+	brokerConnected := false
+
+	registry := usecases.NewHealthRegistry(time.Second, nil)
+	registry.Register("message_bus", connectionCheck("message bus", func() bool { return busConnected }))
+	registry.Register("broker", connectionCheck("broker", func() bool { return brokerConnected }))
+	handler := readinessHandler(registry)
+
+	ready := func() (int, interfaces.HealthReport) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		var report interfaces.HealthReport
+		if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+			t.Fatalf("Failed to decode readiness report: %v", err)
+		}
+		return recorder.Code, report
+	}
+
+	code, report := ready()
+	if code != http.StatusServiceUnavailable || report.Status != interfaces.HealthStatusUnhealthy {
+		t.Fatalf("Expected 503/unhealthy with broker down, got %d/%s", code, report.Status)
+	}
+	if len(report.Checks) != 2 || report.Checks[1].Name != "broker" || report.Checks[1].Detail != "broker disconnected" {
+		t.Errorf("Unexpected check results: %+v", report.Checks)
+	}
+
+	brokerConnected = true
+	if code, report := ready(); code != http.StatusOK || report.Status != interfaces.HealthStatusHealthy {
+		t.Errorf("Expected 200/healthy once the broker connects, got %d/%s", code, report.Status)
+	}
+}
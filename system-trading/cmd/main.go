@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,11 +14,17 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/system-trading/core/internal/agents"
+	"github.com/system-trading/core/internal/entities"
 	"github.com/system-trading/core/internal/infrastructure/brokers"
+	"github.com/system-trading/core/internal/infrastructure/clock"
 	"github.com/system-trading/core/internal/infrastructure/config"
 	"github.com/system-trading/core/internal/infrastructure/logger"
 	"github.com/system-trading/core/internal/infrastructure/messagebus"
 	"github.com/system-trading/core/internal/infrastructure/metrics"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/infrastructure/tracing"
+	"github.com/system-trading/core/internal/infrastructure/validation"
+	"github.com/system-trading/core/internal/infrastructure/webhook"
 	"github.com/system-trading/core/internal/usecases"
 	"github.com/system-trading/core/internal/usecases/interfaces"
 )
@@ -25,15 +32,30 @@ import (
 type Application struct {
 	config        *config.Config
 	logger        *logger.ZapLogger
-	metrics       *metrics.PrometheusMetrics
+	metrics       *metrics.GuardedMetrics
+	// lifecycle times orders from proposal to execution
+	lifecycle     *metrics.PrometheusMetrics
 	messageBus    *messagebus.NATSBus
+	// schemas upgrades order payloads published on older schema versions
+	schemas       *messagebus.SchemaRegistry
 	
 	orderService     *usecases.OrderService
 	portfolioService *usecases.PortfolioService
 	riskService      *usecases.RiskService
+	alertFanout      *usecases.RiskAlertFanout
+	orderWebhook     *webhook.OrderWebhook
+	priceCache       *usecases.PriceCache
+	reconciliation   *usecases.ReconciliationService
+	projector        *usecases.EventProjector
+	expirySweeper    *usecases.OrderExpirySweeper
+	settlement       *usecases.Settlement
+	calendar         *usecases.TradingCalendar
+	health           *usecases.HealthRegistry
 	executionAgent   *agents.ExecutionAgent
+	tracer           *tracing.SpanRecorder
 	
 	httpServer    *http.Server
+	shutdown      *shutdownSequence
 }
 
 func main() {
@@ -43,14 +65,22 @@ func main() {
 }
 
 func run() error {
-	app, err := initializeApplication()
+	started := time.Now()
+	appMetrics := metrics.NewPrometheusMetrics("system-trading-core")
+	probe := newStartupProbe(appMetrics, started, nil)
+	probe.Record(stepMetrics, started, nil)
+
+	app, err := initializeApplication(probe, appMetrics)
 	if err != nil {
+		probe.Finish(err)
 		return fmt.Errorf("failed to initialize application: %w", err)
 	}
 
-	if err := app.Start(); err != nil {
+	if err := probe.Step(stepAgentStart, app.Start); err != nil {
+		probe.Finish(err)
 		return fmt.Errorf("failed to start application: %w", err)
 	}
+	probe.Finish(nil)
 
 	defer app.Shutdown()
 
@@ -62,44 +92,75 @@ func run() error {
 	return app.WaitForShutdown()
 }
 
-func initializeApplication() (*Application, error) {
-	cfg, err := config.Load()
-	if err != nil {
+// initializeApplication takes metrics from the caller so that every step,
+// including one that fails, is recorded
+func initializeApplication(probe *startupProbe, appMetrics *metrics.PrometheusMetrics) (*Application, error) {
+	var cfg *config.Config
+	if err := probe.Step(stepConfig, func() (err error) {
+		cfg, err = config.Load()
+		return err
+	}); err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	appLogger, err := logger.NewZapLogger(cfg.Logging)
-	if err != nil {
+	var appLogger *logger.ZapLogger
+	if err := probe.Step(stepLogger, func() (err error) {
+		appLogger, err = logger.NewZapLogger(cfg.Logging)
+		return err
+	}); err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
+	probe.AttachLogger(appLogger)
+	appMetrics.SetLogger(appLogger)
+	appMetrics.SetMaxLabelValues(cfg.Metrics.MaxLabelValues)
+	// Everything past startup records through the guard so a failing backend or
+	// runaway label cardinality cannot take down the hot path
+	guardedMetrics := metrics.NewGuardedMetrics(appMetrics, appLogger, metrics.GuardedConfig{
+		MaxLabelValues:         cfg.Metrics.MaxLabelValues,
+		MaxConsecutiveFailures: cfg.Metrics.MaxConsecutiveFailures,
+		FailureCooldown:        cfg.Metrics.FailureCooldown,
+	})
 
-	appMetrics := metrics.NewPrometheusMetrics("system-trading-core")
+	var bus *messagebus.NATSBus
+	if err := probe.Step(stepBusConnect, func() error {
+		busCodec, err := messagebus.CodecByName(cfg.NATS.Serialization)
+		if err != nil {
+			return fmt.Errorf("invalid message bus serialization: %w", err)
+		}
 
-	busConfig := messagebus.Config{
-		URL:               cfg.NATS.URL,
-		MaxReconnects:     cfg.NATS.MaxReconnects,
-		ReconnectWait:     cfg.NATS.ReconnectWait,
-		ConnectionTimeout: cfg.NATS.ConnectionTimeout,
-		DrainTimeout:      cfg.NATS.DrainTimeout,
-	}
+		busConfig := messagebus.Config{
+			URL:               cfg.NATS.URL,
+			MaxReconnects:     cfg.NATS.MaxReconnects,
+			ReconnectWait:     cfg.NATS.ReconnectWait,
+			ConnectionTimeout: cfg.NATS.ConnectionTimeout,
+			DrainTimeout:      cfg.NATS.DrainTimeout,
+			Codec:             busCodec,
+		}
 
-	bus, err := messagebus.NewNATSBus(busConfig, appLogger, appMetrics)
-	if err != nil {
+		bus, err = messagebus.NewNATSBus(busConfig, appLogger, guardedMetrics)
+		return err
+	}); err != nil {
 		return nil, fmt.Errorf("failed to initialize message bus: %w", err)
 	}
 
 	app := &Application{
 		config:     cfg,
 		logger:     appLogger,
-		metrics:    appMetrics,
+		metrics:    guardedMetrics,
+		lifecycle:  appMetrics,
 		messageBus: bus,
+		shutdown:   newShutdownSequence(cfg.Shutdown.StepTimeout, appLogger),
 	}
+	app.shutdown.SetTimeouts(cfg.Shutdown.ComponentTimeouts)
+	app.shutdown.Register(componentMessageBus, func(ctx context.Context) error {
+		return bus.Close()
+	})
 
-	if err := app.initializeServices(); err != nil {
+	if err := probe.Step(stepServices, app.initializeServices); err != nil {
 		return nil, fmt.Errorf("failed to initialize services: %w", err)
 	}
 
-	if err := app.setupHTTPServer(); err != nil {
+	if err := probe.Step(stepHTTPSetup, app.setupHTTPServer); err != nil {
 		return nil, fmt.Errorf("failed to setup HTTP server: %w", err)
 	}
 
@@ -114,30 +175,102 @@ func (app *Application) initializeServices() error {
 		MaxDailyLoss:       app.config.Risk.MaxDailyLoss,
 		MaxVaR:             app.config.Risk.MaxVaR,
 		VaRConfidenceLevel: app.config.Risk.VaRConfidenceLevel,
+		MaxDrawdown:        app.config.Risk.MaxDrawdown,
+		StopLossThreshold:  app.config.Risk.StopLossThreshold,
+		CashBufferPct:      app.config.Risk.CashBufferPct,
 	}
 
 	app.portfolioService = usecases.NewPortfolioService(
-		nil, // TODO: Implement repository
+		repositories.NewInMemoryPortfolioRepository(),
 		app.messageBus,
 		app.logger,
 		app.metrics,
 	)
+	app.portfolioService.SetTaxModel(usecases.NewHoldingPeriodTaxModel(app.config.Trading.LongTermHoldingPeriod))
+	app.portfolioService.SetPortfolioUpdateMode(usecases.PortfolioUpdateMode(app.config.Trading.PortfolioUpdateMode))
+	app.portfolioService.SetCostBasisMethod(entities.CostBasisMethod(app.config.Trading.CostBasisMethod))
+	app.portfolioService.SetSnapshotRepository(repositories.NewInMemoryPortfolioSnapshotRepository())
+	if err := bootstrapDefaultPortfolio(context.Background(), app.portfolioService, app.config.Trading.InitialCash, app.logger); err != nil {
+		return err
+	}
 
-	app.orderService = usecases.NewOrderService(
-		nil, // TODO: Implement repository
+	app.riskService = usecases.NewRiskService(
+		app.portfolioService,
 		app.messageBus,
 		app.logger,
 		app.metrics,
-		nil, // TODO: Implement validator
+		riskLimits,
+		usecases.RiskServiceConfig{
+			WarmUpPeriod:          app.config.Risk.WarmUpPeriod,
+			DegradedPolicy:        usecases.DegradedPolicy(app.config.Risk.DegradedPolicy),
+			DegradedMaxOrderValue: app.config.Risk.DegradedMaxOrderValue,
+			AutoFlattenOnDrawdown: app.config.Risk.AutoFlattenOnDrawdown,
+			StalePricePolicy:      usecases.StalePricePolicy(app.config.Risk.StalePricePolicy),
+			AlertDebounceInterval: app.config.Risk.AlertDebounceInterval,
+			DefaultCorrelation:    app.config.Risk.DefaultCorrelation,
+			VaRMethod:             usecases.VaRMethod(app.config.Risk.VaRMethod),
+			HistoricalVaRDays:     app.config.Risk.HistoricalVaRDays,
+		},
 	)
+	app.priceCache = usecases.NewPriceCache(app.messageBus, app.config.Risk.PriceStaleAfter, clock.NewRealClock())
+	app.riskService.SetPriceCache(app.priceCache)
+	// Deliver debounced risk alerts before the fan-out stops accepting them
+	app.shutdown.Register(componentRiskService, func(ctx context.Context) error {
+		app.riskService.Stop()
+		return nil
+	}, componentAlertFanout)
+
+	app.alertFanout = usecases.NewRiskAlertFanout(
+		app.messageBus,
+		app.logger,
+		app.metrics,
+		usecases.BroadcastConfig{
+			BufferSize:      app.config.Risk.AlertSinkBuffer,
+			DeliveryTimeout: app.config.Risk.AlertSinkTimeout,
+		},
+	)
+	if err := app.alertFanout.AddSink(usecases.NewLogAlertSink(app.logger)); err != nil {
+		return fmt.Errorf("failed to register risk alert sink: %w", err)
+	}
+	app.shutdown.Register(componentAlertFanout, app.alertFanout.Stop, componentMessageBus)
 
-	app.riskService = usecases.NewRiskService(
-		app.portfolioService,
+	if app.config.Webhook.Enabled {
+		secret := app.config.Webhook.Secret
+		if secret == "" {
+			secret = app.config.Security.JWTSecret
+		}
+		app.orderWebhook = webhook.NewOrderWebhook(app.messageBus, webhook.Config{
+			URL:         app.config.Webhook.URL,
+			Secret:      secret,
+			Timeout:     app.config.Webhook.Timeout,
+			MaxAttempts: app.config.Webhook.MaxAttempts,
+			RetryDelay:  app.config.Webhook.RetryDelay,
+			DLQTopic:    app.config.Webhook.DLQTopic,
+		}, app.logger, app.metrics)
+	}
+
+	app.tracer = tracing.NewSpanRecorder(tracing.DefaultCapacity)
+	orderRepo := repositories.NewInMemoryOrderRepository()
+
+	app.orderService = usecases.NewOrderService(
+		orderRepo,
 		app.messageBus,
 		app.logger,
 		app.metrics,
-		riskLimits,
+		validation.NewValidator(),
+		app.riskService,
 	)
+	app.orderService.SetTracer(app.tracer)
+	app.orderService.SetSourceThrottle(usecases.NewRateLimiter(
+		usecases.RateLimit{
+			Rate:  app.config.Trading.SourceOrderRate,
+			Burst: app.config.Trading.SourceOrderBurst,
+		},
+		clock.NewRealClock(),
+	))
+
+	app.schemas = messagebus.NewSchemaRegistry()
+	messagebus.RegisterOrderSchemas(app.schemas)
 
 	// Initialize Execution Agent with Mock Broker
 	trader := brokers.NewMockBroker("MockBroker", app.logger)
@@ -146,6 +279,98 @@ func (app *Application) initializeServices() error {
 		trader,
 		app.logger,
 		app.metrics,
+		agents.RetryConfig{
+			MaxRetries:          app.config.Trading.ExecutionMaxRetries,
+			InitialDelay:        app.config.Trading.ExecutionInitialDelay,
+			MaxDelay:            app.config.Trading.ExecutionMaxDelay,
+			BackoffFactor:       app.config.Trading.ExecutionBackoffFactor,
+			JitterFraction:      app.config.Trading.ExecutionJitterFraction,
+			OrderTimeout:        app.config.Trading.ExecutionAttemptTimeout,
+			StatusCheckInterval: app.config.Trading.ExecutionStatusCheckInterval,
+			MaxTrackedOrders:    app.config.Trading.ExecutionMaxTrackedOrders,
+			AbandonAfter:        app.config.Trading.ExecutionAbandonAfter,
+			MaxPendingAge:       app.config.Trading.ExecutionMaxPendingAge,
+		},
+	)
+	app.executionAgent.SetMaxConcurrentExecutions(app.config.Trading.MaxConcurrentExecutions)
+	app.executionAgent.SetDedupWindow(app.config.Trading.ExecutionDedupWindow)
+	app.executionAgent.SetTracer(app.tracer)
+	app.executionAgent.SetSchemaRegistry(app.schemas)
+	// Fills the agent reports feed the risk service, so it stops first
+	app.shutdown.Register(componentExecutionAgent, app.executionAgent.Stop, componentRiskService, componentMessageBus)
+
+	auditRepo := repositories.NewInMemoryAuditRepository()
+	app.reconciliation = usecases.NewReconciliationService(
+		app.portfolioService,
+		trader,
+		auditRepo,
+		app.logger,
+		app.metrics,
+		app.config.Trading.ReconciliationTolerance,
+	)
+	if app.config.Trading.ReconcileOnShutdown {
+		// Reconcile against the broker before the execution agent disconnects it
+		app.shutdown.Register(componentReconciliation, func(ctx context.Context) error {
+			_, err := app.reconciliation.Reconcile(ctx, defaultPortfolioID)
+			return err
+		}, componentExecutionAgent)
+	}
+
+	app.expirySweeper = usecases.NewOrderExpirySweeper(
+		orderRepo,
+		trader,
+		app.messageBus,
+		app.logger,
+		app.metrics,
+		usecases.ExpirySweeperConfig{
+			Interval:  app.config.Trading.ExpirySweepInterval,
+			BatchSize: app.config.Trading.ExpirySweepBatchSize,
+		},
+	)
+	app.shutdown.Register(componentExpirySweeper, func(ctx context.Context) error {
+		app.expirySweeper.Stop()
+		return nil
+	}, componentExecutionAgent, componentMessageBus)
+
+	sessionLocation, err := time.LoadLocation(app.config.Trading.SessionTimezone)
+	if err != nil {
+		return fmt.Errorf("failed to load session timezone: %w", err)
+	}
+	app.calendar = usecases.NewTradingCalendar(sessionLocation, app.config.Trading.SessionOpen, app.config.Trading.SessionClose)
+	app.orderService.SetTradingCalendar(app.calendar)
+	app.settlement = usecases.NewSettlement(
+		app.calendar,
+		app.portfolioService,
+		orderRepo,
+		auditRepo,
+		app.messageBus,
+		app.logger,
+		app.metrics,
+		usecases.SettlementConfig{PortfolioID: defaultPortfolioID},
+	)
+	app.settlement.SetReconciler(app.reconciliation)
+	app.shutdown.Register(componentSettlement, func(ctx context.Context) error {
+		app.settlement.Stop()
+		return nil
+	}, componentReconciliation, componentMessageBus)
+	app.shutdown.Register(componentDayPnLReset, func(ctx context.Context) error {
+		app.portfolioService.StopDayPnLReset()
+		return nil
+	})
+	app.shutdown.Register(componentPortfolioSnapshots, func(ctx context.Context) error {
+		app.portfolioService.StopSnapshots()
+		return nil
+	})
+
+	app.health = usecases.NewHealthRegistry(app.config.Server.HealthCheckTimeout, clock.NewRealClock())
+	app.health.Register("message_bus", connectionCheck("message bus", app.messageBus.IsConnected))
+	app.health.Register("broker", connectionCheck("broker", trader.IsConnected))
+
+	app.projector = usecases.NewEventProjector(
+		app.messageBus,
+		repositories.NewInMemoryEventStore(),
+		app.logger,
+		app.metrics,
 	)
 
 	return nil
@@ -162,16 +387,57 @@ func (app *Application) setupHTTPServer() error {
 		fmt.Fprintf(w, `{"status": "healthy", "timestamp": "%s"}`, time.Now().Format(time.RFC3339))
 	})
 
-	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		if !app.messageBus.IsConnected() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			fmt.Fprintf(w, `{"status": "not ready", "reason": "message bus disconnected"}`)
+	mux.Handle("/ready", readinessHandler(app.health))
+
+	mux.HandleFunc("/reconciliation", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		report := app.reconciliation.LastReport()
+		if report == nil {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"status": "not found", "reason": "no reconciliation has run"}`)
 			return
 		}
 
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(report)
+	})
+
+	mux.HandleFunc("/positions", func(w http.ResponseWriter, r *http.Request) {
+		portfolioID := r.URL.Query().Get("portfolio_id")
+		if portfolioID == "" {
+			portfolioID = defaultPortfolioID
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{"status": "ready", "timestamp": "%s"}`, time.Now().Format(time.RFC3339))
+		json.NewEncoder(w).Encode(app.projector.Positions(portfolioID))
+	})
+
+	mux.HandleFunc("/orders/open", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(app.projector.OpenOrders())
+	})
+
+	mux.HandleFunc("/orders/tracked", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(app.executionAgent.GetTrackedOrders())
+	})
+
+	mux.HandleFunc("GET /traces/{correlationID}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		spans := app.tracer.Trace(r.PathValue("correlationID"))
+		if len(spans) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"status": "not found", "reason": "no spans recorded"}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(spans)
 	})
 
 	serverAddr := fmt.Sprintf("%s:%d", app.config.Server.Host, app.config.Server.Port)
@@ -183,6 +449,7 @@ func (app *Application) setupHTTPServer() error {
 		WriteTimeout: app.config.Server.WriteTimeout,
 		IdleTimeout:  app.config.Server.IdleTimeout,
 	}
+	app.shutdown.Register(componentHTTPServer, app.httpServer.Shutdown, componentExecutionAgent, componentMessageBus)
 
 	return nil
 }
@@ -194,6 +461,44 @@ func (app *Application) Start() error {
 		return fmt.Errorf("failed to start execution agent: %w", err)
 	}
 
+	if err := app.priceCache.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start price cache: %w", err)
+	}
+
+	if err := app.projector.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start event projector: %w", err)
+	}
+
+	if err := app.alertFanout.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start risk alert fan-out: %w", err)
+	}
+
+	if app.orderWebhook != nil {
+		if err := app.orderWebhook.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start order webhook: %w", err)
+		}
+	}
+
+	app.expirySweeper.Start(ctx)
+
+	if err := app.settlement.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start settlement: %w", err)
+	}
+
+	if err := app.portfolioService.StartDayPnLReset(ctx, usecases.DayPnLResetConfig{
+		Calendar:     app.calendar,
+		PortfolioIDs: []string{defaultPortfolioID},
+	}); err != nil {
+		return fmt.Errorf("failed to start day P&L reset: %w", err)
+	}
+
+	if err := app.portfolioService.StartSnapshots(ctx, usecases.PortfolioSnapshotConfig{
+		Interval:     app.config.Trading.SnapshotInterval,
+		PortfolioIDs: []string{defaultPortfolioID},
+	}); err != nil {
+		return fmt.Errorf("failed to start portfolio snapshots: %w", err)
+	}
+
 	go func() {
 		app.logger.Info("Starting HTTP server",
 			interfaces.Field{Key: "addr", Value: app.httpServer.Addr},
@@ -220,8 +525,34 @@ func (app *Application) subscribeToMessageBusTopics() error {
 		return fmt.Errorf("failed to subscribe to order.executed: %w", err)
 	}
 
-	if err := app.messageBus.Subscribe(ctx, "order.proposed", app.handleOrderProposed); err != nil {
-		return fmt.Errorf("failed to subscribe to order.proposed: %w", err)
+	// Orders are decoded through the schema registry so older versions still time
+	orderStages := map[string]string{
+		"order.proposed": metrics.OrderStageProposed,
+		"order.approved": metrics.OrderStageApproved,
+	}
+	for topic, stage := range orderStages {
+		handler := messagebus.VersionedHandler(app.schemas, messagebus.SubjectOrder, app.orderStageHandler(stage))
+		if err := app.messageBus.Subscribe(ctx, topic, handler); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	// The remaining stages only feed the order lifecycle timings
+	stages := map[string]string{
+		"order.rejected":  metrics.OrderStageRejected,
+		"order.cancelled": metrics.OrderStageCancelled,
+		"order.failed":    metrics.OrderStageFailed,
+		"order.expired":   metrics.OrderStageExpired,
+	}
+	for topic, stage := range stages {
+		stage := stage
+		handler := func(ctx context.Context, message []byte) error {
+			app.recordOrderStage(ctx, message, stage)
+			return nil
+		}
+		if err := app.messageBus.Subscribe(ctx, topic, handler); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
 	}
 
 	app.logger.Info("Subscribed to message bus topics")
@@ -229,11 +560,38 @@ func (app *Application) subscribeToMessageBusTopics() error {
 }
 
 func (app *Application) handleOrderExecuted(ctx context.Context, message []byte) error {
+	app.recordOrderStage(ctx, message, metrics.OrderStageExecuted)
 	return nil
 }
 
-func (app *Application) handleOrderProposed(ctx context.Context, message []byte) error {
-	return nil
+// orderStageHandler records stage for each order a versioned handler decodes
+func (app *Application) orderStageHandler(stage string) func(ctx context.Context, message interface{}) error {
+	return func(ctx context.Context, message interface{}) error {
+		if order, ok := message.(*messagebus.OrderMessage); ok && app.lifecycle != nil {
+			app.lifecycle.RecordOrderStage(string(order.Data.ID), stage)
+		}
+		return nil
+	}
+}
+
+// recordOrderStage times the order an event is about. Orders are published
+// with an id, agent events with an order_id; events with neither are ignored.
+func (app *Application) recordOrderStage(ctx context.Context, message []byte, stage string) {
+	var event struct {
+		ID      string `json:"id"`
+		OrderID string `json:"order_id"`
+	}
+	if err := interfaces.DecodeMessage(ctx, message, &event); err != nil {
+		return
+	}
+
+	orderID := event.ID
+	if orderID == "" {
+		orderID = event.OrderID
+	}
+	if orderID != "" && app.lifecycle != nil {
+		app.lifecycle.RecordOrderStage(orderID, stage)
+	}
 }
 
 func (app *Application) WaitForShutdown() error {
@@ -249,46 +607,14 @@ func (app *Application) WaitForShutdown() error {
 }
 
 func (app *Application) Shutdown() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.Shutdown.Timeout)
 	defer cancel()
 
 	app.logger.Info("Shutting down application")
 
-	// Stop execution agent first
-	if app.executionAgent != nil {
-		if err := app.executionAgent.Stop(ctx); err != nil {
-			app.logger.Error("Execution agent shutdown failed",
-				interfaces.Field{Key: "error", Value: err},
-			)
-		} else {
-			app.logger.Info("Execution agent shut down gracefully")
-		}
-	}
-
-	if app.httpServer != nil {
-		if err := app.httpServer.Shutdown(ctx); err != nil {
-			app.logger.Error("HTTP server shutdown failed",
-				interfaces.Field{Key: "error", Value: err},
-			)
-		} else {
-			app.logger.Info("HTTP server shut down gracefully")
-		}
-	}
-
-	if app.messageBus != nil {
-		if err := app.messageBus.Close(); err != nil {
-			app.logger.Error("Message bus close failed",
-				interfaces.Field{Key: "error", Value: err},
-			)
-		} else {
-			app.logger.Info("Message bus closed gracefully")
-		}
-	}
-
-	if app.logger != nil {
-		app.logger.Sync()
-	}
+	app.shutdown.Run(ctx)
 
+	app.logger.Sync()
 	app.logger.Info("Application shutdown complete")
 }
 
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// defaultShutdownStepTimeout bounds each component's stop so one hung component
+// cannot hold up the rest of the shutdown
+const defaultShutdownStepTimeout = 10 * time.Second
+
+const (
+	componentMessageBus         = "message_bus"
+	componentRiskService        = "risk_service"
+	componentAlertFanout        = "alert_fanout"
+	componentExecutionAgent     = "execution_agent"
+	componentReconciliation     = "shutdown_reconciliation"
+	componentExpirySweeper      = "expiry_sweeper"
+	componentSettlement         = "settlement"
+	componentDayPnLReset        = "day_pnl_reset"
+	componentPortfolioSnapshots = "portfolio_snapshots"
+	componentHTTPServer         = "http_server"
+)
+
+type shutdownStep struct {
+	name      string
+	dependsOn []string
+	stop      func(ctx context.Context) error
+}
+
+// shutdownSequence stops components in dependency order. A component is stopped
+// before everything it depends on, so producers stop before their consumers and
+// everything stops before the message bus.
+type shutdownSequence struct {
+	stepTimeout time.Duration
+	// timeouts overrides stepTimeout for individual components
+	timeouts map[string]time.Duration
+	logger   interfaces.Logger
+	steps    []shutdownStep
+}
+
+func newShutdownSequence(stepTimeout time.Duration, logger interfaces.Logger) *shutdownSequence {
+	if stepTimeout <= 0 {
+		stepTimeout = defaultShutdownStepTimeout
+	}
+	return &shutdownSequence{stepTimeout: stepTimeout, logger: logger}
+}
+
+// SetTimeouts gives the named components their own stop timeout instead of the
+// step timeout
+func (s *shutdownSequence) SetTimeouts(timeouts map[string]time.Duration) {
+	s.timeouts = timeouts
+}
+
+func (s *shutdownSequence) timeoutFor(name string) time.Duration {
+	if timeout, ok := s.timeouts[name]; ok && timeout > 0 {
+		return timeout
+	}
+	return s.stepTimeout
+}
+
+// Register adds a component that uses the components named in dependsOn.
+// Dependencies that are never registered are ignored, so optional components
+// can simply be left out.
+func (s *shutdownSequence) Register(name string, stop func(ctx context.Context) error, dependsOn ...string) {
+	s.steps = append(s.steps, shutdownStep{name: name, dependsOn: dependsOn, stop: stop})
+}
+
+// Order returns the component names in the order they will be stopped. Ties
+// are broken by registration order.
+func (s *shutdownSequence) Order() ([]string, error) {
+	index := make(map[string]int, len(s.steps))
+	for i, step := range s.steps {
+		index[step.name] = i
+	}
+
+	// dependents[i] counts registered components that still use step i
+	dependents := make([]int, len(s.steps))
+	for _, step := range s.steps {
+		for _, dependency := range step.dependsOn {
+			if i, ok := index[dependency]; ok {
+				dependents[i]++
+			}
+		}
+	}
+
+	order := make([]string, 0, len(s.steps))
+	stopped := make([]bool, len(s.steps))
+	for len(order) < len(s.steps) {
+		next := -1
+		for i := range s.steps {
+			if !stopped[i] && dependents[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("shutdown dependencies form a cycle")
+		}
+
+		stopped[next] = true
+		order = append(order, s.steps[next].name)
+		for _, dependency := range s.steps[next].dependsOn {
+			if i, ok := index[dependency]; ok {
+				dependents[i]--
+			}
+		}
+	}
+	return order, nil
+}
+
+// Run stops every component, giving each its own timeout but never more than
+// what remains of ctx's deadline, so the whole sequence finishes within it. If
+// the dependencies cannot be ordered, components stop in reverse registration
+// order. It returns the components that were abandoned on a timeout.
+func (s *shutdownSequence) Run(ctx context.Context) []string {
+	order, err := s.Order()
+	if err != nil {
+		s.logger.Error("Falling back to reverse registration order for shutdown",
+			interfaces.Field{Key: "error", Value: err},
+		)
+		order = make([]string, 0, len(s.steps))
+		for i := len(s.steps) - 1; i >= 0; i-- {
+			order = append(order, s.steps[i].name)
+		}
+	}
+
+	steps := make(map[string]shutdownStep, len(s.steps))
+	var total time.Duration
+	for _, step := range s.steps {
+		steps[step.name] = step
+		total += s.timeoutFor(step.name)
+	}
+	if deadline, ok := ctx.Deadline(); ok && total > time.Until(deadline) {
+		s.logger.Warn("Component shutdown timeouts exceed the overall budget; later components get what remains",
+			interfaces.Field{Key: "component_timeouts", Value: total},
+			interfaces.Field{Key: "budget", Value: time.Until(deadline)},
+		)
+	}
+
+	var timedOut []string
+	for _, name := range order {
+		if !s.runStep(ctx, steps[name]) {
+			timedOut = append(timedOut, name)
+		}
+	}
+	if len(timedOut) > 0 {
+		s.logger.Error("Components abandoned during shutdown",
+			interfaces.Field{Key: "components", Value: timedOut},
+		)
+	}
+	return timedOut
+}
+
+// runStep stops one component and reports whether it finished in time
+func (s *shutdownSequence) runStep(ctx context.Context, step shutdownStep) bool {
+	if ctx.Err() != nil {
+		s.logger.Error("Skipping component shutdown, overall deadline passed",
+			interfaces.Field{Key: "component", Value: step.name},
+		)
+		return false
+	}
+
+	timeout := s.timeoutFor(step.name)
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- step.stop(stepCtx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			s.logger.Error("Component shutdown failed",
+				interfaces.Field{Key: "component", Value: step.name},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return true
+		}
+		s.logger.Info("Component shut down",
+			interfaces.Field{Key: "component", Value: step.name},
+		)
+		return true
+	case <-stepCtx.Done():
+		if ctx.Err() != nil {
+			s.logger.Error("Component shutdown cut short by the overall deadline",
+				interfaces.Field{Key: "component", Value: step.name},
+			)
+			return false
+		}
+		s.logger.Error("Component shutdown timed out",
+			interfaces.Field{Key: "component", Value: step.name},
+			interfaces.Field{Key: "timeout", Value: timeout},
+		)
+		return false
+	}
+}
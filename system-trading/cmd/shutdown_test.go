@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+)
+
+func newShutdownTestSequence(t *testing.T, stepTimeout time.Duration) *shutdownSequence {
+	t.Helper()
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return newShutdownSequence(stepTimeout, testLogger)
+}
+
+func TestShutdownSequence_StopsInDependencyOrder(t *testing.T) {
+	seq := newShutdownTestSequence(t, time.Second)
+
+	var mu sync.Mutex
+	var stopped []string
+	register := func(name string, dependsOn ...string) {
+		seq.Register(name, func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stopped = append(stopped, name)
+			return nil
+		}, dependsOn...)
+	}
+
+	// Registered bus-first, as the application builds them
+	register("bus")
+	register("consumer", "bus")
+	register("producer", "consumer", "bus")
+	register("http", "producer", "not_registered")
+
+	seq.Run(context.Background())
+
+	want := []string{"http", "producer", "consumer", "bus"}
+	if len(stopped) != len(want) {
+		t.Fatalf("Expected shutdown order %v, got %v", want, stopped)
+	}
+	for i := range want {
+		if stopped[i] != want[i] {
+			t.Fatalf("Expected shutdown order %v, got %v", want, stopped)
+		}
+	}
+}
+
+func TestShutdownSequence_OrderRejectsCycles(t *testing.T) {
+	seq := newShutdownTestSequence(t, time.Second)
+	noop := func(ctx context.Context) error { return nil }
+	seq.Register("a", noop, "b")
+	seq.Register("b", noop, "a")
+
+	if _, err := seq.Order(); err == nil {
+		t.Fatal("Expected an error for cyclic dependencies")
+	}
+}
+
+func TestShutdownSequence_HungStepDoesNotBlockTheRest(t *testing.T) {
+	seq := newShutdownTestSequence(t, 20*time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	busClosed := make(chan struct{})
+	seq.Register("bus", func(ctx context.Context) error {
+		close(busClosed)
+		return nil
+	})
+	seq.Register("hung", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, "bus")
+
+	done := make(chan struct{})
+	go func() {
+		seq.Run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown blocked on a hung component")
+	}
+	select {
+	case <-busClosed:
+	default:
+		t.Fatal("Expected the bus to stop after the hung component timed out")
+	}
+}
+
+func TestShutdownSequence_PerComponentTimeoutsStayWithinBudget(t *testing.T) {
+	seq := newShutdownTestSequence(t, time.Second)
+	seq.SetTimeouts(map[string]time.Duration{"hung": 30 * time.Millisecond})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var mu sync.Mutex
+	stoppedAt := make(map[string]time.Duration)
+	started := time.Now()
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stoppedAt[name] = time.Since(started)
+			return nil
+		}
+	}
+
+	seq.Register("bus", record("bus"))
+	seq.Register("consumer", record("consumer"), "bus")
+	seq.Register("hung", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, "consumer")
+
+	// The step timeouts sum to more than the budget; the later steps are
+	// clamped to what remains instead of overrunning it
+	const budget = 500 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	timedOut := seq.Run(ctx)
+	elapsed := time.Since(started)
+
+	if len(timedOut) != 1 || timedOut[0] != "hung" {
+		t.Fatalf("Expected only the hung component to time out, got %v", timedOut)
+	}
+	if elapsed >= budget {
+		t.Errorf("Expected shutdown within the %s budget, took %s", budget, elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, name := range []string{"consumer", "bus"} {
+		at, ok := stoppedAt[name]
+		if !ok {
+			t.Fatalf("Expected %s to shut down after the hung component", name)
+		}
+		// The hung component is abandoned at its own 30ms, not the 1s step timeout
+		if at < 30*time.Millisecond || at > 300*time.Millisecond {
+			t.Errorf("Expected %s to stop shortly after the hung component's 30ms timeout, stopped at %s", name, at)
+		}
+	}
+}
+
+func TestShutdownSequence_SkipsStepsOnceBudgetIsSpent(t *testing.T) {
+	seq := newShutdownTestSequence(t, time.Second)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	busStopped := false
+	seq.Register("bus", func(ctx context.Context) error {
+		busStopped = true
+		return nil
+	})
+	seq.Register("hung", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, "bus")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	timedOut := seq.Run(ctx)
+	if len(timedOut) != 2 || busStopped {
+		t.Errorf("Expected both components to be abandoned once the budget ran out, got %v", timedOut)
+	}
+}
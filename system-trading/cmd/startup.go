@@ -0,0 +1,135 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// Startup steps, in the order they run
+const (
+	stepMetrics    = "metrics"
+	stepConfig     = "config_load"
+	stepLogger     = "logger"
+	stepBusConnect = "bus_connect"
+	stepServices   = "services"
+	stepHTTPSetup  = "http_setup"
+	stepAgentStart = "agent_start"
+)
+
+type startupStepResult struct {
+	name     string
+	duration time.Duration
+	err      error
+}
+
+// startupProbe times each initialization step so slow or failing startups can
+// be diagnosed. Steps that run before the logger exists are buffered and
+// logged once it is attached.
+type startupProbe struct {
+	now     func() time.Time
+	started time.Time
+	metrics interfaces.MetricsCollector
+	logger  interfaces.Logger
+	pending []startupStepResult
+}
+
+// newStartupProbe starts timing from started, which lets the caller include the
+// creation of the metrics collector itself
+func newStartupProbe(metrics interfaces.MetricsCollector, started time.Time, now func() time.Time) *startupProbe {
+	if now == nil {
+		now = time.Now
+	}
+	return &startupProbe{
+		now:     now,
+		started: started,
+		metrics: metrics,
+	}
+}
+
+// AttachLogger logs any steps that completed before the logger was available
+func (p *startupProbe) AttachLogger(logger interfaces.Logger) {
+	p.logger = logger
+	for _, result := range p.pending {
+		p.logStep(result)
+	}
+	p.pending = nil
+}
+
+// Step runs fn and records its duration, whether or not it fails
+func (p *startupProbe) Step(name string, fn func() error) error {
+	start := p.now()
+	err := fn()
+	p.Record(name, start, err)
+	return err
+}
+
+// Record records a step that began at start and has just finished
+func (p *startupProbe) Record(name string, start time.Time, err error) {
+	result := startupStepResult{name: name, duration: p.now().Sub(start), err: err}
+
+	p.metrics.RecordDuration("startup_step_duration", result.duration.Seconds(), map[string]string{
+		"step":   name,
+		"status": startupStatus(err),
+	})
+
+	if p.logger == nil {
+		p.pending = append(p.pending, result)
+	} else {
+		p.logStep(result)
+	}
+}
+
+// Finish records the total startup duration and returns it
+func (p *startupProbe) Finish(err error) time.Duration {
+	total := p.now().Sub(p.started)
+	p.metrics.SetGauge("startup_duration", total.Seconds(), map[string]string{
+		"status": startupStatus(err),
+	})
+
+	if p.logger == nil {
+		// Startup failed before the logger came up; don't lose the timings
+		for _, result := range p.pending {
+			log.Printf("startup step %s took %s (error: %v)", result.name, result.duration, result.err)
+		}
+		p.pending = nil
+		return total
+	}
+
+	if err != nil {
+		p.logger.Error("Startup failed",
+			interfaces.Field{Key: "duration", Value: total.String()},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		return total
+	}
+
+	p.logger.Info("Startup complete",
+		interfaces.Field{Key: "duration", Value: total.String()},
+	)
+	return total
+}
+
+func (p *startupProbe) logStep(result startupStepResult) {
+	if result.err != nil {
+		p.logger.Error("Startup step failed",
+			interfaces.Field{Key: "step", Value: result.name},
+			interfaces.Field{Key: "duration", Value: result.duration.String()},
+			interfaces.Field{Key: "error", Value: result.err},
+		)
+		return
+	}
+
+	p.logger.Info("Startup step complete",
+		interfaces.Field{Key: "step", Value: result.name},
+		interfaces.Field{Key: "duration", Value: result.duration.String()},
+	)
+}
+
+func startupStatus(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
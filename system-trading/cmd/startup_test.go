@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type recordedMetric struct {
+	name   string
+	value  float64
+	labels map[string]string
+}
+
+type recordingMetrics struct {
+	durations []recordedMetric
+	gauges    []recordedMetric
+}
+
+func (m *recordingMetrics) IncrementCounter(name string, labels map[string]string) {}
+
+func (m *recordingMetrics) RecordDuration(name string, duration float64, labels map[string]string) {
+	m.durations = append(m.durations, recordedMetric{name: name, value: duration, labels: labels})
+}
+
+func (m *recordingMetrics) ObserveValue(name string, value float64, labels map[string]string) {}
+
+func (m *recordingMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.gauges = append(m.gauges, recordedMetric{name: name, value: value, labels: labels})
+}
+
+// tickingClock advances by step every time it is read
+func tickingClock(start time.Time, step time.Duration) func() time.Time {
+	current := start
+	return func() time.Time {
+		current = current.Add(step)
+		return current
+	}
+}
+
+func TestStartupProbe_RecordsEveryStepAndTotal(t *testing.T) {
+	recorder := &recordingMetrics{}
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	probe := newStartupProbe(recorder, start, tickingClock(start, 10*time.Millisecond))
+
+	steps := []string{stepConfig, stepLogger, stepBusConnect, stepServices, stepHTTPSetup, stepAgentStart}
+	for _, step := range steps {
+		if err := probe.Step(step, func() error { return nil }); err != nil {
+			t.Fatalf("Step %s failed: %v", step, err)
+		}
+	}
+	total := probe.Finish(nil)
+
+	if len(recorder.durations) != len(steps) {
+		t.Fatalf("Expected %d step timings, got %d", len(steps), len(recorder.durations))
+	}
+	for i, step := range steps {
+		metric := recorder.durations[i]
+		if metric.name != "startup_step_duration" || metric.labels["step"] != step || metric.labels["status"] != "ok" {
+			t.Errorf("Unexpected timing for step %s: %+v", step, metric)
+		}
+		if metric.value != 0.01 {
+			t.Errorf("Expected step %s to take 10ms, got %.3fs", step, metric.value)
+		}
+	}
+
+	if len(recorder.gauges) != 1 || recorder.gauges[0].name != "startup_duration" {
+		t.Fatalf("Expected a startup_duration gauge, got %+v", recorder.gauges)
+	}
+	if recorder.gauges[0].value != total.Seconds() || total <= 0 {
+		t.Errorf("Expected gauge to record total %s, got %.3fs", total, recorder.gauges[0].value)
+	}
+	if recorder.gauges[0].labels["status"] != "ok" {
+		t.Errorf("Expected ok status, got %q", recorder.gauges[0].labels["status"])
+	}
+}
+
+func TestStartupProbe_FailingStepStillRecorded(t *testing.T) {
+	recorder := &recordingMetrics{}
+	start := time.Now()
+	probe := newStartupProbe(recorder, start, tickingClock(start, time.Millisecond))
+
+	connectErr := errors.New("nats: no servers available")
+	if err := probe.Step(stepBusConnect, func() error { return connectErr }); !errors.Is(err, connectErr) {
+		t.Fatalf("Expected step to return its error, got %v", err)
+	}
+	probe.Finish(connectErr)
+
+	if len(recorder.durations) != 1 {
+		t.Fatalf("Expected the failing step to be timed, got %d timings", len(recorder.durations))
+	}
+	if labels := recorder.durations[0].labels; labels["step"] != stepBusConnect || labels["status"] != "error" {
+		t.Errorf("Unexpected labels for failing step: %v", labels)
+	}
+	if len(recorder.gauges) != 1 || recorder.gauges[0].labels["status"] != "error" {
+		t.Errorf("Expected startup duration recorded with error status, got %+v", recorder.gauges)
+	}
+}
@@ -2,7 +2,10 @@ package agents
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,44 +17,168 @@ type DataCollectorAgent struct {
 	messageBus      interfaces.MessageBus
 	priceProvider   interfaces.PriceProvider
 	newsProvider    interfaces.NewsProvider
+	historyProvider interfaces.HistoricalDataProvider
 	marketDataRepo  interfaces.MarketDataRepository
+	validator       interfaces.Validator
+	sentiment       interfaces.SentimentAnalyzer
+	macroProvider   interfaces.MacroDataProvider
 	logger          interfaces.Logger
 	metrics         interfaces.MetricsCollector
+	config          DataCollectorConfig
 	
-	subscriptions   map[entities.Symbol]bool
+	subscriptions   map[entities.Symbol]int
 	mu              sync.RWMutex
+	backfillSem     chan struct{}
+	rateMu          sync.Mutex
+	health          interfaces.HealthAggregator
+	newsSeen        *newsDedup
+	lastHistoryCall time.Time
+	startTime       time.Time
+	lastPriceUpdate time.Time
 	ctx             context.Context
 	cancel          context.CancelFunc
 	wg              sync.WaitGroup
+
+	// symbolUpdates and resubscribing track feed liveness per subscribed
+	// symbol for the subscription supervisor. Guarded by mu.
+	symbolUpdates map[entities.Symbol]time.Time
+	resubscribing map[entities.Symbol]*resubscribeState
 }
 
 type DataCollectorConfig struct {
 	SubscriptionSymbols []entities.Symbol `json:"symbols"`
 	NewsUpdateInterval  time.Duration     `json:"news_interval"`
 	HealthCheckInterval time.Duration     `json:"health_interval"`
+
+	BackfillChunkSize       time.Duration `json:"backfill_chunk_size"`
+	BackfillMaxConcurrency  int           `json:"backfill_max_concurrency"`
+	BackfillRequestInterval time.Duration `json:"backfill_request_interval"`
+	// BackfillOnSubscribe backfills BackfillLookback of history in the
+	// background whenever a symbol is first subscribed
+	BackfillOnSubscribe bool          `json:"backfill_on_subscribe"`
+	BackfillLookback    time.Duration `json:"backfill_lookback"`
+
+	// SubscribeConcurrency bounds parallel provider subscriptions at startup
+	SubscribeConcurrency int           `json:"subscribe_concurrency"`
+	SubscribeTimeout     time.Duration `json:"subscribe_timeout"`
+
+	// NewsDedupWindow is how long a seen article is remembered so repeat
+	// deliveries are skipped; NewsDedupCapacity bounds how many are remembered
+	NewsDedupWindow   time.Duration `json:"news_dedup_window"`
+	NewsDedupCapacity int           `json:"news_dedup_capacity"`
+
+	// MacroIndicators are collected hourly; DefaultMacroIndicators when empty.
+	// An indicator without a Period is recorded as MONTHLY.
+	MacroIndicators []MacroIndicatorSource `json:"macro_indicators"`
+
+	// PriceStalenessWindow is how long a subscribed symbol may go without a
+	// price update before it is resubscribed; zero disables the supervisor
+	PriceStalenessWindow time.Duration `json:"price_staleness_window"`
+	// ResubscribeMaxAttempts caps consecutive resubscribe attempts per symbol;
+	// a symbol that exhausts them is retried only after an update arrives
+	ResubscribeMaxAttempts    int           `json:"resubscribe_max_attempts"`
+	ResubscribeInitialBackoff time.Duration `json:"resubscribe_initial_backoff"`
+	ResubscribeMaxBackoff     time.Duration `json:"resubscribe_max_backoff"`
+}
+
+// SubscriptionReport summarizes a batch symbol subscription
+type SubscriptionReport struct {
+	Succeeded int
+	Failed    map[entities.Symbol]error
+}
+
+// BackfillProgress is reported to BackfillAll's callback as each symbol finishes
+type BackfillProgress struct {
+	Symbol    entities.Symbol
+	Stored    int
+	Err       error
+	Completed int
+	Total     int
+}
+
+// BackfillReport summarizes a multi-symbol backfill. Pending lists symbols that
+// were not started before cancellation; passing Pending plus the Failed symbols
+// to another BackfillAll resumes the run.
+type BackfillReport struct {
+	Stored  map[entities.Symbol]int
+	Failed  map[entities.Symbol]error
+	Pending []entities.Symbol
 }
 
 func NewDataCollectorAgent(
 	messageBus interfaces.MessageBus,
 	priceProvider interfaces.PriceProvider,
 	newsProvider interfaces.NewsProvider,
+	historyProvider interfaces.HistoricalDataProvider,
 	marketDataRepo interfaces.MarketDataRepository,
+	validator interfaces.Validator,
 	logger interfaces.Logger,
 	metrics interfaces.MetricsCollector,
 	config DataCollectorConfig,
 ) *DataCollectorAgent {
 	ctx, cancel := context.WithCancel(context.Background())
+
+	if config.BackfillChunkSize <= 0 {
+		config.BackfillChunkSize = 24 * time.Hour
+	}
+	if config.BackfillMaxConcurrency <= 0 {
+		config.BackfillMaxConcurrency = 4
+	}
+	if config.BackfillLookback <= 0 {
+		config.BackfillLookback = 7 * 24 * time.Hour
+	}
+	if config.SubscribeConcurrency <= 0 {
+		config.SubscribeConcurrency = 8
+	}
+	if config.SubscribeTimeout <= 0 {
+		config.SubscribeTimeout = 10 * time.Second
+	}
+	if config.NewsDedupWindow <= 0 {
+		config.NewsDedupWindow = 24 * time.Hour
+	}
+	if config.NewsDedupCapacity <= 0 {
+		config.NewsDedupCapacity = 10000
+	}
+	if len(config.MacroIndicators) == 0 {
+		config.MacroIndicators = DefaultMacroIndicators
+	}
+	indicators := make([]MacroIndicatorSource, len(config.MacroIndicators))
+	for i, source := range config.MacroIndicators {
+		if source.Period == "" {
+			source.Period = "MONTHLY"
+		}
+		indicators[i] = source
+	}
+	config.MacroIndicators = indicators
+	if config.ResubscribeMaxAttempts <= 0 {
+		config.ResubscribeMaxAttempts = 5
+	}
+	if config.ResubscribeInitialBackoff <= 0 {
+		config.ResubscribeInitialBackoff = time.Second
+	}
+	if config.ResubscribeMaxBackoff <= 0 {
+		config.ResubscribeMaxBackoff = time.Minute
+	}
 	
 	return &DataCollectorAgent{
-		messageBus:     messageBus,
-		priceProvider:  priceProvider,
-		newsProvider:   newsProvider,
-		marketDataRepo: marketDataRepo,
-		logger:         logger,
-		metrics:        metrics,
-		subscriptions:  make(map[entities.Symbol]bool),
-		ctx:            ctx,
-		cancel:         cancel,
+		messageBus:      messageBus,
+		priceProvider:   priceProvider,
+		newsProvider:    newsProvider,
+		historyProvider: historyProvider,
+		marketDataRepo:  marketDataRepo,
+		validator:       validator,
+		sentiment:       NewKeywordSentimentAnalyzer(),
+		macroProvider:   NewStaticMacroDataProvider(defaultMacroValues),
+		logger:          logger,
+		metrics:         metrics,
+		config:          config,
+		subscriptions:   make(map[entities.Symbol]int),
+		symbolUpdates:   make(map[entities.Symbol]time.Time),
+		resubscribing:   make(map[entities.Symbol]*resubscribeState),
+		backfillSem:     make(chan struct{}, config.BackfillMaxConcurrency),
+		newsSeen:        newNewsDedup(config.NewsDedupCapacity, config.NewsDedupWindow),
+		ctx:             ctx,
+		cancel:          cancel,
 	}
 }
 
@@ -60,14 +187,22 @@ func (a *DataCollectorAgent) Start(config DataCollectorConfig) error {
 		interfaces.Field{Key: "symbols", Value: len(config.SubscriptionSymbols)},
 	)
 
-	if err := a.subscribeToMarketData(config.SubscriptionSymbols); err != nil {
+	if _, err := a.subscribeToMarketData(a.ctx, config.SubscriptionSymbols); err != nil {
 		return fmt.Errorf("failed to subscribe to market data: %w", err)
 	}
 
+	a.mu.Lock()
+	a.startTime = time.Now()
+	a.mu.Unlock()
+
 	a.wg.Add(3)
 	go a.runNewsCollector(config.NewsUpdateInterval)
 	go a.runHealthMonitor(config.HealthCheckInterval)
 	go a.runMacroDataCollector()
+	if a.config.PriceStalenessWindow > 0 {
+		a.wg.Add(1)
+		go a.runSubscriptionSupervisor()
+	}
 
 	a.metrics.SetGauge("agent_health", 1, map[string]string{
 		"agent_name": "data_collector",
@@ -91,7 +226,9 @@ func (a *DataCollectorAgent) Stop() error {
 			)
 		}
 	}
-	a.subscriptions = make(map[entities.Symbol]bool)
+	a.subscriptions = make(map[entities.Symbol]int)
+	a.symbolUpdates = make(map[entities.Symbol]time.Time)
+	a.resubscribing = make(map[entities.Symbol]*resubscribeState)
 	a.mu.Unlock()
 
 	done := make(chan struct{})
@@ -114,63 +251,471 @@ func (a *DataCollectorAgent) Stop() error {
 	return nil
 }
 
+// AddSymbol takes a reference on the price subscription for symbol. Only the
+// first reference subscribes at the provider; later calls just bump the count.
 func (a *DataCollectorAgent) AddSymbol(symbol entities.Symbol) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.subscriptions[symbol] {
-		return fmt.Errorf("already subscribed to symbol: %s", symbol)
-	}
-
-	if err := a.priceProvider.SubscribeToPrice(a.ctx, symbol, a.handlePriceUpdate); err != nil {
-		return fmt.Errorf("failed to subscribe to price for %s: %w", symbol, err)
+	if a.subscriptions[symbol] == 0 {
+		if err := a.priceProvider.SubscribeToPrice(a.ctx, symbol, a.handlePriceUpdate); err != nil {
+			return fmt.Errorf("failed to subscribe to price for %s: %w", symbol, err)
+		}
+		a.symbolUpdates[symbol] = time.Now()
+		a.backfillOnSubscribe(symbol)
 	}
 
-	a.subscriptions[symbol] = true
+	a.subscriptions[symbol]++
+	a.recordSubscriptionRefCount(symbol)
 
 	a.logger.Info("Added symbol subscription",
 		interfaces.Field{Key: "symbol", Value: symbol},
+		interfaces.Field{Key: "ref_count", Value: a.subscriptions[symbol]},
 	)
 
 	return nil
 }
 
+// RemoveSymbol releases one reference on symbol, unsubscribing at the provider
+// when the last reference is released
 func (a *DataCollectorAgent) RemoveSymbol(symbol entities.Symbol) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if !a.subscriptions[symbol] {
+	count := a.subscriptions[symbol]
+	if count == 0 {
 		return fmt.Errorf("not subscribed to symbol: %s", symbol)
 	}
 
-	if err := a.priceProvider.UnsubscribeFromPrice(a.ctx, symbol); err != nil {
-		return fmt.Errorf("failed to unsubscribe from price for %s: %w", symbol, err)
+	if count == 1 {
+		if err := a.priceProvider.UnsubscribeFromPrice(a.ctx, symbol); err != nil {
+			return fmt.Errorf("failed to unsubscribe from price for %s: %w", symbol, err)
+		}
+		delete(a.subscriptions, symbol)
+		delete(a.symbolUpdates, symbol)
+		delete(a.resubscribing, symbol)
+	} else {
+		a.subscriptions[symbol]--
 	}
-
-	delete(a.subscriptions, symbol)
+	a.recordSubscriptionRefCount(symbol)
 
 	a.logger.Info("Removed symbol subscription",
 		interfaces.Field{Key: "symbol", Value: symbol},
+		interfaces.Field{Key: "ref_count", Value: a.subscriptions[symbol]},
 	)
 
 	return nil
 }
 
-func (a *DataCollectorAgent) subscribeToMarketData(symbols []entities.Symbol) error {
+// SubscriptionRefCount returns how many consumers currently hold symbol
+func (a *DataCollectorAgent) SubscriptionRefCount(symbol entities.Symbol) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.subscriptions[symbol]
+}
+
+func (a *DataCollectorAgent) recordSubscriptionRefCount(symbol entities.Symbol) {
+	a.metrics.SetGauge("symbol_subscription_refs", float64(a.subscriptions[symbol]), map[string]string{
+		"symbol": string(symbol),
+	})
+}
+
+// BackfillMarketData loads historical bars for symbol over [from, to) in chunks of
+// BackfillChunkSize. Bars whose timestamps are already stored are skipped, and the
+// backfill resumes from the latest stored bar, so an interrupted run can simply be
+// repeated. It returns the number of newly stored bars.
+func (a *DataCollectorAgent) BackfillMarketData(ctx context.Context, symbol entities.Symbol, from, to time.Time) (int, error) {
+	if a.historyProvider == nil {
+		return 0, fmt.Errorf("historical data provider is not configured")
+	}
+	if !from.Before(to) {
+		return 0, fmt.Errorf("invalid backfill range: from %s is not before to %s", from, to)
+	}
+
+	select {
+	case a.backfillSem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-a.backfillSem }()
+
+	existing, err := a.marketDataRepo.GetMarketDataHistory(ctx, symbol, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load stored history for %s: %w", symbol, err)
+	}
+
+	stored := make(map[int64]bool, len(existing))
+	start := from
+	for _, bar := range existing {
+		stored[bar.Timestamp.UnixNano()] = true
+		if bar.Timestamp.After(start) {
+			start = bar.Timestamp
+		}
+	}
+
+	if start.After(from) {
+		a.logger.Info("Resuming market data backfill",
+			interfaces.Field{Key: "symbol", Value: symbol},
+			interfaces.Field{Key: "resume_from", Value: start},
+		)
+	}
+
+	count := 0
+	for windowStart := start; windowStart.Before(to); {
+		windowEnd := windowStart.Add(a.config.BackfillChunkSize)
+		if windowEnd.After(to) {
+			windowEnd = to
+		}
+
+		if err := a.waitForHistorySlot(ctx); err != nil {
+			return count, err
+		}
+
+		bars, err := a.historyProvider.GetHistoricalBars(ctx, symbol, windowStart, windowEnd)
+		if err != nil {
+			a.metrics.IncrementCounter("market_data_backfill_errors", map[string]string{
+				"symbol": string(symbol),
+			})
+			return count, fmt.Errorf("backfill of %s interrupted at %s: %w", symbol, windowStart, err)
+		}
+
+		sort.Slice(bars, func(i, j int) bool {
+			return bars[i].Timestamp.Before(bars[j].Timestamp)
+		})
+
+		for _, bar := range bars {
+			key := bar.Timestamp.UnixNano()
+			if stored[key] || bar.Timestamp.Before(from) || !bar.Timestamp.Before(to) {
+				continue
+			}
+
+			if err := a.marketDataRepo.SaveMarketData(ctx, bar); err != nil {
+				return count, fmt.Errorf("failed to store backfilled bar for %s at %s: %w", symbol, bar.Timestamp, err)
+			}
+
+			stored[key] = true
+			count++
+		}
+
+		windowStart = windowEnd
+	}
+
+	a.metrics.IncrementCounter("market_data_backfilled", map[string]string{
+		"symbol": string(symbol),
+	})
+
+	a.logger.Info("Market data backfill completed",
+		interfaces.Field{Key: "symbol", Value: symbol},
+		interfaces.Field{Key: "stored", Value: count},
+	)
+
+	return count, nil
+}
+
+// BackfillAll backfills each symbol over [from, to) on BackfillMaxConcurrency
+// workers. A failed symbol is recorded in the report without stopping the
+// others; only cancellation of ctx is returned as an error. onProgress, if not
+// nil, is called once per finished symbol and never concurrently.
+func (a *DataCollectorAgent) BackfillAll(ctx context.Context, symbols []entities.Symbol, from, to time.Time, onProgress func(BackfillProgress)) (BackfillReport, error) {
+	report := BackfillReport{
+		Stored: make(map[entities.Symbol]int),
+		Failed: make(map[entities.Symbol]error),
+	}
+
+	// Duplicates are dropped so a symbol is never backfilled twice at once
+	unique := make([]entities.Symbol, 0, len(symbols))
+	seen := make(map[entities.Symbol]bool, len(symbols))
 	for _, symbol := range symbols {
-		if err := a.AddSymbol(symbol); err != nil {
-			a.logger.Error("Failed to subscribe to symbol",
+		if !seen[symbol] {
+			seen[symbol] = true
+			unique = append(unique, symbol)
+		}
+	}
+
+	var reportMu sync.Mutex
+	started := make(map[entities.Symbol]bool, len(unique))
+	completed := 0
+
+	queue := make(chan entities.Symbol)
+	var workers sync.WaitGroup
+	for i := 0; i < a.config.BackfillMaxConcurrency && i < len(unique); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for symbol := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				reportMu.Lock()
+				started[symbol] = true
+				reportMu.Unlock()
+
+				stored, err := a.BackfillMarketData(ctx, symbol, from, to)
+				if err != nil {
+					a.logger.Error("Failed to backfill symbol",
+						interfaces.Field{Key: "symbol", Value: symbol},
+						interfaces.Field{Key: "stored", Value: stored},
+						interfaces.Field{Key: "error", Value: err},
+					)
+				}
+
+				reportMu.Lock()
+				report.Stored[symbol] = stored
+				if err != nil {
+					report.Failed[symbol] = err
+				}
+				completed++
+				a.metrics.SetGauge("market_data_backfill_progress", float64(completed)/float64(len(unique)), map[string]string{
+					"agent_name": "data_collector",
+				})
+				if onProgress != nil {
+					onProgress(BackfillProgress{
+						Symbol:    symbol,
+						Stored:    stored,
+						Err:       err,
+						Completed: completed,
+						Total:     len(unique),
+					})
+				}
+				reportMu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, symbol := range unique {
+		select {
+		case queue <- symbol:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	workers.Wait()
+
+	for _, symbol := range unique {
+		if !started[symbol] {
+			report.Pending = append(report.Pending, symbol)
+		}
+	}
+
+	a.logger.Info("Market data backfill of all symbols completed",
+		interfaces.Field{Key: "requested", Value: len(unique)},
+		interfaces.Field{Key: "failed", Value: len(report.Failed)},
+		interfaces.Field{Key: "pending", Value: len(report.Pending)},
+	)
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// BackfillSymbol backfills the lookback of history up to now for symbol, so
+// analytics have data before live updates accumulate
+func (a *DataCollectorAgent) BackfillSymbol(ctx context.Context, symbol entities.Symbol, lookback time.Duration) error {
+	if lookback <= 0 {
+		return fmt.Errorf("backfill lookback must be positive, got %s", lookback)
+	}
+
+	to := time.Now()
+	_, err := a.BackfillMarketData(ctx, symbol, to.Add(-lookback), to)
+	return err
+}
+
+// backfillOnSubscribe starts a background backfill of a newly subscribed symbol
+// when BackfillOnSubscribe is set. A failed backfill is logged and leaves the
+// live subscription in place.
+func (a *DataCollectorAgent) backfillOnSubscribe(symbol entities.Symbol) {
+	if !a.config.BackfillOnSubscribe || a.historyProvider == nil {
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		if err := a.BackfillSymbol(a.ctx, symbol, a.config.BackfillLookback); err != nil {
+			a.logger.Warn("Backfill on subscribe failed, continuing with live data only",
 				interfaces.Field{Key: "symbol", Value: symbol},
 				interfaces.Field{Key: "error", Value: err},
 			)
+		}
+	}()
+}
+
+// waitForHistorySlot spaces historical provider calls by BackfillRequestInterval
+// across all concurrent backfills.
+func (a *DataCollectorAgent) waitForHistorySlot(ctx context.Context) error {
+	if a.config.BackfillRequestInterval <= 0 {
+		return nil
+	}
+
+	a.rateMu.Lock()
+	next := a.lastHistoryCall.Add(a.config.BackfillRequestInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	a.lastHistoryCall = next
+	a.rateMu.Unlock()
+
+	select {
+	case <-time.After(time.Until(next)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// subscribeToMarketData subscribes symbols on a bounded pool of workers. A failed
+// or timed-out symbol is recorded in the report without aborting the batch; only
+// cancellation of ctx is returned as an error.
+func (a *DataCollectorAgent) subscribeToMarketData(ctx context.Context, symbols []entities.Symbol) (SubscriptionReport, error) {
+	report := SubscriptionReport{Failed: make(map[entities.Symbol]error)}
+	var reportMu sync.Mutex
+
+	queue := make(chan entities.Symbol)
+	var workers sync.WaitGroup
+	for i := 0; i < a.config.SubscribeConcurrency && i < len(symbols); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for symbol := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				err := a.subscribeWithTimeout(ctx, symbol)
+
+				reportMu.Lock()
+				if err != nil {
+					report.Failed[symbol] = err
+				} else {
+					report.Succeeded++
+				}
+				reportMu.Unlock()
+
+				if err != nil {
+					a.logger.Error("Failed to subscribe to symbol",
+						interfaces.Field{Key: "symbol", Value: symbol},
+						interfaces.Field{Key: "error", Value: err},
+					)
+				}
+			}
+		}()
+	}
+
+	// Duplicates are dropped so two workers never subscribe the same symbol at once
+	queued := make(map[entities.Symbol]bool, len(symbols))
+feed:
+	for _, symbol := range symbols {
+		if queued[symbol] {
 			continue
 		}
+		queued[symbol] = true
+
+		select {
+		case queue <- symbol:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	workers.Wait()
+
+	a.metrics.SetGauge("symbol_subscriptions_failed", float64(len(report.Failed)), map[string]string{
+		"agent_name": "data_collector",
+	})
+	a.logger.Info("Market data subscription completed",
+		interfaces.Field{Key: "requested", Value: len(symbols)},
+		interfaces.Field{Key: "succeeded", Value: report.Succeeded},
+		interfaces.Field{Key: "failed", Value: len(report.Failed)},
+	)
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// subscribeWithTimeout subscribes one symbol, giving up after SubscribeTimeout
+// even if the provider ignores its context. A subscription that completes after
+// being abandoned is undone so the provider is not left streaming an untracked symbol.
+func (a *DataCollectorAgent) subscribeWithTimeout(ctx context.Context, symbol entities.Symbol) error {
+	a.mu.Lock()
+	if a.subscriptions[symbol] > 0 {
+		a.subscriptions[symbol]++
+		a.recordSubscriptionRefCount(symbol)
+		a.mu.Unlock()
+		return nil
+	}
+	a.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.SubscribeTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- a.priceProvider.SubscribeToPrice(ctx, symbol, a.handlePriceUpdate)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to price for %s: %w", symbol, err)
+		}
+	case <-ctx.Done():
+		go func() {
+			if err := <-done; err == nil {
+				a.priceProvider.UnsubscribeFromPrice(context.Background(), symbol)
+			}
+		}()
+		a.metrics.IncrementCounter("symbol_subscription_timeouts", map[string]string{
+			"symbol": string(symbol),
+		})
+		return fmt.Errorf("subscribing to %s: %w", symbol, ctx.Err())
 	}
+
+	a.mu.Lock()
+	a.subscriptions[symbol]++
+	a.symbolUpdates[symbol] = time.Now()
+	a.recordSubscriptionRefCount(symbol)
+	a.mu.Unlock()
+
+	a.backfillOnSubscribe(symbol)
 	return nil
 }
 
+// handlePriceUpdate validates a price update, then saves and republishes it
 func (a *DataCollectorAgent) handlePriceUpdate(marketData *entities.MarketData) {
 	start := time.Now()
+
+	a.mu.Lock()
+	if a.subscriptions[marketData.Symbol] > 0 {
+		a.symbolUpdates[marketData.Symbol] = start
+		delete(a.resubscribing, marketData.Symbol)
+	}
+	a.mu.Unlock()
+
+	// The feed is alive even when it sends garbage, so liveness is recorded
+	// before the update is validated
+	if a.validator != nil {
+		if err := a.validator.ValidateMarketData(marketData); err != nil {
+			reason := "invalid"
+			var rejection *entities.MarketDataRejection
+			if errors.As(err, &rejection) {
+				reason = rejection.Reason
+			}
+			a.metrics.IncrementCounter("market_data_rejected", map[string]string{
+				"reason": reason,
+			})
+			a.logger.Warn("Dropped invalid market data",
+				interfaces.Field{Key: "symbol", Value: marketData.Symbol},
+				interfaces.Field{Key: "reason", Value: reason},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return
+		}
+	}
 	defer func() {
 		a.metrics.RecordDuration("market_data_processing_duration", time.Since(start).Seconds(), map[string]string{
 			"symbol": string(marketData.Symbol),
@@ -205,6 +750,10 @@ func (a *DataCollectorAgent) handlePriceUpdate(marketData *entities.MarketData)
 		return
 	}
 
+	a.mu.Lock()
+	a.lastPriceUpdate = time.Now()
+	a.mu.Unlock()
+
 	a.metrics.IncrementCounter("market_data_processed", map[string]string{
 		"symbol": string(marketData.Symbol),
 	})
@@ -276,8 +825,32 @@ func (a *DataCollectorAgent) collectLatestNews() {
 	})
 }
 
+// SetSentimentAnalyzer replaces the analyzer used to score incoming news; nil
+// disables enrichment
+func (a *DataCollectorAgent) SetSentimentAnalyzer(analyzer interfaces.SentimentAnalyzer) {
+	a.sentiment = analyzer
+}
+
+// handleNewsUpdate saves and publishes article unless it was already seen
+// within NewsDedupWindow. An article that fails to save or publish is
+// forgotten so a later delivery can retry it.
 func (a *DataCollectorAgent) handleNewsUpdate(article *entities.NewsArticle) {
+	key := articleKey(article)
+	if !a.newsSeen.Add(key, time.Now()) {
+		a.metrics.IncrementCounter("news_duplicates_skipped", map[string]string{
+			"source": article.Source,
+		})
+		a.logger.Debug("Skipped duplicate news article",
+			interfaces.Field{Key: "article_id", Value: article.ID},
+			interfaces.Field{Key: "source", Value: article.Source},
+		)
+		return
+	}
+
+	a.enrichSentiment(article)
+
 	if err := a.marketDataRepo.SaveNewsArticle(a.ctx, article); err != nil {
+		a.newsSeen.Remove(key)
 		a.logger.Error("Failed to save news article",
 			interfaces.Field{Key: "article_id", Value: article.ID},
 			interfaces.Field{Key: "error", Value: err},
@@ -289,6 +862,7 @@ func (a *DataCollectorAgent) handleNewsUpdate(article *entities.NewsArticle) {
 	}
 
 	if err := a.messageBus.Publish(a.ctx, "raw.news.article", article); err != nil {
+		a.newsSeen.Remove(key)
 		a.logger.Error("Failed to publish news article",
 			interfaces.Field{Key: "article_id", Value: article.ID},
 			interfaces.Field{Key: "error", Value: err},
@@ -310,6 +884,45 @@ func (a *DataCollectorAgent) handleNewsUpdate(article *entities.NewsArticle) {
 	)
 }
 
+// enrichSentiment attaches sentiment and relevance scores to article. Scores
+// outside the validator's ranges are clamped, and NaN scores are dropped.
+func (a *DataCollectorAgent) enrichSentiment(article *entities.NewsArticle) {
+	if a.sentiment == nil {
+		return
+	}
+
+	score, err := a.sentiment.Analyze(a.ctx, article)
+	if err != nil {
+		a.logger.Warn("Sentiment analysis failed, publishing article without scores",
+			interfaces.Field{Key: "article_id", Value: article.ID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		a.metrics.IncrementCounter("news_sentiment_errors", map[string]string{
+			"source": article.Source,
+		})
+		return
+	}
+
+	clamped, ok := clampSentimentScore(score)
+	if !ok {
+		a.logger.Warn("Rejected non-numeric sentiment score",
+			interfaces.Field{Key: "article_id", Value: article.ID},
+		)
+		a.metrics.IncrementCounter("news_sentiment_rejected", map[string]string{
+			"source": article.Source,
+		})
+		return
+	}
+	if clamped != score {
+		a.metrics.IncrementCounter("news_sentiment_clamped", map[string]string{
+			"source": article.Source,
+		})
+	}
+
+	article.Sentiment = clamped.Sentiment
+	article.Relevance = clamped.Relevance
+}
+
 func (a *DataCollectorAgent) runMacroDataCollector() {
 	defer a.wg.Done()
 
@@ -329,20 +942,34 @@ func (a *DataCollectorAgent) runMacroDataCollector() {
 	}
 }
 
+// SetMacroDataProvider replaces the provider macro indicator values are
+// fetched from
+func (a *DataCollectorAgent) SetMacroDataProvider(provider interfaces.MacroDataProvider) {
+	a.macroProvider = provider
+}
+
+// collectMacroData fetches, saves and publishes every configured indicator. An
+// indicator that cannot be fetched is skipped for this run.
 func (a *DataCollectorAgent) collectMacroData() {
-	indicators := []MacroIndicatorSource{
-		{Name: "GDP", Country: "US", Impact: "HIGH"},
-		{Name: "INFLATION_RATE", Country: "US", Impact: "HIGH"},
-		{Name: "UNEMPLOYMENT_RATE", Country: "US", Impact: "MEDIUM"},
-		{Name: "INTEREST_RATE", Country: "US", Impact: "HIGH"},
-	}
+	for _, source := range a.config.MacroIndicators {
+		value, err := a.macroProvider.GetIndicatorValue(a.ctx, source.Name, source.Country)
+		if err != nil {
+			a.logger.Error("Failed to fetch macro indicator",
+				interfaces.Field{Key: "indicator", Value: source.Name},
+				interfaces.Field{Key: "country", Value: source.Country},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			a.metrics.IncrementCounter("macro_data_fetch_errors", map[string]string{
+				"indicator": source.Name,
+			})
+			continue
+		}
 
-	for _, source := range indicators {
 		indicator := &entities.MacroIndicator{
 			Name:      source.Name,
-			Value:     a.fetchMacroValue(source.Name),
+			Value:     value,
 			Country:   source.Country,
-			Period:    "MONTHLY",
+			Period:    source.Period,
 			Timestamp: time.Now(),
 			Impact:    source.Impact,
 		}
@@ -390,51 +1017,91 @@ func (a *DataCollectorAgent) runHealthMonitor(interval time.Duration) {
 	}
 }
 
-func (a *DataCollectorAgent) publishHealthStatus() {
+// SetHealthRegistry registers the collector's health check with registry and
+// makes the periodic system.health publication report the registry's aggregate
+func (a *DataCollectorAgent) SetHealthRegistry(registry interfaces.HealthAggregator) {
+	registry.Register("data_collector", a.checkHealth)
+
+	a.mu.Lock()
+	a.health = registry
+	a.mu.Unlock()
+}
+
+// checkHealth is degraded while configured symbols have no active subscription
+func (a *DataCollectorAgent) checkHealth(ctx context.Context) (interfaces.HealthStatus, string) {
 	a.mu.RLock()
 	subscriptionCount := len(a.subscriptions)
 	a.mu.RUnlock()
 
-	healthStatus := HealthStatusMessage{
-		ComponentName: "data_collector",
-		Status:        "healthy",
-		Metrics: map[string]interface{}{
-			"active_subscriptions": subscriptionCount,
-			"uptime_seconds":       time.Since(time.Now()).Seconds(),
-		},
-		Timestamp: time.Now(),
+	detail := fmt.Sprintf("%d active subscriptions", subscriptionCount)
+	if subscriptionCount == 0 && len(a.config.SubscriptionSymbols) > 0 {
+		return interfaces.HealthStatusDegraded, detail
 	}
+	return interfaces.HealthStatusHealthy, detail
+}
 
-	if err := a.messageBus.Publish(a.ctx, "system.health", healthStatus); err != nil {
+func (a *DataCollectorAgent) publishHealthStatus() {
+	a.mu.RLock()
+	registry := a.health
+	a.mu.RUnlock()
+
+	var report interfaces.HealthReport
+	if registry != nil {
+		report = registry.Check(a.ctx)
+	} else {
+		status, detail := a.checkHealth(a.ctx)
+		report = interfaces.HealthReport{
+			Status:    status,
+			Checks:    []interfaces.HealthCheckResult{{Name: "data_collector", Status: status, Detail: detail}},
+			Timestamp: time.Now(),
+		}
+	}
+
+	for i := range report.Checks {
+		if report.Checks[i].Name == "data_collector" {
+			report.Checks[i].Metrics = a.healthMetrics()
+		}
+	}
+
+	if err := a.messageBus.Publish(a.ctx, "system.health", report); err != nil {
 		a.logger.Warn("Failed to publish health status",
 			interfaces.Field{Key: "error", Value: err},
 		)
 	}
 }
 
-func (a *DataCollectorAgent) fetchMacroValue(indicator string) float64 {
-	values := map[string]float64{
-		"GDP":                2.1,
-		"INFLATION_RATE":     3.2,
-		"UNEMPLOYMENT_RATE":  4.1,
-		"INTEREST_RATE":      5.25,
+// healthMetrics are the figures attached to the collector's system.health check
+func (a *DataCollectorAgent) healthMetrics() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var uptime float64
+	if !a.startTime.IsZero() {
+		uptime = time.Since(a.startTime).Seconds()
 	}
-	
-	if value, exists := values[indicator]; exists {
-		return value
+	healthMetrics := map[string]interface{}{
+		"active_subscriptions": len(a.subscriptions),
+		"uptime_seconds":       uptime,
+		"goroutines":           runtime.NumGoroutine(),
+	}
+	if !a.lastPriceUpdate.IsZero() {
+		healthMetrics["last_price_update"] = a.lastPriceUpdate
 	}
-	return 0.0
+	return healthMetrics
 }
 
+// MacroIndicatorSource configures one macroeconomic indicator to collect
 type MacroIndicatorSource struct {
-	Name    string
-	Country string
-	Impact  string
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Period  string `json:"period"`
+	Impact  string `json:"impact"`
 }
 
-type HealthStatusMessage struct {
-	ComponentName string                 `json:"component_name"`
-	Status        string                 `json:"status"`
-	Metrics       map[string]interface{} `json:"metrics"`
-	Timestamp     time.Time              `json:"timestamp"`
-}
\ No newline at end of file
+// DefaultMacroIndicators are collected when the config lists none
+var DefaultMacroIndicators = []MacroIndicatorSource{
+	{Name: "GDP", Country: "US", Period: "MONTHLY", Impact: "HIGH"},
+	{Name: "INFLATION_RATE", Country: "US", Period: "MONTHLY", Impact: "HIGH"},
+	{Name: "UNEMPLOYMENT_RATE", Country: "US", Period: "MONTHLY", Impact: "MEDIUM"},
+	{Name: "INTEREST_RATE", Country: "US", Period: "MONTHLY", Impact: "HIGH"},
+}
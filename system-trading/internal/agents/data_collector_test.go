@@ -0,0 +1,976 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/metrics"
+	"github.com/system-trading/core/internal/infrastructure/validation"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// fakeMarketDataRepo is an in-memory MarketDataRepository for data collector tests
+type fakeMarketDataRepo struct {
+	mu       sync.Mutex
+	bars     map[entities.Symbol][]*entities.MarketData
+	articles []*entities.NewsArticle
+	macro    []*entities.MacroIndicator
+	saves    int
+}
+
+func newFakeMarketDataRepo() *fakeMarketDataRepo {
+	return &fakeMarketDataRepo{bars: make(map[entities.Symbol][]*entities.MarketData)}
+}
+
+func (r *fakeMarketDataRepo) SaveMarketData(ctx context.Context, data *entities.MarketData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bars[data.Symbol] = append(r.bars[data.Symbol], data)
+	r.saves++
+	return nil
+}
+
+func (r *fakeMarketDataRepo) GetLatestMarketData(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bars := r.bars[symbol]
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no market data for %s", symbol)
+	}
+	return bars[len(bars)-1], nil
+}
+
+func (r *fakeMarketDataRepo) GetMarketDataHistory(ctx context.Context, symbol entities.Symbol, from, to time.Time) ([]*entities.MarketData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*entities.MarketData
+	for _, bar := range r.bars[symbol] {
+		if !bar.Timestamp.Before(from) && bar.Timestamp.Before(to) {
+			result = append(result, bar)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeMarketDataRepo) SaveNewsArticle(ctx context.Context, article *entities.NewsArticle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.articles = append(r.articles, article)
+	return nil
+}
+
+func (r *fakeMarketDataRepo) GetNewsArticles(ctx context.Context, symbols []entities.Symbol, from time.Time) ([]*entities.NewsArticle, error) {
+	return nil, nil
+}
+
+func (r *fakeMarketDataRepo) SaveMacroIndicator(ctx context.Context, indicator *entities.MacroIndicator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.macro = append(r.macro, indicator)
+	return nil
+}
+
+func (r *fakeMarketDataRepo) GetMacroIndicators(ctx context.Context, names []string, from time.Time) ([]*entities.MacroIndicator, error) {
+	return nil, nil
+}
+
+func (r *fakeMarketDataRepo) saveCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saves
+}
+
+// fakeHistoryProvider serves hourly bars and can be told to fail after N calls
+// or always for some symbols
+type fakeHistoryProvider struct {
+	mu          sync.Mutex
+	calls       []time.Time
+	failAfter   int
+	failSymbols map[entities.Symbol]bool
+}
+
+func (p *fakeHistoryProvider) GetHistoricalBars(ctx context.Context, symbol entities.Symbol, from, to time.Time) ([]*entities.MarketData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failAfter > 0 && len(p.calls) >= p.failAfter {
+		return nil, fmt.Errorf("provider unavailable")
+	}
+	if p.failSymbols[symbol] {
+		return nil, fmt.Errorf("no history for %s", symbol)
+	}
+	p.calls = append(p.calls, from)
+
+	var bars []*entities.MarketData
+	for ts := from.Truncate(time.Hour); ts.Before(to); ts = ts.Add(time.Hour) {
+		if ts.Before(from) {
+			continue
+		}
+		bars = append(bars, &entities.MarketData{
+			Symbol:    symbol,
+			Price:     100,
+			Open:      100,
+			High:      101,
+			Low:       99,
+			Bid:       99.9,
+			Ask:       100.1,
+			Timestamp: ts,
+		})
+	}
+	return bars, nil
+}
+
+// fakePriceProvider counts provider-level subscribe and unsubscribe calls
+type fakePriceProvider struct {
+	mu           sync.Mutex
+	subscribed   map[entities.Symbol]bool
+	subscribes   int
+	unsubscribes int
+}
+
+func newFakePriceProvider() *fakePriceProvider {
+	return &fakePriceProvider{subscribed: make(map[entities.Symbol]bool)}
+}
+
+func (f *fakePriceProvider) GetRealTimePrice(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error) {
+	return nil, fmt.Errorf("no price for %s", symbol)
+}
+
+func (f *fakePriceProvider) SubscribeToPrice(ctx context.Context, symbol entities.Symbol, callback func(*entities.MarketData)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribed[symbol] = true
+	f.subscribes++
+	return nil
+}
+
+func (f *fakePriceProvider) UnsubscribeFromPrice(ctx context.Context, symbol entities.Symbol) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subscribed, symbol)
+	f.unsubscribes++
+	return nil
+}
+
+func (f *fakePriceProvider) subscribeCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscribes
+}
+
+func (f *fakePriceProvider) isSubscribed(symbol entities.Symbol) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscribed[symbol]
+}
+
+func setupTestDataCollector(t *testing.T, prices ifs.PriceProvider, history *fakeHistoryProvider, repo *fakeMarketDataRepo) *DataCollectorAgent {
+	t.Helper()
+
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{
+		Level:  "error",
+		Format: "json",
+		Output: "stdout",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test logger: %v", err)
+	}
+
+	testMetrics := metrics.NewPrometheusMetricsWithRegistry("test-data-collector", prometheus.NewRegistry())
+
+	return NewDataCollectorAgent(
+		messagebus.NewMockMessageBus(),
+		prices,
+		nil,
+		history,
+		repo,
+		validation.NewValidator(),
+		testLogger,
+		testMetrics,
+		DataCollectorConfig{
+			BackfillChunkSize:      24 * time.Hour,
+			BackfillMaxConcurrency: 2,
+		},
+	)
+}
+
+func TestDataCollector_BackfillIdempotent(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	history := &fakeHistoryProvider{}
+	agent := setupTestDataCollector(t, nil, history, repo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	stored, err := agent.BackfillMarketData(ctx, "AAPL", from, to)
+	if err != nil {
+		t.Fatalf("First backfill failed: %v", err)
+	}
+	if stored != 72 {
+		t.Fatalf("Expected 72 bars stored on first run, got %d", stored)
+	}
+
+	stored, err = agent.BackfillMarketData(ctx, "AAPL", from, to)
+	if err != nil {
+		t.Fatalf("Second backfill failed: %v", err)
+	}
+	if stored != 0 {
+		t.Errorf("Expected second backfill to store nothing, stored %d", stored)
+	}
+	if repo.saveCount() != 72 {
+		t.Errorf("Expected 72 total saves, got %d", repo.saveCount())
+	}
+}
+
+func TestDataCollector_BackfillResumesAfterInterruption(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	history := &fakeHistoryProvider{failAfter: 2}
+	agent := setupTestDataCollector(t, nil, history, repo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	stored, err := agent.BackfillMarketData(ctx, "AAPL", from, to)
+	if err == nil {
+		t.Fatal("Expected interrupted backfill to return an error")
+	}
+	if stored != 48 {
+		t.Fatalf("Expected 48 bars stored before interruption, got %d", stored)
+	}
+
+	history.mu.Lock()
+	history.failAfter = 0
+	history.calls = nil
+	history.mu.Unlock()
+
+	stored, err = agent.BackfillMarketData(ctx, "AAPL", from, to)
+	if err != nil {
+		t.Fatalf("Resumed backfill failed: %v", err)
+	}
+	if stored != 24 {
+		t.Errorf("Expected resumed backfill to store the remaining 24 bars, got %d", stored)
+	}
+
+	lastStored := from.Add(47 * time.Hour)
+	history.mu.Lock()
+	firstCall := history.calls[0]
+	history.mu.Unlock()
+	if !firstCall.Equal(lastStored) {
+		t.Errorf("Expected resume from last stored bar %s, got %s", lastStored, firstCall)
+	}
+
+	if repo.saveCount() != 72 {
+		t.Errorf("Expected 72 total saves without duplicates, got %d", repo.saveCount())
+	}
+}
+
+func TestDataCollector_BackfillAllContinuesPastFailures(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	history := &fakeHistoryProvider{failSymbols: map[entities.Symbol]bool{"MSFT": true}}
+	agent := setupTestDataCollector(t, nil, history, repo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+	symbols := []entities.Symbol{"AAPL", "MSFT", "GOOG", "TSLA", "AAPL"}
+
+	var progress []BackfillProgress
+	report, err := agent.BackfillAll(ctx, symbols, from, to, func(p BackfillProgress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("BackfillAll failed: %v", err)
+	}
+
+	if len(progress) != 4 {
+		t.Fatalf("Expected one progress report per distinct symbol, got %d", len(progress))
+	}
+	for i, p := range progress {
+		if p.Completed != i+1 || p.Total != 4 {
+			t.Errorf("Expected progress %d/4, got %d/%d", i+1, p.Completed, p.Total)
+		}
+		if (p.Err != nil) != (p.Symbol == "MSFT") {
+			t.Errorf("Unexpected progress error for %s: %v", p.Symbol, p.Err)
+		}
+	}
+
+	if len(report.Failed) != 1 || report.Failed["MSFT"] == nil {
+		t.Errorf("Expected only MSFT to fail, got %v", report.Failed)
+	}
+	for _, symbol := range []entities.Symbol{"AAPL", "GOOG", "TSLA"} {
+		if report.Stored[symbol] != 72 {
+			t.Errorf("Expected 72 bars stored for %s, got %d", symbol, report.Stored[symbol])
+		}
+	}
+	if len(report.Pending) != 0 {
+		t.Errorf("Expected nothing pending, got %v", report.Pending)
+	}
+
+	// Retrying the failed symbol once the provider recovers completes the run
+	history.mu.Lock()
+	history.failSymbols = nil
+	history.mu.Unlock()
+
+	report, err = agent.BackfillAll(ctx, []entities.Symbol{"MSFT"}, from, to, nil)
+	if err != nil || len(report.Failed) != 0 || report.Stored["MSFT"] != 72 {
+		t.Errorf("Expected the retry to store 72 MSFT bars, got %+v (err %v)", report, err)
+	}
+	if repo.saveCount() != 4*72 {
+		t.Errorf("Expected %d total saves, got %d", 4*72, repo.saveCount())
+	}
+}
+
+func TestDataCollector_BackfillAllRespectsCancellation(t *testing.T) {
+	agent := setupTestDataCollector(t, nil, &fakeHistoryProvider{}, newFakeMarketDataRepo())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	symbols := []entities.Symbol{"AAPL", "MSFT", "GOOG"}
+	report, err := agent.BackfillAll(ctx, symbols, from, from.Add(24*time.Hour), nil)
+	if err == nil {
+		t.Fatal("Expected a cancelled backfill to return an error")
+	}
+	if len(report.Pending)+len(report.Failed) != len(symbols) {
+		t.Errorf("Expected every symbol to be pending or failed for a resume, got %+v", report)
+	}
+}
+
+func TestDataCollectorAgent_SymbolSubscriptionRefCounting(t *testing.T) {
+	prices := newFakePriceProvider()
+	agent := setupTestDataCollector(t, prices, &fakeHistoryProvider{}, newFakeMarketDataRepo())
+
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("First AddSymbol failed: %v", err)
+	}
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("Second AddSymbol should share the subscription, got: %v", err)
+	}
+	if got := agent.SubscriptionRefCount("AAPL"); got != 2 {
+		t.Fatalf("Expected ref count 2, got %d", got)
+	}
+	if prices.subscribes != 1 {
+		t.Errorf("Expected a single provider subscription, got %d", prices.subscribes)
+	}
+
+	if err := agent.RemoveSymbol("AAPL"); err != nil {
+		t.Fatalf("First RemoveSymbol failed: %v", err)
+	}
+	if got := agent.SubscriptionRefCount("AAPL"); got != 1 {
+		t.Errorf("Expected ref count 1 after first removal, got %d", got)
+	}
+	if !prices.isSubscribed("AAPL") {
+		t.Error("Expected provider subscription to remain while a reference is held")
+	}
+
+	if err := agent.RemoveSymbol("AAPL"); err != nil {
+		t.Fatalf("Second RemoveSymbol failed: %v", err)
+	}
+	if got := agent.SubscriptionRefCount("AAPL"); got != 0 {
+		t.Errorf("Expected ref count 0 after last removal, got %d", got)
+	}
+	if prices.isSubscribed("AAPL") || prices.unsubscribes != 1 {
+		t.Errorf("Expected exactly one provider unsubscribe, got %d", prices.unsubscribes)
+	}
+
+	if err := agent.RemoveSymbol("AAPL"); err == nil {
+		t.Error("Expected error removing a symbol with no references")
+	}
+}
+
+// fakeSentimentAnalyzer returns a fixed score per article ID
+type fakeSentimentAnalyzer struct {
+	scores map[string]ifs.SentimentScore
+	err    error
+}
+
+func (f *fakeSentimentAnalyzer) Analyze(ctx context.Context, article *entities.NewsArticle) (ifs.SentimentScore, error) {
+	if f.err != nil {
+		return ifs.SentimentScore{}, f.err
+	}
+	return f.scores[article.ID], nil
+}
+
+func TestDataCollectorAgent_NewsSentimentEnrichment(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, repo)
+	agent.SetSentimentAnalyzer(&fakeSentimentAnalyzer{scores: map[string]ifs.SentimentScore{
+		"in-range":     {Sentiment: -0.4, Relevance: 0.7},
+		"out-of-range": {Sentiment: 3.5, Relevance: -2},
+		"nan":          {Sentiment: math.NaN(), Relevance: 0.5},
+	}})
+	validator := validation.NewValidator()
+
+	tests := []struct {
+		id            string
+		wantSentiment float64
+		wantRelevance float64
+	}{
+		{"in-range", -0.4, 0.7},
+		{"out-of-range", 1, 0},
+		{"nan", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			article := &entities.NewsArticle{
+				ID:      tt.id,
+				Title:   "Quarterly results",
+				Content: "Company reports quarterly results",
+				Source:  "wire",
+				Symbols: []entities.Symbol{"AAPL"},
+			}
+			agent.handleNewsUpdate(article)
+
+			if article.Sentiment != tt.wantSentiment || article.Relevance != tt.wantRelevance {
+				t.Errorf("Expected sentiment %.2f relevance %.2f, got %.2f and %.2f",
+					tt.wantSentiment, tt.wantRelevance, article.Sentiment, article.Relevance)
+			}
+			if err := validator.ValidateNewsArticle(article); err != nil {
+				t.Errorf("Enriched article failed validation: %v", err)
+			}
+		})
+	}
+
+	bus := agent.messageBus.(*messagebus.MockMessageBus)
+	published := bus.GetMessagesByTopic("raw.news.article")
+	if len(published) != len(tests) {
+		t.Fatalf("Expected %d published articles, got %d", len(tests), len(published))
+	}
+	if first := published[0].Message.(*entities.NewsArticle); first.Sentiment != -0.4 {
+		t.Errorf("Expected published article to carry its score, got %.2f", first.Sentiment)
+	}
+}
+
+func TestKeywordSentimentAnalyzer(t *testing.T) {
+	analyzer := NewKeywordSentimentAnalyzer()
+
+	tests := []struct {
+		name          string
+		article       entities.NewsArticle
+		wantSentiment float64
+		wantRelevance float64
+	}{
+		{
+			name: "positive headline symbol",
+			article: entities.NewsArticle{
+				Title:   "AAPL shares surge on record profit",
+				Symbols: []entities.Symbol{"AAPL"},
+			},
+			wantSentiment: 1,
+			wantRelevance: 1,
+		},
+		{
+			name: "mixed with body-only symbol",
+			article: entities.NewsArticle{
+				Title:   "Chipmakers decline",
+				Content: "MSFT posts strong growth despite a lawsuit",
+				Symbols: []entities.Symbol{"MSFT", "TSLA"},
+			},
+			wantSentiment: 0,
+			wantRelevance: 0.25,
+		},
+		{
+			name:    "neutral without symbols",
+			article: entities.NewsArticle{Title: "Markets open"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := analyzer.Analyze(context.Background(), &tt.article)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if score.Sentiment != tt.wantSentiment || score.Relevance != tt.wantRelevance {
+				t.Errorf("Expected %.2f/%.2f, got %.2f/%.2f",
+					tt.wantSentiment, tt.wantRelevance, score.Sentiment, score.Relevance)
+			}
+		})
+	}
+}
+
+// hangingPriceProvider never returns from SubscribeToPrice for the listed
+// symbols until release is closed, ignoring its context
+type hangingPriceProvider struct {
+	*fakePriceProvider
+	hang    map[entities.Symbol]bool
+	release chan struct{}
+}
+
+func (h *hangingPriceProvider) SubscribeToPrice(ctx context.Context, symbol entities.Symbol, callback func(*entities.MarketData)) error {
+	if h.hang[symbol] {
+		<-h.release
+	}
+	return h.fakePriceProvider.SubscribeToPrice(ctx, symbol, callback)
+}
+
+func TestDataCollector_ConcurrentSubscribeTimesOutHungSymbols(t *testing.T) {
+	prices := &hangingPriceProvider{
+		fakePriceProvider: newFakePriceProvider(),
+		hang:              map[entities.Symbol]bool{"HUNG1": true, "HUNG2": true},
+		release:           make(chan struct{}),
+	}
+	agent := setupTestDataCollector(t, prices, nil, nil)
+	agent.config.SubscribeConcurrency = 4
+	agent.config.SubscribeTimeout = 50 * time.Millisecond
+
+	symbols := []entities.Symbol{"HUNG1", "HUNG2"}
+	for i := 0; i < 40; i++ {
+		symbols = append(symbols, entities.Symbol(fmt.Sprintf("SYM%02d", i)))
+	}
+
+	start := time.Now()
+	report, err := agent.subscribeToMarketData(context.Background(), symbols)
+	if err != nil {
+		t.Fatalf("subscribeToMarketData failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Hung symbols held up the batch for %v", elapsed)
+	}
+
+	if report.Succeeded != 40 {
+		t.Errorf("Expected 40 successful subscriptions, got %d", report.Succeeded)
+	}
+	if len(report.Failed) != 2 {
+		t.Fatalf("Expected the 2 hung symbols to fail, got %v", report.Failed)
+	}
+	for symbol := range prices.hang {
+		if !errors.Is(report.Failed[symbol], context.DeadlineExceeded) {
+			t.Errorf("Expected %s to time out, got %v", symbol, report.Failed[symbol])
+		}
+		if agent.SubscriptionRefCount(symbol) != 0 {
+			t.Errorf("Expected no subscription recorded for %s", symbol)
+		}
+	}
+	if agent.SubscriptionRefCount("SYM07") != 1 {
+		t.Errorf("Expected SYM07 to be subscribed once, got %d", agent.SubscriptionRefCount("SYM07"))
+	}
+
+	// When the provider finally answers, the abandoned subscriptions are undone
+	close(prices.release)
+	deadline := time.Now().Add(time.Second)
+	for prices.isSubscribed("HUNG1") || prices.isSubscribed("HUNG2") || prices.subscribeCount() < 42 {
+		if time.Now().After(deadline) {
+			t.Fatal("Abandoned subscriptions were not cleaned up")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDataCollector_SubscribeRespectsCancellation(t *testing.T) {
+	prices := &hangingPriceProvider{
+		fakePriceProvider: newFakePriceProvider(),
+		hang:              map[entities.Symbol]bool{"HUNG1": true},
+		release:           make(chan struct{}),
+	}
+	defer close(prices.release)
+	agent := setupTestDataCollector(t, prices, nil, nil)
+	agent.config.SubscribeConcurrency = 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := agent.subscribeToMarketData(ctx, []entities.Symbol{"HUNG1", "AAPL", "MSFT"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected cancellation error, got %v", err)
+	}
+	if report.Succeeded != 0 || len(report.Failed) != 1 {
+		t.Errorf("Expected only the in-flight symbol to be attempted, got %+v", report)
+	}
+}
+
+// idleNewsProvider has no news, for tests that start the collector
+type idleNewsProvider struct{}
+
+func (idleNewsProvider) GetLatestNews(ctx context.Context, symbols []entities.Symbol) ([]*entities.NewsArticle, error) {
+	return nil, nil
+}
+
+func (idleNewsProvider) SubscribeToNews(ctx context.Context, callback func(*entities.NewsArticle)) error {
+	return nil
+}
+
+func TestDataCollectorAgent_HealthReportsUptime(t *testing.T) {
+	agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, newFakeMarketDataRepo())
+	agent.newsProvider = idleNewsProvider{}
+	bus := agent.messageBus.(*messagebus.MockMessageBus)
+
+	started := time.Now()
+	if err := agent.Start(DataCollectorConfig{NewsUpdateInterval: time.Hour, HealthCheckInterval: time.Hour}); err != nil {
+		t.Fatalf("Failed to start data collector: %v", err)
+	}
+	defer agent.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	agent.handlePriceUpdate(validQuote("AAPL", 150))
+	agent.publishHealthStatus()
+	elapsed := time.Since(started).Seconds()
+
+	messages := bus.GetMessagesByTopic("system.health")
+	if len(messages) != 1 {
+		t.Fatalf("Expected one health message, got %d", len(messages))
+	}
+	report := messages[0].Message.(ifs.HealthReport)
+	if len(report.Checks) != 1 || report.Checks[0].Metrics == nil {
+		t.Fatalf("Expected data_collector metrics in the report, got %+v", report.Checks)
+	}
+	healthMetrics := report.Checks[0].Metrics
+
+	uptime, _ := healthMetrics["uptime_seconds"].(float64)
+	if uptime < 0.05 || uptime > elapsed {
+		t.Errorf("Expected uptime between 0.05s and %.3fs, got %v", elapsed, healthMetrics["uptime_seconds"])
+	}
+	if goroutines, _ := healthMetrics["goroutines"].(int); goroutines <= 0 {
+		t.Errorf("Expected a positive goroutine count, got %v", healthMetrics["goroutines"])
+	}
+	if _, ok := healthMetrics["last_price_update"].(time.Time); !ok {
+		t.Errorf("Expected last_price_update after a price update, got %v", healthMetrics["last_price_update"])
+	}
+}
+
+func TestDataCollectorAgent_SkipsDuplicateNews(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, repo)
+	bus := agent.messageBus.(*messagebus.MockMessageBus)
+
+	deliver := func() {
+		agent.handleNewsUpdate(&entities.NewsArticle{
+			ID:      "article-1",
+			Title:   "Quarterly results",
+			Content: "Company reports quarterly results",
+			Source:  "wire",
+			Symbols: []entities.Symbol{"AAPL"},
+		})
+	}
+	deliver()
+	deliver()
+
+	if published := bus.GetMessagesByTopic("raw.news.article"); len(published) != 1 {
+		t.Errorf("Expected one publish for a repeated article, got %d", len(published))
+	}
+	repo.mu.Lock()
+	saved := len(repo.articles)
+	repo.mu.Unlock()
+	if saved != 1 {
+		t.Errorf("Expected one save for a repeated article, got %d", saved)
+	}
+}
+
+func TestNewsDedup(t *testing.T) {
+	start := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+	dedup := newNewsDedup(2, time.Hour)
+
+	if !dedup.Add("a", start) || dedup.Add("a", start.Add(time.Minute)) {
+		t.Fatal("Expected the second sighting within the window to be a duplicate")
+	}
+	if !dedup.Add("a", start.Add(time.Hour)) {
+		t.Error("Expected an article to be new again once the window has passed")
+	}
+
+	// Capacity 2: adding b and c evicts a, the least recently seen
+	dedup.Add("b", start.Add(2*time.Hour))
+	dedup.Add("c", start.Add(2*time.Hour))
+	if !dedup.Add("a", start.Add(2*time.Hour)) {
+		t.Error("Expected the evicted article to be treated as new")
+	}
+
+	if key := articleKey(&entities.NewsArticle{Source: "wire", Title: "t", Content: "c"}); key == "" ||
+		key != articleKey(&entities.NewsArticle{Source: "wire", Title: "t", Content: "c"}) {
+		t.Error("Expected articles without IDs to share a content key")
+	}
+}
+
+// flakyPriceProvider fails the next failures subscribe calls
+type flakyPriceProvider struct {
+	*fakePriceProvider
+	failures int
+	attempts int
+}
+
+func (f *flakyPriceProvider) SubscribeToPrice(ctx context.Context, symbol entities.Symbol, callback func(*entities.MarketData)) error {
+	f.mu.Lock()
+	f.attempts++
+	if f.failures > 0 {
+		f.failures--
+		f.mu.Unlock()
+		return fmt.Errorf("feed unavailable")
+	}
+	f.mu.Unlock()
+	return f.fakePriceProvider.SubscribeToPrice(ctx, symbol, callback)
+}
+
+func (f *flakyPriceProvider) attemptCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts
+}
+
+func TestDataCollectorAgent_ResubscribesStalledSymbols(t *testing.T) {
+	prices := &flakyPriceProvider{fakePriceProvider: newFakePriceProvider()}
+	agent := setupTestDataCollector(t, prices, &fakeHistoryProvider{}, newFakeMarketDataRepo())
+	agent.config.PriceStalenessWindow = time.Minute
+	agent.config.ResubscribeMaxAttempts = 3
+	agent.config.ResubscribeInitialBackoff = time.Second
+	agent.config.ResubscribeMaxBackoff = 10 * time.Second
+
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("AddSymbol failed: %v", err)
+	}
+	subscribedAt := time.Now()
+
+	agent.checkStalledSubscriptions(subscribedAt.Add(30 * time.Second))
+	if got := prices.attemptCount(); got != 1 {
+		t.Fatalf("Expected no resubscribe before the staleness window, got %d subscribe calls", got)
+	}
+
+	// The feed went quiet; the first resubscribe fails
+	prices.mu.Lock()
+	prices.failures = 1
+	delete(prices.subscribed, "AAPL")
+	prices.mu.Unlock()
+
+	stalled := subscribedAt.Add(2 * time.Minute)
+	agent.checkStalledSubscriptions(stalled)
+	if got := prices.attemptCount(); got != 2 {
+		t.Fatalf("Expected a resubscribe attempt once stale, got %d subscribe calls", got)
+	}
+	agent.checkStalledSubscriptions(stalled.Add(500 * time.Millisecond))
+	if got := prices.attemptCount(); got != 2 {
+		t.Fatalf("Expected the retry to wait for its backoff, got %d subscribe calls", got)
+	}
+
+	// The retry after the backoff succeeds and restarts the staleness clock
+	recovered := stalled.Add(time.Second)
+	agent.checkStalledSubscriptions(recovered)
+	if got := prices.attemptCount(); got != 3 || !prices.isSubscribed("AAPL") {
+		t.Fatalf("Expected the retry to resubscribe AAPL, got %d subscribe calls", got)
+	}
+	agent.checkStalledSubscriptions(recovered.Add(30 * time.Second))
+	if got := prices.attemptCount(); got != 3 {
+		t.Errorf("Expected a recovered symbol to get a fresh staleness window, got %d subscribe calls", got)
+	}
+	if got := agent.SubscriptionRefCount("AAPL"); got != 1 {
+		t.Errorf("Expected resubscribing to leave the ref count at 1, got %d", got)
+	}
+}
+
+func TestDataCollectorAgent_ResubscribeGivesUpAfterMaxAttempts(t *testing.T) {
+	prices := &flakyPriceProvider{fakePriceProvider: newFakePriceProvider()}
+	agent := setupTestDataCollector(t, prices, &fakeHistoryProvider{}, newFakeMarketDataRepo())
+	agent.config.PriceStalenessWindow = time.Minute
+	agent.config.ResubscribeMaxAttempts = 2
+	agent.config.ResubscribeInitialBackoff = time.Second
+
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("AddSymbol failed: %v", err)
+	}
+	prices.mu.Lock()
+	prices.failures = 10
+	prices.mu.Unlock()
+
+	stalled := time.Now().Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		agent.checkStalledSubscriptions(stalled.Add(time.Duration(i) * time.Minute))
+	}
+	if got := prices.attemptCount(); got != 3 {
+		t.Errorf("Expected 2 resubscribe attempts after the initial subscribe, got %d calls", got)
+	}
+
+	// A price update shows the feed is alive and resets the attempt budget
+	agent.handlePriceUpdate(validQuote("AAPL", 150))
+	agent.mu.RLock()
+	_, retrying := agent.resubscribing["AAPL"]
+	agent.mu.RUnlock()
+	if retrying {
+		t.Error("Expected a price update to clear the resubscribe state")
+	}
+}
+
+func TestDataCollectorAgent_CollectsConfiguredMacroIndicators(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, repo)
+	agent.config.MacroIndicators = []MacroIndicatorSource{
+		{Name: "CPI", Country: "DE", Period: "MONTHLY", Impact: "HIGH"},
+		{Name: "GDP", Country: "JP", Period: "QUARTERLY", Impact: "MEDIUM"},
+		{Name: "PMI", Country: "UK", Period: "MONTHLY", Impact: "LOW"},
+	}
+	agent.SetMacroDataProvider(NewStaticMacroDataProvider(map[string]float64{
+		"CPI": 2.4,
+		"GDP": 0.3,
+	}))
+
+	agent.collectMacroData()
+
+	want := map[string]entities.MacroIndicator{
+		"CPI": {Name: "CPI", Value: 2.4, Country: "DE", Period: "MONTHLY", Impact: "HIGH"},
+		"GDP": {Name: "GDP", Value: 0.3, Country: "JP", Period: "QUARTERLY", Impact: "MEDIUM"},
+	}
+	check := func(where string, got *entities.MacroIndicator) {
+		expected, ok := want[got.Name]
+		if !ok {
+			t.Errorf("Unexpected %s indicator %s", where, got.Name)
+			return
+		}
+		got.Timestamp = time.Time{}
+		if *got != expected {
+			t.Errorf("Expected %s indicator %+v, got %+v", where, expected, *got)
+		}
+	}
+
+	repo.mu.Lock()
+	saved := repo.macro
+	repo.mu.Unlock()
+	if len(saved) != 2 {
+		t.Fatalf("Expected the 2 indicators with values to be saved, got %d", len(saved))
+	}
+	for _, indicator := range saved {
+		check("saved", indicator)
+	}
+
+	bus := agent.messageBus.(*messagebus.MockMessageBus)
+	published := bus.GetMessagesByTopic("raw.macro.indicator")
+	if len(published) != 2 {
+		t.Fatalf("Expected 2 published indicators, got %d", len(published))
+	}
+	for _, message := range published {
+		check("published", message.Message.(*entities.MacroIndicator))
+	}
+}
+
+// validQuote is a price update that passes market data validation
+func validQuote(symbol entities.Symbol, price float64) *entities.MarketData {
+	return &entities.MarketData{
+		Symbol:    symbol,
+		Price:     price,
+		Bid:       price - 0.05,
+		Ask:       price + 0.05,
+		High:      price + 1,
+		Low:       price - 1,
+		Volume:    1000,
+		Timestamp: time.Now(),
+	}
+}
+
+func TestDataCollectorAgent_ValidatesMarketData(t *testing.T) {
+	crossed := validQuote("AAPL", 150)
+	crossed.Bid, crossed.Ask = 150.10, 149.90
+
+	tests := []struct {
+		name       string
+		data       *entities.MarketData
+		wantSaved  bool
+		wantReason string
+	}{
+		{"valid update", validQuote("AAPL", 150), true, ""},
+		{"crossed bid and ask", crossed, false, "crossed_quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeMarketDataRepo()
+			agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, repo)
+			recorder := &recordingMetrics{}
+			agent.metrics = recorder
+			bus := agent.messageBus.(*messagebus.MockMessageBus)
+
+			agent.handlePriceUpdate(tt.data)
+
+			saved := repo.saveCount() == 1
+			published := len(bus.GetMessagesByTopic("raw.market_data")) == 1
+			if saved != tt.wantSaved || published != tt.wantSaved {
+				t.Errorf("Expected saved and published %t, got saved %t published %t", tt.wantSaved, saved, published)
+			}
+
+			rejected := recorder.named(recorder.counters, "market_data_rejected")
+			if tt.wantReason == "" {
+				if len(rejected) != 0 {
+					t.Errorf("Expected no rejection, got %v", rejected)
+				}
+				return
+			}
+			if len(rejected) != 1 || rejected[0].labels["reason"] != tt.wantReason {
+				t.Errorf("Expected one rejection labelled %s, got %v", tt.wantReason, rejected)
+			}
+		})
+	}
+}
+
+func TestDataCollectorAgent_BackfillsOnSubscribe(t *testing.T) {
+	repo := newFakeMarketDataRepo()
+	history := &fakeHistoryProvider{failSymbols: map[entities.Symbol]bool{"TSLA": true}}
+	prices := newFakePriceProvider()
+	agent := setupTestDataCollector(t, prices, history, repo)
+	recorder := &recordingMetrics{}
+	agent.metrics = recorder
+	agent.config.BackfillOnSubscribe = true
+	agent.config.BackfillLookback = 5 * time.Hour
+	defer agent.Stop()
+
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("AddSymbol failed: %v", err)
+	}
+	if err := agent.AddSymbol("TSLA"); err != nil {
+		t.Fatalf("Expected a failing backfill not to fail AddSymbol, got %v", err)
+	}
+
+	backfillErrors := func() int {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		count := 0
+		for _, counter := range recorder.counters {
+			if counter.name == "market_data_backfill_errors" {
+				count++
+			}
+		}
+		return count
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for (repo.saveCount() < 5 || backfillErrors() == 0) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := repo.saveCount(); got != 5 {
+		t.Errorf("Expected 5 hourly bars backfilled for a 5h lookback, got %d", got)
+	}
+	if backfillErrors() != 1 {
+		t.Error("Expected the TSLA backfill to fail")
+	}
+	if !prices.isSubscribed("TSLA") || agent.SubscriptionRefCount("TSLA") != 1 {
+		t.Error("Expected TSLA to stay subscribed after its backfill failed")
+	}
+
+	// A second reference does not backfill again
+	if err := agent.AddSymbol("AAPL"); err != nil {
+		t.Fatalf("AddSymbol failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	history.mu.Lock()
+	calls := len(history.calls)
+	history.mu.Unlock()
+	if calls != 1 {
+		t.Errorf("Expected one history request for AAPL, got %d", calls)
+	}
+}
+
+func TestDataCollectorAgent_BackfillSymbolRejectsNonPositiveLookback(t *testing.T) {
+	agent := setupTestDataCollector(t, newFakePriceProvider(), &fakeHistoryProvider{}, newFakeMarketDataRepo())
+	if err := agent.BackfillSymbol(context.Background(), "AAPL", 0); err == nil {
+		t.Error("Expected a zero lookback to be rejected")
+	}
+}
@@ -2,12 +2,16 @@ package agents
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/backoff"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
 	"github.com/system-trading/core/internal/interfaces"
 	ifs "github.com/system-trading/core/internal/usecases/interfaces"
 )
@@ -24,9 +28,42 @@ type ExecutionAgent struct {
 	cancel          context.CancelFunc
 	wg              sync.WaitGroup
 	retryConfig     RetryConfig
+	jitter          ifs.JitterSource
+	
+	// executionSlots bounds concurrent executeOrder calls from order.approved
+	executionSlots  chan struct{}
+	inFlight        atomic.Int64
+	
+	// processedOrders remembers approved order IDs for dedupWindow so a
+	// redelivered order.approved is not submitted to the broker twice.
+	// processedQueue holds the same IDs oldest first for pruning. Guarded by mu.
+	processedOrders map[entities.OrderID]time.Time
+	processedQueue  []entities.OrderID
+	dedupWindow     time.Duration
+	
+	// ocoCancelled holds OCO orders whose sibling filled before they were
+	// placed, so they are dropped on arrival, for up to dedupWindow. Guarded by mu.
+	ocoCancelled    map[entities.OrderID]time.Time
+	
+	tracer          ifs.Tracer
+	
+	// schemas upgrades older order.approved payloads; nil decodes them as-is
+	schemas         *messagebus.SchemaRegistry
 }
 
-// ExecutionContext tracks the state of an order being executed
+// DefaultMaxConcurrentExecutions is how many approved orders execute at once
+// unless changed with SetMaxConcurrentExecutions
+const DefaultMaxConcurrentExecutions = 10
+
+// DefaultDedupWindow is how long an approved order ID is remembered unless
+// changed with SetDedupWindow
+const DefaultDedupWindow = 24 * time.Hour
+
+// defaultStopTimeout bounds Stop when the caller's context has no deadline
+const defaultStopTimeout = 30 * time.Second
+
+// ExecutionContext tracks the state of an order being executed.
+// Order is the agent's own copy and is never shared with callers.
 type ExecutionContext struct {
 	Order           *entities.Order
 	BrokerOrderID   string
@@ -34,6 +71,19 @@ type ExecutionContext struct {
 	LastStatusCheck time.Time
 	RetryCount      int
 	Status          entities.OrderStatus
+	// FilledQuantity is the cumulative quantity the broker has reported filled
+	FilledQuantity  float64
+}
+
+// ExecutionContextSnapshot is a point-in-time copy of a tracked order's state
+type ExecutionContextSnapshot struct {
+	OrderID        entities.OrderID     `json:"order_id"`
+	BrokerOrderID  string               `json:"broker_order_id"`
+	Symbol         entities.Symbol      `json:"symbol"`
+	Status         entities.OrderStatus `json:"status"`
+	SubmittedAt    time.Time            `json:"submitted_at"`
+	RetryCount     int                  `json:"retry_count"`
+	FilledQuantity float64              `json:"filled_quantity"`
 }
 
 // RetryConfig defines retry behavior for failed operations
@@ -42,7 +92,18 @@ type RetryConfig struct {
 	InitialDelay    time.Duration
 	MaxDelay        time.Duration
 	BackoffFactor   float64
+	// JitterFraction spreads retry delays by ±this fraction to avoid retry storms
+	JitterFraction  float64
+	// OrderTimeout bounds each PlaceOrder attempt; a timed-out attempt is retried
+	OrderTimeout    time.Duration
 	StatusCheckInterval time.Duration
+	// MaxTrackedOrders caps orderTracker; once exceeded, the oldest orders older
+	// than AbandonAfter are evicted and reported on order.abandoned.
+	MaxTrackedOrders int
+	AbandonAfter     time.Duration
+	// MaxPendingAge is how long an order may stay unresolved at the broker before
+	// it is cancelled and reported on order.expired. Zero disables expiry.
+	MaxPendingAge time.Duration
 }
 
 // ExecutedOrderMessage represents a message published when an order is executed
@@ -59,12 +120,29 @@ type ExecutedOrderMessage struct {
 	BrokerName      string    `json:"broker_name"`
 }
 
+// PartialFillMessage is published on order.partially_filled for each increase in
+// an order's filled quantity short of the full order
+type PartialFillMessage struct {
+	OrderID           string    `json:"order_id"`
+	BrokerOrderID     string    `json:"broker_order_id"`
+	Symbol            string    `json:"symbol"`
+	Side              string    `json:"side"`
+	Quantity          float64   `json:"quantity"`
+	FillQuantity      float64   `json:"fill_quantity"`
+	FilledQuantity    float64   `json:"filled_quantity"`
+	RemainingQuantity float64   `json:"remaining_quantity"`
+	AveragePrice      float64   `json:"average_price"`
+	Timestamp         time.Time `json:"timestamp"`
+	BrokerName        string    `json:"broker_name"`
+}
+
 // NewExecutionAgent creates a new execution agent
 func NewExecutionAgent(
 	messageBus ifs.MessageBus,
 	trader interfaces.Trader,
 	logger ifs.Logger,
 	metrics ifs.MetricsCollector,
+	retryConfig RetryConfig,
 ) *ExecutionAgent {
 	ctx, cancel := context.WithCancel(context.Background())
 	
@@ -76,13 +154,29 @@ func NewExecutionAgent(
 		orderTracker: make(map[string]*ExecutionContext),
 		ctx:          ctx,
 		cancel:       cancel,
-		retryConfig: RetryConfig{
-			MaxRetries:          3,
-			InitialDelay:        1 * time.Second,
-			MaxDelay:           30 * time.Second,
-			BackoffFactor:      2.0,
-			StatusCheckInterval: 5 * time.Second,
-		},
+		jitter:       backoff.NewJitterSource(),
+		executionSlots: make(chan struct{}, DefaultMaxConcurrentExecutions),
+		processedOrders: make(map[entities.OrderID]time.Time),
+		ocoCancelled: make(map[entities.OrderID]time.Time),
+		dedupWindow:  DefaultDedupWindow,
+		retryConfig:  retryConfig,
+		tracer:       ifs.NoopTracer{},
+	}
+}
+
+// DefaultRetryConfig returns the retry behavior used when nothing is configured
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:          3,
+		InitialDelay:        1 * time.Second,
+		MaxDelay:            30 * time.Second,
+		BackoffFactor:       2.0,
+		JitterFraction:      0.1,
+		OrderTimeout:        10 * time.Second,
+		StatusCheckInterval: 5 * time.Second,
+		MaxTrackedOrders:    10000,
+		AbandonAfter:        1 * time.Hour,
+		MaxPendingAge:       24 * time.Hour,
 	}
 }
 
@@ -94,9 +188,7 @@ func (ea *ExecutionAgent) Start(ctx context.Context) error {
 	
 	// Connect to broker
 	if err := ea.trader.Connect(ctx); err != nil {
-		ea.metrics.IncrementCounter("execution_agent_errors", map[string]string{
-			"type": "broker_connection_failed",
-		})
+		ea.recordError("broker_connection_failed", err)
 		return fmt.Errorf("failed to connect to broker: %w", err)
 	}
 	
@@ -117,10 +209,17 @@ func (ea *ExecutionAgent) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop gracefully shuts down the execution agent
+// Stop gracefully shuts down the execution agent, waiting for its goroutines
+// until ctx is done, or for defaultStopTimeout when ctx has no deadline
 func (ea *ExecutionAgent) Stop(ctx context.Context) error {
 	ea.logger.Info("Stopping execution agent")
 	
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultStopTimeout)
+		defer cancel()
+	}
+	
 	// Cancel context to stop all operations
 	ea.cancel()
 	
@@ -144,19 +243,18 @@ func (ea *ExecutionAgent) Stop(ctx context.Context) error {
 	case <-done:
 		ea.logger.Info("Execution agent stopped successfully")
 		return nil
-	case <-time.After(30 * time.Second):
+	case <-ctx.Done():
 		ea.logger.Warn("Execution agent shutdown timeout")
-		return fmt.Errorf("shutdown timeout")
+		return fmt.Errorf("shutdown timeout: %w", ctx.Err())
 	}
 }
 
-// handleApprovedOrder processes approved orders for execution
+// handleApprovedOrder processes approved orders for execution. Each message is
+// decoded into a fresh order, so handlers always receive their own copy.
 func (ea *ExecutionAgent) handleApprovedOrder(ctx context.Context, data []byte) error {
-	var order entities.Order
-	if err := json.Unmarshal(data, &order); err != nil {
-		ea.metrics.IncrementCounter("execution_agent_errors", map[string]string{
-			"type": "message_decode_failed",
-		})
+	order, err := ea.decodeOrder(ctx, data)
+	if err != nil {
+		ea.recordError("message_decode_failed", err)
 		return fmt.Errorf("failed to unmarshal order: %w", err)
 	}
 	
@@ -167,27 +265,74 @@ func (ea *ExecutionAgent) handleApprovedOrder(ctx context.Context, data []byte)
 		ifs.Field{Key: "quantity", Value: order.Quantity},
 	)
 	
+	// The bus delivers at least once; never send the same order to the broker twice
+	if ea.markProcessed(order.ID) {
+		ea.logger.Warn("Skipping already processed order",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+		)
+		ea.metrics.IncrementCounter("execution_agent_duplicate_orders", map[string]string{
+			"broker": ea.trader.GetBrokerName(),
+		})
+		return nil
+	}
+	
+	if ea.takeOCOCancelled(order.ID) {
+		ea.logger.Info("Dropping order whose OCO sibling already filled",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+			ifs.Field{Key: "sibling_order_id", Value: string(order.OCOSiblingID)},
+		)
+		ea.publishOrderEvent(ctx, "order.cancelled", &order, nil, nil)
+		return nil
+	}
+	
+	// Wait for an execution slot so a burst of approvals cannot flood the broker
+	select {
+	case ea.executionSlots <- struct{}{}:
+	case <-ctx.Done():
+		// Nothing reached the broker, so a redelivery must still be executed
+		ea.unmarkProcessed(order.ID)
+		return ctx.Err()
+	}
+	ea.metrics.SetGauge("execution_agent_orders_in_flight", float64(ea.inFlight.Add(1)), map[string]string{
+		"broker": ea.trader.GetBrokerName(),
+	})
+	defer func() {
+		ea.metrics.SetGauge("execution_agent_orders_in_flight", float64(ea.inFlight.Add(-1)), map[string]string{
+			"broker": ea.trader.GetBrokerName(),
+		})
+		<-ea.executionSlots
+	}()
+	
 	// Execute the order
-	if err := ea.executeOrder(ctx, &order); err != nil {
+	retries, err := ea.executeOrder(ctx, &order)
+	if err != nil {
 		ea.logger.Error("Failed to execute order",
 			ifs.Field{Key: "order_id", Value: string(order.ID)},
 			ifs.Field{Key: "error", Value: err.Error()},
 		)
 		
-		ea.metrics.IncrementCounter("execution_agent_errors", map[string]string{
-			"type": "order_execution_failed",
-		})
+		ea.recordError("order_execution_failed", err)
+		if errors.Is(err, errOrderInvalid) {
+			ea.unmarkProcessed(order.ID)
+		}
 		
 		// Publish order failure event
 		ea.publishOrderEvent(ctx, "order.failed", &order, nil, err)
 		return err
 	}
 	
+	ea.metrics.ObserveValue("execution_agent_order_retries", float64(retries), map[string]string{
+		"broker": ea.trader.GetBrokerName(),
+	})
+	
 	return nil
 }
 
-// executeOrder executes a single order
-func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Order) error {
+// executeOrder executes a single order and returns how many retries the broker
+// submission needed. The agent works on a copy of order, so the caller may keep
+// using the original without racing the agent's goroutines.
+func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Order) (int, error) {
+	order = order.Clone()
 	startTime := time.Now()
 	
 	defer func() {
@@ -200,12 +345,16 @@ func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Orde
 	
 	// Validate order before execution
 	if err := ea.validateOrder(order); err != nil {
-		return fmt.Errorf("order validation failed: %w", err)
+		return 0, fmt.Errorf("%w: %w", errOrderInvalid, err)
 	}
 	
+	submitted := ea.tracer.StartSpan(string(order.ID), "order.submitted", startTime)
+	
 	// Attempt to place order with retries
 	var result *interfaces.OrderResult
 	var err error
+	attempts := 0
+	placement := &orderPlacement{}
 	
 	for attempt := 0; attempt <= ea.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
@@ -219,14 +368,33 @@ func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Orde
 			select {
 			case <-time.After(delay):
 			case <-ctx.Done():
-				return ctx.Err()
+				ea.abandonPlacement(order, placement)
+				submitted.End(ctx.Err())
+				return attempts - 1, ctx.Err()
+			}
+			
+			// A timed-out attempt was acknowledged after all; retrying would
+			// open a second order at the broker
+			if late := placement.acknowledged(); late != nil {
+				result, err = late, nil
+				break
 			}
 		}
 		
-		result, err = ea.trader.PlaceOrder(ctx, order)
+		attempts++
+		result, err = ea.placeOrderWithTimeout(ctx, order, placement)
 		if err == nil {
+			if !placement.accept(result) {
+				ea.cancelDuplicateOrder(ctx, order, result.BrokerOrderID)
+				result = placement.acknowledged()
+			}
 			break
 		}
+		if ctx.Err() != nil {
+			ea.abandonPlacement(order, placement)
+			submitted.End(ctx.Err())
+			return attempts - 1, ctx.Err()
+		}
 		
 		ea.logger.Warn("Order execution attempt failed",
 			ifs.Field{Key: "order_id", Value: string(order.ID)},
@@ -240,13 +408,24 @@ func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Orde
 		}
 	}
 	
+	if late := placement.settle(); err != nil && late != nil {
+		result, err = late, nil
+	}
+	
+	retries := attempts - 1
+	submitted.End(err)
+	submittedAt := time.Now()
+	if errors.Is(err, errOrderAttemptTimeout) {
+		return retries, fmt.Errorf("order execution timed out after %d attempts: %w", attempts, err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to place order after %d attempts: %w", 
-			ea.retryConfig.MaxRetries+1, err)
+		return retries, fmt.Errorf("failed to place order after %d attempts: %w", attempts, err)
 	}
 	
-	// Track the order for status monitoring
-	ea.trackOrder(order, result.BrokerOrderID)
+	// Track the order for status monitoring; IOC and FOK orders resolve below
+	if !order.IsImmediate() {
+		ea.trackOrder(order, result.BrokerOrderID)
+	}
 	
 	ea.logger.Info("Order submitted to broker",
 		ifs.Field{Key: "order_id", Value: string(order.ID)},
@@ -260,11 +439,238 @@ func (ea *ExecutionAgent) executeOrder(ctx context.Context, order *entities.Orde
 		"broker": ea.trader.GetBrokerName(),
 	})
 	
+	if order.IsImmediate() {
+		ea.resolveImmediateOrder(ctx, order, result, submittedAt)
+		return retries, nil
+	}
+	
 	// For market orders that are immediately executed, publish execution event
 	if result.Status == entities.OrderStatusExecuted && result.ExecutedPrice != nil {
+		filled := ea.tracer.StartSpan(string(order.ID), "order.filled", submittedAt)
 		ea.publishExecutedOrder(ctx, order, result)
+		filled.End(nil)
+		ea.cancelOCOSibling(ctx, order)
 	}
 	
+	return retries, nil
+}
+
+// errOrderInvalid marks an order rejected by validation before any attempt to
+// place it
+var errOrderInvalid = errors.New("order validation failed")
+
+// errOrderAttemptTimeout marks a PlaceOrder attempt that exceeded OrderTimeout
+var errOrderAttemptTimeout = errors.New("order attempt timed out")
+
+type placeOrderResult struct {
+	result *interfaces.OrderResult
+	err    error
+}
+
+// placeOrderWithTimeout runs one PlaceOrder attempt bounded by OrderTimeout. It
+// returns when the timeout fires even if the broker ignores its context.
+func (ea *ExecutionAgent) placeOrderWithTimeout(ctx context.Context, order *entities.Order, placement *orderPlacement) (*interfaces.OrderResult, error) {
+	if ea.retryConfig.OrderTimeout <= 0 {
+		return ea.trader.PlaceOrder(ctx, order)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, ea.retryConfig.OrderTimeout)
+
+	// The attempt gets its own copy since it may outlive this call
+	attemptOrder := order.Clone()
+	done := make(chan placeOrderResult, 1)
+	go func() {
+		defer cancel()
+		result, err := ea.trader.PlaceOrder(attemptCtx, attemptOrder)
+		done <- placeOrderResult{result: result, err: err}
+	}()
+
+	select {
+	case placed := <-done:
+		if placed.err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return nil, ea.attemptTimeoutError()
+		}
+		return placed.result, placed.err
+	case <-attemptCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		go ea.watchLateAcknowledgement(order, done, placement)
+		return nil, ea.attemptTimeoutError()
+	}
+}
+
+func (ea *ExecutionAgent) attemptTimeoutError() error {
+	return fmt.Errorf("%w after %s: %w", errOrderAttemptTimeout, ea.retryConfig.OrderTimeout,
+		&interfaces.BrokerError{Code: "TIMEOUT", Message: "broker did not acknowledge order in time"})
+}
+
+// orderPlacement keeps at most one of an order's PlaceOrder attempts live at
+// the broker: the first attempt acknowledged wins and any later one is
+// cancelled. Once the agent settles the placement, a late acknowledgement is
+// tracked by its watcher instead of being handed back to the retry loop.
+type orderPlacement struct {
+	mu       sync.Mutex
+	accepted *interfaces.OrderResult
+	settled  bool
+}
+
+// accept records result as the order's broker order, reporting false when
+// another attempt was acknowledged first
+func (p *orderPlacement) accept(result *interfaces.OrderResult) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if p.accepted != nil {
+		return false
+	}
+	p.accepted = result
+	return true
+}
+
+func (p *orderPlacement) acknowledged() *interfaces.OrderResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.accepted
+}
+
+// settle stops handing late acknowledgements to the retry loop and returns
+// the accepted result, if any
+func (p *orderPlacement) settle() *interfaces.OrderResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settled = true
+	return p.accepted
+}
+
+// lateAccept is accept for an abandoned attempt. It also reports whether the
+// agent has settled, in which case the caller owns tracking the order.
+func (p *orderPlacement) lateAccept(result *interfaces.OrderResult) (accepted, settled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if p.accepted != nil {
+		return false, p.settled
+	}
+	p.accepted = result
+	return true, p.settled
+}
+
+// abandonPlacement settles a placement the agent gives up on early, tracking
+// any attempt the broker already acknowledged so it is not left unmonitored
+func (ea *ExecutionAgent) abandonPlacement(order *entities.Order, placement *orderPlacement) {
+	if result := placement.settle(); result != nil {
+		ea.trackOrder(order, result.BrokerOrderID)
+	}
+}
+
+// watchLateAcknowledgement waits for an abandoned attempt. If the broker
+// accepts the order after all, it is cancelled when a retry was acknowledged
+// first, handed to the retry loop while it is still running, and otherwise
+// tracked so it is not left unmonitored.
+func (ea *ExecutionAgent) watchLateAcknowledgement(order *entities.Order, done <-chan placeOrderResult, placement *orderPlacement) {
+	placed := <-done
+	if placed.err != nil || placed.result == nil {
+		return
+	}
+
+	ea.metrics.IncrementCounter("execution_agent_late_acks", map[string]string{
+		"symbol": string(order.Symbol),
+		"broker": ea.trader.GetBrokerName(),
+	})
+	ea.logger.Error("Broker accepted order after attempt timed out",
+		ifs.Field{Key: "order_id", Value: string(order.ID)},
+		ifs.Field{Key: "broker_order_id", Value: placed.result.BrokerOrderID},
+	)
+	
+	accepted, settled := placement.lateAccept(placed.result)
+	switch {
+	case !accepted:
+		ea.cancelDuplicateOrder(context.Background(), order, placed.result.BrokerOrderID)
+	case settled:
+		ea.trackOrder(order, placed.result.BrokerOrderID)
+	}
+}
+
+// cancelDuplicateOrder cancels a broker order opened by an attempt that lost
+// to another attempt for the same order
+func (ea *ExecutionAgent) cancelDuplicateOrder(ctx context.Context, order *entities.Order, brokerOrderID string) {
+	ea.metrics.IncrementCounter("execution_agent_duplicate_orders_cancelled", map[string]string{
+		"symbol": string(order.Symbol),
+		"broker": ea.trader.GetBrokerName(),
+	})
+	
+	if err := ea.trader.CancelOrder(ctx, brokerOrderID); err != nil {
+		ea.logger.Error("Failed to cancel duplicate broker order",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+			ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+			ifs.Field{Key: "error", Value: err.Error()},
+		)
+		return
+	}
+	ea.logger.Warn("Cancelled duplicate broker order",
+		ifs.Field{Key: "order_id", Value: string(order.ID)},
+		ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+	)
+}
+
+// OrderNotReplaceableError is returned by ReplaceOrder when the order is not
+// tracked or is no longer a resting limit order
+type OrderNotReplaceableError struct {
+	BrokerOrderID string
+	Reason        string
+}
+
+func (e *OrderNotReplaceableError) Error() string {
+	return fmt.Sprintf("order %s cannot be replaced: %s", e.BrokerOrderID, e.Reason)
+}
+
+// ReplaceOrder reprices a resting limit order in place instead of cancelling
+// and resubmitting it. The tracker lock is held for the whole replace so the
+// status monitor cannot execute or drop the order mid-amendment.
+func (ea *ExecutionAgent) ReplaceOrder(ctx context.Context, brokerOrderID string, newPrice float64) error {
+	if newPrice <= 0 {
+		return fmt.Errorf("replacement price must be positive, got %v", newPrice)
+	}
+	
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	
+	execCtx, exists := ea.orderTracker[brokerOrderID]
+	if !exists {
+		return &OrderNotReplaceableError{BrokerOrderID: brokerOrderID, Reason: "order is not tracked"}
+	}
+	if execCtx.Status == entities.OrderStatusExecuted {
+		return &OrderNotReplaceableError{BrokerOrderID: brokerOrderID, Reason: "order already executed"}
+	}
+	if execCtx.Order.Type != entities.OrderTypeLimit {
+		return &OrderNotReplaceableError{
+			BrokerOrderID: brokerOrderID,
+			Reason:        fmt.Sprintf("%s orders have no price to amend", execCtx.Order.Type),
+		}
+	}
+	
+	previousPrice := execCtx.Order.Price
+	if err := ea.trader.ReplaceOrder(ctx, brokerOrderID, newPrice); err != nil {
+		ea.recordError("order_replace_failed", err)
+		return fmt.Errorf("failed to replace order: %w", err)
+	}
+	
+	execCtx.Order.Price = &newPrice
+	execCtx.Order.UpdatedAt = time.Now()
+	
+	ea.logger.Info("Order replaced",
+		ifs.Field{Key: "order_id", Value: string(execCtx.Order.ID)},
+		ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+		ifs.Field{Key: "old_price", Value: previousPrice},
+		ifs.Field{Key: "new_price", Value: newPrice},
+	)
+	
+	ea.metrics.IncrementCounter("execution_agent_orders_replaced", map[string]string{
+		"symbol": string(execCtx.Order.Symbol),
+		"broker": ea.trader.GetBrokerName(),
+	})
+	
 	return nil
 }
 
@@ -286,7 +692,7 @@ func (ea *ExecutionAgent) validateOrder(order *entities.Order) error {
 		return fmt.Errorf("invalid order side: %s", order.Side)
 	}
 	
-	if order.Type != entities.OrderTypeMarket && order.Type != entities.OrderTypeLimit {
+	if order.Type != entities.OrderTypeMarket && order.Type != entities.OrderTypeLimit && order.Type != entities.OrderTypeStop {
 		return fmt.Errorf("unsupported order type: %s", order.Type)
 	}
 	
@@ -294,22 +700,136 @@ func (ea *ExecutionAgent) validateOrder(order *entities.Order) error {
 		return fmt.Errorf("limit orders must have a positive price")
 	}
 	
+	if order.Type == entities.OrderTypeStop && (order.StopPrice == nil || *order.StopPrice <= 0) {
+		return fmt.Errorf("stop orders must have a positive stop price")
+	}
+	
 	return nil
 }
 
-// trackOrder adds an order to the tracking system
+// trackOrder adds a copy of the order to the tracking system
 func (ea *ExecutionAgent) trackOrder(order *entities.Order, brokerOrderID string) {
-	ea.mu.Lock()
-	defer ea.mu.Unlock()
+	tracked := order.Clone()
 	
+	ea.mu.Lock()
 	ea.orderTracker[brokerOrderID] = &ExecutionContext{
-		Order:           order,
+		Order:           tracked,
 		BrokerOrderID:   brokerOrderID,
 		SubmittedAt:     time.Now(),
 		LastStatusCheck: time.Now(),
 		RetryCount:      0,
 		Status:          entities.OrderStatusPending,
 	}
+	evicted := ea.evictAbandonedOrdersLocked()
+	ea.mu.Unlock()
+	
+	for _, execCtx := range evicted {
+		ea.logger.Warn("Evicted abandoned order from tracker",
+			ifs.Field{Key: "order_id", Value: string(execCtx.Order.ID)},
+			ifs.Field{Key: "broker_order_id", Value: execCtx.BrokerOrderID},
+			ifs.Field{Key: "submitted_at", Value: execCtx.SubmittedAt},
+		)
+		
+		ea.metrics.IncrementCounter("execution_agent_orders_abandoned", map[string]string{
+			"symbol": string(execCtx.Order.Symbol),
+			"broker": ea.trader.GetBrokerName(),
+		})
+		
+		ea.publishOrderEvent(ea.ctx, "order.abandoned", execCtx.Order, &interfaces.OrderStatus{
+			BrokerOrderID: execCtx.BrokerOrderID,
+			Status:        execCtx.Status,
+			LastUpdate:    execCtx.LastStatusCheck,
+		}, nil)
+	}
+}
+
+// GetTrackedOrders returns snapshots of every order the agent is monitoring,
+// oldest submission first
+func (ea *ExecutionAgent) GetTrackedOrders() []ExecutionContextSnapshot {
+	ea.mu.RLock()
+	snapshots := make([]ExecutionContextSnapshot, 0, len(ea.orderTracker))
+	for _, execCtx := range ea.orderTracker {
+		snapshots = append(snapshots, execCtx.snapshot())
+	}
+	ea.mu.RUnlock()
+	
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].SubmittedAt.Equal(snapshots[j].SubmittedAt) {
+			return snapshots[i].SubmittedAt.Before(snapshots[j].SubmittedAt)
+		}
+		return snapshots[i].BrokerOrderID < snapshots[j].BrokerOrderID
+	})
+	return snapshots
+}
+
+// GetTrackedOrder returns a snapshot of the tracked order with brokerOrderID
+func (ea *ExecutionAgent) GetTrackedOrder(brokerOrderID string) (ExecutionContextSnapshot, bool) {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+	
+	execCtx, exists := ea.orderTracker[brokerOrderID]
+	if !exists {
+		return ExecutionContextSnapshot{}, false
+	}
+	return execCtx.snapshot(), true
+}
+
+// snapshot copies the context's fields. Callers must hold ea.mu.
+func (c *ExecutionContext) snapshot() ExecutionContextSnapshot {
+	return ExecutionContextSnapshot{
+		OrderID:        c.Order.ID,
+		BrokerOrderID:  c.BrokerOrderID,
+		Symbol:         c.Order.Symbol,
+		Status:         c.Status,
+		SubmittedAt:    c.SubmittedAt,
+		RetryCount:     c.RetryCount,
+		FilledQuantity: c.FilledQuantity,
+	}
+}
+
+// evictAbandonedOrdersLocked removes the oldest non-terminal orders older than
+// AbandonAfter until the tracker is back within MaxTrackedOrders. Orders younger
+// than AbandonAfter are never evicted, so the cap may be exceeded briefly under a
+// burst of live orders. Must be called with ea.mu held.
+func (ea *ExecutionAgent) evictAbandonedOrdersLocked() []*ExecutionContext {
+	maxTracked := ea.retryConfig.MaxTrackedOrders
+	if maxTracked <= 0 || len(ea.orderTracker) <= maxTracked {
+		return nil
+	}
+	
+	cutoff := time.Now().Add(-ea.retryConfig.AbandonAfter)
+	candidates := make([]*ExecutionContext, 0, len(ea.orderTracker))
+	for _, execCtx := range ea.orderTracker {
+		if isTerminalStatus(execCtx.Status) || execCtx.SubmittedAt.After(cutoff) {
+			continue
+		}
+		candidates = append(candidates, execCtx)
+	}
+	
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].SubmittedAt.Before(candidates[j].SubmittedAt)
+	})
+	
+	excess := len(ea.orderTracker) - maxTracked
+	if excess > len(candidates) {
+		excess = len(candidates)
+	}
+	
+	evicted := candidates[:excess]
+	for _, execCtx := range evicted {
+		delete(ea.orderTracker, execCtx.BrokerOrderID)
+	}
+	
+	return evicted
+}
+
+// isTerminalStatus reports whether an order can no longer change state
+func isTerminalStatus(status entities.OrderStatus) bool {
+	switch status {
+	case entities.OrderStatusExecuted, entities.OrderStatusCancelled, entities.OrderStatusRejected:
+		return true
+	}
+	return false
 }
 
 // monitorOrderStatus monitors pending orders and publishes execution events
@@ -331,13 +851,30 @@ func (ea *ExecutionAgent) monitorOrderStatus() {
 
 // checkPendingOrders checks the status of all pending orders
 func (ea *ExecutionAgent) checkPendingOrders() {
+	var cutoff time.Time
+	if ea.retryConfig.MaxPendingAge > 0 {
+		cutoff = time.Now().Add(-ea.retryConfig.MaxPendingAge)
+	}
+	
+	now := time.Now()
 	ea.mu.RLock()
 	orderIDs := make([]string, 0, len(ea.orderTracker))
-	for brokerOrderID := range ea.orderTracker {
+	var expired []string
+	for brokerOrderID, execCtx := range ea.orderTracker {
+		// DAY and GTD orders expire on their own schedule, anything else once
+		// it has been pending too long
+		if execCtx.Order.ExpiresBy(now) || (!cutoff.IsZero() && execCtx.SubmittedAt.Before(cutoff)) {
+			expired = append(expired, brokerOrderID)
+			continue
+		}
 		orderIDs = append(orderIDs, brokerOrderID)
 	}
 	ea.mu.RUnlock()
 	
+	for _, brokerOrderID := range expired {
+		ea.expireOrder(brokerOrderID)
+	}
+	
 	for _, brokerOrderID := range orderIDs {
 		if err := ea.checkOrderStatus(brokerOrderID); err != nil {
 			ea.logger.Error("Failed to check order status",
@@ -348,6 +885,57 @@ func (ea *ExecutionAgent) checkPendingOrders() {
 	}
 }
 
+// expireOrder cancels an order whose time in force has run out, or that the
+// broker has left unresolved past MaxPendingAge, and stops tracking it
+func (ea *ExecutionAgent) expireOrder(brokerOrderID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	
+	cancelErr := ea.trader.CancelOrder(ctx, brokerOrderID)
+	if cancelErr != nil {
+		// The order may have filled after all; give the status path a last look
+		if err := ea.checkOrderStatus(brokerOrderID); err != nil {
+			ea.logger.Warn("Final status check for expired order failed",
+				ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+		}
+	}
+	
+	ea.mu.Lock()
+	execCtx, exists := ea.orderTracker[brokerOrderID]
+	delete(ea.orderTracker, brokerOrderID)
+	ea.mu.Unlock()
+	
+	if !exists {
+		return // resolved by the final status check
+	}
+	
+	status := execCtx.Status
+	if cancelErr == nil {
+		status = entities.OrderStatusCancelled
+	}
+	
+	ea.logger.Warn("Expired pending order",
+		ifs.Field{Key: "order_id", Value: string(execCtx.Order.ID)},
+		ifs.Field{Key: "time_in_force", Value: string(execCtx.Order.TimeInForce)},
+		ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+		ifs.Field{Key: "submitted_at", Value: execCtx.SubmittedAt},
+		ifs.Field{Key: "cancelled", Value: cancelErr == nil},
+	)
+	
+	ea.metrics.IncrementCounter("execution_agent_orders_expired", map[string]string{
+		"symbol": string(execCtx.Order.Symbol),
+		"broker": ea.trader.GetBrokerName(),
+	})
+	
+	ea.publishOrderEvent(ctx, "order.expired", execCtx.Order, &interfaces.OrderStatus{
+		BrokerOrderID: brokerOrderID,
+		Status:        status,
+		LastUpdate:    execCtx.LastStatusCheck,
+	}, cancelErr)
+}
+
 // checkOrderStatus checks the status of a specific order
 func (ea *ExecutionAgent) checkOrderStatus(brokerOrderID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -358,6 +946,13 @@ func (ea *ExecutionAgent) checkOrderStatus(brokerOrderID string) error {
 		return fmt.Errorf("failed to get order status: %w", err)
 	}
 	
+	var filled float64
+	if status.ExecutedQty != nil {
+		filled = *status.ExecutedQty
+	}
+	
+	// Decide and untrack under one lock so concurrent checks of the same order
+	// cannot both publish its fill or terminal event
 	ea.mu.Lock()
 	execCtx, exists := ea.orderTracker[brokerOrderID]
 	if !exists {
@@ -367,41 +962,85 @@ func (ea *ExecutionAgent) checkOrderStatus(brokerOrderID string) error {
 	
 	execCtx.LastStatusCheck = time.Now()
 	previousStatus := execCtx.Status
+	previousFilled := execCtx.FilledQuantity
 	execCtx.Status = status.Status
+	if filled > previousFilled {
+		execCtx.FilledQuantity = filled
+	}
+	order := execCtx.Order.Clone()
+	submittedAt := execCtx.SubmittedAt
+	
+	fullyFilled := status.Status == entities.OrderStatusExecuted || (filled > 0 && filled >= order.Quantity)
+	terminal := fullyFilled || status.Status == entities.OrderStatusCancelled || status.Status == entities.OrderStatusRejected
+	if terminal {
+		delete(ea.orderTracker, brokerOrderID)
+	}
 	ea.mu.Unlock()
 	
-	// Handle status changes
 	if status.Status != previousStatus {
 		ea.logger.Info("Order status changed",
 			ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
 			ifs.Field{Key: "old_status", Value: string(previousStatus)},
 			ifs.Field{Key: "new_status", Value: string(status.Status)},
 		)
-		
-		switch status.Status {
-		case entities.OrderStatusExecuted:
-			if status.ExecutedPrice != nil && status.ExecutedQty != nil {
-				ea.publishExecutedOrderFromStatus(ctx, execCtx.Order, brokerOrderID, status)
-			}
-			
-			// Remove from tracking
-			ea.mu.Lock()
-			delete(ea.orderTracker, brokerOrderID)
-			ea.mu.Unlock()
-			
-		case entities.OrderStatusCancelled, entities.OrderStatusRejected:
-			ea.publishOrderEvent(ctx, "order.cancelled", execCtx.Order, status, nil)
-			
-			// Remove from tracking
-			ea.mu.Lock()
-			delete(ea.orderTracker, brokerOrderID)
-			ea.mu.Unlock()
+	}
+	
+	switch {
+	case fullyFilled:
+		if status.ExecutedPrice != nil && status.ExecutedQty != nil {
+			// The fill span covers the wait from submission until the fill was seen
+			filled := ea.tracer.StartSpan(string(order.ID), "order.filled", submittedAt)
+			ea.publishExecutedOrderFromStatus(ctx, order, brokerOrderID, status)
+			filled.End(nil)
 		}
+		ea.cancelOCOSibling(ctx, order)
+		
+	case status.Status == entities.OrderStatusCancelled, status.Status == entities.OrderStatusRejected:
+		ea.publishOrderEvent(ctx, "order.cancelled", order, status, nil)
+		
+	case filled > previousFilled:
+		// A repeated report of the same cumulative quantity publishes nothing
+		ea.publishPartialFill(ctx, order, brokerOrderID, status, filled-previousFilled)
 	}
 	
 	return nil
 }
 
+// publishPartialFill publishes the increment of a fill that leaves the order open
+func (ea *ExecutionAgent) publishPartialFill(ctx context.Context, order *entities.Order,
+	brokerOrderID string, status *interfaces.OrderStatus, increment float64) {
+	
+	message := PartialFillMessage{
+		OrderID:           string(order.ID),
+		BrokerOrderID:     brokerOrderID,
+		Symbol:            string(order.Symbol),
+		Side:              string(order.Side),
+		Quantity:          order.Quantity,
+		FillQuantity:      increment,
+		FilledQuantity:    *status.ExecutedQty,
+		RemainingQuantity: order.Quantity - *status.ExecutedQty,
+		Timestamp:         status.LastUpdate,
+		BrokerName:        ea.trader.GetBrokerName(),
+	}
+	if status.ExecutedPrice != nil {
+		message.AveragePrice = *status.ExecutedPrice
+	}
+	
+	if err := ea.messageBus.Publish(ctx, "order.partially_filled", message); err != nil {
+		ea.logger.Error("Failed to publish partial fill",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+			ifs.Field{Key: "error", Value: err.Error()},
+		)
+		return
+	}
+	
+	ea.metrics.IncrementCounter("execution_agent_partial_fills", map[string]string{
+		"symbol": string(order.Symbol),
+		"side":   string(order.Side),
+		"broker": ea.trader.GetBrokerName(),
+	})
+}
+
 // publishExecutedOrder publishes an executed order event
 func (ea *ExecutionAgent) publishExecutedOrder(ctx context.Context, order *entities.Order, 
 	result *interfaces.OrderResult) {
@@ -500,25 +1139,132 @@ func (ea *ExecutionAgent) publishOrderEvent(ctx context.Context, topic string, o
 	}
 }
 
-// calculateRetryDelay calculates the delay for retry attempts using exponential backoff
-func (ea *ExecutionAgent) calculateRetryDelay(attempt int) time.Duration {
-	delay := float64(ea.retryConfig.InitialDelay) * 
-		(ea.retryConfig.BackoffFactor * float64(attempt))
+// SetMaxConcurrentExecutions limits how many approved orders execute at once;
+// further orders wait for a slot. It must be called before Start.
+func (ea *ExecutionAgent) SetMaxConcurrentExecutions(limit int) {
+	if limit <= 0 {
+		limit = DefaultMaxConcurrentExecutions
+	}
+	ea.executionSlots = make(chan struct{}, limit)
+}
+
+// SetDedupWindow sets how long approved order IDs are remembered for duplicate
+// detection. Zero or less disables duplicate detection.
+func (ea *ExecutionAgent) SetDedupWindow(window time.Duration) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	ea.dedupWindow = window
+}
+
+// markProcessed records orderID and reports whether it was already processed
+// within the dedup window
+func (ea *ExecutionAgent) markProcessed(orderID entities.OrderID) bool {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	
+	if ea.dedupWindow <= 0 {
+		return false
+	}
+	
+	now := time.Now()
+	for len(ea.processedQueue) > 0 {
+		oldest := ea.processedQueue[0]
+		if now.Sub(ea.processedOrders[oldest]) < ea.dedupWindow {
+			break
+		}
+		delete(ea.processedOrders, oldest)
+		ea.processedQueue = ea.processedQueue[1:]
+	}
+	
+	if _, seen := ea.processedOrders[orderID]; seen {
+		return true
+	}
+	ea.processedOrders[orderID] = now
+	ea.processedQueue = append(ea.processedQueue, orderID)
+	return false
+}
+
+// unmarkProcessed forgets orderID so a redelivery of an order that failed
+// before reaching the broker is executed
+func (ea *ExecutionAgent) unmarkProcessed(orderID entities.OrderID) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	delete(ea.processedOrders, orderID)
+}
+
+// SetTracer records order.submitted and order.filled spans, keyed by order ID
+func (ea *ExecutionAgent) SetTracer(tracer ifs.Tracer) {
+	ea.tracer = tracer
+}
+
+// SetSchemaRegistry decodes order.approved through registry, so payloads of
+// any registered order schema version are upgraded before execution
+func (ea *ExecutionAgent) SetSchemaRegistry(registry *messagebus.SchemaRegistry) {
+	ea.schemas = registry
+}
+
+// decodeOrder reads an order.approved payload, through the schema registry if set
+func (ea *ExecutionAgent) decodeOrder(ctx context.Context, data []byte) (entities.Order, error) {
+	if ea.schemas == nil {
+		var order entities.Order
+		err := ifs.DecodeMessage(ctx, data, &order)
+		return order, err
+	}
 	
-	if delay > float64(ea.retryConfig.MaxDelay) {
-		delay = float64(ea.retryConfig.MaxDelay)
+	decoded, err := ea.schemas.Decode(ctx, messagebus.SubjectOrder, data)
+	if err != nil {
+		return entities.Order{}, err
+	}
+	message, ok := decoded.(*messagebus.OrderMessage)
+	if !ok {
+		return entities.Order{}, fmt.Errorf("unexpected order message type %T", decoded)
+	}
+	return message.Data, nil
+}
+
+// SetJitterSource replaces the source of retry jitter, e.g. with a seeded one in tests
+func (ea *ExecutionAgent) SetJitterSource(source ifs.JitterSource) {
+	ea.jitter = source
+}
+
+// calculateRetryDelay returns InitialDelay * BackoffFactor^(attempt-1), capped at
+// MaxDelay and jittered. Attempt 0 is the first try, not a retry, and has no delay.
+func (ea *ExecutionAgent) calculateRetryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	return backoff.Backoff{
+		InitialDelay: ea.retryConfig.InitialDelay,
+		MaxDelay:     ea.retryConfig.MaxDelay,
+		Factor:       ea.retryConfig.BackoffFactor,
+		Jitter:       ea.retryConfig.JitterFraction,
+		Source:       ea.jitter,
+	}.Delay(attempt)
+}
+
+// recordError counts an agent error, labelled with the broker's error code when
+// err carries one
+func (ea *ExecutionAgent) recordError(errType string, err error) {
+	brokerCode := ""
+	var brokerErr *interfaces.BrokerError
+	if errors.As(err, &brokerErr) {
+		brokerCode = brokerErr.Code
 	}
 	
-	return time.Duration(delay)
+	ea.metrics.IncrementCounter("execution_agent_errors", map[string]string{
+		"type":        errType,
+		"broker_code": brokerCode,
+	})
 }
 
 // isRetryableError determines if an error is retryable
 func (ea *ExecutionAgent) isRetryableError(err error) bool {
-	if brokerErr, ok := err.(*interfaces.BrokerError); ok {
+	var brokerErr *interfaces.BrokerError
+	if errors.As(err, &brokerErr) {
 		switch brokerErr.Code {
 		case "CONNECTION_FAILED", "TIMEOUT", "TEMPORARY_ERROR":
 			return true
-		case "ORDER_REJECTED", "INSUFFICIENT_FUNDS", "INVALID_SYMBOL":
+		case "ORDER_REJECTED", "INSUFFICIENT_FUNDS", "INVALID_SYMBOL", "MARKET_CLOSED", "SYMBOL_HALTED":
 			return false
 		default:
 			return true
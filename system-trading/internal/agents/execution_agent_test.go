@@ -3,11 +3,17 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/backoff"
 	"github.com/system-trading/core/internal/infrastructure/brokers"
 	"github.com/system-trading/core/internal/infrastructure/config"
 	"github.com/system-trading/core/internal/infrastructure/logger"
@@ -29,8 +35,8 @@ func setupTestExecutionAgent(t *testing.T) (*ExecutionAgent, *messagebus.MockMes
 		t.Fatalf("Failed to create test logger: %v", err)
 	}
 
-	// Create test metrics with unique name to avoid registration conflicts
-	testMetrics := metrics.NewPrometheusMetrics("test-execution-agent-" + fmt.Sprintf("%d", time.Now().UnixNano()))
+	// Create test metrics in their own registry
+	testMetrics := metrics.NewPrometheusMetricsWithRegistry("test-execution-agent", prometheus.NewRegistry())
 
 	// Create mock message bus
 	mockBus := messagebus.NewMockMessageBus()
@@ -39,7 +45,7 @@ func setupTestExecutionAgent(t *testing.T) (*ExecutionAgent, *messagebus.MockMes
 	mockBroker := brokers.NewMockBroker("TestBroker", testLogger)
 
 	// Create execution agent
-	agent := NewExecutionAgent(mockBus, mockBroker, testLogger, testMetrics)
+	agent := NewExecutionAgent(mockBus, mockBroker, testLogger, testMetrics, DefaultRetryConfig())
 
 	return agent, mockBus, mockBroker
 }
@@ -77,6 +83,10 @@ func TestExecutionAgent_HandleApprovedOrder(t *testing.T) {
 	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
 	defer agent.Stop(context.Background())
 
+	// Execute market orders inline so the fill is visible as soon as the handler returns
+	mockBroker.SetSynchronous(true)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -111,8 +121,9 @@ func TestExecutionAgent_HandleApprovedOrder(t *testing.T) {
 		t.Fatalf("Failed to handle approved order: %v", err)
 	}
 
-	// Give some time for async processing
-	time.Sleep(100 * time.Millisecond)
+	if executed := mockBus.GetMessagesByTopic("order.executed"); len(executed) != 1 {
+		t.Errorf("Expected 1 order.executed event, got %d", len(executed))
+	}
 
 	// Check if order was executed (for market orders, execution is immediate)
 	account, err := mockBroker.GetAccountInfo(ctx)
@@ -225,31 +236,106 @@ func TestExecutionAgent_RetryLogic(t *testing.T) {
 	agent, _, mockBroker := setupTestExecutionAgent(t)
 	defer agent.Stop(context.Background())
 
-	// Configure higher error rate to test retries
-	SetMockBrokerErrorRate(mockBroker, 0.8) // 80% error rate
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	SetMockBrokerErrorRate(mockBroker, 0)
 	err := agent.Start(ctx)
 	if err != nil {
 		t.Fatalf("Failed to start execution agent: %v", err)
 	}
 
+	// Configure higher error rate to test retries
+	SetMockBrokerErrorRate(mockBroker, 0.8) // 80% error rate
+
 	order := createTestOrder()
 
 	// Test retry behavior
-	err = agent.executeOrder(ctx, order)
+	_, err = agent.executeOrder(ctx, order)
 	// Even with high error rate, it should eventually succeed or return meaningful error
 	if err != nil {
 		t.Logf("Order execution failed after retries (expected with high error rate): %v", err)
 	}
 }
 
+func TestExecutionAgent_SeededRetryJitterIsReproducible(t *testing.T) {
+	first, _, _ := setupTestExecutionAgent(t)
+	second, _, _ := setupTestExecutionAgent(t)
+	first.SetJitterSource(backoff.NewSeededSource(7))
+	second.SetJitterSource(backoff.NewSeededSource(7))
+
+	for attempt := 1; attempt <= first.retryConfig.MaxRetries; attempt++ {
+		got, want := first.calculateRetryDelay(attempt), second.calculateRetryDelay(attempt)
+		if got != want {
+			t.Errorf("Attempt %d: expected identical delays for the same seed, got %v and %v", attempt, got, want)
+		}
+
+		base := first.retryConfig.InitialDelay << (attempt - 1)
+		spread := time.Duration(float64(base) * first.retryConfig.JitterFraction)
+		if got < base-spread || got > base+spread {
+			t.Errorf("Attempt %d: delay %v outside %v ± %v", attempt, got, base, spread)
+		}
+	}
+}
+
+func TestExecutionAgent_RetryDelaySequence(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RetryConfig
+		want   []time.Duration
+	}{
+		{
+			name:   "doubling capped at max",
+			config: RetryConfig{InitialDelay: time.Second, MaxDelay: 30 * time.Second, BackoffFactor: 2},
+			want:   []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second},
+		},
+		{
+			name:   "tripling from 100ms",
+			config: RetryConfig{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, BackoffFactor: 3},
+			want:   []time.Duration{0, 100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second},
+		},
+		{
+			name:   "constant with factor 1",
+			config: RetryConfig{InitialDelay: 500 * time.Millisecond, MaxDelay: time.Second, BackoffFactor: 1},
+			want:   []time.Duration{0, 500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, _, _ := setupTestExecutionAgent(t)
+			agent.retryConfig = tt.config
+
+			for attempt, want := range tt.want {
+				if got := agent.calculateRetryDelay(attempt); got != want {
+					t.Errorf("Attempt %d: expected %v, got %v", attempt, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutionAgent_DefaultRetryJitterWithinTenPercent(t *testing.T) {
+	agent, _, _ := setupTestExecutionAgent(t)
+
+	if got := agent.calculateRetryDelay(0); got != 0 {
+		t.Errorf("Expected no delay before the first attempt, got %v", got)
+	}
+	for i := 0; i < 200; i++ {
+		got := agent.calculateRetryDelay(3)
+		if got < 3600*time.Millisecond || got > 4400*time.Millisecond {
+			t.Fatalf("Jittered delay %v outside 4s ± 10%%", got)
+		}
+	}
+}
+
 func TestExecutionAgent_OrderStatusMonitoring(t *testing.T) {
 	agent, _, mockBroker := setupTestExecutionAgent(t)
 	defer agent.Stop(context.Background())
 
+	mockBroker.SetSynchronous(true)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -269,9 +355,6 @@ func TestExecutionAgent_OrderStatusMonitoring(t *testing.T) {
 	// Track the order
 	agent.trackOrder(order, result.BrokerOrderID)
 
-	// Wait for status monitoring to run
-	time.Sleep(200 * time.Millisecond)
-
 	// Check order status
 	status, err := mockBroker.GetOrderStatus(ctx, result.BrokerOrderID)
 	if err != nil {
@@ -362,4 +445,933 @@ func TestExecutionAgent_ErrorScenarios(t *testing.T) {
 // SetErrorRate method for testing (interface to private field)
 func SetMockBrokerErrorRate(mb *brokers.MockBroker, rate float64) {
 	mb.SetErrorRate(rate)
-}
\ No newline at end of file
+}
+func TestExecutionAgent_TrackedOrderIsolatedFromCaller(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	defer agent.Stop(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	price := 150.0
+	order := createTestOrder()
+	order.Type = entities.OrderTypeLimit
+	order.Price = &price
+
+	result, err := mockBroker.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("Failed to place order: %v", err)
+	}
+	agent.trackOrder(order, result.BrokerOrderID)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Caller keeps mutating its original order
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			order.Quantity = float64(i)
+			*order.Price = float64(i)
+			order.Cancel()
+		}
+	}()
+
+	// Agent concurrently reads and publishes its tracked copy
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			agent.mu.RLock()
+			tracked := agent.orderTracker[result.BrokerOrderID].Order.Clone()
+			agent.mu.RUnlock()
+			agent.publishOrderEvent(ctx, "order.tracked", tracked, nil, nil)
+			agent.checkOrderStatus(result.BrokerOrderID)
+		}
+	}()
+
+	wg.Wait()
+
+	agent.mu.RLock()
+	tracked := agent.orderTracker[result.BrokerOrderID].Order
+	agent.mu.RUnlock()
+
+	if tracked.Quantity != 100 || *tracked.Price != 150.0 || tracked.Status != entities.OrderStatusApproved {
+		t.Errorf("Tracked order was mutated through the caller's pointer: %+v", tracked)
+	}
+
+	for _, msg := range mockBus.GetMessagesByTopic("order.tracked") {
+		event := msg.Message.(map[string]interface{})
+		if event["quantity"] != 100.0 {
+			t.Fatalf("Published event saw caller mutation: quantity=%v", event["quantity"])
+		}
+	}
+}
+
+func TestExecutionAgent_TrackerCapEvictsOldestOrders(t *testing.T) {
+	agent, mockBus, _ := setupTestExecutionAgent(t)
+	defer agent.Stop(context.Background())
+
+	agent.retryConfig.MaxTrackedOrders = 10
+	agent.retryConfig.AbandonAfter = time.Minute
+
+	// Flood the tracker with stale orders that never reach a terminal state
+	for i := 0; i < 25; i++ {
+		order := createTestOrder()
+		order.ID = entities.OrderID(fmt.Sprintf("stale-%d", i))
+		agent.trackOrder(order, fmt.Sprintf("broker-stale-%d", i))
+
+		agent.mu.Lock()
+		agent.orderTracker[fmt.Sprintf("broker-stale-%d", i)].SubmittedAt = time.Now().Add(-time.Hour + time.Duration(i)*time.Second)
+		agent.mu.Unlock()
+	}
+
+	agent.mu.RLock()
+	tracked := len(agent.orderTracker)
+	_, oldestKept := agent.orderTracker["broker-stale-0"]
+	_, newestKept := agent.orderTracker["broker-stale-24"]
+	agent.mu.RUnlock()
+
+	if tracked != 10 {
+		t.Fatalf("Expected tracker to hold the cap of 10 orders, got %d", tracked)
+	}
+	if oldestKept {
+		t.Error("Expected the oldest order to be evicted")
+	}
+	if !newestKept {
+		t.Error("Expected the newest order to be kept")
+	}
+
+	abandoned := mockBus.GetMessagesByTopic("order.abandoned")
+	if len(abandoned) != 15 {
+		t.Fatalf("Expected 15 order.abandoned events, got %d", len(abandoned))
+	}
+	event := abandoned[0].Message.(map[string]interface{})
+	if event["order_id"] != "stale-0" {
+		t.Errorf("Expected first abandoned order to be stale-0, got %v", event["order_id"])
+	}
+
+	// Recently submitted orders are never evicted, even above the cap
+	for i := 0; i < 5; i++ {
+		order := createTestOrder()
+		order.ID = entities.OrderID(fmt.Sprintf("fresh-%d", i))
+		agent.trackOrder(order, fmt.Sprintf("broker-fresh-%d", i))
+	}
+
+	agent.mu.RLock()
+	for i := 0; i < 5; i++ {
+		if _, exists := agent.orderTracker[fmt.Sprintf("broker-fresh-%d", i)]; !exists {
+			t.Errorf("Expected fresh order %d to remain tracked", i)
+		}
+	}
+	agent.mu.RUnlock()
+}
+
+// slowTrader ignores its context and takes delay to answer every PlaceOrder
+type slowTrader struct {
+	*brokers.MockBroker
+	delay time.Duration
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *slowTrader) PlaceOrder(ctx context.Context, order *entities.Order) (*interfaces.OrderResult, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+	return nil, &interfaces.BrokerError{Code: "CONNECTION_FAILED", Message: "no response"}
+}
+
+func TestExecutionAgent_OrderTimeoutRetriesThenGivesUp(t *testing.T) {
+	agent, _, mockBroker := setupTestExecutionAgent(t)
+	trader := &slowTrader{MockBroker: mockBroker, delay: 500 * time.Millisecond}
+	agent.trader = trader
+	agent.retryConfig.OrderTimeout = 20 * time.Millisecond
+	agent.retryConfig.MaxRetries = 2
+	agent.retryConfig.InitialDelay = time.Millisecond
+	agent.retryConfig.MaxDelay = time.Millisecond
+
+	start := time.Now()
+	_, err := agent.executeOrder(context.Background(), createTestOrder())
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "order execution timed out after 3 attempts") {
+		t.Fatalf("Expected timeout after 3 attempts, got %v", err)
+	}
+	if elapsed >= trader.delay {
+		t.Errorf("Expected the agent to give up before a single PlaceOrder returned, took %v", elapsed)
+	}
+
+	trader.mu.Lock()
+	calls := trader.calls
+	trader.mu.Unlock()
+	if calls != 3 {
+		t.Errorf("Expected each timed-out attempt to be retried, got %d PlaceOrder calls", calls)
+	}
+}
+
+// lateAckTrader acknowledges its first PlaceOrder only after delay, ignoring
+// the attempt's context, and answers later calls straight away
+type lateAckTrader struct {
+	*brokers.MockBroker
+	delay time.Duration
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (l *lateAckTrader) PlaceOrder(ctx context.Context, order *entities.Order) (*interfaces.OrderResult, error) {
+	l.mu.Lock()
+	l.calls++
+	first := l.calls == 1
+	l.mu.Unlock()
+
+	if first {
+		time.Sleep(l.delay)
+		return l.MockBroker.PlaceOrder(context.Background(), order)
+	}
+	return l.MockBroker.PlaceOrder(ctx, order)
+}
+
+// liveBrokerOrders waits for the broker to have seen want orders and returns
+// how many of them are still open
+func liveBrokerOrders(t *testing.T, mockBroker *brokers.MockBroker, want int) int {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		history := mockBroker.GetOrderHistory()
+		live := 0
+		for _, mockOrder := range history {
+			if mockOrder.Status != entities.OrderStatusCancelled && mockOrder.Status != entities.OrderStatusRejected {
+				live++
+			}
+		}
+		if len(history) == want && live <= 1 || time.Now().After(deadline) {
+			return live
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExecutionAgent_LateAcknowledgementLeavesOneLiveOrder(t *testing.T) {
+	restingBuy := func() *entities.Order {
+		price := 1.0
+		order := createTestOrder()
+		order.Type = entities.OrderTypeLimit
+		order.Price = &price
+		return order
+	}
+
+	t.Run("retry acknowledged first", func(t *testing.T) {
+		agent, _, mockBroker := setupTestExecutionAgent(t)
+		SetMockBrokerErrorRate(mockBroker, 0)
+		mockBroker.SetLatency(0)
+		if err := mockBroker.Connect(context.Background()); err != nil {
+			t.Fatalf("Failed to connect broker: %v", err)
+		}
+		trader := &lateAckTrader{MockBroker: mockBroker, delay: 100 * time.Millisecond}
+		agent.trader = trader
+		agent.retryConfig.OrderTimeout = 20 * time.Millisecond
+		agent.retryConfig.MaxRetries = 2
+		agent.retryConfig.InitialDelay = time.Millisecond
+		agent.retryConfig.MaxDelay = time.Millisecond
+
+		if _, err := agent.executeOrder(context.Background(), restingBuy()); err != nil {
+			t.Fatalf("Expected the retry to place the order, got %v", err)
+		}
+
+		if live := liveBrokerOrders(t, mockBroker, 2); live != 1 {
+			t.Errorf("Expected exactly one live broker order, got %d", live)
+		}
+	})
+
+	t.Run("late acknowledgement before retry", func(t *testing.T) {
+		agent, _, mockBroker := setupTestExecutionAgent(t)
+		SetMockBrokerErrorRate(mockBroker, 0)
+		mockBroker.SetLatency(0)
+		if err := mockBroker.Connect(context.Background()); err != nil {
+			t.Fatalf("Failed to connect broker: %v", err)
+		}
+		trader := &lateAckTrader{MockBroker: mockBroker, delay: 40 * time.Millisecond}
+		agent.trader = trader
+		agent.retryConfig.OrderTimeout = 20 * time.Millisecond
+		agent.retryConfig.MaxRetries = 2
+		agent.retryConfig.InitialDelay = 200 * time.Millisecond
+		agent.retryConfig.MaxDelay = 200 * time.Millisecond
+
+		if _, err := agent.executeOrder(context.Background(), restingBuy()); err != nil {
+			t.Fatalf("Expected the late acknowledgement to be used, got %v", err)
+		}
+
+		trader.mu.Lock()
+		calls := trader.calls
+		trader.mu.Unlock()
+		if calls != 1 {
+			t.Errorf("Expected no retry once the first attempt was acknowledged, got %d PlaceOrder calls", calls)
+		}
+		if live := liveBrokerOrders(t, mockBroker, 1); live != 1 {
+			t.Errorf("Expected exactly one live broker order, got %d", live)
+		}
+	})
+}
+
+func TestExecutionAgent_DuplicateMetricsKeepTheirOwnLabels(t *testing.T) {
+	agent, _, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+	SetMockBrokerErrorRate(mockBroker, 0)
+	mockBroker.SetLatency(0)
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	order := createTestOrder()
+	data, _ := json.Marshal(order)
+	for i := 0; i < 2; i++ {
+		if err := agent.handleApprovedOrder(ctx, data); err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+	}
+	agent.cancelDuplicateOrder(ctx, order, "BROKER-DUPLICATE")
+
+	families, err := agent.metrics.(*metrics.PrometheusMetrics).Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	counts := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			counts[family.GetName()] += metric.GetCounter().GetValue()
+		}
+	}
+	for _, name := range []string{"execution_agent_duplicate_orders_total", "execution_agent_duplicate_orders_cancelled_total"} {
+		if counts[name] != 1 {
+			t.Errorf("Expected %s to count once, got %v", name, counts[name])
+		}
+	}
+}
+
+func TestExecutionAgent_ReplaceOrder(t *testing.T) {
+	agent, _, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+
+	SetMockBrokerErrorRate(mockBroker, 0)
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	place := func(orderType entities.OrderType) string {
+		price := 150.0
+		order := createTestOrder()
+		order.Type = orderType
+		order.Price = &price
+		result, err := mockBroker.PlaceOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("Failed to place order: %v", err)
+		}
+		agent.trackOrder(order, result.BrokerOrderID)
+		return result.BrokerOrderID
+	}
+
+	t.Run("pending limit order is repriced", func(t *testing.T) {
+		brokerOrderID := place(entities.OrderTypeLimit)
+
+		if err := agent.ReplaceOrder(ctx, brokerOrderID, 148.5); err != nil {
+			t.Fatalf("ReplaceOrder failed: %v", err)
+		}
+
+		agent.mu.RLock()
+		tracked := *agent.orderTracker[brokerOrderID].Order.Price
+		agent.mu.RUnlock()
+		if tracked != 148.5 {
+			t.Errorf("Expected tracked price 148.5, got %v", tracked)
+		}
+
+		// The broker fills at the amended order, and the monitor sees it as usual
+		if err := mockBroker.ForceExecute(brokerOrderID); err != nil {
+			t.Fatalf("ForceExecute failed: %v", err)
+		}
+		if err := agent.checkOrderStatus(brokerOrderID); err != nil {
+			t.Fatalf("checkOrderStatus failed: %v", err)
+		}
+		agent.mu.RLock()
+		_, stillTracked := agent.orderTracker[brokerOrderID]
+		agent.mu.RUnlock()
+		if stillTracked {
+			t.Error("Expected the executed order to leave the tracker")
+		}
+	})
+
+	t.Run("executed order is rejected", func(t *testing.T) {
+		brokerOrderID := place(entities.OrderTypeLimit)
+		agent.mu.Lock()
+		agent.orderTracker[brokerOrderID].Status = entities.OrderStatusExecuted
+		agent.mu.Unlock()
+
+		var notReplaceable *OrderNotReplaceableError
+		if err := agent.ReplaceOrder(ctx, brokerOrderID, 149); !errors.As(err, &notReplaceable) {
+			t.Fatalf("Expected OrderNotReplaceableError, got %v", err)
+		}
+	})
+
+	t.Run("untracked order is rejected", func(t *testing.T) {
+		var notReplaceable *OrderNotReplaceableError
+		if err := agent.ReplaceOrder(ctx, "MOCK_unknown", 149); !errors.As(err, &notReplaceable) {
+			t.Fatalf("Expected OrderNotReplaceableError, got %v", err)
+		}
+	})
+
+	t.Run("market order is rejected", func(t *testing.T) {
+		mockBroker.SetSynchronous(true)
+		defer mockBroker.SetSynchronous(false)
+		brokerOrderID := place(entities.OrderTypeMarket)
+
+		var notReplaceable *OrderNotReplaceableError
+		if err := agent.ReplaceOrder(ctx, brokerOrderID, 149); !errors.As(err, &notReplaceable) {
+			t.Fatalf("Expected OrderNotReplaceableError, got %v", err)
+		}
+	})
+}
+
+func TestExecutionAgent_ExpiresStuckPendingOrders(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+	agent.retryConfig.MaxPendingAge = time.Minute
+
+	SetMockBrokerErrorRate(mockBroker, 0)
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	price := 150.0
+	order := createTestOrder()
+	order.Type = entities.OrderTypeLimit
+	order.Price = &price
+	result, err := mockBroker.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("Failed to place order: %v", err)
+	}
+
+	// A stuck order the broker still knows, one it has lost, and a fresh one
+	agent.trackOrder(order, result.BrokerOrderID)
+	agent.trackOrder(order, "MOCK_lost")
+	agent.trackOrder(order, "MOCK_fresh")
+	agent.mu.Lock()
+	agent.orderTracker[result.BrokerOrderID].SubmittedAt = time.Now().Add(-2 * time.Minute)
+	agent.orderTracker["MOCK_lost"].SubmittedAt = time.Now().Add(-2 * time.Minute)
+	agent.mu.Unlock()
+
+	agent.checkPendingOrders()
+
+	agent.mu.RLock()
+	_, stuckTracked := agent.orderTracker[result.BrokerOrderID]
+	_, lostTracked := agent.orderTracker["MOCK_lost"]
+	_, freshTracked := agent.orderTracker["MOCK_fresh"]
+	agent.mu.RUnlock()
+	if stuckTracked || lostTracked {
+		t.Error("Expected expired orders to leave the tracker")
+	}
+	if !freshTracked {
+		t.Error("Expected the fresh order to stay tracked")
+	}
+
+	status, err := mockBroker.GetOrderStatus(ctx, result.BrokerOrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != entities.OrderStatusCancelled {
+		t.Errorf("Expected the stuck order to be cancelled at the broker, got %s", status.Status)
+	}
+
+	events := make(map[string]map[string]interface{})
+	for _, msg := range mockBus.GetMessagesByTopic("order.expired") {
+		event := msg.Message.(map[string]interface{})
+		events[event["broker_order_id"].(string)] = event
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 order.expired events, got %d", len(events))
+	}
+	if stuck := events[result.BrokerOrderID]; stuck["status"] != string(entities.OrderStatusCancelled) || stuck["error"] != nil {
+		t.Errorf("Unexpected event for cancelled order: %v", stuck)
+	}
+	if lost := events["MOCK_lost"]; lost["error"] == nil {
+		t.Errorf("Expected the failed cancel to be reported, got %v", lost)
+	}
+}
+
+// concurrencyTrader records how many PlaceOrder calls overlap
+type concurrencyTrader struct {
+	*brokers.MockBroker
+
+	mu      sync.Mutex
+	current int
+	peak    int
+	placed  int
+}
+
+func (c *concurrencyTrader) PlaceOrder(ctx context.Context, order *entities.Order) (*interfaces.OrderResult, error) {
+	c.mu.Lock()
+	c.current++
+	c.placed++
+	if c.current > c.peak {
+		c.peak = c.current
+	}
+	id := fmt.Sprintf("CONC_%d", c.placed)
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+	return &interfaces.OrderResult{BrokerOrderID: id, Status: entities.OrderStatusPending, Timestamp: time.Now()}, nil
+}
+
+func TestExecutionAgent_BoundsConcurrentExecutions(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	trader := &concurrencyTrader{MockBroker: mockBroker}
+	agent.trader = trader
+	agent.SetMaxConcurrentExecutions(3)
+
+	ctx := context.Background()
+	if err := mockBus.Subscribe(ctx, "order.approved", agent.handleApprovedOrder); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	handler := mockBus.GetHandler("order.approved")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		order := createTestOrder()
+		order.ID = entities.OrderID(fmt.Sprintf("burst-%d", i))
+		data, _ := json.Marshal(order)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := handler(ctx, data); err != nil {
+				t.Errorf("Handler failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	trader.mu.Lock()
+	defer trader.mu.Unlock()
+	if trader.placed != 20 {
+		t.Errorf("Expected all 20 orders placed, got %d", trader.placed)
+	}
+	if trader.peak > 3 {
+		t.Errorf("Peak concurrency %d exceeded the limit of 3", trader.peak)
+	}
+	if agent.inFlight.Load() != 0 {
+		t.Errorf("Expected no orders in flight afterwards, got %d", agent.inFlight.Load())
+	}
+}
+
+func TestExecutionAgent_DecodesApprovedOrdersThroughSchemaRegistry(t *testing.T) {
+	agent, _, mockBroker := setupTestExecutionAgent(t)
+	trader := &concurrencyTrader{MockBroker: mockBroker}
+	agent.trader = trader
+
+	registry := messagebus.NewSchemaRegistry()
+	messagebus.RegisterOrderSchemas(registry)
+	agent.SetSchemaRegistry(registry)
+
+	ctx := context.Background()
+	bare, _ := json.Marshal(createTestOrder())
+	order := createTestOrder()
+	order.ID = "test-order-enveloped"
+	enveloped, _ := json.Marshal(messagebus.OrderMessage{Data: *order})
+
+	for _, data := range [][]byte{bare, enveloped} {
+		if err := agent.handleApprovedOrder(ctx, data); err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+	}
+
+	future := []byte(`{"schema_version": 99, "data": {"id": "order-future", "symbol": "AAPL"}}`)
+	if err := agent.handleApprovedOrder(ctx, future); !errors.Is(err, messagebus.ErrUnsupportedSchemaVersion) {
+		t.Errorf("Expected ErrUnsupportedSchemaVersion, got %v", err)
+	}
+
+	trader.mu.Lock()
+	defer trader.mu.Unlock()
+	if trader.placed != 2 {
+		t.Errorf("Expected the bare and enveloped orders placed, got %d", trader.placed)
+	}
+}
+
+func TestExecutionAgent_PublishesIncrementalPartialFills(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+
+	SetMockBrokerErrorRate(mockBroker, 0)
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	price := 150.0
+	order := createTestOrder()
+	order.Type = entities.OrderTypeLimit
+	order.Price = &price
+	result, err := mockBroker.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("Failed to place order: %v", err)
+	}
+	agent.trackOrder(order, result.BrokerOrderID)
+
+	// 30%, then 70%, then 100% of the order, with each status checked twice
+	for _, fill := range []float64{30, 40, 30} {
+		if err := mockBroker.FillPartially(result.BrokerOrderID, fill, 150); err != nil {
+			t.Fatalf("FillPartially failed: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := agent.checkOrderStatus(result.BrokerOrderID); err != nil {
+				t.Fatalf("checkOrderStatus failed: %v", err)
+			}
+		}
+	}
+
+	partials := mockBus.GetMessagesByTopic("order.partially_filled")
+	if len(partials) != 2 {
+		t.Fatalf("Expected 2 partial fill events, got %d", len(partials))
+	}
+	want := []struct{ fill, filled, remaining float64 }{{30, 30, 70}, {40, 70, 30}}
+	for i, msg := range partials {
+		event := msg.Message.(PartialFillMessage)
+		if event.FillQuantity != want[i].fill || event.FilledQuantity != want[i].filled || event.RemainingQuantity != want[i].remaining {
+			t.Errorf("Partial fill %d: expected %+v, got %+v", i, want[i], event)
+		}
+	}
+
+	executed := mockBus.GetMessagesByTopic("order.executed")
+	if len(executed) != 1 {
+		t.Fatalf("Expected exactly one order.executed event, got %d", len(executed))
+	}
+	if event := executed[0].Message.(ExecutedOrderMessage); event.ExecutedQty != 100 {
+		t.Errorf("Expected the executed event to carry the full 100 shares, got %v", event.ExecutedQty)
+	}
+
+	agent.mu.RLock()
+	_, tracked := agent.orderTracker[result.BrokerOrderID]
+	agent.mu.RUnlock()
+	if tracked {
+		t.Error("Expected the fully filled order to leave the tracker")
+	}
+}
+
+func TestExecutionAgent_RedeliveredApprovalPlacedOnce(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	trader := &concurrencyTrader{MockBroker: mockBroker}
+	agent.trader = trader
+
+	ctx := context.Background()
+	if err := mockBus.Subscribe(ctx, "order.approved", agent.handleApprovedOrder); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	handler := mockBus.GetHandler("order.approved")
+
+	data, err := json.Marshal(createTestOrder())
+	if err != nil {
+		t.Fatalf("Failed to marshal order: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := handler(ctx, data); err != nil {
+			t.Fatalf("Delivery %d failed: %v", i+1, err)
+		}
+	}
+
+	trader.mu.Lock()
+	placed := trader.placed
+	trader.mu.Unlock()
+	if placed != 1 {
+		t.Errorf("Expected exactly 1 PlaceOrder for a redelivered order, got %d", placed)
+	}
+
+	// Once the window has passed the order ID is forgotten
+	agent.SetDedupWindow(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if err := handler(ctx, data); err != nil {
+		t.Fatalf("Delivery after the window failed: %v", err)
+	}
+	trader.mu.Lock()
+	defer trader.mu.Unlock()
+	if trader.placed != 2 {
+		t.Errorf("Expected the order to be placed again after the dedup window, got %d placements", trader.placed)
+	}
+}
+
+func TestExecutionAgent_RedeliveryAfterCancelledSlotWaitIsPlaced(t *testing.T) {
+	agent, _, mockBroker := setupTestExecutionAgent(t)
+	trader := &concurrencyTrader{MockBroker: mockBroker}
+	agent.trader = trader
+	agent.SetMaxConcurrentExecutions(1)
+
+	data, err := json.Marshal(createTestOrder())
+	if err != nil {
+		t.Fatalf("Failed to marshal order: %v", err)
+	}
+
+	// Hold the only slot so the first delivery waits until its context ends
+	agent.executionSlots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := agent.handleApprovedOrder(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the slot wait to be cancelled, got %v", err)
+	}
+	<-agent.executionSlots
+
+	if err := agent.handleApprovedOrder(context.Background(), data); err != nil {
+		t.Fatalf("Redelivery failed: %v", err)
+	}
+
+	trader.mu.Lock()
+	defer trader.mu.Unlock()
+	if trader.placed != 1 {
+		t.Errorf("Expected exactly 1 PlaceOrder after the redelivery, got %d", trader.placed)
+	}
+}
+
+func TestExecutionAgent_TrackedOrderSnapshots(t *testing.T) {
+	agent, _, _ := setupTestExecutionAgent(t)
+
+	first := createTestOrder()
+	second := createTestOrder()
+	second.ID = "test-order-456"
+	second.Symbol = "MSFT"
+
+	agent.trackOrder(first, "BROKER_1")
+	agent.trackOrder(second, "BROKER_2")
+	agent.mu.Lock()
+	agent.orderTracker["BROKER_2"].SubmittedAt = agent.orderTracker["BROKER_1"].SubmittedAt.Add(time.Second)
+	agent.orderTracker["BROKER_2"].RetryCount = 2
+	agent.mu.Unlock()
+
+	snapshots := agent.GetTrackedOrders()
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 tracked orders, got %d", len(snapshots))
+	}
+	want := []struct {
+		orderID       entities.OrderID
+		brokerOrderID string
+		symbol        entities.Symbol
+		retries       int
+	}{
+		{"test-order-123", "BROKER_1", "AAPL", 0},
+		{"test-order-456", "BROKER_2", "MSFT", 2},
+	}
+	for i, w := range want {
+		got := snapshots[i]
+		if got.OrderID != w.orderID || got.BrokerOrderID != w.brokerOrderID || got.Symbol != w.symbol ||
+			got.RetryCount != w.retries || got.Status != entities.OrderStatusPending || got.SubmittedAt.IsZero() {
+			t.Errorf("Snapshot %d: unexpected %+v", i, got)
+		}
+	}
+
+	// Mutating the returned snapshots must not reach the tracker
+	snapshots[0].Status = entities.OrderStatusCancelled
+	snapshots[0].RetryCount = 99
+	snapshots = append(snapshots[:0], snapshots[1:]...)
+
+	got, ok := agent.GetTrackedOrder("BROKER_1")
+	if !ok {
+		t.Fatal("Expected BROKER_1 to still be tracked")
+	}
+	if got.Status != entities.OrderStatusPending || got.RetryCount != 0 {
+		t.Errorf("Tracker state changed through a snapshot: %+v", got)
+	}
+	if len(agent.GetTrackedOrders()) != 2 {
+		t.Error("Expected both orders to remain tracked")
+	}
+	if _, ok := agent.GetTrackedOrder("missing"); ok {
+		t.Error("Expected no snapshot for an untracked order")
+	}
+}
+
+type recordedObservation struct {
+	name   string
+	value  float64
+	labels map[string]string
+}
+
+// recordingMetrics keeps counters and histogram observations for assertions
+type recordingMetrics struct {
+	mu           sync.Mutex
+	counters     []recordedObservation
+	observations []recordedObservation
+}
+
+func (m *recordingMetrics) IncrementCounter(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters = append(m.counters, recordedObservation{name: name, value: 1, labels: labels})
+}
+
+func (m *recordingMetrics) RecordDuration(name string, duration float64, labels map[string]string) {}
+
+func (m *recordingMetrics) SetGauge(name string, value float64, labels map[string]string) {}
+
+func (m *recordingMetrics) ObserveValue(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observations = append(m.observations, recordedObservation{name: name, value: value, labels: labels})
+}
+
+func (m *recordingMetrics) named(records []recordedObservation, name string) []recordedObservation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var matched []recordedObservation
+	for _, record := range records {
+		if record.name == name {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+func TestExecutionAgent_RecordsRetriesAndBrokerErrorCodes(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	recorder := &recordingMetrics{}
+	agent.metrics = recorder
+	agent.retryConfig.InitialDelay = time.Millisecond
+	agent.retryConfig.MaxDelay = time.Millisecond
+	mockBroker.SetLatency(0)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
+	ctx := context.Background()
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+	if err := mockBus.Subscribe(ctx, "order.approved", agent.handleApprovedOrder); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	handler := mockBus.GetHandler("order.approved")
+
+	// Two transient failures, then the submission goes through
+	mockBroker.FailNextOrders(2, "TEMPORARY_ERROR")
+	data, _ := json.Marshal(createTestOrder())
+	if err := handler(ctx, data); err != nil {
+		t.Fatalf("Expected the order to succeed on its third attempt, got %v", err)
+	}
+
+	retries := recorder.named(recorder.observations, "execution_agent_order_retries")
+	if len(retries) != 1 || retries[0].value != 2 {
+		t.Fatalf("Expected one retries observation of 2, got %+v", retries)
+	}
+	if retries[0].labels["broker"] != "TestBroker" {
+		t.Errorf("Expected the broker label, got %v", retries[0].labels)
+	}
+
+	// A non-retryable rejection is counted with its broker code
+	mockBroker.FailNextOrders(1, "ORDER_REJECTED")
+	rejected := createTestOrder()
+	rejected.ID = "test-order-rejected"
+	data, _ = json.Marshal(rejected)
+	if err := handler(ctx, data); err == nil {
+		t.Fatal("Expected the rejected order to fail")
+	}
+
+	errorCounts := recorder.named(recorder.counters, "execution_agent_errors")
+	if len(errorCounts) != 1 {
+		t.Fatalf("Expected one execution error, got %+v", errorCounts)
+	}
+	if labels := errorCounts[0].labels; labels["type"] != "order_execution_failed" || labels["broker_code"] != "ORDER_REJECTED" {
+		t.Errorf("Expected order_execution_failed with broker_code ORDER_REJECTED, got %v", labels)
+	}
+	if n := len(recorder.named(recorder.observations, "execution_agent_order_retries")); n != 1 {
+		t.Errorf("Expected failed submissions not to observe retries, got %d observations", n)
+	}
+}
+
+func TestExecutionAgent_StopOrderFillsOnceTriggered(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	defer agent.Stop(context.Background())
+
+	mockBroker.SetSynchronous(true)
+	mockBroker.SetFillPriceModel(brokers.LastPriceModel{Fallback: 100})
+	mockBroker.SetMarketPrice("AAPL", 150)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := agent.Start(ctx); err != nil {
+		t.Fatalf("Failed to start execution agent: %v", err)
+	}
+
+	stop := 155.0
+	order := createTestOrder()
+	order.Type = entities.OrderTypeStop
+	order.StopPrice = &stop
+	orderData, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("Failed to marshal order: %v", err)
+	}
+	if err := mockBus.GetHandler("order.approved")(ctx, orderData); err != nil {
+		t.Fatalf("Failed to handle approved stop order: %v", err)
+	}
+
+	if executed := mockBus.GetMessagesByTopic("order.executed"); len(executed) != 0 {
+		t.Fatalf("Expected the stop to rest below its trigger, got %d executions", len(executed))
+	}
+	agent.mu.RLock()
+	var brokerOrderID string
+	for id := range agent.orderTracker {
+		brokerOrderID = id
+	}
+	agent.mu.RUnlock()
+	if brokerOrderID == "" {
+		t.Fatal("Expected the resting stop order to be tracked")
+	}
+
+	// 154 does not reach the stop; 156 crosses it
+	for _, price := range []float64{154, 156} {
+		mockBroker.SetMarketPrice("AAPL", price)
+		if err := agent.checkOrderStatus(brokerOrderID); err != nil {
+			t.Fatalf("checkOrderStatus failed: %v", err)
+		}
+	}
+
+	executed := mockBus.GetMessagesByTopic("order.executed")
+	if len(executed) != 1 {
+		t.Fatalf("Expected the triggered stop to fill once, got %d executions", len(executed))
+	}
+	if event := executed[0].Message.(ExecutedOrderMessage); event.ExecutedPrice != 156 || event.ExecutedQty != 100 {
+		t.Errorf("Expected 100 filled at 156, got %v at %v", event.ExecutedQty, event.ExecutedPrice)
+	}
+}
+
+func TestExecutionAgent_InsufficientFundsIsNotRetried(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	recorder := &recordingMetrics{}
+	agent.metrics = recorder
+	agent.retryConfig.InitialDelay = time.Millisecond
+	agent.retryConfig.MaxDelay = time.Millisecond
+	mockBroker.SetLatency(0)
+	SetMockBrokerErrorRate(mockBroker, 0)
+	mockBroker.SetCashBalance(100)
+
+	ctx := context.Background()
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+	if err := mockBus.Subscribe(ctx, "order.approved", agent.handleApprovedOrder); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	data, _ := json.Marshal(createTestOrder())
+	err := mockBus.GetHandler("order.approved")(ctx, data)
+	if err == nil || !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Fatalf("Expected a single failed attempt, got %v", err)
+	}
+
+	errorCounts := recorder.named(recorder.counters, "execution_agent_errors")
+	if len(errorCounts) != 1 || errorCounts[0].labels["broker_code"] != "INSUFFICIENT_FUNDS" {
+		t.Errorf("Expected one INSUFFICIENT_FUNDS error, got %+v", errorCounts)
+	}
+}
@@ -0,0 +1,36 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultMacroValues back the collector until a real MacroDataProvider is set
+var defaultMacroValues = map[string]float64{
+	"GDP":               2.1,
+	"INFLATION_RATE":    3.2,
+	"UNEMPLOYMENT_RATE": 4.1,
+	"INTEREST_RATE":     5.25,
+}
+
+// StaticMacroDataProvider serves fixed indicator values keyed by name,
+// whatever the country
+type StaticMacroDataProvider struct {
+	values map[string]float64
+}
+
+func NewStaticMacroDataProvider(values map[string]float64) *StaticMacroDataProvider {
+	copied := make(map[string]float64, len(values))
+	for name, value := range values {
+		copied[name] = value
+	}
+	return &StaticMacroDataProvider{values: copied}
+}
+
+func (p *StaticMacroDataProvider) GetIndicatorValue(ctx context.Context, name, country string) (float64, error) {
+	value, ok := p.values[name]
+	if !ok {
+		return 0, fmt.Errorf("no value for macro indicator %s (%s)", name, country)
+	}
+	return value, nil
+}
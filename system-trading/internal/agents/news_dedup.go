@@ -0,0 +1,85 @@
+package agents
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// newsDedup remembers recently seen article keys so the same article arriving
+// from both polling and the push subscription is published once. It holds at
+// most capacity keys, evicting the least recently seen, and forgets a key once
+// window has passed since it was seen.
+type newsDedup struct {
+	capacity int
+	window   time.Duration
+	order    *list.List
+	entries  map[string]*list.Element
+	mu       sync.Mutex
+}
+
+type seenArticle struct {
+	key    string
+	seenAt time.Time
+}
+
+func newNewsDedup(capacity int, window time.Duration) *newsDedup {
+	return &newsDedup{
+		capacity: capacity,
+		window:   window,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Add records key as seen at now and reports whether it was not already seen
+// within the window
+func (d *newsDedup) Add(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if element, exists := d.entries[key]; exists {
+		seen := element.Value.(*seenArticle)
+		if now.Sub(seen.seenAt) < d.window {
+			return false
+		}
+		seen.seenAt = now
+		d.order.MoveToBack(element)
+		return true
+	}
+
+	d.entries[key] = d.order.PushBack(&seenArticle{key: key, seenAt: now})
+	for d.order.Len() > d.capacity {
+		d.removeLocked(d.order.Front())
+	}
+	return true
+}
+
+// Remove forgets key so a later delivery of the article is processed again
+func (d *newsDedup) Remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if element, exists := d.entries[key]; exists {
+		d.removeLocked(element)
+	}
+}
+
+func (d *newsDedup) removeLocked(element *list.Element) {
+	d.order.Remove(element)
+	delete(d.entries, element.Value.(*seenArticle).key)
+}
+
+// articleKey identifies an article by its ID, or by a hash of its source,
+// title and content when the provider sends none
+func articleKey(article *entities.NewsArticle) string {
+	if article.ID != "" {
+		return article.ID
+	}
+	sum := sha256.Sum256([]byte(article.Source + "\x00" + article.Title + "\x00" + article.Content))
+	return hex.EncodeToString(sum[:])
+}
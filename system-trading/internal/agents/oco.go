@@ -0,0 +1,87 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/interfaces"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// cancelOCOSibling cancels the other half of a one-cancels-other pair once
+// order has filled. A sibling not yet placed is remembered and dropped when it
+// arrives.
+func (ea *ExecutionAgent) cancelOCOSibling(ctx context.Context, order *entities.Order) {
+	siblingID := order.OCOSiblingID
+	if siblingID == "" {
+		return
+	}
+
+	ea.mu.Lock()
+	var sibling *ExecutionContext
+	for _, execCtx := range ea.orderTracker {
+		if execCtx.Order.ID == siblingID {
+			sibling = execCtx
+			break
+		}
+	}
+	if sibling == nil {
+		// A fill reported on placement is seen again by the status check, so
+		// this can record siblings that were already cancelled; prune old ones
+		now := time.Now()
+		for id, at := range ea.ocoCancelled {
+			if now.Sub(at) > ea.dedupWindow {
+				delete(ea.ocoCancelled, id)
+			}
+		}
+		ea.ocoCancelled[siblingID] = now
+		ea.mu.Unlock()
+		return
+	}
+	delete(ea.orderTracker, sibling.BrokerOrderID)
+	siblingOrder := sibling.Order.Clone()
+	ea.mu.Unlock()
+
+	if err := ea.trader.CancelOrder(ctx, sibling.BrokerOrderID); err != nil {
+		// The sibling may have filled as well; let the status check report it
+		ea.mu.Lock()
+		ea.orderTracker[sibling.BrokerOrderID] = sibling
+		ea.mu.Unlock()
+
+		ea.logger.Error("Failed to cancel OCO sibling",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+			ifs.Field{Key: "sibling_order_id", Value: string(siblingID)},
+			ifs.Field{Key: "broker_order_id", Value: sibling.BrokerOrderID},
+			ifs.Field{Key: "error", Value: err.Error()},
+		)
+		ea.recordError("oco_cancel_failed", err)
+		return
+	}
+
+	ea.logger.Info("Cancelled OCO sibling",
+		ifs.Field{Key: "order_id", Value: string(order.ID)},
+		ifs.Field{Key: "sibling_order_id", Value: string(siblingID)},
+		ifs.Field{Key: "broker_order_id", Value: sibling.BrokerOrderID},
+	)
+	ea.metrics.IncrementCounter("execution_agent_oco_cancels", map[string]string{
+		"symbol": string(order.Symbol),
+		"broker": ea.trader.GetBrokerName(),
+	})
+	ea.publishOrderEvent(ctx, "order.cancelled", siblingOrder, &interfaces.OrderStatus{
+		BrokerOrderID: sibling.BrokerOrderID,
+		Status:        entities.OrderStatusCancelled,
+		LastUpdate:    sibling.LastStatusCheck,
+	}, nil)
+}
+
+// takeOCOCancelled reports whether orderID's sibling already filled, forgetting
+// it either way
+func (ea *ExecutionAgent) takeOCOCancelled(orderID entities.OrderID) bool {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	_, cancelled := ea.ocoCancelled[orderID]
+	delete(ea.ocoCancelled, orderID)
+	return cancelled
+}
@@ -0,0 +1,150 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/brokers"
+)
+
+// bracketExits returns a linked take-profit limit at 160 and stop-loss stop at
+// 140 closing a 100 share AAPL long
+func bracketExits() (takeProfit, stopLoss *entities.Order) {
+	takeProfitPrice, stopLossPrice := 160.0, 140.0
+	takeProfit = entities.NewOrder("AAPL", entities.OrderSideSell, entities.OrderTypeLimit, 100, &takeProfitPrice)
+	stopLoss = entities.NewOrder("AAPL", entities.OrderSideSell, entities.OrderTypeStop, 100, nil)
+	stopLoss.StopPrice = &stopLossPrice
+	takeProfit.ID, stopLoss.ID = "take-profit", "stop-loss"
+	takeProfit.OCOSiblingID, stopLoss.OCOSiblingID = stopLoss.ID, takeProfit.ID
+	return takeProfit, stopLoss
+}
+
+func TestExecutionAgent_OCOFillCancelsSibling(t *testing.T) {
+	tests := []struct {
+		name      string
+		fill      func(t *testing.T, broker *brokers.MockBroker, brokerOrderIDs map[entities.OrderID]string)
+		filled    entities.OrderID
+		cancelled entities.OrderID
+	}{
+		{
+			name: "take-profit hit cancels stop-loss",
+			fill: func(t *testing.T, broker *brokers.MockBroker, brokerOrderIDs map[entities.OrderID]string) {
+				if err := broker.ForceExecute(brokerOrderIDs["take-profit"]); err != nil {
+					t.Fatalf("ForceExecute failed: %v", err)
+				}
+			},
+			filled:    "take-profit",
+			cancelled: "stop-loss",
+		},
+		{
+			name: "stop-loss hit cancels take-profit",
+			fill: func(t *testing.T, broker *brokers.MockBroker, brokerOrderIDs map[entities.OrderID]string) {
+				broker.SetMarketPrice("AAPL", 139)
+			},
+			filled:    "stop-loss",
+			cancelled: "take-profit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+			defer agent.Stop(context.Background())
+
+			mockBroker.SetSynchronous(true)
+			mockBroker.SetFillPriceModel(brokers.LastPriceModel{Fallback: 150})
+			mockBroker.SetMarketPrice("AAPL", 150)
+			SetMockBrokerErrorRate(mockBroker, 0)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := agent.Start(ctx); err != nil {
+				t.Fatalf("Failed to start execution agent: %v", err)
+			}
+
+			takeProfit, stopLoss := bracketExits()
+			for _, order := range []*entities.Order{takeProfit, stopLoss} {
+				data, err := json.Marshal(order)
+				if err != nil {
+					t.Fatalf("Failed to marshal order: %v", err)
+				}
+				if err := mockBus.GetHandler("order.approved")(ctx, data); err != nil {
+					t.Fatalf("Failed to handle approved %s: %v", order.ID, err)
+				}
+			}
+
+			brokerOrderIDs := make(map[entities.OrderID]string)
+			for _, tracked := range agent.GetTrackedOrders() {
+				brokerOrderIDs[tracked.OrderID] = tracked.BrokerOrderID
+			}
+			if len(brokerOrderIDs) != 2 {
+				t.Fatalf("Expected both exits resting at the broker, got %v", brokerOrderIDs)
+			}
+
+			tt.fill(t, mockBroker, brokerOrderIDs)
+			if err := agent.checkOrderStatus(brokerOrderIDs[tt.filled]); err != nil {
+				t.Fatalf("checkOrderStatus failed: %v", err)
+			}
+
+			status, err := mockBroker.GetOrderStatus(ctx, brokerOrderIDs[tt.cancelled])
+			if err != nil {
+				t.Fatalf("GetOrderStatus failed: %v", err)
+			}
+			if status.Status != entities.OrderStatusCancelled {
+				t.Errorf("Expected %s cancelled at the broker, got %s", tt.cancelled, status.Status)
+			}
+			if tracked := agent.GetTrackedOrders(); len(tracked) != 0 {
+				t.Errorf("Expected neither exit still tracked, got %+v", tracked)
+			}
+
+			executed := mockBus.GetMessagesByTopic("order.executed")
+			if len(executed) != 1 || executed[0].Message.(ExecutedOrderMessage).OrderID != string(tt.filled) {
+				t.Fatalf("Expected only %s to execute, got %+v", tt.filled, executed)
+			}
+			cancelled := mockBus.GetMessagesByTopic("order.cancelled")
+			if len(cancelled) != 1 || cancelled[0].Message.(map[string]interface{})["order_id"] != string(tt.cancelled) {
+				t.Errorf("Expected a cancellation event for %s, got %+v", tt.cancelled, cancelled)
+			}
+		})
+	}
+}
+
+func TestExecutionAgent_OCOSiblingFilledBeforePlacementIsDropped(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	defer agent.Stop(context.Background())
+
+	mockBroker.SetSynchronous(true)
+	mockBroker.SetFillPriceModel(brokers.LastPriceModel{Fallback: 150})
+	mockBroker.SetMarketPrice("AAPL", 130)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := agent.Start(ctx); err != nil {
+		t.Fatalf("Failed to start execution agent: %v", err)
+	}
+
+	// The stop-loss is already through its trigger and fills on arrival
+	takeProfit, stopLoss := bracketExits()
+	for _, order := range []*entities.Order{stopLoss, takeProfit} {
+		data, err := json.Marshal(order)
+		if err != nil {
+			t.Fatalf("Failed to marshal order: %v", err)
+		}
+		if err := mockBus.GetHandler("order.approved")(ctx, data); err != nil {
+			t.Fatalf("Failed to handle approved %s: %v", order.ID, err)
+		}
+	}
+
+	for _, tracked := range agent.GetTrackedOrders() {
+		if tracked.OrderID == takeProfit.ID {
+			t.Errorf("Expected the take-profit never to reach the broker, got %+v", tracked)
+		}
+	}
+	if cancelled := mockBus.GetMessagesByTopic("order.cancelled"); len(cancelled) != 1 {
+		t.Errorf("Expected the take-profit reported cancelled, got %d events", len(cancelled))
+	}
+}
@@ -0,0 +1,212 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/interfaces"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// QuoteSource provides the top of the book a peg follows
+type QuoteSource interface {
+	// BestBidAsk returns the best bid and ask; ok is false when either is missing
+	BestBidAsk(symbol string) (bid, ask float64, ok bool)
+}
+
+type PegConfig struct {
+	// RepriceInterval is how often the peg compares its price with the book
+	RepriceInterval time.Duration
+	// MaxChase is how far the peg may move from its first price before it stops
+	// chasing; zero disables the limit
+	MaxChase float64
+	// CrossOnMaxChase cancels the peg and sends the unfilled remainder as a
+	// market order once MaxChase is exceeded, instead of leaving it resting
+	CrossOnMaxChase bool
+	Clock           ifs.Clock
+}
+
+// PegResult describes how a pegged order was worked
+type PegResult struct {
+	OrderID       entities.OrderID `json:"order_id"`
+	BrokerOrderID string           `json:"broker_order_id"`
+	// Prices lists every limit price the peg rested at, in order
+	Prices         []float64            `json:"prices"`
+	FilledQuantity float64              `json:"filled_quantity"`
+	Remaining      float64              `json:"remaining"`
+	Status         entities.OrderStatus `json:"status"`
+	// Crossed is set when the remainder was sent as MarketOrderID
+	Crossed       bool   `json:"crossed"`
+	MarketOrderID string `json:"market_order_id,omitempty"`
+}
+
+// PegExecutor works a limit order pegged to the near side of the book: buys
+// rest at the best bid and sells at the best ask, repriced as the book moves.
+type PegExecutor struct {
+	trader  interfaces.Trader
+	quotes  QuoteSource
+	logger  ifs.Logger
+	metrics ifs.MetricsCollector
+	config  PegConfig
+	clock   ifs.Clock
+}
+
+func NewPegExecutor(trader interfaces.Trader, quotes QuoteSource, logger ifs.Logger, metrics ifs.MetricsCollector, config PegConfig) *PegExecutor {
+	if config.RepriceInterval <= 0 {
+		config.RepriceInterval = time.Second
+	}
+
+	pegClock := config.Clock
+	if pegClock == nil {
+		pegClock = clock.NewRealClock()
+	}
+
+	return &PegExecutor{
+		trader:  trader,
+		quotes:  quotes,
+		logger:  logger,
+		metrics: metrics,
+		config:  config,
+		clock:   pegClock,
+	}
+}
+
+// Execute places order as a pegged limit order and reprices it every
+// RepriceInterval until it fills, is cancelled at the broker, or the book moves
+// more than MaxChase from the first peg price.
+func (p *PegExecutor) Execute(ctx context.Context, order *entities.Order) (*PegResult, error) {
+	start, ok := p.pegPrice(order)
+	if !ok {
+		return nil, fmt.Errorf("no quote to peg %s to", order.Symbol)
+	}
+
+	child := order.Clone()
+	child.Type = entities.OrderTypeLimit
+	child.Price = &start
+	placed, err := p.trader.PlaceOrder(ctx, child)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place pegged order: %w", err)
+	}
+
+	result := &PegResult{
+		OrderID:       order.ID,
+		BrokerOrderID: placed.BrokerOrderID,
+		Prices:        []float64{start},
+		Remaining:     order.Quantity,
+		Status:        placed.Status,
+	}
+	current := start
+
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-p.clock.After(p.config.RepriceInterval):
+		}
+
+		status, err := p.trader.GetOrderStatus(ctx, result.BrokerOrderID)
+		if err != nil {
+			return result, fmt.Errorf("failed to get pegged order status: %w", err)
+		}
+		p.recordFills(result, order.Quantity, status)
+		if result.Remaining <= 0 || isTerminalStatus(status.Status) {
+			return result, nil
+		}
+
+		target, ok := p.pegPrice(order)
+		if !ok || target == current {
+			continue
+		}
+		if p.config.MaxChase > 0 && math.Abs(target-start) > p.config.MaxChase {
+			return p.stopChasing(ctx, order, result, target)
+		}
+
+		if err := p.trader.ReplaceOrder(ctx, result.BrokerOrderID, target); err != nil {
+			// The order may have filled in the meantime; the next status check tells
+			p.logger.Warn("Failed to reprice pegged order",
+				ifs.Field{Key: "broker_order_id", Value: result.BrokerOrderID},
+				ifs.Field{Key: "price", Value: target},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+			continue
+		}
+
+		current = target
+		result.Prices = append(result.Prices, target)
+		p.metrics.IncrementCounter("peg_reprices", map[string]string{
+			"symbol": string(order.Symbol),
+		})
+	}
+}
+
+// stopChasing leaves the peg resting at its last price or, with
+// CrossOnMaxChase, cancels it and sends the remainder at market
+func (p *PegExecutor) stopChasing(ctx context.Context, order *entities.Order, result *PegResult, target float64) (*PegResult, error) {
+	p.logger.Warn("Pegged order exceeded max chase",
+		ifs.Field{Key: "order_id", Value: string(order.ID)},
+		ifs.Field{Key: "start_price", Value: result.Prices[0]},
+		ifs.Field{Key: "market_price", Value: target},
+		ifs.Field{Key: "cross", Value: p.config.CrossOnMaxChase},
+	)
+	p.metrics.IncrementCounter("peg_max_chase_exceeded", map[string]string{
+		"symbol": string(order.Symbol),
+		"cross":  fmt.Sprintf("%t", p.config.CrossOnMaxChase),
+	})
+
+	if !p.config.CrossOnMaxChase {
+		return result, nil
+	}
+
+	if err := p.trader.CancelOrder(ctx, result.BrokerOrderID); err != nil {
+		return result, fmt.Errorf("failed to cancel pegged order before crossing: %w", err)
+	}
+	// Read the final fills after the cancel so a late fill is not sent twice
+	status, err := p.trader.GetOrderStatus(ctx, result.BrokerOrderID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get pegged order status: %w", err)
+	}
+	p.recordFills(result, order.Quantity, status)
+	if result.Remaining <= 0 {
+		return result, nil
+	}
+
+	market := order.Clone()
+	market.Type = entities.OrderTypeMarket
+	market.Price = nil
+	market.Quantity = result.Remaining
+	placed, err := p.trader.PlaceOrder(ctx, market)
+	if err != nil {
+		return result, fmt.Errorf("failed to cross pegged order to market: %w", err)
+	}
+
+	result.Crossed = true
+	result.MarketOrderID = placed.BrokerOrderID
+	result.Status = placed.Status
+	if placed.ExecutedQty != nil {
+		result.FilledQuantity += *placed.ExecutedQty
+		result.Remaining = math.Max(order.Quantity-result.FilledQuantity, 0)
+	}
+	return result, nil
+}
+
+func (p *PegExecutor) recordFills(result *PegResult, quantity float64, status *interfaces.OrderStatus) {
+	result.FilledQuantity, _ = filledQuantity(status)
+	result.Remaining = math.Max(quantity-result.FilledQuantity, 0)
+	result.Status = status.Status
+}
+
+// pegPrice is the best bid for buys and the best ask for sells
+func (p *PegExecutor) pegPrice(order *entities.Order) (float64, bool) {
+	bid, ask, ok := p.quotes.BestBidAsk(string(order.Symbol))
+	if !ok {
+		return 0, false
+	}
+	if order.Side == entities.OrderSideSell {
+		return ask, true
+	}
+	return bid, true
+}
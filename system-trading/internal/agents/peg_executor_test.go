@@ -0,0 +1,117 @@
+package agents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/brokers"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+)
+
+func setBestBid(broker *brokers.MockBroker, bid float64) {
+	broker.SetOrderBook("AAPL", brokers.OrderBook{
+		Bids: []brokers.BookLevel{{Price: bid, Quantity: 500}},
+		Asks: []brokers.BookLevel{{Price: bid + 0.1, Quantity: 500}},
+	})
+}
+
+func TestPegExecutor_ChasesToMaxDistanceThenCrosses(t *testing.T) {
+	tests := []struct {
+		name       string
+		cross      bool
+		wantStatus entities.OrderStatus
+		wantPegEnd entities.OrderStatus
+	}{
+		{"crosses to market", true, entities.OrderStatusExecuted, entities.OrderStatusCancelled},
+		{"stops chasing and rests", false, entities.OrderStatusPending, entities.OrderStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, _, broker := setupTestExecutionAgent(t)
+			broker.SetErrorRate(0)
+			broker.SetLatency(0)
+			broker.SetSynchronous(true)
+			broker.SetFillPriceModel(brokers.BookWalkModel{})
+			if err := broker.Connect(context.Background()); err != nil {
+				t.Fatalf("Failed to connect broker: %v", err)
+			}
+			setBestBid(broker, 100)
+
+			fakeClock := clock.NewFakeClock(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+			peg := NewPegExecutor(broker, broker, agent.logger, agent.metrics, PegConfig{
+				RepriceInterval: time.Second,
+				MaxChase:        0.5,
+				CrossOnMaxChase: tt.cross,
+				Clock:           fakeClock,
+			})
+
+			order := createTestOrder()
+			order.Type = entities.OrderTypeLimit
+			done := make(chan *PegResult, 1)
+			go func() {
+				result, err := peg.Execute(context.Background(), order)
+				if err != nil {
+					t.Errorf("Execute failed: %v", err)
+				}
+				done <- result
+			}()
+
+			// The bid walks up 0.2, then to the 0.5 limit, then past it
+			for _, bid := range []float64{100.2, 100.5, 100.8} {
+				waitForClockWaiter(t, fakeClock)
+				setBestBid(broker, bid)
+				fakeClock.Advance(time.Second)
+			}
+
+			var result *PegResult
+			select {
+			case result = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Execute did not return")
+			}
+			if result == nil {
+				t.FailNow()
+			}
+
+			want := []float64{100, 100.2, 100.5}
+			if len(result.Prices) != len(want) {
+				t.Fatalf("Expected peg prices %v, got %v", want, result.Prices)
+			}
+			for i := range want {
+				if result.Prices[i] != want[i] {
+					t.Errorf("Expected peg prices %v, got %v", want, result.Prices)
+					break
+				}
+			}
+
+			if result.Crossed != tt.cross || result.Status != tt.wantStatus {
+				t.Errorf("Expected crossed=%t with status %s, got %+v", tt.cross, tt.wantStatus, result)
+			}
+			status, err := broker.GetOrderStatus(context.Background(), result.BrokerOrderID)
+			if err != nil {
+				t.Fatalf("Failed to get peg status: %v", err)
+			}
+			if status.Status != tt.wantPegEnd {
+				t.Errorf("Expected the pegged order to end %s, got %s", tt.wantPegEnd, status.Status)
+			}
+			if tt.cross && (result.FilledQuantity != 100 || result.Remaining != 0) {
+				t.Errorf("Expected the market order to fill the remaining 100, got %+v", result)
+			}
+		})
+	}
+}
+
+func waitForClockWaiter(t *testing.T, fakeClock *clock.FakeClock) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for fakeClock.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the peg to wait on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
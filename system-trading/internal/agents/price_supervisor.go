@@ -0,0 +1,131 @@
+package agents
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/backoff"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// resubscribeState tracks recovery of one stalled price subscription
+type resubscribeState struct {
+	attempts    int
+	nextAttempt time.Time
+}
+
+// runSubscriptionSupervisor resubscribes symbols whose price feed has gone
+// quiet for PriceStalenessWindow, checking twice per window
+func (a *DataCollectorAgent) runSubscriptionSupervisor() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.config.PriceStalenessWindow / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			a.checkStalledSubscriptions(now)
+		}
+	}
+}
+
+// checkStalledSubscriptions resubscribes every symbol that has had no update
+// for PriceStalenessWindow as of now and whose backoff has elapsed. A symbol
+// that has used ResubscribeMaxAttempts is left alone until an update arrives.
+func (a *DataCollectorAgent) checkStalledSubscriptions(now time.Time) {
+	retryDelay := backoff.Backoff{
+		InitialDelay: a.config.ResubscribeInitialBackoff,
+		MaxDelay:     a.config.ResubscribeMaxBackoff,
+		Factor:       2,
+	}
+
+	a.mu.Lock()
+	var due []entities.Symbol
+	for symbol := range a.subscriptions {
+		lastUpdate, tracked := a.symbolUpdates[symbol]
+		if !tracked || now.Sub(lastUpdate) < a.config.PriceStalenessWindow {
+			continue
+		}
+
+		state := a.resubscribing[symbol]
+		if state == nil {
+			state = &resubscribeState{}
+			a.resubscribing[symbol] = state
+		}
+		if state.attempts >= a.config.ResubscribeMaxAttempts || now.Before(state.nextAttempt) {
+			continue
+		}
+		state.attempts++
+		state.nextAttempt = now.Add(retryDelay.Delay(state.attempts))
+		due = append(due, symbol)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i] < due[j] })
+	for _, symbol := range due {
+		a.resubscribe(symbol, now)
+	}
+}
+
+// resubscribe drops whatever is left of symbol's subscription at the provider
+// and subscribes again
+func (a *DataCollectorAgent) resubscribe(symbol entities.Symbol, now time.Time) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.config.SubscribeTimeout)
+	defer cancel()
+
+	// The old subscription may still be registered even though it stopped
+	// delivering; a failure here just means the provider already dropped it
+	a.priceProvider.UnsubscribeFromPrice(ctx, symbol)
+	err := a.priceProvider.SubscribeToPrice(ctx, symbol, a.handlePriceUpdate)
+
+	a.mu.Lock()
+	if err != nil {
+		attempts := 0
+		if state := a.resubscribing[symbol]; state != nil {
+			attempts = state.attempts
+		}
+		a.mu.Unlock()
+
+		a.metrics.IncrementCounter("price_subscription_resubscribes", map[string]string{
+			"symbol": string(symbol),
+			"status": "failure",
+		})
+		if attempts >= a.config.ResubscribeMaxAttempts {
+			a.logger.Error("Giving up resubscribing to stalled price feed until an update arrives",
+				interfaces.Field{Key: "symbol", Value: symbol},
+				interfaces.Field{Key: "attempts", Value: attempts},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return
+		}
+		a.logger.Warn("Failed to resubscribe to stalled price feed",
+			interfaces.Field{Key: "symbol", Value: symbol},
+			interfaces.Field{Key: "attempt", Value: attempts},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		return
+	}
+
+	if a.subscriptions[symbol] == 0 {
+		// RemoveSymbol released the symbol while we were resubscribing
+		a.mu.Unlock()
+		a.priceProvider.UnsubscribeFromPrice(context.Background(), symbol)
+		return
+	}
+	delete(a.resubscribing, symbol)
+	a.symbolUpdates[symbol] = now
+	a.mu.Unlock()
+
+	a.metrics.IncrementCounter("price_subscription_resubscribes", map[string]string{
+		"symbol": string(symbol),
+		"status": "success",
+	})
+	a.logger.Info("Resubscribed to stalled price feed",
+		interfaces.Field{Key: "symbol", Value: symbol},
+	)
+}
@@ -0,0 +1,179 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/brokers"
+	"github.com/system-trading/core/internal/infrastructure/marketdata"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/usecases"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func TestScenario_SeededRunKeepsCashAndPositionsConsistent(t *testing.T) {
+	const initialCash = 100000.0
+	scenario := marketdata.ScenarioConfig{
+		Seed:              42,
+		Symbols:           []entities.Symbol{"AAPL", "MSFT", "TSLA"},
+		Steps:             200,
+		NewsProbability:   0.3,
+		SignalProbability: 0.6,
+		MaxSignalQuantity: 200,
+		Volatility:        0.01,
+	}
+
+	// The same seed must reproduce the same stream
+	events := marketdata.NewScenarioGenerator(scenario).Events()
+	if replay := marketdata.NewScenarioGenerator(scenario).Events(); !reflect.DeepEqual(events, replay) {
+		t.Fatal("Expected a seeded scenario to be reproducible")
+	}
+
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+	mockBroker.SetLatency(0)
+	mockBroker.SetSynchronous(true)
+	mockBroker.SetFillPriceModel(brokers.LastPriceModel{})
+	SetMockBrokerErrorRate(mockBroker, 0)
+	if err := agent.Start(ctx); err != nil {
+		t.Fatalf("Failed to start execution agent: %v", err)
+	}
+	defer agent.Stop(ctx)
+	approve := mockBus.GetHandler("order.approved")
+
+	portfolioRepo := repositories.NewInMemoryPortfolioRepository()
+	portfolios := usecases.NewPortfolioService(portfolioRepo, mockBus, agent.logger, agent.metrics)
+	if _, _, err := portfolios.EnsurePortfolio(ctx, "default", initialCash); err != nil {
+		t.Fatalf("Failed to create portfolio: %v", err)
+	}
+	prices := usecases.NewPriceCache(mockBus, time.Hour, nil)
+	risk := usecases.NewRiskService(portfolios, mockBus, agent.logger, agent.metrics, &ifs.RiskLimits{
+		MaxPositionSize:    0.4,
+		MaxConcentration:   1,
+		MaxLeverage:        10,
+		MaxDailyLoss:       1,
+		MaxVaR:             math.Inf(1),
+		VaRConfidenceLevel: 0.95,
+		CashBufferPct:      0.10,
+	}, usecases.RiskServiceConfig{})
+	risk.SetPriceCache(prices)
+	analyzer := NewKeywordSentimentAnalyzer()
+
+	// filled tracks net quantity per symbol from order.executed events
+	filled := make(map[entities.Symbol]float64)
+	applied := 0
+	executedCount, rejected := 0, 0
+
+	for _, event := range events {
+		switch event.Kind {
+		case marketdata.ScenarioMarketTick:
+			prices.Update(event.MarketData)
+			mockBroker.SetMarketPrice(string(event.MarketData.Symbol), event.MarketData.Price)
+			if err := portfolios.UpdatePositionPrices(ctx, "default", event.MarketData); err != nil {
+				t.Fatalf("Step %d: failed to mark positions: %v", event.Step, err)
+			}
+
+		case marketdata.ScenarioNews:
+			if _, err := analyzer.Analyze(ctx, event.News); err != nil {
+				t.Fatalf("Step %d: failed to analyze news: %v", event.Step, err)
+			}
+
+		case marketdata.ScenarioSignal:
+			signal := event.Signal
+			quantity := signal.Quantity
+			if signal.Side == entities.OrderSideSell {
+				// The strategy never sells more than it holds
+				portfolio, err := portfolios.GetPortfolio(ctx, "default")
+				if err != nil {
+					t.Fatalf("Step %d: failed to get portfolio: %v", event.Step, err)
+				}
+				position, exists := portfolio.GetPosition(signal.Symbol)
+				if !exists {
+					continue
+				}
+				quantity = math.Min(quantity, position.Quantity)
+			}
+
+			order := entities.NewOrder(signal.Symbol, signal.Side, entities.OrderTypeMarket, quantity, nil)
+			if err := risk.ValidateOrder(ctx, order); err != nil {
+				rejected++
+				continue
+			}
+			order.Approve()
+			data, err := json.Marshal(order)
+			if err != nil {
+				t.Fatalf("Failed to marshal order: %v", err)
+			}
+			if err := approve(ctx, data); err != nil {
+				t.Fatalf("Step %d: execution failed: %v", event.Step, err)
+			}
+		}
+
+		// Book every new fill into the portfolio, as the order.executed consumer does
+		executed := mockBus.GetMessagesByTopic("order.executed")
+		for ; applied < len(executed); applied++ {
+			fill := executed[applied].Message.(ExecutedOrderMessage)
+			order := entities.NewOrder(entities.Symbol(fill.Symbol), entities.OrderSide(fill.Side),
+				entities.OrderTypeMarket, fill.Quantity, nil)
+			order.Execute(fill.ExecutedPrice, fill.ExecutedQty)
+			order.Fees = fill.Fees
+			if err := portfolios.ProcessOrderExecution(ctx, order); err != nil {
+				t.Fatalf("Step %d: failed to book fill %+v: %v", event.Step, fill, err)
+			}
+
+			if order.Side == entities.OrderSideBuy {
+				filled[order.Symbol] += fill.ExecutedQty
+			} else {
+				filled[order.Symbol] -= fill.ExecutedQty
+			}
+			executedCount++
+		}
+
+		portfolio, err := portfolios.GetPortfolio(ctx, "default")
+		if err != nil {
+			t.Fatalf("Step %d: failed to get portfolio: %v", event.Step, err)
+		}
+		if portfolio.Cash < 0 {
+			t.Fatalf("Step %d: cash went negative: %v", event.Step, portfolio.Cash)
+		}
+	}
+
+	if executedCount == 0 || rejected == 0 {
+		t.Fatalf("Expected the scenario to both execute and reject orders, got %d executed and %d rejected",
+			executedCount, rejected)
+	}
+
+	portfolio, err := portfolios.GetPortfolio(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+	account, err := mockBroker.GetAccountInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get account info: %v", err)
+	}
+	brokerPositions := make(map[entities.Symbol]float64)
+	for _, position := range account.Positions {
+		brokerPositions[entities.Symbol(position.Symbol)] = position.Quantity
+	}
+
+	for _, symbol := range scenario.Symbols {
+		held := 0.0
+		if position, exists := portfolio.GetPosition(symbol); exists {
+			held = position.Quantity
+		}
+		if math.Abs(held-filled[symbol]) > 1e-9 {
+			t.Errorf("%s: portfolio holds %v but fills net to %v", symbol, held, filled[symbol])
+		}
+		if math.Abs(held-brokerPositions[symbol]) > 1e-9 {
+			t.Errorf("%s: portfolio holds %v but the broker reports %v", symbol, held, brokerPositions[symbol])
+		}
+	}
+	if math.Abs(portfolio.Cash-account.CashBalance) > 1e-6 {
+		t.Errorf("Portfolio cash %v does not reconcile with broker cash %v", portfolio.Cash, account.CashBalance)
+	}
+}
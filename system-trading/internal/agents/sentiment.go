@@ -0,0 +1,96 @@
+package agents
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+var (
+	positiveNewsKeywords = map[string]struct{}{
+		"beat": {}, "beats": {}, "bullish": {}, "gain": {}, "gains": {}, "growth": {},
+		"outperform": {}, "profit": {}, "rally": {}, "record": {}, "rise": {}, "rises": {},
+		"strong": {}, "surge": {}, "surges": {}, "upgrade": {}, "upgraded": {},
+	}
+	negativeNewsKeywords = map[string]struct{}{
+		"bearish": {}, "decline": {}, "declines": {}, "downgrade": {}, "downgraded": {},
+		"fall": {}, "falls": {}, "fraud": {}, "lawsuit": {}, "loss": {}, "losses": {},
+		"miss": {}, "misses": {}, "plunge": {}, "recall": {}, "weak": {},
+	}
+)
+
+// KeywordSentimentAnalyzer scores articles by counting positive and negative
+// keywords, and rates relevance by where the article's symbols are mentioned
+type KeywordSentimentAnalyzer struct{}
+
+func NewKeywordSentimentAnalyzer() KeywordSentimentAnalyzer {
+	return KeywordSentimentAnalyzer{}
+}
+
+func (KeywordSentimentAnalyzer) Analyze(ctx context.Context, article *entities.NewsArticle) (interfaces.SentimentScore, error) {
+	title := tokenize(article.Title)
+	content := tokenize(article.Content)
+
+	positive, negative := 0, 0
+	for _, words := range [][]string{title, content} {
+		for _, word := range words {
+			if _, ok := positiveNewsKeywords[word]; ok {
+				positive++
+			}
+			if _, ok := negativeNewsKeywords[word]; ok {
+				negative++
+			}
+		}
+	}
+
+	var score interfaces.SentimentScore
+	if positive+negative > 0 {
+		score.Sentiment = float64(positive-negative) / float64(positive+negative)
+	}
+
+	// A symbol in the headline is fully relevant, one only in the body half so
+	if len(article.Symbols) > 0 {
+		inTitle, inContent := wordSet(title), wordSet(content)
+		total := 0.0
+		for _, symbol := range article.Symbols {
+			word := strings.ToLower(string(symbol))
+			if _, ok := inTitle[word]; ok {
+				total += 1
+			} else if _, ok := inContent[word]; ok {
+				total += 0.5
+			}
+		}
+		score.Relevance = total / float64(len(article.Symbols))
+	}
+
+	return score, nil
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func wordSet(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+// clampSentimentScore forces scores into the ranges the validator accepts and
+// reports false if a score is not a number at all
+func clampSentimentScore(score interfaces.SentimentScore) (interfaces.SentimentScore, bool) {
+	if math.IsNaN(score.Sentiment) || math.IsNaN(score.Relevance) {
+		return interfaces.SentimentScore{}, false
+	}
+	score.Sentiment = math.Max(-1, math.Min(1, score.Sentiment))
+	score.Relevance = math.Max(0, math.Min(1, score.Relevance))
+	return score, true
+}
@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/interfaces"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// resolveImmediateOrder settles an IOC or FOK order right after placement: the
+// filled part is published on order.executed and any unfilled remainder on
+// order.expired. If the broker left the order open, the agent cancels the
+// remainder itself.
+func (ea *ExecutionAgent) resolveImmediateOrder(ctx context.Context, order *entities.Order,
+	result *interfaces.OrderResult, submittedAt time.Time) {
+
+	status := &interfaces.OrderStatus{
+		BrokerOrderID: result.BrokerOrderID,
+		Status:        result.Status,
+		ExecutedPrice: result.ExecutedPrice,
+		ExecutedQty:   result.ExecutedQty,
+		Fees:          result.Fees,
+		LastUpdate:    result.Timestamp,
+	}
+
+	if !isTerminalStatus(result.Status) {
+		if err := ea.trader.CancelOrder(ctx, result.BrokerOrderID); err != nil {
+			ea.logger.Warn("Failed to cancel unfilled remainder of immediate order",
+				ifs.Field{Key: "order_id", Value: string(order.ID)},
+				ifs.Field{Key: "broker_order_id", Value: result.BrokerOrderID},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+		}
+
+		current, err := ea.trader.GetOrderStatus(ctx, result.BrokerOrderID)
+		if err != nil {
+			// Leave it to the monitor to report however it ends
+			ea.logger.Error("Failed to get status of immediate order",
+				ifs.Field{Key: "order_id", Value: string(order.ID)},
+				ifs.Field{Key: "broker_order_id", Value: result.BrokerOrderID},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+			ea.trackOrder(order, result.BrokerOrderID)
+			return
+		}
+		status = current
+	}
+
+	var filled float64
+	if status.ExecutedQty != nil {
+		filled = *status.ExecutedQty
+	}
+
+	if filled > 0 && status.ExecutedPrice != nil {
+		span := ea.tracer.StartSpan(string(order.ID), "order.filled", submittedAt)
+		ea.publishExecutedOrderFromStatus(ctx, order, result.BrokerOrderID, status)
+		span.End(nil)
+		ea.cancelOCOSibling(ctx, order)
+	}
+
+	if filled < order.Quantity {
+		ea.logger.Info("Immediate order expired unfilled",
+			ifs.Field{Key: "order_id", Value: string(order.ID)},
+			ifs.Field{Key: "time_in_force", Value: string(order.TimeInForce)},
+			ifs.Field{Key: "filled_quantity", Value: filled},
+			ifs.Field{Key: "quantity", Value: order.Quantity},
+		)
+		ea.metrics.IncrementCounter("execution_agent_orders_expired", map[string]string{
+			"symbol": string(order.Symbol),
+			"broker": ea.trader.GetBrokerName(),
+		})
+		ea.publishOrderEvent(ctx, "order.expired", order, status, nil)
+	}
+}
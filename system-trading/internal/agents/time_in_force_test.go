@@ -0,0 +1,124 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/brokers"
+)
+
+func TestExecutionAgent_ImmediateOrdersResolveOnPlacement(t *testing.T) {
+	tests := []struct {
+		name         string
+		tif          entities.TimeInForce
+		wantExecuted float64
+		wantExpired  bool
+	}{
+		{"IOC publishes the partial fill and expires the rest", entities.TimeInForceIOC, 60, true},
+		{"FOK short of depth expires without filling", entities.TimeInForceFOK, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+			defer agent.Stop(context.Background())
+
+			SetMockBrokerErrorRate(mockBroker, 0)
+			mockBroker.SetFillPriceModel(brokers.BookWalkModel{Fallback: 100})
+			mockBroker.SetOrderBook("AAPL", brokers.OrderBook{
+				Asks: []brokers.BookLevel{{Price: 100, Quantity: 60}, {Price: 102, Quantity: 100}},
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := agent.Start(ctx); err != nil {
+				t.Fatalf("Failed to start execution agent: %v", err)
+			}
+
+			limit := 101.0
+			order := createTestOrder()
+			order.Type = entities.OrderTypeLimit
+			order.Price = &limit
+			order.TimeInForce = tt.tif
+			data, err := json.Marshal(order)
+			if err != nil {
+				t.Fatalf("Failed to marshal order: %v", err)
+			}
+			if err := mockBus.GetHandler("order.approved")(ctx, data); err != nil {
+				t.Fatalf("Failed to handle approved order: %v", err)
+			}
+
+			executed := mockBus.GetMessagesByTopic("order.executed")
+			if tt.wantExecuted == 0 && len(executed) != 0 {
+				t.Errorf("Expected no execution, got %d", len(executed))
+			}
+			if tt.wantExecuted > 0 {
+				if len(executed) != 1 {
+					t.Fatalf("Expected one execution, got %d", len(executed))
+				}
+				if event := executed[0].Message.(ExecutedOrderMessage); event.ExecutedQty != tt.wantExecuted || event.Quantity != 100 {
+					t.Errorf("Expected %v of 100 executed, got %v of %v", tt.wantExecuted, event.ExecutedQty, event.Quantity)
+				}
+			}
+			if expired := mockBus.GetMessagesByTopic("order.expired"); (len(expired) == 1) != tt.wantExpired {
+				t.Errorf("Expected expired %t, got %d order.expired events", tt.wantExpired, len(expired))
+			}
+			if tracked := agent.GetTrackedOrders(); len(tracked) != 0 {
+				t.Errorf("Expected immediate orders never to be tracked, got %+v", tracked)
+			}
+		})
+	}
+}
+
+func TestExecutionAgent_MonitorExpiresDayOrdersAtTheClose(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	defer agent.Stop(context.Background())
+
+	SetMockBrokerErrorRate(mockBroker, 0)
+	ctx := context.Background()
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect broker: %v", err)
+	}
+
+	place := func(id entities.OrderID, tif entities.TimeInForce, expiresAt *time.Time) string {
+		price := 90.0
+		order := createTestOrder()
+		order.ID = id
+		order.Type = entities.OrderTypeLimit
+		order.Price = &price
+		order.TimeInForce = tif
+		order.ExpiresAt = expiresAt
+		result, err := mockBroker.PlaceOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("Failed to place order: %v", err)
+		}
+		agent.trackOrder(order, result.BrokerOrderID)
+		return result.BrokerOrderID
+	}
+
+	closed := time.Now().Add(-time.Minute)
+	stillOpen := time.Now().Add(time.Hour)
+	dayOrder := place("day-closed", entities.TimeInForceDay, &closed)
+	place("day-open", entities.TimeInForceDay, &stillOpen)
+	place("gtc", entities.TimeInForceGTC, nil)
+
+	agent.checkPendingOrders()
+
+	expired := mockBus.GetMessagesByTopic("order.expired")
+	if len(expired) != 1 || expired[0].Message.(map[string]interface{})["order_id"] != "day-closed" {
+		t.Fatalf("Expected only the closed DAY order expired, got %+v", expired)
+	}
+	status, err := mockBroker.GetOrderStatus(ctx, dayOrder)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != entities.OrderStatusCancelled {
+		t.Errorf("Expected the DAY order cancelled at the broker, got %s", status.Status)
+	}
+	if tracked := agent.GetTrackedOrders(); len(tracked) != 2 {
+		t.Errorf("Expected the open DAY order and the GTC order still tracked, got %+v", tracked)
+	}
+}
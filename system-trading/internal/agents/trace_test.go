@@ -0,0 +1,81 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/infrastructure/tracing"
+	"github.com/system-trading/core/internal/usecases"
+)
+
+func TestOrderFlow_RecordsOrderedSpans(t *testing.T) {
+	agent, mockBus, mockBroker := setupTestExecutionAgent(t)
+	ctx := context.Background()
+	mockBroker.SetLatency(0)
+	mockBroker.SetSynchronous(true)
+	SetMockBrokerErrorRate(mockBroker, 0)
+
+	tracer := tracing.NewSpanRecorder(100)
+	agent.SetTracer(tracer)
+	if err := agent.Start(ctx); err != nil {
+		t.Fatalf("Failed to start execution agent: %v", err)
+	}
+	defer agent.Stop(ctx)
+
+	orders := usecases.NewOrderService(repositories.NewInMemoryOrderRepository(), mockBus,
+		agent.logger, agent.metrics, nil, nil)
+	orders.SetTracer(tracer)
+
+	order, err := orders.CreateOrder(ctx, usecases.CreateOrderRequest{
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeMarket,
+		Quantity: 10,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, order.ID, entities.OrderStatusApproved); err != nil {
+		t.Fatalf("UpdateOrderStatus failed: %v", err)
+	}
+
+	// The mock bus only records publishes, so hand the approval to the agent
+	approved := mockBus.GetMessagesByTopic("order.approved")
+	if len(approved) != 1 {
+		t.Fatalf("Expected one order.approved message, got %d", len(approved))
+	}
+	data, err := json.Marshal(approved[0].Message)
+	if err != nil {
+		t.Fatalf("Failed to marshal approval: %v", err)
+	}
+	if err := mockBus.GetHandler("order.approved")(ctx, data); err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+
+	trace := tracer.Trace(string(order.ID))
+	want := []string{"order.created", "order.approved", "order.submitted", "order.filled"}
+	if len(trace) != len(want) {
+		t.Fatalf("Expected spans %v, got %+v", want, trace)
+	}
+	for i, span := range trace {
+		if span.Name != want[i] {
+			t.Errorf("Span %d: expected %s, got %s", i, want[i], span.Name)
+		}
+		if span.Error != "" {
+			t.Errorf("Span %s recorded an error: %s", span.Name, span.Error)
+		}
+		if span.Duration < 0 || span.End.Before(span.Start) {
+			t.Errorf("Span %s has an invalid duration %s", span.Name, span.Duration)
+		}
+		if i > 0 && span.Start.Before(trace[i-1].End) {
+			t.Errorf("Span %s started before %s ended", span.Name, trace[i-1].Name)
+		}
+	}
+
+	if spans := tracer.Trace("unknown-order"); len(spans) != 0 {
+		t.Errorf("Expected no spans for an unknown correlation ID, got %d", len(spans))
+	}
+}
@@ -0,0 +1,215 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/interfaces"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// Venue is a named broker connection that orders can be routed to
+type Venue struct {
+	Name   string
+	Trader interfaces.Trader
+}
+
+type VenueRouterConfig struct {
+	// LatencyBudget is how long a venue has to fill its child order before the
+	// unfilled remainder is cancelled there and sent to the next venue
+	LatencyBudget time.Duration
+	// PollInterval is how often a working child order's status is checked
+	PollInterval time.Duration
+	Clock        ifs.Clock
+}
+
+// VenueFill is the quantity one venue filled for a routed order
+type VenueFill struct {
+	Venue         string  `json:"venue"`
+	BrokerOrderID string  `json:"broker_order_id"`
+	Quantity      float64 `json:"quantity"`
+	AveragePrice  float64 `json:"average_price"`
+}
+
+// RoutingResult attributes a routed order's fills to the venues that made them
+type RoutingResult struct {
+	OrderID        entities.OrderID `json:"order_id"`
+	Fills          []VenueFill      `json:"fills"`
+	FilledQuantity float64          `json:"filled_quantity"`
+	AveragePrice   float64          `json:"average_price"`
+	// Remaining is left working at the last venue when no venue filled it in time
+	Remaining float64 `json:"remaining"`
+}
+
+// VenueRouter seeks liquidity across venues in priority order: each venue gets
+// the order's unfilled remainder for one latency budget before it escalates.
+type VenueRouter struct {
+	venues  []Venue
+	logger  ifs.Logger
+	metrics ifs.MetricsCollector
+	config  VenueRouterConfig
+	clock   ifs.Clock
+}
+
+func NewVenueRouter(venues []Venue, logger ifs.Logger, metrics ifs.MetricsCollector, config VenueRouterConfig) *VenueRouter {
+	if config.LatencyBudget <= 0 {
+		config.LatencyBudget = 2 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 100 * time.Millisecond
+	}
+
+	routerClock := config.Clock
+	if routerClock == nil {
+		routerClock = clock.NewRealClock()
+	}
+
+	return &VenueRouter{
+		venues:  venues,
+		logger:  logger,
+		metrics: metrics,
+		config:  config,
+		clock:   routerClock,
+	}
+}
+
+// Route works order across the venues until it is filled or the last venue's
+// budget runs out. The child order at the last venue is left working.
+func (r *VenueRouter) Route(ctx context.Context, order *entities.Order) (*RoutingResult, error) {
+	if len(r.venues) == 0 {
+		return nil, fmt.Errorf("no venues configured")
+	}
+
+	result := &RoutingResult{OrderID: order.ID, Remaining: order.Quantity}
+	var notional float64
+
+	for i, venue := range r.venues {
+		last := i == len(r.venues)-1
+
+		child := order.Clone()
+		child.Quantity = result.Remaining
+		placed, err := venue.Trader.PlaceOrder(ctx, child)
+		if err != nil {
+			if last {
+				return result, fmt.Errorf("failed to place order at %s: %w", venue.Name, err)
+			}
+			r.logger.Warn("Venue rejected routed order, escalating",
+				ifs.Field{Key: "order_id", Value: string(order.ID)},
+				ifs.Field{Key: "venue", Value: venue.Name},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+			r.escalate(venue, r.venues[i+1])
+			continue
+		}
+
+		fill, err := r.workChildOrder(ctx, venue, placed.BrokerOrderID, child.Quantity, last)
+		if err != nil {
+			return result, err
+		}
+
+		if fill.Quantity > 0 {
+			result.Fills = append(result.Fills, fill)
+			result.FilledQuantity += fill.Quantity
+			result.Remaining -= fill.Quantity
+			notional += fill.Quantity * fill.AveragePrice
+		}
+		if result.Remaining <= 0 || last {
+			break
+		}
+
+		r.escalate(venue, r.venues[i+1])
+	}
+
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if result.FilledQuantity > 0 {
+		result.AveragePrice = notional / result.FilledQuantity
+	}
+
+	r.logger.Info("Routed order",
+		ifs.Field{Key: "order_id", Value: string(order.ID)},
+		ifs.Field{Key: "venues_used", Value: len(result.Fills)},
+		ifs.Field{Key: "filled_quantity", Value: result.FilledQuantity},
+		ifs.Field{Key: "remaining", Value: result.Remaining},
+	)
+
+	return result, nil
+}
+
+// workChildOrder polls a child order until it fills or the latency budget runs
+// out. On timeout the child is cancelled unless it is at the last venue, and its
+// final fills are read after the cancel so late fills are not lost.
+func (r *VenueRouter) workChildOrder(ctx context.Context, venue Venue, brokerOrderID string,
+	quantity float64, last bool) (VenueFill, error) {
+
+	fill := VenueFill{Venue: venue.Name, BrokerOrderID: brokerOrderID}
+	deadline := r.clock.Now().Add(r.config.LatencyBudget)
+
+	for {
+		status, err := venue.Trader.GetOrderStatus(ctx, brokerOrderID)
+		if err != nil {
+			return fill, fmt.Errorf("failed to get order status from %s: %w", venue.Name, err)
+		}
+		fill.Quantity, fill.AveragePrice = filledQuantity(status)
+
+		if fill.Quantity >= quantity || isTerminalStatus(status.Status) {
+			return fill, nil
+		}
+		if !r.clock.Now().Before(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fill, ctx.Err()
+		case <-r.clock.After(r.config.PollInterval):
+		}
+	}
+
+	if last {
+		return fill, nil
+	}
+
+	if err := venue.Trader.CancelOrder(ctx, brokerOrderID); err != nil {
+		r.logger.Warn("Failed to cancel child order before escalating",
+			ifs.Field{Key: "venue", Value: venue.Name},
+			ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+			ifs.Field{Key: "error", Value: err.Error()},
+		)
+	}
+
+	status, err := venue.Trader.GetOrderStatus(ctx, brokerOrderID)
+	if err != nil {
+		return fill, fmt.Errorf("failed to get final order status from %s: %w", venue.Name, err)
+	}
+	fill.Quantity, fill.AveragePrice = filledQuantity(status)
+	return fill, nil
+}
+
+func (r *VenueRouter) escalate(from, to Venue) {
+	r.metrics.IncrementCounter("venue_router_escalations", map[string]string{
+		"from": from.Name,
+		"to":   to.Name,
+	})
+}
+
+// filledQuantity returns how much of an order has filled and at what average price
+func filledQuantity(status *interfaces.OrderStatus) (float64, float64) {
+	if status.ExecutedQty != nil && status.ExecutedPrice != nil {
+		return *status.ExecutedQty, *status.ExecutedPrice
+	}
+
+	var quantity, notional float64
+	for _, fill := range status.Fills {
+		quantity += fill.Quantity
+		notional += fill.Quantity * fill.Price
+	}
+	if quantity == 0 {
+		return 0, 0
+	}
+	return quantity, notional / quantity
+}
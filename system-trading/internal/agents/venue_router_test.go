@@ -0,0 +1,155 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/interfaces"
+)
+
+// scriptedTrader fills fillFraction of every order at price as soon as it is
+// placed and never fills any more of it. Only the order methods are scripted.
+type scriptedTrader struct {
+	interfaces.Trader
+	name         string
+	fillFraction float64
+	price        float64
+
+	mu     sync.Mutex
+	orders map[string]*scriptedOrder
+}
+
+type scriptedOrder struct {
+	quantity float64
+	filled   float64
+	status   entities.OrderStatus
+}
+
+func newScriptedTrader(name string, fillFraction, price float64) *scriptedTrader {
+	return &scriptedTrader{
+		name:         name,
+		fillFraction: fillFraction,
+		price:        price,
+		orders:       make(map[string]*scriptedOrder),
+	}
+}
+
+func (s *scriptedTrader) PlaceOrder(ctx context.Context, order *entities.Order) (*interfaces.OrderResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("%s-%d", s.name, len(s.orders)+1)
+	placed := &scriptedOrder{quantity: order.Quantity, filled: order.Quantity * s.fillFraction, status: entities.OrderStatusPending}
+	if placed.filled >= placed.quantity {
+		placed.status = entities.OrderStatusExecuted
+	}
+	s.orders[id] = placed
+	return &interfaces.OrderResult{BrokerOrderID: id, Status: placed.status}, nil
+}
+
+func (s *scriptedTrader) CancelOrder(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[orderID].status = entities.OrderStatusCancelled
+	return nil
+}
+
+func (s *scriptedTrader) GetOrderStatus(ctx context.Context, orderID string) (*interfaces.OrderStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	placed := s.orders[orderID]
+	status := &interfaces.OrderStatus{BrokerOrderID: orderID, Status: placed.status}
+	if placed.filled > 0 {
+		filled, price := placed.filled, s.price
+		status.ExecutedQty = &filled
+		status.ExecutedPrice = &price
+	}
+	return status, nil
+}
+
+func (s *scriptedTrader) order(id string) scriptedOrder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.orders[id]
+}
+
+func TestVenueRouter_EscalatesRemainderAfterLatencyBudget(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+	primary := newScriptedTrader("primary", 0.4, 100)
+	secondary := newScriptedTrader("secondary", 1, 101)
+
+	agent, _, _ := setupTestExecutionAgent(t)
+	router := NewVenueRouter(
+		[]Venue{{Name: "primary", Trader: primary}, {Name: "secondary", Trader: secondary}},
+		agent.logger, agent.metrics,
+		VenueRouterConfig{LatencyBudget: time.Second, PollInterval: 100 * time.Millisecond, Clock: fakeClock},
+	)
+
+	type routed struct {
+		result *RoutingResult
+		err    error
+	}
+	done := make(chan routed, 1)
+	go func() {
+		result, err := router.Route(context.Background(), createTestOrder())
+		done <- routed{result, err}
+	}()
+
+	// Step the clock one poll at a time until the primary's budget runs out
+	var out routed
+	deadline := time.Now().Add(5 * time.Second)
+	for out.result == nil && out.err == nil {
+		select {
+		case out = <-done:
+			continue
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Route did not finish")
+		}
+		if fakeClock.Waiters() > 0 {
+			fakeClock.Advance(100 * time.Millisecond)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if out.err != nil {
+		t.Fatalf("Route failed: %v", out.err)
+	}
+	result := out.result
+
+	want := []VenueFill{
+		{Venue: "primary", BrokerOrderID: "primary-1", Quantity: 40, AveragePrice: 100},
+		{Venue: "secondary", BrokerOrderID: "secondary-1", Quantity: 60, AveragePrice: 101},
+	}
+	if len(result.Fills) != len(want) {
+		t.Fatalf("Expected fills %+v, got %+v", want, result.Fills)
+	}
+	for i := range want {
+		if result.Fills[i] != want[i] {
+			t.Errorf("Fill %d: expected %+v, got %+v", i, want[i], result.Fills[i])
+		}
+	}
+
+	if result.FilledQuantity != 100 || result.Remaining != 0 {
+		t.Errorf("Expected 100 filled with nothing remaining, got %v filled, %v remaining", result.FilledQuantity, result.Remaining)
+	}
+	if math.Abs(result.AveragePrice-100.6) > 1e-9 {
+		t.Errorf("Expected average price 100.6, got %v", result.AveragePrice)
+	}
+
+	if status := primary.order("primary-1").status; status != entities.OrderStatusCancelled {
+		t.Errorf("Expected the primary's unfilled remainder to be cancelled, got %s", status)
+	}
+	if quantity := secondary.order("secondary-1").quantity; quantity != 60 {
+		t.Errorf("Expected only the 60-share remainder at the secondary, got %v", quantity)
+	}
+}
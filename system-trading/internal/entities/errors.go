@@ -5,6 +5,7 @@ import "errors"
 var (
 	ErrOrderNotFound         = errors.New("order not found")
 	ErrPositionNotFound      = errors.New("position not found")
+	ErrPortfolioNotFound     = errors.New("portfolio not found")
 	ErrInsufficientQuantity  = errors.New("insufficient quantity")
 	ErrInsufficientCash      = errors.New("insufficient cash balance")
 	ErrInvalidOrderType      = errors.New("invalid order type")
@@ -18,4 +19,22 @@ var (
 	ErrConnectionFailed      = errors.New("connection failed")
 	ErrAuthenticationFailed  = errors.New("authentication failed")
 	ErrRateLimitExceeded     = errors.New("rate limit exceeded")
-)
\ No newline at end of file
+	ErrTradingHalted         = errors.New("trading halted")
+	ErrStalePrice            = errors.New("stale price")
+	ErrInvalidMarketData     = errors.New("invalid market data")
+)
+
+// MarketDataRejection explains why market data failed validation. Reason is a
+// short, fixed label such as "crossed_quote" that is safe to use in metrics.
+type MarketDataRejection struct {
+	Reason  string
+	Message string
+}
+
+func (e *MarketDataRejection) Error() string {
+	return e.Message
+}
+
+func (e *MarketDataRejection) Unwrap() error {
+	return ErrInvalidMarketData
+}
@@ -9,6 +9,7 @@ type Symbol string
 type OrderType string
 type OrderSide string
 type OrderStatus string
+type TimeInForce string
 
 const (
 	OrderTypeMarket OrderType = "MARKET"
@@ -27,6 +28,21 @@ const (
 	OrderStatusExecuted  OrderStatus = "EXECUTED"
 	OrderStatusCancelled OrderStatus = "CANCELLED"
 	OrderStatusRejected  OrderStatus = "REJECTED"
+	OrderStatusExpired   OrderStatus = "EXPIRED"
+)
+
+const (
+	// TimeInForceGTC orders stay live until filled or cancelled
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceGTD orders expire at ExpiresAt if still live
+	TimeInForceGTD TimeInForce = "GTD"
+	// TimeInForceDay orders expire at the close of the session they were placed
+	// in, carried in ExpiresAt
+	TimeInForceDay TimeInForce = "DAY"
+	// TimeInForceIOC orders fill what they can on arrival and cancel the rest
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK orders fill completely on arrival or not at all
+	TimeInForceFOK TimeInForce = "FOK"
 )
 
 type Order struct {
@@ -36,12 +52,31 @@ type Order struct {
 	Type      OrderType   `json:"type"`
 	Quantity  float64     `json:"quantity"`
 	Price     *float64    `json:"price,omitempty"`
+	// StopPrice is where a stop order triggers into a market order
+	StopPrice *float64    `json:"stop_price,omitempty"`
 	Status    OrderStatus `json:"status"`
 	CreatedAt time.Time   `json:"created_at"`
 	UpdatedAt time.Time   `json:"updated_at"`
 	ExecutedAt *time.Time  `json:"executed_at,omitempty"`
 	ExecutedPrice *float64 `json:"executed_price,omitempty"`
 	ExecutedQuantity *float64 `json:"executed_quantity,omitempty"`
+	Fees      float64     `json:"fees,omitempty"`
+	TimeInForce TimeInForce `json:"time_in_force,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+	BrokerOrderID string  `json:"broker_order_id,omitempty"`
+	// Source identifies the strategy or client that submitted the order
+	Source    string      `json:"source,omitempty"`
+	// PortfolioID is the portfolio the order trades for; empty means DefaultPortfolioID
+	PortfolioID string    `json:"portfolio_id,omitempty"`
+	// PriceStale records that risk validation priced the order from a stale quote
+	PriceStale bool       `json:"price_stale,omitempty"`
+	// ChildOrderIDs are a bracket entry's take-profit and stop-loss orders,
+	// released once the entry fills
+	ChildOrderIDs []OrderID `json:"child_order_ids,omitempty"`
+	// ParentOrderID is the bracket entry a child order waits on
+	ParentOrderID OrderID `json:"parent_order_id,omitempty"`
+	// OCOSiblingID is cancelled when this order fills, and vice versa
+	OCOSiblingID OrderID  `json:"oco_sibling_id,omitempty"`
 }
 
 func NewOrder(symbol Symbol, side OrderSide, orderType OrderType, quantity float64, price *float64) *Order {
@@ -59,6 +94,52 @@ func NewOrder(symbol Symbol, side OrderSide, orderType OrderType, quantity float
 	}
 }
 
+// PortfolioOrDefault returns the order's portfolio, or DefaultPortfolioID when unset
+func (o *Order) PortfolioOrDefault() string {
+	if o.PortfolioID == "" {
+		return DefaultPortfolioID
+	}
+	return o.PortfolioID
+}
+
+// Clone returns a deep copy of the order, including its pointer fields, so the copy
+// can be read or mutated independently of the original.
+func (o *Order) Clone() *Order {
+	if o == nil {
+		return nil
+	}
+
+	clone := *o
+	if o.Price != nil {
+		price := *o.Price
+		clone.Price = &price
+	}
+	if o.StopPrice != nil {
+		stopPrice := *o.StopPrice
+		clone.StopPrice = &stopPrice
+	}
+	if o.ExecutedAt != nil {
+		executedAt := *o.ExecutedAt
+		clone.ExecutedAt = &executedAt
+	}
+	if o.ExecutedPrice != nil {
+		executedPrice := *o.ExecutedPrice
+		clone.ExecutedPrice = &executedPrice
+	}
+	if o.ExecutedQuantity != nil {
+		executedQuantity := *o.ExecutedQuantity
+		clone.ExecutedQuantity = &executedQuantity
+	}
+	if o.ExpiresAt != nil {
+		expiresAt := *o.ExpiresAt
+		clone.ExpiresAt = &expiresAt
+	}
+	if o.ChildOrderIDs != nil {
+		clone.ChildOrderIDs = append([]OrderID(nil), o.ChildOrderIDs...)
+	}
+	return &clone
+}
+
 func (o *Order) Approve() {
 	o.Status = OrderStatusApproved
 	o.UpdatedAt = time.Now()
@@ -83,6 +164,44 @@ func (o *Order) Cancel() {
 	o.UpdatedAt = time.Now()
 }
 
+func (o *Order) Expire() {
+	o.Status = OrderStatusExpired
+	o.UpdatedAt = time.Now()
+}
+
+// IsLive reports whether the order can still be filled
+func (o *Order) IsLive() bool {
+	return o.Status == OrderStatusPending || o.Status == OrderStatusApproved
+}
+
+// HasExpiry reports whether the order is a GTD or DAY order with an expiry time
+func (o *Order) HasExpiry() bool {
+	return (o.TimeInForce == TimeInForceGTD || o.TimeInForce == TimeInForceDay) && o.ExpiresAt != nil
+}
+
+// ExpiresBy reports whether a live GTD or DAY order's expiry is at or before t
+func (o *Order) ExpiresBy(t time.Time) bool {
+	return o.HasExpiry() && o.IsLive() && !o.ExpiresAt.After(t)
+}
+
+// IsImmediate reports whether the order must resolve on arrival, as IOC and
+// FOK orders do
+func (o *Order) IsImmediate() bool {
+	return o.TimeInForce == TimeInForceIOC || o.TimeInForce == TimeInForceFOK
+}
+
+// StopTriggered reports whether price has crossed a stop order's stop: at or
+// above it for a buy stop, at or below it for a sell stop
+func (o *Order) StopTriggered(price float64) bool {
+	if o.Type != OrderTypeStop || o.StopPrice == nil || price <= 0 {
+		return false
+	}
+	if o.Side == OrderSideBuy {
+		return price >= *o.StopPrice
+	}
+	return price <= *o.StopPrice
+}
+
 func generateID() string {
 	return time.Now().Format("20060102150405") + "-" + randomString(8)
 }
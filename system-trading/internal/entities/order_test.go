@@ -0,0 +1,40 @@
+package entities
+
+import "testing"
+
+func TestOrder_CloneIsDeep(t *testing.T) {
+	price := 100.0
+	original := NewOrder("AAPL", OrderSideBuy, OrderTypeLimit, 10, &price)
+	stop := 95.0
+	original.StopPrice = &stop
+	original.ChildOrderIDs = []OrderID{"take-profit", "stop-loss"}
+	original.Execute(101, 10)
+
+	clone := original.Clone()
+
+	*original.Price = 1
+	*original.ExecutedPrice = 2
+	*original.ExecutedQuantity = 3
+	*original.StopPrice = 5
+	original.ChildOrderIDs[0] = "replaced"
+	original.Quantity = 4
+	original.Cancel()
+
+	if *clone.Price != 100 || *clone.ExecutedPrice != 101 || *clone.ExecutedQuantity != 10 {
+		t.Errorf("Clone shares pointer fields with original: %+v", clone)
+	}
+	if *clone.StopPrice != 95 || clone.ChildOrderIDs[0] != "take-profit" {
+		t.Errorf("Clone shares stop price or child IDs with original: %+v", clone)
+	}
+	if clone.Quantity != 10 || clone.Status != OrderStatusExecuted {
+		t.Errorf("Clone shares value fields with original: %+v", clone)
+	}
+	if clone.ExecutedAt == original.ExecutedAt {
+		t.Error("Clone shares ExecutedAt pointer with original")
+	}
+
+	var nilOrder *Order
+	if nilOrder.Clone() != nil {
+		t.Error("Expected nil clone of nil order")
+	}
+}
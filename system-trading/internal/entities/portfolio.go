@@ -1,11 +1,25 @@
 package entities
 
 import (
+	"math"
 	"time"
 )
 
 type PositionID string
 
+// DefaultPortfolioID is the portfolio orders trade for when they name none
+const DefaultPortfolioID = "default"
+
+// CostBasisMethod decides which cost a sale's realized P&L is measured against
+type CostBasisMethod string
+
+const (
+	// CostBasisFIFO matches a sale against the oldest open lots first
+	CostBasisFIFO CostBasisMethod = "fifo"
+	// CostBasisAverage matches a sale against the position's average price
+	CostBasisAverage CostBasisMethod = "average"
+)
+
 type Position struct {
 	ID            PositionID `json:"id"`
 	Symbol        Symbol     `json:"symbol"`
@@ -15,17 +29,84 @@ type Position struct {
 	MarketValue   float64    `json:"market_value"`
 	UnrealizedPnL float64    `json:"unrealized_pnl"`
 	RealizedPnL   float64    `json:"realized_pnl"`
+	Fees          float64    `json:"fees"`
+	// Lots are the open purchase lots, oldest first, consumed FIFO on sale
+	Lots          []Lot      `json:"lots,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
+type Lot struct {
+	Quantity float64   `json:"quantity"`
+	Price    float64   `json:"price"`
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// RealizedLot records the sale of (part of) one lot
+type RealizedLot struct {
+	Symbol    Symbol    `json:"symbol"`
+	Quantity  float64   `json:"quantity"`
+	CostPrice float64   `json:"cost_price"`
+	SalePrice float64   `json:"sale_price"`
+	Gain      float64   `json:"gain"`
+	OpenedAt  time.Time `json:"opened_at"`
+	ClosedAt  time.Time `json:"closed_at"`
+}
+
+// DailyPnL is one settled trading day's realized P&L net of fees
+type DailyPnL struct {
+	TradingDay string    `json:"trading_day"`
+	PnL        float64   `json:"pnl"`
+	SettledAt  time.Time `json:"settled_at"`
+}
+
+// PortfolioSnapshot is a portfolio's value at one point in time
+type PortfolioSnapshot struct {
+	PortfolioID string    `json:"portfolio_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	TotalValue  float64   `json:"total_value"`
+	Cash        float64   `json:"cash"`
+	TotalPnL    float64   `json:"total_pnl"`
+}
+
+// CashFlowType classifies a cash movement that is not a trade
+type CashFlowType string
+
+const (
+	CashFlowDividend CashFlowType = "dividend"
+)
+
+// CashFlow is cash credited to or debited from a portfolio outside of trading
+type CashFlow struct {
+	Type      CashFlowType `json:"type"`
+	Symbol    Symbol       `json:"symbol,omitempty"`
+	Amount    float64      `json:"amount"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// HoldingPeriod is how long the lot was held before it was sold
+func (l RealizedLot) HoldingPeriod() time.Duration {
+	return l.ClosedAt.Sub(l.OpenedAt)
+}
+
 type Portfolio struct {
 	ID               string               `json:"id"`
 	Cash             float64              `json:"cash"`
 	TotalValue       float64              `json:"total_value"`
 	Positions        map[Symbol]*Position `json:"positions"`
+	// ClosedPositions keeps realized P&L and fees for symbols whose position was fully closed
+	ClosedPositions  map[Symbol]*Position `json:"closed_positions,omitempty"`
+	RealizedLots     []RealizedLot        `json:"realized_lots,omitempty"`
 	TotalPnL         float64              `json:"total_pnl"`
+	// DayPnL is realized P&L net of fees since the last settlement
 	DayPnL           float64              `json:"day_pnl"`
+	// PnLHistory holds each settled trading day's DayPnL, oldest first
+	PnLHistory       []DailyPnL           `json:"pnl_history,omitempty"`
+	// StartOfDayValue is TotalValue when DayPnL was last reset at DayStartedAt
+	StartOfDayValue  float64              `json:"start_of_day_value,omitempty"`
+	DayStartedAt     time.Time            `json:"day_started_at,omitempty"`
+	// CashFlows records dividends and other non-trade cash movements, oldest first
+	CashFlows        []CashFlow           `json:"cash_flows,omitempty"`
 	LastUpdated      time.Time            `json:"last_updated"`
 }
 
@@ -35,6 +116,7 @@ func NewPortfolio(initialCash float64) *Portfolio {
 		Cash:        initialCash,
 		TotalValue:  initialCash,
 		Positions:   make(map[Symbol]*Position),
+		ClosedPositions: make(map[Symbol]*Position),
 		TotalPnL:    0.0,
 		DayPnL:      0.0,
 		LastUpdated: time.Now(),
@@ -42,13 +124,20 @@ func NewPortfolio(initialCash float64) *Portfolio {
 }
 
 func (p *Portfolio) AddPosition(symbol Symbol, quantity float64, price float64) {
+	p.AddPositionAt(symbol, quantity, price, time.Now())
+}
+
+// AddPositionAt buys quantity at price, opening a lot dated at
+func (p *Portfolio) AddPositionAt(symbol Symbol, quantity float64, price float64, at time.Time) {
 	now := time.Now()
+	lot := Lot{Quantity: quantity, Price: price, OpenedAt: at}
 	
 	if position, exists := p.Positions[symbol]; exists {
 		newQuantity := position.Quantity + quantity
 		newAveragePrice := ((position.AveragePrice * position.Quantity) + (price * quantity)) / newQuantity
 		position.Quantity = newQuantity
 		position.AveragePrice = newAveragePrice
+		position.Lots = append(position.Lots, lot)
 		position.UpdatedAt = now
 	} else {
 		p.Positions[symbol] = &Position{
@@ -58,6 +147,7 @@ func (p *Portfolio) AddPosition(symbol Symbol, quantity float64, price float64)
 			AveragePrice: price,
 			CurrentPrice: price,
 			MarketValue:  quantity * price,
+			Lots:         []Lot{lot},
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -68,6 +158,18 @@ func (p *Portfolio) AddPosition(symbol Symbol, quantity float64, price float64)
 }
 
 func (p *Portfolio) RemovePosition(symbol Symbol, quantity float64, price float64) error {
+	return p.RemovePositionAt(symbol, quantity, price, time.Now())
+}
+
+// RemovePositionAt sells quantity at price with average-cost realized P&L
+func (p *Portfolio) RemovePositionAt(symbol Symbol, quantity float64, price float64, at time.Time) error {
+	return p.SellAt(symbol, quantity, price, at, CostBasisAverage)
+}
+
+// SellAt sells quantity at price, consuming lots FIFO and recording each consumed
+// slice in RealizedLots as closed at. Realized P&L is measured against method's
+// cost; under FIFO the average price of what remains is re-derived from its lots.
+func (p *Portfolio) SellAt(symbol Symbol, quantity float64, price float64, at time.Time, method CostBasisMethod) error {
 	position, exists := p.Positions[symbol]
 	if !exists {
 		return ErrPositionNotFound
@@ -77,22 +179,200 @@ func (p *Portfolio) RemovePosition(symbol Symbol, quantity float64, price float6
 		return ErrInsufficientQuantity
 	}
 	
+	fifoCost := p.consumeLots(position, quantity, price, at)
+	
 	realizedPnL := (price - position.AveragePrice) * quantity
+	if method == CostBasisFIFO {
+		realizedPnL = price*quantity - fifoCost
+	}
 	position.RealizedPnL += realizedPnL
 	position.Quantity -= quantity
 	position.UpdatedAt = time.Now()
+	if method == CostBasisFIFO {
+		position.AveragePrice = lotAveragePrice(position)
+	}
 	
 	if position.Quantity == 0 {
-		delete(p.Positions, symbol)
+		p.closePosition(position)
 	}
 	
 	p.Cash += quantity * price
 	p.TotalPnL += realizedPnL
+	p.DayPnL += realizedPnL
 	p.updateTotalValue()
 	
 	return nil
 }
 
+// consumeLots removes quantity from position's lots oldest first and returns
+// their total cost. Quantity not covered by lots, e.g. positions opened before
+// lots were tracked, is realized at the average price as of the position's
+// creation.
+func (p *Portfolio) consumeLots(position *Position, quantity float64, price float64, at time.Time) float64 {
+	cost := 0.0
+	remaining := quantity
+	for remaining > 0 && len(position.Lots) > 0 {
+		lot := &position.Lots[0]
+		taken := math.Min(lot.Quantity, remaining)
+		p.realizeLot(position.Symbol, taken, lot.Price, price, lot.OpenedAt, at)
+		cost += taken * lot.Price
+		
+		lot.Quantity -= taken
+		remaining -= taken
+		if lot.Quantity <= lotEpsilon {
+			position.Lots = position.Lots[1:]
+		}
+	}
+	
+	if remaining > lotEpsilon {
+		p.realizeLot(position.Symbol, remaining, position.AveragePrice, price, position.CreatedAt, at)
+		cost += remaining * position.AveragePrice
+	}
+	return cost
+}
+
+// lotAveragePrice is the cost-weighted price of position's open lots, or its
+// current average price when the lots do not cover the whole quantity
+func lotAveragePrice(position *Position) float64 {
+	quantity, cost := 0.0, 0.0
+	for _, lot := range position.Lots {
+		quantity += lot.Quantity
+		cost += lot.Quantity * lot.Price
+	}
+	if quantity <= lotEpsilon || math.Abs(quantity-position.Quantity) > lotEpsilon {
+		return position.AveragePrice
+	}
+	return cost / quantity
+}
+
+// lotEpsilon absorbs float residue when lots are split across several sales
+const lotEpsilon = 1e-9
+
+func (p *Portfolio) realizeLot(symbol Symbol, quantity, costPrice, salePrice float64, openedAt, closedAt time.Time) {
+	p.RealizedLots = append(p.RealizedLots, RealizedLot{
+		Symbol:    symbol,
+		Quantity:  quantity,
+		CostPrice: costPrice,
+		SalePrice: salePrice,
+		Gain:      (salePrice - costPrice) * quantity,
+		OpenedAt:  openedAt,
+		ClosedAt:  closedAt,
+	})
+}
+
+// ChargeFees deducts trading fees from cash and attributes them to the symbol's
+// position, or to its closed record if the position is no longer open
+func (p *Portfolio) ChargeFees(symbol Symbol, fees float64) {
+	if fees == 0 {
+		return
+	}
+	
+	if position, exists := p.Positions[symbol]; exists {
+		position.Fees += fees
+		position.UpdatedAt = time.Now()
+	} else {
+		p.closedRecord(symbol).Fees += fees
+	}
+	
+	p.Cash -= fees
+	p.TotalPnL -= fees
+	p.DayPnL -= fees
+	p.updateTotalValue()
+}
+
+// closePosition moves a fully closed position's realized P&L and fees into
+// ClosedPositions so they remain attributable after the position is removed
+func (p *Portfolio) closePosition(position *Position) {
+	closed := p.closedRecord(position.Symbol)
+	closed.RealizedPnL += position.RealizedPnL
+	closed.Fees += position.Fees
+	closed.UpdatedAt = time.Now()
+	
+	delete(p.Positions, position.Symbol)
+}
+
+func (p *Portfolio) closedRecord(symbol Symbol) *Position {
+	if p.ClosedPositions == nil {
+		p.ClosedPositions = make(map[Symbol]*Position)
+	}
+	
+	closed, exists := p.ClosedPositions[symbol]
+	if !exists {
+		now := time.Now()
+		closed = &Position{
+			ID:        PositionID(generateID()),
+			Symbol:    symbol,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		p.ClosedPositions[symbol] = closed
+	}
+	return closed
+}
+
+// CreditDividend pays perShare on the symbol's position into cash and records
+// the cash flow. Cost basis is unaffected; a short position pays the dividend.
+func (p *Portfolio) CreditDividend(symbol Symbol, perShare float64, at time.Time) (float64, error) {
+	position, exists := p.Positions[symbol]
+	if !exists {
+		return 0, ErrPositionNotFound
+	}
+	
+	amount := position.Quantity * perShare
+	p.Cash += amount
+	p.CashFlows = append(p.CashFlows, CashFlow{
+		Type:      CashFlowDividend,
+		Symbol:    symbol,
+		Amount:    amount,
+		Timestamp: at,
+	})
+	p.updateTotalValue()
+	
+	return amount, nil
+}
+
+// Split multiplies the symbol's quantity by ratio and divides its prices by it,
+// lot by lot, so cost basis and market value are unchanged
+func (p *Portfolio) Split(symbol Symbol, ratio float64, at time.Time) error {
+	position, exists := p.Positions[symbol]
+	if !exists {
+		return ErrPositionNotFound
+	}
+	
+	position.Quantity *= ratio
+	position.AveragePrice /= ratio
+	position.CurrentPrice /= ratio
+	for i := range position.Lots {
+		position.Lots[i].Quantity *= ratio
+		position.Lots[i].Price /= ratio
+	}
+	position.UpdatedAt = at
+	p.updateTotalValue()
+	
+	return nil
+}
+
+// RollDay moves DayPnL into PnLHistory under tradingDay and starts a new day at zero
+func (p *Portfolio) RollDay(tradingDay string, at time.Time) DailyPnL {
+	day := DailyPnL{
+		TradingDay: tradingDay,
+		PnL:        p.DayPnL,
+		SettledAt:  at,
+	}
+	p.PnLHistory = append(p.PnLHistory, day)
+	p.DayPnL = 0
+	p.LastUpdated = at
+	return day
+}
+
+// StartDay snapshots the start-of-day value and zeroes DayPnL at the session open
+func (p *Portfolio) StartDay(at time.Time) {
+	p.StartOfDayValue = p.TotalValue
+	p.DayStartedAt = at
+	p.DayPnL = 0
+	p.LastUpdated = at
+}
+
 func (p *Portfolio) UpdatePositionPrice(symbol Symbol, price float64) {
 	if position, exists := p.Positions[symbol]; exists {
 		position.CurrentPrice = price
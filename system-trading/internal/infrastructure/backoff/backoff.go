@@ -0,0 +1,86 @@
+package backoff
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// Backoff computes exponentially growing retry delays with optional jitter
+type Backoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// Factor multiplies the delay after each attempt; values below 1 are treated as 1
+	Factor float64
+	// Jitter spreads each delay uniformly over ±Jitter of its base value, e.g.
+	// 0.2 yields delays between 80% and 120% of the exponential delay
+	Jitter float64
+	// Source supplies the jitter; nil uses a shared randomly seeded source
+	Source ifs.JitterSource
+}
+
+// Delay returns the delay to wait before retry number attempt (starting at 1)
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	factor := b.Factor
+	if factor < 1 {
+		factor = 1
+	}
+
+	delay := float64(b.InitialDelay) * math.Pow(factor, float64(attempt-1))
+	if b.MaxDelay > 0 && delay > float64(b.MaxDelay) {
+		delay = float64(b.MaxDelay)
+	}
+
+	if b.Jitter > 0 {
+		source := b.Source
+		if source == nil {
+			source = defaultSource
+		}
+		jitter := math.Min(b.Jitter, 1)
+		delay *= 1 - jitter + 2*jitter*source.Float64()
+		if b.MaxDelay > 0 && delay > float64(b.MaxDelay) {
+			delay = float64(b.MaxDelay)
+		}
+	}
+
+	return time.Duration(delay)
+}
+
+var defaultSource = NewJitterSource()
+
+// SeededSource is a concurrency-safe JitterSource with a reproducible sequence
+type SeededSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewSeededSource returns a source that yields the same sequence for the same seed
+func NewSeededSource(seed uint64) *SeededSource {
+	return &SeededSource{rng: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// NewJitterSource returns a source seeded from crypto/rand, falling back to the
+// current time if the system entropy source is unavailable
+func NewJitterSource() *SeededSource {
+	var seed [8]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return NewSeededSource(uint64(time.Now().UnixNano()))
+	}
+	return NewSeededSource(binary.LittleEndian.Uint64(seed[:]))
+}
+
+// Float64 returns a value in [0, 1)
+func (s *SeededSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
@@ -0,0 +1,69 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_SeededJitterSequence(t *testing.T) {
+	newBackoff := func() Backoff {
+		return Backoff{
+			InitialDelay: 100 * time.Millisecond,
+			MaxDelay:     time.Second,
+			Factor:       2,
+			Jitter:       0.2,
+			Source:       NewSeededSource(42),
+		}
+	}
+
+	// Base delays are 100ms, 200ms, 400ms, 800ms, then capped at 1s
+	expected := []time.Duration{
+		113018900,
+		163425596,
+		444171687,
+		748627352,
+		time.Second,
+		time.Second,
+	}
+
+	for run := 0; run < 2; run++ {
+		b := newBackoff()
+		for i, want := range expected {
+			if got := b.Delay(i + 1); got != want {
+				t.Errorf("Run %d, attempt %d: expected %v, got %v", run, i+1, want, got)
+			}
+		}
+	}
+}
+
+func TestBackoff_WithoutJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff Backoff
+		attempt int
+		want    time.Duration
+	}{
+		{"first attempt", Backoff{InitialDelay: time.Second, Factor: 2}, 1, time.Second},
+		{"grows exponentially", Backoff{InitialDelay: time.Second, Factor: 2}, 4, 8 * time.Second},
+		{"capped", Backoff{InitialDelay: time.Second, MaxDelay: 5 * time.Second, Factor: 2}, 4, 5 * time.Second},
+		{"constant when factor unset", Backoff{InitialDelay: time.Second}, 3, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.backoff.Delay(tt.attempt); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoff_JitterStaysWithinBounds(t *testing.T) {
+	b := Backoff{InitialDelay: time.Second, Jitter: 0.5, Source: NewJitterSource()}
+
+	for i := 0; i < 1000; i++ {
+		if got := b.Delay(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jittered delay %v outside ±50%% of 1s", got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package brokers
+
+import (
+	"math"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// FeeModel decides the commission the mock broker charges on a fill of
+// quantity at price
+type FeeModel interface {
+	Fee(order *entities.Order, quantity, price float64) float64
+}
+
+// PerShareFeeModel charges PerShare for each share, at least Minimum per fill
+type PerShareFeeModel struct {
+	PerShare float64
+	Minimum  float64
+}
+
+func (m PerShareFeeModel) Fee(order *entities.Order, quantity, price float64) float64 {
+	return math.Max(quantity*m.PerShare, m.Minimum)
+}
+
+// PercentageFeeModel charges Rate of the fill's notional, at least Minimum per
+// fill
+type PercentageFeeModel struct {
+	Rate    float64
+	Minimum float64
+}
+
+func (m PercentageFeeModel) Fee(order *entities.Order, quantity, price float64) float64 {
+	return math.Max(quantity*price*m.Rate, m.Minimum)
+}
+
+// FlatFeeModel charges Amount on every fill regardless of its size
+type FlatFeeModel struct {
+	Amount float64
+}
+
+func (m FlatFeeModel) Fee(order *entities.Order, quantity, price float64) float64 {
+	return m.Amount
+}
+
+// SlippageModel moves a market fill price against the order, simulating the
+// impact of taking liquidity
+type SlippageModel interface {
+	Slip(order *entities.Order, price float64) float64
+}
+
+// NoSlippage fills at the price it is given
+type NoSlippage struct{}
+
+func (NoSlippage) Slip(order *entities.Order, price float64) float64 { return price }
+
+// LinearSlippageModel moves the price against the order by RatePerUnit of the
+// price for each unit of quantity: a buy of 100 at rate 0.0001 pays 1% more
+type LinearSlippageModel struct {
+	RatePerUnit float64
+}
+
+func (m LinearSlippageModel) Slip(order *entities.Order, price float64) float64 {
+	impact := price * m.RatePerUnit * order.Quantity
+	if order.Side == entities.OrderSideSell {
+		return math.Max(price-impact, 0)
+	}
+	return price + impact
+}
+
+// PriceEstimator prices an order before it fills, for the mock broker's
+// buying power check and fee estimates
+type PriceEstimator interface {
+	EstimatePrice(order *entities.Order, quote MarketQuote) float64
+}
+
+// OrderPriceEstimator prices an order at its limit or stop price, else the
+// last price, else Fallback
+type OrderPriceEstimator struct {
+	Fallback float64
+}
+
+func (e OrderPriceEstimator) EstimatePrice(order *entities.Order, quote MarketQuote) float64 {
+	switch {
+	case order.Price != nil:
+		return *order.Price
+	case order.StopPrice != nil:
+		return *order.StopPrice
+	case quote.LastPrice > 0:
+		return quote.LastPrice
+	}
+	return e.Fallback
+}
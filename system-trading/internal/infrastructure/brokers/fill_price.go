@@ -0,0 +1,132 @@
+package brokers
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// BookLevel is the resting quantity at one price in a simulated order book
+type BookLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBook is a simulated order book with the best price first on each side
+type OrderBook struct {
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+// MarketQuote is the mock broker's view of a symbol's market when an order fills.
+// A zero LastPrice means no trade price has been set.
+type MarketQuote struct {
+	LastPrice float64
+	Book      OrderBook
+}
+
+// FillPriceModel decides the price a mock order fills at
+type FillPriceModel interface {
+	FillPrice(order *entities.Order, quote MarketQuote) float64
+}
+
+// FixedPriceModel fills every order at Price
+type FixedPriceModel struct {
+	Price float64
+}
+
+func (m FixedPriceModel) FillPrice(order *entities.Order, quote MarketQuote) float64 {
+	return m.Price
+}
+
+// LastPriceModel fills at the symbol's last traded price, or Fallback if none is set
+type LastPriceModel struct {
+	Fallback float64
+}
+
+func (m LastPriceModel) FillPrice(order *entities.Order, quote MarketQuote) float64 {
+	if quote.LastPrice > 0 {
+		return quote.LastPrice
+	}
+	return m.Fallback
+}
+
+// MidPriceModel fills at the midpoint of the best bid and ask, falling back to
+// the last traded price and then Fallback when either side of the book is empty
+type MidPriceModel struct {
+	Fallback float64
+}
+
+func (m MidPriceModel) FillPrice(order *entities.Order, quote MarketQuote) float64 {
+	if len(quote.Book.Bids) > 0 && len(quote.Book.Asks) > 0 {
+		return (quote.Book.Bids[0].Price + quote.Book.Asks[0].Price) / 2
+	}
+	return LastPriceModel{Fallback: m.Fallback}.FillPrice(order, quote)
+}
+
+// RandomPriceModel fills uniformly within Base ± Spread
+type RandomPriceModel struct {
+	Base   float64
+	Spread float64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRandomPriceModel creates a random model. A nil source uses the global
+// generator; pass a seeded source for repeatable fills.
+func NewRandomPriceModel(base, spread float64, source rand.Source) *RandomPriceModel {
+	model := &RandomPriceModel{Base: base, Spread: spread}
+	if source != nil {
+		model.rng = rand.New(source)
+	}
+	return model
+}
+
+func (m *RandomPriceModel) FillPrice(order *entities.Order, quote MarketQuote) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sample := rand.Float64
+	if m.rng != nil {
+		sample = m.rng.Float64
+	}
+	return m.Base + (sample()-0.5)*2*m.Spread
+}
+
+// BookWalkModel fills at the volume-weighted price of walking the opposite side
+// of the book for the order's quantity. Quantity beyond the book's depth fills
+// at the last level. An empty side falls back to the last traded price and then
+// Fallback.
+type BookWalkModel struct {
+	Fallback float64
+}
+
+func (m BookWalkModel) FillPrice(order *entities.Order, quote MarketQuote) float64 {
+	levels := quote.Book.Asks
+	if order.Side == entities.OrderSideSell {
+		levels = quote.Book.Bids
+	}
+	if len(levels) == 0 || order.Quantity <= 0 {
+		return LastPriceModel{Fallback: m.Fallback}.FillPrice(order, quote)
+	}
+
+	remaining := order.Quantity
+	notional := 0.0
+	for _, level := range levels {
+		take := level.Quantity
+		if take > remaining {
+			take = remaining
+		}
+		notional += take * level.Price
+		remaining -= take
+		if remaining <= 0 {
+			break
+		}
+	}
+	if remaining > 0 {
+		notional += remaining * levels[len(levels)-1].Price
+	}
+	return notional / order.Quantity
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
 	"github.com/system-trading/core/internal/interfaces"
 	ifs "github.com/system-trading/core/internal/usecases/interfaces"
 )
@@ -20,16 +21,40 @@ type MockBroker struct {
 	account     *interfaces.AccountInfo
 	latency     time.Duration
 	errorRate   float64
+	synchronous bool
+	failNext     int
+	failNextCode string
+	fillModel   FillPriceModel
+	lastPrices  map[string]float64
+	books       map[string]OrderBook
+	walker      *PriceWalker
+	feeModel    FeeModel
+	estimator   PriceEstimator
+	hours       MarketHours
+	halted      map[string]bool
+	history     []*MockOrder
+	slippage    SlippageModel
+	clock       ifs.Clock
+	rng         *rand.Rand
+	rngMu       sync.Mutex
+	orderSeq    int
 	mu          sync.RWMutex
 	logger      ifs.Logger
 }
 
+// MarketHours reports whether the market is open at a time;
+// *usecases.TradingCalendar is one
+type MarketHours interface {
+	IsOpen(t time.Time) bool
+}
+
 // MockOrder represents an order in the mock broker
 type MockOrder struct {
 	Order         *entities.Order
 	BrokerOrderID string
 	Status        entities.OrderStatus
 	Fills         []interfaces.Fill
+	Events        []OrderEvent
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -42,6 +67,14 @@ func NewMockBroker(name string, logger ifs.Logger) *MockBroker {
 		orders:    make(map[string]*MockOrder),
 		latency:   100 * time.Millisecond, // Simulate network latency
 		errorRate: 0.01,                   // 1% error rate
+		fillModel: NewRandomPriceModel(100, 1, nil), // $100 ±$1
+		lastPrices: make(map[string]float64),
+		books:      make(map[string]OrderBook),
+		halted:     make(map[string]bool),
+		feeModel:   PerShareFeeModel{PerShare: 0.005, Minimum: 1}, // $0.005/share, min $1
+		slippage:   NoSlippage{},
+		estimator:  OrderPriceEstimator{Fallback: 100},
+		clock:      clock.NewRealClock(),
 		logger:    logger,
 		account: &interfaces.AccountInfo{
 			AccountID:   "MOCK_ACCOUNT_001",
@@ -61,13 +94,13 @@ func (mb *MockBroker) Connect(ctx context.Context) error {
 	
 	// Simulate connection time
 	select {
-	case <-time.After(mb.latency):
+	case <-mb.clock.After(mb.latency):
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 	
 	// Simulate occasional connection failures
-	if rand.Float64() < mb.errorRate {
+	if mb.randFloat64() < mb.errorRate {
 		return &interfaces.BrokerError{
 			Code:    "CONNECTION_FAILED",
 			Message: "Failed to connect to mock broker",
@@ -109,46 +142,95 @@ func (mb *MockBroker) PlaceOrder(ctx context.Context, order *entities.Order) (*i
 	
 	// Simulate processing time
 	select {
-	case <-time.After(mb.latency):
+	case <-mb.clock.After(mb.latency):
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 	
-	// Simulate occasional order rejection
-	if rand.Float64() < mb.errorRate {
+	if mb.failNext > 0 {
+		mb.failNext--
 		return nil, &interfaces.BrokerError{
-			Code:    "ORDER_REJECTED",
-			Message: "Order rejected by mock broker",
-			Details: fmt.Sprintf("Simulated rejection for order %s", order.ID),
+			Code:    mb.failNextCode,
+			Message: "Scripted failure from mock broker",
+			Details: fmt.Sprintf("Scripted failure for order %s", order.ID),
 		}
 	}
 	
 	// Generate broker order ID
-	brokerOrderID := fmt.Sprintf("MOCK_%d", time.Now().UnixNano())
+	mb.orderSeq++
+	brokerOrderID := fmt.Sprintf("MOCK_%d_%d", mb.clock.Now().UnixNano(), mb.orderSeq)
 	
 	// Create mock order
 	mockOrder := &MockOrder{
-		Order:         order,
+		Order:         order.Clone(),
 		BrokerOrderID: brokerOrderID,
-		Status:        entities.OrderStatusPending,
 		Fills:         []interfaces.Fill{},
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		CreatedAt:     mb.clock.Now(),
 	}
+	mb.history = append(mb.history, mockOrder)
 	
-	mb.orders[brokerOrderID] = mockOrder
-	
-	// For market orders, simulate immediate execution
-	if order.Type == entities.OrderTypeMarket {
-		go mb.simulateExecution(brokerOrderID)
+	if err := mb.admitLocked(order); err != nil {
+		mb.recordLocked(mockOrder, "rejected", entities.OrderStatusRejected)
+		return nil, err
 	}
 	
+	mb.recordLocked(mockOrder, "placed", entities.OrderStatusPending)
+	mb.orders[brokerOrderID] = mockOrder
+	mb.refreshBuyingPowerLocked()
+	
 	result := &interfaces.OrderResult{
 		BrokerOrderID: brokerOrderID,
 		Status:        entities.OrderStatusPending,
 		Message:       "Order submitted successfully",
-		Timestamp:     time.Now(),
-		Fees:          mb.calculateFees(order),
+		Timestamp:     mb.clock.Now(),
+		Fees:          mb.estimateFees(order),
+	}
+	
+	// IOC and FOK orders resolve against the market as it stands on arrival
+	if order.IsImmediate() {
+		mb.fillImmediateLocked(mockOrder)
+		result.Status = mockOrder.Status
+		if len(mockOrder.Fills) > 0 {
+			fill := mockOrder.Fills[0]
+			result.ExecutedPrice = &fill.Price
+			result.ExecutedQty = &fill.Quantity
+			result.Fees = fill.Fees
+		}
+		if mockOrder.Status == entities.OrderStatusCancelled {
+			result.Message = fmt.Sprintf("%s order unfilled remainder cancelled", order.TimeInForce)
+		}
+		
+		mb.logger.Info("Immediate order resolved by mock broker",
+			ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
+			ifs.Field{Key: "time_in_force", Value: string(order.TimeInForce)},
+			ifs.Field{Key: "status", Value: string(mockOrder.Status)},
+		)
+		return result, nil
+	}
+	
+	// A stop already through the last price triggers on arrival
+	if order.Type == entities.OrderTypeStop && mockOrder.Order.StopTriggered(mb.lastPrices[string(order.Symbol)]) {
+		mb.triggerStopLocked(mockOrder)
+		if mockOrder.Status == entities.OrderStatusExecuted {
+			fill := mockOrder.Fills[len(mockOrder.Fills)-1]
+			result.Status = entities.OrderStatusExecuted
+			result.ExecutedPrice = &fill.Price
+			result.ExecutedQty = &fill.Quantity
+			result.Fees = fill.Fees
+		}
+	}
+	
+	// For market orders, simulate immediate execution
+	if order.Type == entities.OrderTypeMarket {
+		if mb.synchronous {
+			fill := mb.executeLocked(mockOrder)
+			result.Status = entities.OrderStatusExecuted
+			result.ExecutedPrice = &fill.Price
+			result.ExecutedQty = &fill.Quantity
+			result.Fees = fill.Fees
+		} else {
+			go mb.simulateExecution(brokerOrderID)
+		}
 	}
 	
 	mb.logger.Info("Order placed with mock broker",
@@ -188,8 +270,8 @@ func (mb *MockBroker) CancelOrder(ctx context.Context, orderID string) error {
 		}
 	}
 	
-	mockOrder.Status = entities.OrderStatusCancelled
-	mockOrder.UpdatedAt = time.Now()
+	mb.recordLocked(mockOrder, "cancelled", entities.OrderStatusCancelled)
+	mb.refreshBuyingPowerLocked()
 	
 	mb.logger.Info("Order cancelled",
 		ifs.Field{Key: "broker_order_id", Value: orderID},
@@ -198,10 +280,51 @@ func (mb *MockBroker) CancelOrder(ctx context.Context, orderID string) error {
 	return nil
 }
 
-// GetOrderStatus retrieves the current status of an order
+// ReplaceOrder reprices a pending limit order
+func (mb *MockBroker) ReplaceOrder(ctx context.Context, orderID string, newPrice float64) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	
+	if !mb.connected {
+		return &interfaces.BrokerError{
+			Code:    "NOT_CONNECTED",
+			Message: "Not connected to broker",
+		}
+	}
+	
+	mockOrder, exists := mb.orders[orderID]
+	if !exists {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_FOUND",
+			Message: "Order not found",
+		}
+	}
+	
+	if mockOrder.Status != entities.OrderStatusPending || mockOrder.Order.Type != entities.OrderTypeLimit {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_REPLACEABLE",
+			Message: "Only pending limit orders can be replaced",
+			Details: fmt.Sprintf("Order %s is a %s order in status %s", orderID, mockOrder.Order.Type, mockOrder.Status),
+		}
+	}
+	
+	mockOrder.Order.Price = &newPrice
+	mb.recordLocked(mockOrder, "replaced", mockOrder.Status)
+	mb.refreshBuyingPowerLocked()
+	
+	mb.logger.Info("Order replaced",
+		ifs.Field{Key: "broker_order_id", Value: orderID},
+		ifs.Field{Key: "new_price", Value: newPrice},
+	)
+	
+	return nil
+}
+
+// GetOrderStatus retrieves the current status of an order. With a price walker
+// set, polling a pending order first moves its symbol's price a step.
 func (mb *MockBroker) GetOrderStatus(ctx context.Context, orderID string) (*interfaces.OrderStatus, error) {
-	mb.mu.RLock()
-	defer mb.mu.RUnlock()
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
 	
 	if !mb.connected {
 		return nil, &interfaces.BrokerError{
@@ -218,16 +341,20 @@ func (mb *MockBroker) GetOrderStatus(ctx context.Context, orderID string) (*inte
 		}
 	}
 	
+	if mockOrder.Status == entities.OrderStatusPending {
+		mb.stepPriceLocked(string(mockOrder.Order.Symbol))
+	}
+	
 	status := &interfaces.OrderStatus{
 		BrokerOrderID: mockOrder.BrokerOrderID,
 		Status:        mockOrder.Status,
-		Fees:          mb.calculateFees(mockOrder.Order),
+		Fees:          mb.estimateFees(mockOrder.Order),
 		LastUpdate:    mockOrder.UpdatedAt,
 		Fills:         mockOrder.Fills,
 	}
 	
-	// Add execution details if executed
-	if mockOrder.Status == entities.OrderStatusExecuted && len(mockOrder.Fills) > 0 {
+	// Add execution details once anything has filled
+	if len(mockOrder.Fills) > 0 {
 		totalQuantity := 0.0
 		weightedPrice := 0.0
 		
@@ -240,6 +367,7 @@ func (mb *MockBroker) GetOrderStatus(ctx context.Context, orderID string) (*inte
 			avgPrice := weightedPrice / totalQuantity
 			status.ExecutedPrice = &avgPrice
 			status.ExecutedQty = &totalQuantity
+			status.Fees = mb.calculateFees(mockOrder.Order, totalQuantity, avgPrice)
 			remaining := mockOrder.Order.Quantity - totalQuantity
 			status.RemainingQty = &remaining
 		}
@@ -262,7 +390,7 @@ func (mb *MockBroker) GetAccountInfo(ctx context.Context) (*interfaces.AccountIn
 	
 	// Return a copy of account info
 	accountCopy := *mb.account
-	accountCopy.LastUpdated = time.Now()
+	accountCopy.LastUpdated = mb.clock.Now()
 	
 	return &accountCopy, nil
 }
@@ -286,11 +414,336 @@ func (mb *MockBroker) SetErrorRate(rate float64) {
 	mb.errorRate = rate
 }
 
-// simulateExecution simulates order execution for market orders
+// FailNextOrders makes the next count PlaceOrder calls fail with a BrokerError
+// carrying code, regardless of the error rate
+func (mb *MockBroker) FailNextOrders(count int, code string) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.failNext = count
+	mb.failNextCode = code
+}
+
+// SetLatency sets the simulated network delay of Connect and PlaceOrder
+func (mb *MockBroker) SetLatency(latency time.Duration) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.latency = latency
+}
+
+// SetSynchronous makes market orders execute inline in PlaceOrder instead of on a
+// background goroutine, so tests can observe fills without sleeping
+func (mb *MockBroker) SetSynchronous(synchronous bool) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.synchronous = synchronous
+}
+
+// SetRandSource makes the broker draw its simulated errors and execution
+// delays from rng, so a seeded source gives a repeatable run. Nil restores the
+// global generator.
+func (mb *MockBroker) SetRandSource(rng *rand.Rand) {
+	mb.rngMu.Lock()
+	defer mb.rngMu.Unlock()
+	mb.rng = rng
+}
+
+// SetClock replaces the clock used for simulated latency, execution delays and
+// timestamps; a fake clock lets tests run without real sleeps
+func (mb *MockBroker) SetClock(clk ifs.Clock) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.clock = clk
+}
+
+func (mb *MockBroker) randFloat64() float64 {
+	mb.rngMu.Lock()
+	defer mb.rngMu.Unlock()
+	if mb.rng == nil {
+		return rand.Float64()
+	}
+	return mb.rng.Float64()
+}
+
+func (mb *MockBroker) randIntn(n int) int {
+	mb.rngMu.Lock()
+	defer mb.rngMu.Unlock()
+	if mb.rng == nil {
+		return rand.Intn(n)
+	}
+	return mb.rng.Intn(n)
+}
+
+// SetFillPriceModel chooses how this broker prices fills
+func (mb *MockBroker) SetFillPriceModel(model FillPriceModel) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.fillModel = model
+}
+
+// SetMarketPrice records the last traded price for symbol, triggers any pending
+// stop orders on it that the price has crossed and fills any resting limit
+// orders it has reached
+func (mb *MockBroker) SetMarketPrice(symbol string, price float64) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.setMarketPriceLocked(symbol, price)
+}
+
+// SetFeeModel replaces the default commission of $0.005 per share, minimum $1
+func (mb *MockBroker) SetFeeModel(model FeeModel) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.feeModel = model
+}
+
+// SetSlippageModel moves market execution prices against the order after the
+// fill price model picks them. Limit and immediate fills are not slipped.
+func (mb *MockBroker) SetSlippageModel(model SlippageModel) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	if model == nil {
+		model = NoSlippage{}
+	}
+	mb.slippage = model
+}
+
+// SetPriceEstimator replaces how orders are priced before they fill, for the
+// buying power check and fee estimates
+func (mb *MockBroker) SetPriceEstimator(estimator PriceEstimator) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.estimator = estimator
+}
+
+// SetCashBalance sets the account's cash and, with it, its buying power
+func (mb *MockBroker) SetCashBalance(cash float64) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.account.CashBalance = cash
+	mb.refreshBuyingPowerLocked()
+}
+
+// SetMarketHours makes orders placed while hours reports the market closed,
+// by the broker's clock, fail with MARKET_CLOSED. Nil keeps the market open.
+func (mb *MockBroker) SetMarketHours(hours MarketHours) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.hours = hours
+}
+
+// HaltSymbol makes orders for symbol fail with SYMBOL_HALTED until it resumes
+func (mb *MockBroker) HaltSymbol(symbol string) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.halted[symbol] = true
+}
+
+// ResumeSymbol lifts a halt on symbol
+func (mb *MockBroker) ResumeSymbol(symbol string) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	delete(mb.halted, symbol)
+}
+
+// SetPriceWalker makes the simulated market move on its own: polling the status
+// of a pending order and each asynchronous market execution advance the
+// symbol's price one step, with the same effect as SetMarketPrice. Market
+// orders still fill at the fill price model's price; pair the walker with
+// LastPriceModel to fill them at the walked price. Nil stops the walk.
+func (mb *MockBroker) SetPriceWalker(walker *PriceWalker) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.walker = walker
+}
+
+// stepPriceLocked advances symbol's price one step of the walk, if there is
+// one. Must be called with mb.mu held.
+func (mb *MockBroker) stepPriceLocked(symbol string) {
+	if mb.walker == nil {
+		return
+	}
+	mb.setMarketPriceLocked(symbol, mb.walker.Next(symbol, mb.lastPrices[symbol]))
+}
+
+// setMarketPriceLocked is SetMarketPrice with mb.mu held
+func (mb *MockBroker) setMarketPriceLocked(symbol string, price float64) {
+	mb.lastPrices[symbol] = price
+	
+	for _, mockOrder := range mb.orders {
+		if mockOrder.Status != entities.OrderStatusPending || string(mockOrder.Order.Symbol) != symbol {
+			continue
+		}
+		switch {
+		case mockOrder.Order.StopTriggered(price):
+			mb.triggerStopLocked(mockOrder)
+		case limitReached(mockOrder.Order, price):
+			mb.fillLimitLocked(mockOrder, price)
+		}
+	}
+}
+
+// limitReached reports whether price is at or through a limit order's limit:
+// at or below it for a buy, at or above it for a sell
+func limitReached(order *entities.Order, price float64) bool {
+	if order.Type != entities.OrderTypeLimit || order.Price == nil || price <= 0 {
+		return false
+	}
+	if order.Side == entities.OrderSideBuy {
+		return price <= *order.Price
+	}
+	return price >= *order.Price
+}
+
+// fillLimitLocked fills what remains of a resting limit order at the market
+// price, which limitReached guarantees is at the limit or better. Must be
+// called with mb.mu held.
+func (mb *MockBroker) fillLimitLocked(mockOrder *MockOrder, price float64) {
+	filled := 0.0
+	for _, fill := range mockOrder.Fills {
+		filled += fill.Quantity
+	}
+	quantity := mockOrder.Order.Quantity - filled
+	fees := mb.calculateFees(mockOrder.Order, quantity, price)
+	
+	mockOrder.Fills = append(mockOrder.Fills, interfaces.Fill{
+		Price:     price,
+		Quantity:  quantity,
+		Fees:      fees,
+		Timestamp: mb.clock.Now(),
+	})
+	mb.recordLocked(mockOrder, "filled", entities.OrderStatusExecuted)
+	
+	mb.updateAccountPosition(string(mockOrder.Order.Symbol), mockOrder.Order.Side, quantity, price, fees)
+	
+	mb.logger.Info("Mock limit order filled",
+		ifs.Field{Key: "broker_order_id", Value: mockOrder.BrokerOrderID},
+		ifs.Field{Key: "limit_price", Value: *mockOrder.Order.Price},
+		ifs.Field{Key: "price", Value: price},
+		ifs.Field{Key: "quantity", Value: quantity},
+	)
+}
+
+// triggerStopLocked turns a stop order into a market order and executes it like
+// any other market order. Must be called with mb.mu held.
+func (mb *MockBroker) triggerStopLocked(mockOrder *MockOrder) {
+	mockOrder.Order.Type = entities.OrderTypeMarket
+	mb.recordLocked(mockOrder, "triggered", mockOrder.Status)
+	
+	mb.logger.Info("Mock stop order triggered",
+		ifs.Field{Key: "broker_order_id", Value: mockOrder.BrokerOrderID},
+		ifs.Field{Key: "stop_price", Value: *mockOrder.Order.StopPrice},
+		ifs.Field{Key: "last_price", Value: mb.lastPrices[string(mockOrder.Order.Symbol)]},
+	)
+	
+	if mb.synchronous {
+		mb.executeLocked(mockOrder)
+	} else {
+		go mb.simulateExecution(mockOrder.BrokerOrderID)
+	}
+}
+
+// SetOrderBook replaces the simulated order book for symbol
+func (mb *MockBroker) SetOrderBook(symbol string, book OrderBook) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.books[symbol] = book
+}
+
+// BestBidAsk returns the top of the simulated order book for symbol. ok is false
+// when either side of the book is empty.
+func (mb *MockBroker) BestBidAsk(symbol string) (bid, ask float64, ok bool) {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+	
+	book := mb.books[symbol]
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0, 0, false
+	}
+	return book.Bids[0].Price, book.Asks[0].Price, true
+}
+
+// ForceExecute fills a pending order immediately, regardless of its type
+func (mb *MockBroker) ForceExecute(brokerOrderID string) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	
+	mockOrder, exists := mb.orders[brokerOrderID]
+	if !exists {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_FOUND",
+			Message: "Order not found",
+		}
+	}
+	
+	if mockOrder.Status != entities.OrderStatusPending {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_PENDING",
+			Message: "Only pending orders can be executed",
+			Details: fmt.Sprintf("Order %s is %s", brokerOrderID, mockOrder.Status),
+		}
+	}
+	
+	mb.executeLocked(mockOrder)
+	return nil
+}
+
+// FillPartially fills quantity of a pending order at price, executing the order
+// once its cumulative fills reach its quantity
+func (mb *MockBroker) FillPartially(brokerOrderID string, quantity, price float64) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	
+	mockOrder, exists := mb.orders[brokerOrderID]
+	if !exists {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_FOUND",
+			Message: "Order not found",
+		}
+	}
+	
+	if mockOrder.Status != entities.OrderStatusPending {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_NOT_PENDING",
+			Message: "Only pending orders can be filled",
+			Details: fmt.Sprintf("Order %s is %s", brokerOrderID, mockOrder.Status),
+		}
+	}
+	
+	filled := 0.0
+	for _, fill := range mockOrder.Fills {
+		filled += fill.Quantity
+	}
+	if remaining := mockOrder.Order.Quantity - filled; quantity > remaining {
+		quantity = remaining
+	}
+	
+	fees := mb.calculateFees(mockOrder.Order, quantity, price)
+	mockOrder.Fills = append(mockOrder.Fills, interfaces.Fill{
+		Price:     price,
+		Quantity:  quantity,
+		Fees:      fees,
+		Timestamp: mb.clock.Now(),
+	})
+	if filled+quantity >= mockOrder.Order.Quantity {
+		mb.recordLocked(mockOrder, "filled", entities.OrderStatusExecuted)
+	} else {
+		mb.recordLocked(mockOrder, "partially_filled", mockOrder.Status)
+	}
+	
+	mb.updateAccountPosition(string(mockOrder.Order.Symbol), mockOrder.Order.Side, quantity, price, fees)
+	return nil
+}
+
+// simulateExecution executes a market order after a random delay, moving the
+// price walk a step first when one is set
 func (mb *MockBroker) simulateExecution(brokerOrderID string) {
+	mb.mu.RLock()
+	clk := mb.clock
+	mb.mu.RUnlock()
+	
 	// Wait for a random execution delay (50-500ms)
-	delay := time.Duration(50+rand.Intn(450)) * time.Millisecond
-	time.Sleep(delay)
+	delay := time.Duration(50+mb.randIntn(450)) * time.Millisecond
+	<-clk.After(delay)
 	
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
@@ -300,52 +753,240 @@ func (mb *MockBroker) simulateExecution(brokerOrderID string) {
 		return
 	}
 	
-	// Simulate market price with small random variation
-	basePrice := 100.0 // Default price
-	marketPrice := basePrice + (rand.Float64()-0.5)*2 // ±$1 variation
+	mb.stepPriceLocked(string(mockOrder.Order.Symbol))
+	mb.executeLocked(mockOrder)
+}
+
+// executeLocked fills a pending order at the price chosen by the fill price
+// model. Must be called with mb.mu held.
+func (mb *MockBroker) executeLocked(mockOrder *MockOrder) interfaces.Fill {
+	brokerOrderID := mockOrder.BrokerOrderID
+	
+	symbol := string(mockOrder.Order.Symbol)
+	marketPrice := mb.fillModel.FillPrice(mockOrder.Order, MarketQuote{
+		LastPrice: mb.lastPrices[symbol],
+		Book:      mb.books[symbol],
+	})
+	marketPrice = mb.slippage.Slip(mockOrder.Order, marketPrice)
 	
 	// Create fill
 	fill := interfaces.Fill{
 		Price:     marketPrice,
 		Quantity:  mockOrder.Order.Quantity,
-		Fees:      mb.calculateFees(mockOrder.Order),
-		Timestamp: time.Now(),
+		Fees:      mb.calculateFees(mockOrder.Order, mockOrder.Order.Quantity, marketPrice),
+		Timestamp: mb.clock.Now(),
 	}
 	
 	mockOrder.Fills = append(mockOrder.Fills, fill)
-	mockOrder.Status = entities.OrderStatusExecuted
-	mockOrder.UpdatedAt = time.Now()
+	mb.recordLocked(mockOrder, "filled", entities.OrderStatusExecuted)
 	
 	// Update account positions
 	mb.updateAccountPosition(string(mockOrder.Order.Symbol), mockOrder.Order.Side, 
-		mockOrder.Order.Quantity, marketPrice)
+		mockOrder.Order.Quantity, marketPrice, fill.Fees)
 	
 	mb.logger.Info("Mock order executed",
 		ifs.Field{Key: "broker_order_id", Value: brokerOrderID},
 		ifs.Field{Key: "price", Value: marketPrice},
 		ifs.Field{Key: "quantity", Value: mockOrder.Order.Quantity},
 	)
+	
+	return fill
 }
 
-// calculateFees calculates commission fees for an order
-func (mb *MockBroker) calculateFees(order *entities.Order) float64 {
-	// Simple fee structure: $0.005 per share, minimum $1
-	fees := order.Quantity * 0.005
-	if fees < 1.0 {
-		fees = 1.0
+// fillImmediateLocked fills what an IOC or FOK order can take from the market
+// right now and cancels the rest. A FOK order that cannot fill completely is
+// cancelled without filling. Must be called with mb.mu held.
+func (mb *MockBroker) fillImmediateLocked(mockOrder *MockOrder) {
+	order := mockOrder.Order
+	symbol := string(order.Symbol)
+	quote := MarketQuote{LastPrice: mb.lastPrices[symbol], Book: mb.books[symbol]}
+	
+	available := immediateQuantity(order, quote)
+	if available > order.Quantity {
+		available = order.Quantity
+	}
+	if order.TimeInForce == entities.TimeInForceFOK && available < order.Quantity {
+		available = 0
+	}
+	
+	if available <= 0 {
+		mb.recordLocked(mockOrder, "cancelled", entities.OrderStatusCancelled)
+		return
 	}
-	return fees
+	
+	filled := order.Clone()
+	filled.Quantity = available
+	price := mb.fillModel.FillPrice(filled, quote)
+	fees := mb.calculateFees(filled, available, price)
+	mockOrder.Fills = append(mockOrder.Fills, interfaces.Fill{
+		Price:     price,
+		Quantity:  available,
+		Fees:      fees,
+		Timestamp: mb.clock.Now(),
+	})
+	if available >= order.Quantity {
+		mb.recordLocked(mockOrder, "filled", entities.OrderStatusExecuted)
+	} else {
+		mb.recordLocked(mockOrder, "partially_filled", entities.OrderStatusPending)
+		mb.recordLocked(mockOrder, "cancelled", entities.OrderStatusCancelled)
+	}
+	
+	mb.updateAccountPosition(symbol, order.Side, available, price, fees)
 }
 
-// updateAccountPosition updates account cash and positions after execution
+// immediateQuantity is how much of order the market can fill on arrival: the
+// opposite side of the book within the order's limit, or the whole order when
+// there is no book and the last price is marketable
+func immediateQuantity(order *entities.Order, quote MarketQuote) float64 {
+	marketable := func(price float64) bool {
+		switch {
+		case order.Type == entities.OrderTypeLimit && order.Price != nil:
+			if order.Side == entities.OrderSideBuy {
+				return price <= *order.Price
+			}
+			return price >= *order.Price
+		case order.Type == entities.OrderTypeStop:
+			return order.StopTriggered(quote.LastPrice)
+		}
+		return true
+	}
+	
+	levels := quote.Book.Asks
+	if order.Side == entities.OrderSideSell {
+		levels = quote.Book.Bids
+	}
+	if len(levels) == 0 {
+		if order.Type == entities.OrderTypeMarket || (quote.LastPrice > 0 && marketable(quote.LastPrice)) {
+			return order.Quantity
+		}
+		return 0
+	}
+	
+	available := 0.0
+	for _, level := range levels {
+		if !marketable(level.Price) {
+			break
+		}
+		available += level.Quantity
+	}
+	return available
+}
+
+// calculateFees is the fee model's commission on a fill of quantity at price
+func (mb *MockBroker) calculateFees(order *entities.Order, quantity, price float64) float64 {
+	return mb.feeModel.Fee(order, quantity, price)
+}
+
+// estimateFees is the commission on the whole order before it fills, at the
+// price estimator's price
+func (mb *MockBroker) estimateFees(order *entities.Order) float64 {
+	return mb.calculateFees(order, order.Quantity, mb.estimatePriceLocked(order))
+}
+
+// estimatePriceLocked prices order before it fills. Must be called with mb.mu
+// held.
+func (mb *MockBroker) estimatePriceLocked(order *entities.Order) float64 {
+	symbol := string(order.Symbol)
+	return mb.estimator.EstimatePrice(order, MarketQuote{
+		LastPrice: mb.lastPrices[symbol],
+		Book:      mb.books[symbol],
+	})
+}
+
+// admitLocked decides whether the broker accepts order: it may be rejected at
+// random, outside market hours, on a halted symbol or for lack of buying power.
+// Must be called with mb.mu held.
+func (mb *MockBroker) admitLocked(order *entities.Order) error {
+	// Simulate occasional order rejection
+	if mb.randFloat64() < mb.errorRate {
+		return &interfaces.BrokerError{
+			Code:    "ORDER_REJECTED",
+			Message: "Order rejected by mock broker",
+			Details: fmt.Sprintf("Simulated rejection for order %s", order.ID),
+		}
+	}
+	
+	if err := mb.checkTradableLocked(order); err != nil {
+		return err
+	}
+	return mb.checkBuyingPowerLocked(order)
+}
+
+// checkTradableLocked rejects an order placed outside market hours, when they
+// are set, or on a halted symbol. Must be called with mb.mu held.
+func (mb *MockBroker) checkTradableLocked(order *entities.Order) error {
+	if mb.hours != nil && !mb.hours.IsOpen(mb.clock.Now()) {
+		return &interfaces.BrokerError{
+			Code:    "MARKET_CLOSED",
+			Message: "Market is closed",
+			Details: fmt.Sprintf("Order %s placed outside market hours at %s", order.ID, mb.clock.Now().Format(time.RFC3339)),
+		}
+	}
+	if mb.halted[string(order.Symbol)] {
+		return &interfaces.BrokerError{
+			Code:    "SYMBOL_HALTED",
+			Message: "Trading in symbol is halted",
+			Details: fmt.Sprintf("Order %s for halted symbol %s", order.ID, order.Symbol),
+		}
+	}
+	return nil
+}
+
+// checkBuyingPowerLocked rejects a buy whose estimated notional plus fees is
+// more than the account's buying power: its cash less what open buy orders
+// have reserved. Must be called with mb.mu held.
+func (mb *MockBroker) checkBuyingPowerLocked(order *entities.Order) error {
+	if order.Side != entities.OrderSideBuy {
+		return nil
+	}
+	
+	price := mb.estimatePriceLocked(order)
+	required := order.Quantity*price + mb.calculateFees(order, order.Quantity, price)
+	available := mb.account.CashBalance - mb.reservedLocked()
+	if required <= available {
+		return nil
+	}
+	return &interfaces.BrokerError{
+		Code:    "INSUFFICIENT_FUNDS",
+		Message: "Insufficient buying power",
+		Details: fmt.Sprintf("Order %s needs %.2f, buying power is %.2f", order.ID, required, available),
+	}
+}
+
+// reservedLocked is the estimated notional plus fees of what remains unfilled
+// of every open buy order. Must be called with mb.mu held.
+func (mb *MockBroker) reservedLocked() float64 {
+	reserved := 0.0
+	for _, mockOrder := range mb.orders {
+		if mockOrder.Status != entities.OrderStatusPending || mockOrder.Order.Side != entities.OrderSideBuy {
+			continue
+		}
+		remaining := mockOrder.Order.Quantity
+		for _, fill := range mockOrder.Fills {
+			remaining -= fill.Quantity
+		}
+		if remaining <= 0 {
+			continue
+		}
+		price := mb.estimatePriceLocked(mockOrder.Order)
+		reserved += remaining*price + mb.calculateFees(mockOrder.Order, remaining, price)
+	}
+	return reserved
+}
+
+// refreshBuyingPowerLocked sets the account's buying power to its cash less
+// what open buy orders have reserved, after a fill, cancel or replace changed
+// either. Must be called with mb.mu held.
+func (mb *MockBroker) refreshBuyingPowerLocked() {
+	mb.account.BuyingPower = mb.account.CashBalance - mb.reservedLocked()
+}
+
+// updateAccountPosition updates account cash and positions after a fill that
+// was charged fees
 func (mb *MockBroker) updateAccountPosition(symbol string, side entities.OrderSide, 
-	quantity, price float64) {
+	quantity, price, fees float64) {
 	
 	tradeValue := quantity * price
-	fees := quantity * 0.005
-	if fees < 1.0 {
-		fees = 1.0
-	}
 	
 	// Update cash balance
 	if side == entities.OrderSideBuy {
@@ -374,7 +1015,7 @@ func (mb *MockBroker) updateAccountPosition(symbol string, side entities.OrderSi
 			mb.account.Positions[i].MarketValue = mb.account.Positions[i].Quantity * price
 			mb.account.Positions[i].UnrealizedPnL = mb.account.Positions[i].MarketValue - 
 				(mb.account.Positions[i].Quantity * mb.account.Positions[i].AveragePrice)
-			mb.account.Positions[i].LastUpdated = time.Now()
+			mb.account.Positions[i].LastUpdated = mb.clock.Now()
 			
 			// Remove position if quantity is zero
 			if mb.account.Positions[i].Quantity == 0 {
@@ -392,7 +1033,7 @@ func (mb *MockBroker) updateAccountPosition(symbol string, side entities.OrderSi
 			AveragePrice:  price,
 			MarketValue:   tradeValue,
 			UnrealizedPnL: 0,
-			LastUpdated:   time.Now(),
+			LastUpdated:   mb.clock.Now(),
 		}
 		mb.account.Positions = append(mb.account.Positions, position)
 	}
@@ -403,6 +1044,6 @@ func (mb *MockBroker) updateAccountPosition(symbol string, side entities.OrderSi
 		totalPositionValue += pos.MarketValue
 	}
 	mb.account.TotalValue = mb.account.CashBalance + totalPositionValue
-	mb.account.BuyingPower = mb.account.CashBalance // Simplified
-	mb.account.LastUpdated = time.Now()
+	mb.refreshBuyingPowerLocked()
+	mb.account.LastUpdated = mb.clock.Now()
 }
\ No newline at end of file
@@ -0,0 +1,793 @@
+package brokers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/interfaces"
+)
+
+func setupTestMockBroker(t *testing.T) *MockBroker {
+	t.Helper()
+
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{
+		Level:  "error",
+		Format: "json",
+		Output: "stdout",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test logger: %v", err)
+	}
+
+	broker := NewMockBroker("TestBroker", testLogger)
+	broker.latency = 0
+	broker.SetErrorRate(0)
+	if err := broker.Connect(context.Background()); err != nil {
+		t.Fatalf("Failed to connect mock broker: %v", err)
+	}
+	return broker
+}
+
+func TestMockBroker_SynchronousMarketOrderExecutesImmediately(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	ctx := context.Background()
+
+	order := &entities.Order{
+		ID:       "sync-1",
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeMarket,
+		Quantity: 10,
+	}
+
+	result, err := broker.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != entities.OrderStatusExecuted {
+		t.Fatalf("Expected executed result, got %s", result.Status)
+	}
+	if result.ExecutedPrice == nil || result.ExecutedQty == nil || *result.ExecutedQty != 10 {
+		t.Fatalf("Expected executed price and quantity on result, got %+v", result)
+	}
+
+	status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != entities.OrderStatusExecuted {
+		t.Errorf("Expected order to be executed at the broker, got %s", status.Status)
+	}
+
+	account, err := broker.GetAccountInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetAccountInfo failed: %v", err)
+	}
+	if len(account.Positions) != 1 || account.Positions[0].Quantity != 10 {
+		t.Errorf("Expected a 10 share AAPL position, got %+v", account.Positions)
+	}
+}
+
+func TestMockBroker_ForceExecute(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	ctx := context.Background()
+
+	price := 100.0
+	order := &entities.Order{
+		ID:       "limit-1",
+		Symbol:   "MSFT",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 5,
+		Price:    &price,
+	}
+
+	result, err := broker.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != entities.OrderStatusPending {
+		t.Fatalf("Expected limit order to stay pending, got %s", result.Status)
+	}
+
+	if err := broker.ForceExecute(result.BrokerOrderID); err != nil {
+		t.Fatalf("ForceExecute failed: %v", err)
+	}
+
+	status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != entities.OrderStatusExecuted {
+		t.Errorf("Expected forced order to be executed, got %s", status.Status)
+	}
+
+	if err := broker.ForceExecute(result.BrokerOrderID); err == nil {
+		t.Error("Expected error when executing an already executed order")
+	}
+	if err := broker.ForceExecute("missing"); err == nil {
+		t.Error("Expected error for unknown order")
+	}
+}
+
+func TestMockBroker_FillPriceModels(t *testing.T) {
+	book := OrderBook{
+		Bids: []BookLevel{{Price: 99, Quantity: 50}, {Price: 98, Quantity: 100}},
+		Asks: []BookLevel{{Price: 101, Quantity: 40}, {Price: 102, Quantity: 60}},
+	}
+
+	tests := []struct {
+		name     string
+		model    FillPriceModel
+		side     entities.OrderSide
+		quantity float64
+		want     float64
+	}{
+		{name: "fixed", model: FixedPriceModel{Price: 42}, side: entities.OrderSideBuy, quantity: 10, want: 42},
+		{name: "last", model: LastPriceModel{Fallback: 1}, side: entities.OrderSideBuy, quantity: 10, want: 123.45},
+		{name: "mid", model: MidPriceModel{Fallback: 1}, side: entities.OrderSideBuy, quantity: 10, want: 100},
+		// 40@101 + 60@102
+		{name: "book walk buy", model: BookWalkModel{Fallback: 1}, side: entities.OrderSideBuy, quantity: 100, want: 101.6},
+		// 50@99 + 100@98, with the 50 beyond the book's depth at 98
+		{name: "book walk sell past depth", model: BookWalkModel{Fallback: 1}, side: entities.OrderSideSell, quantity: 200, want: 98.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := setupTestMockBroker(t)
+			broker.SetSynchronous(true)
+			broker.SetFillPriceModel(tt.model)
+			broker.SetMarketPrice("AAPL", 123.45)
+			broker.SetOrderBook("AAPL", book)
+
+			result, err := broker.PlaceOrder(context.Background(), &entities.Order{
+				ID:       entities.OrderID("fill-" + tt.name),
+				Symbol:   "AAPL",
+				Side:     tt.side,
+				Type:     entities.OrderTypeMarket,
+				Quantity: tt.quantity,
+			})
+			if err != nil {
+				t.Fatalf("PlaceOrder failed: %v", err)
+			}
+			if result.ExecutedPrice == nil {
+				t.Fatalf("Expected an executed price, got %+v", result)
+			}
+			if math.Abs(*result.ExecutedPrice-tt.want) > 1e-9 {
+				t.Errorf("Expected fill at %v, got %v", tt.want, *result.ExecutedPrice)
+			}
+		})
+	}
+}
+
+func TestMockBroker_FillPriceModelFallbacks(t *testing.T) {
+	order := &entities.Order{Symbol: "AAPL", Side: entities.OrderSideBuy, Quantity: 10}
+
+	if got := (LastPriceModel{Fallback: 100}).FillPrice(order, MarketQuote{}); got != 100 {
+		t.Errorf("Expected last model to fall back to 100 without a last price, got %v", got)
+	}
+	if got := (BookWalkModel{Fallback: 100}).FillPrice(order, MarketQuote{LastPrice: 105}); got != 105 {
+		t.Errorf("Expected book walk to fall back to the last price on an empty book, got %v", got)
+	}
+
+	model := NewRandomPriceModel(100, 1, rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		if got := model.FillPrice(order, MarketQuote{}); got < 99 || got > 101 {
+			t.Fatalf("Expected random fill within 100 ±1, got %v", got)
+		}
+	}
+}
+
+func TestMockBroker_StopOrderTriggersIntoMarketOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     entities.OrderSide
+		stop     float64
+		notYet   float64
+		crossing float64
+	}{
+		{"sell stop below the market", entities.OrderSideSell, 95, 96, 94.5},
+		{"buy stop above the market", entities.OrderSideBuy, 105, 104, 105},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := setupTestMockBroker(t)
+			broker.SetSynchronous(true)
+			broker.SetFillPriceModel(LastPriceModel{Fallback: 100})
+			broker.SetMarketPrice("AAPL", 100)
+			ctx := context.Background()
+
+			stop := tt.stop
+			order := &entities.Order{
+				ID:        "stop-1",
+				Symbol:    "AAPL",
+				Side:      tt.side,
+				Type:      entities.OrderTypeStop,
+				Quantity:  10,
+				StopPrice: &stop,
+			}
+			result, err := broker.PlaceOrder(ctx, order)
+			if err != nil {
+				t.Fatalf("PlaceOrder failed: %v", err)
+			}
+			if result.Status != entities.OrderStatusPending {
+				t.Fatalf("Expected the stop to rest, got %s", result.Status)
+			}
+
+			for _, price := range []float64{tt.notYet, tt.crossing} {
+				broker.SetMarketPrice("AAPL", price)
+				status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+				if err != nil {
+					t.Fatalf("GetOrderStatus failed: %v", err)
+				}
+				if price == tt.notYet && status.Status != entities.OrderStatusPending {
+					t.Fatalf("Expected the stop to hold at %v, got %s", price, status.Status)
+				}
+				if price == tt.crossing {
+					if status.Status != entities.OrderStatusExecuted || status.ExecutedPrice == nil || *status.ExecutedPrice != tt.crossing {
+						t.Fatalf("Expected a fill at %v, got %+v", tt.crossing, status)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMockBroker_ImmediateOrdersAgainstBookDepth(t *testing.T) {
+	tests := []struct {
+		name        string
+		tif         entities.TimeInForce
+		limit       float64
+		wantStatus  entities.OrderStatus
+		wantFilled  float64
+		wantAverage float64
+	}{
+		{"IOC fills what is inside the limit", entities.TimeInForceIOC, 100, entities.OrderStatusCancelled, 60, 100},
+		{"FOK short of depth fills nothing", entities.TimeInForceFOK, 100, entities.OrderStatusCancelled, 0, 0},
+		{"FOK with enough depth fills in full", entities.TimeInForceFOK, 101, entities.OrderStatusExecuted, 100, 100.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := setupTestMockBroker(t)
+			broker.SetFillPriceModel(BookWalkModel{Fallback: 100})
+			broker.SetOrderBook("AAPL", OrderBook{
+				Bids: []BookLevel{{Price: 99, Quantity: 500}},
+				Asks: []BookLevel{{Price: 100, Quantity: 60}, {Price: 101, Quantity: 100}},
+			})
+			ctx := context.Background()
+
+			limit := tt.limit
+			result, err := broker.PlaceOrder(ctx, &entities.Order{
+				ID:          "immediate-1",
+				Symbol:      "AAPL",
+				Side:        entities.OrderSideBuy,
+				Type:        entities.OrderTypeLimit,
+				Quantity:    100,
+				Price:       &limit,
+				TimeInForce: tt.tif,
+			})
+			if err != nil {
+				t.Fatalf("PlaceOrder failed: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Expected %s, got %s", tt.wantStatus, result.Status)
+			}
+
+			status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+			if err != nil {
+				t.Fatalf("GetOrderStatus failed: %v", err)
+			}
+			var filled, average float64
+			if status.ExecutedQty != nil {
+				filled, average = *status.ExecutedQty, *status.ExecutedPrice
+			}
+			if filled != tt.wantFilled || math.Abs(average-tt.wantAverage) > 1e-9 {
+				t.Errorf("Expected %v filled at %v, got %v at %v", tt.wantFilled, tt.wantAverage, filled, average)
+			}
+		})
+	}
+}
+
+func TestMockBroker_LimitOrderRestsUntilWalkReachesLimit(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetPriceWalker(NewPriceWalker(100, 1, rand.NewSource(7)))
+	ctx := context.Background()
+
+	limit := 97.0
+	result, err := broker.PlaceOrder(ctx, &entities.Order{
+		ID:       "limit-1",
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 10,
+		Price:    &limit,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != entities.OrderStatusPending {
+		t.Fatalf("Expected the limit order to rest, got %s", result.Status)
+	}
+
+	var status *interfaces.OrderStatus
+	polls := 0
+	for polls < 10000 {
+		polls++
+		status, err = broker.GetOrderStatus(ctx, result.BrokerOrderID)
+		if err != nil {
+			t.Fatalf("GetOrderStatus failed: %v", err)
+		}
+
+		broker.mu.RLock()
+		price := broker.lastPrices["AAPL"]
+		broker.mu.RUnlock()
+		if status.Status != entities.OrderStatusPending {
+			break
+		}
+		if price <= limit {
+			t.Fatalf("Expected the order to fill once the walk reached %v, still pending at %v", limit, price)
+		}
+	}
+
+	if status.Status != entities.OrderStatusExecuted {
+		t.Fatalf("Expected the walk to reach the limit within %d polls, got %s", polls, status.Status)
+	}
+	if polls < 3 {
+		t.Errorf("Expected a walk with ±1 steps to need at least 3 polls to fall 3 points, took %d", polls)
+	}
+	if status.ExecutedPrice == nil || *status.ExecutedPrice > limit || *status.ExecutedQty != 10 {
+		t.Errorf("Expected 10 filled at or below %v, got %+v", limit, status)
+	}
+}
+
+func TestMockBroker_SetMarketPriceFillsReachedLimits(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	ctx := context.Background()
+
+	limit := 105.0
+	result, err := broker.PlaceOrder(ctx, &entities.Order{
+		ID:       "limit-2",
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideSell,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 5,
+		Price:    &limit,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		price      float64
+		wantStatus entities.OrderStatus
+	}{
+		{104.5, entities.OrderStatusPending},
+		{105.25, entities.OrderStatusExecuted},
+	} {
+		broker.SetMarketPrice("AAPL", tt.price)
+		status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+		if err != nil {
+			t.Fatalf("GetOrderStatus failed: %v", err)
+		}
+		if status.Status != tt.wantStatus {
+			t.Fatalf("At %v expected %s, got %s", tt.price, tt.wantStatus, status.Status)
+		}
+	}
+
+	status, _ := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+	if *status.ExecutedPrice != 105.25 {
+		t.Errorf("Expected the sell limit to fill at the better market price 105.25, got %v", *status.ExecutedPrice)
+	}
+}
+
+func TestMockBroker_SeededRunIsRepeatable(t *testing.T) {
+	// run places orders on a broker with a 30% error rate, a fake clock and
+	// seeded randomness, and returns each order's error code or fill price
+	run := func(seed int64) []string {
+		broker := setupTestMockBroker(t)
+		fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC))
+		broker.SetClock(fakeClock)
+		broker.SetRandSource(rand.New(rand.NewSource(seed)))
+		broker.SetFillPriceModel(NewRandomPriceModel(100, 1, rand.NewSource(seed)))
+		broker.SetErrorRate(0.3)
+		ctx := context.Background()
+
+		var outcomes []string
+		for i := 0; i < 20; i++ {
+			result, err := broker.PlaceOrder(ctx, &entities.Order{
+				ID:       entities.OrderID(fmt.Sprintf("order-%d", i)),
+				Symbol:   "AAPL",
+				Side:     entities.OrderSideBuy,
+				Type:     entities.OrderTypeMarket,
+				Quantity: 1,
+			})
+			var brokerErr *interfaces.BrokerError
+			if errors.As(err, &brokerErr) {
+				outcomes = append(outcomes, brokerErr.Code)
+				continue
+			}
+			if err != nil {
+				t.Fatalf("PlaceOrder failed: %v", err)
+			}
+
+			// The execution waits on the fake clock, so no real time passes
+			for fakeClock.Waiters() == 0 {
+				runtime.Gosched()
+			}
+			fakeClock.Advance(time.Second)
+
+			var status *interfaces.OrderStatus
+			for status == nil || status.Status != entities.OrderStatusExecuted {
+				if status, err = broker.GetOrderStatus(ctx, result.BrokerOrderID); err != nil {
+					t.Fatalf("GetOrderStatus failed: %v", err)
+				}
+				runtime.Gosched()
+			}
+			outcomes = append(outcomes, fmt.Sprintf("%.6f", *status.ExecutedPrice))
+		}
+		return outcomes
+	}
+
+	started := time.Now()
+	first, second := run(42), run(42)
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Expected a fake clock run to skip execution delays, took %v", elapsed)
+	}
+
+	rejected := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected the same outcome for order %d with the same seed, got %s and %s", i, first[i], second[i])
+		}
+		if first[i] == "ORDER_REJECTED" {
+			rejected++
+		}
+	}
+	if rejected == 0 || rejected == len(first) {
+		t.Errorf("Expected a mix of fills and rejections at a 30%% error rate, got %v", first)
+	}
+	if other := run(7); reflect.DeepEqual(first, other) {
+		t.Error("Expected a different seed to give a different run")
+	}
+}
+
+func TestMockBroker_FeeAndSlippageModels(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	broker.SetFillPriceModel(FixedPriceModel{Price: 100})
+	broker.SetFeeModel(PercentageFeeModel{Rate: 0.001, Minimum: 1})
+	broker.SetSlippageModel(LinearSlippageModel{RatePerUnit: 0.0001})
+	ctx := context.Background()
+
+	tests := []struct {
+		side      entities.OrderSide
+		wantPrice float64
+		wantFees  float64
+	}{
+		// 200 units at 0.0001 moves the price 2% against the order; the fee is
+		// 0.1% of the slipped notional
+		{entities.OrderSideBuy, 102, 20.4},
+		{entities.OrderSideSell, 98, 19.6},
+	}
+	for _, tt := range tests {
+		before, err := broker.GetAccountInfo(ctx)
+		if err != nil {
+			t.Fatalf("GetAccountInfo failed: %v", err)
+		}
+
+		result, err := broker.PlaceOrder(ctx, &entities.Order{
+			ID:       entities.OrderID("cost-" + string(tt.side)),
+			Symbol:   "AAPL",
+			Side:     tt.side,
+			Type:     entities.OrderTypeMarket,
+			Quantity: 200,
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if result.ExecutedPrice == nil || math.Abs(*result.ExecutedPrice-tt.wantPrice) > 1e-9 {
+			t.Errorf("%s: expected fill at %v, got %+v", tt.side, tt.wantPrice, result.ExecutedPrice)
+		}
+		if math.Abs(result.Fees-tt.wantFees) > 1e-9 {
+			t.Errorf("%s: expected fees %v, got %v", tt.side, tt.wantFees, result.Fees)
+		}
+
+		status, err := broker.GetOrderStatus(ctx, result.BrokerOrderID)
+		if err != nil {
+			t.Fatalf("GetOrderStatus failed: %v", err)
+		}
+		if math.Abs(status.Fees-tt.wantFees) > 1e-9 {
+			t.Errorf("%s: expected status fees %v, got %v", tt.side, tt.wantFees, status.Fees)
+		}
+
+		// Cash moves by the notional, against the order, less the model's fee
+		after, err := broker.GetAccountInfo(ctx)
+		if err != nil {
+			t.Fatalf("GetAccountInfo failed: %v", err)
+		}
+		wantDelta := 200*tt.wantPrice - tt.wantFees
+		if tt.side == entities.OrderSideBuy {
+			wantDelta = -200*tt.wantPrice - tt.wantFees
+		}
+		if delta := after.CashBalance - before.CashBalance; math.Abs(delta-wantDelta) > 1e-9 {
+			t.Errorf("%s: expected a cash delta of %v, got %v", tt.side, wantDelta, delta)
+		}
+	}
+
+	// A small order pays the minimum, and a flat model ignores size
+	if fee := (PercentageFeeModel{Rate: 0.001, Minimum: 1}).Fee(nil, 1, 100); fee != 1 {
+		t.Errorf("Expected the minimum fee of 1, got %v", fee)
+	}
+	if fee := (FlatFeeModel{Amount: 4.95}).Fee(nil, 1000, 100); fee != 4.95 {
+		t.Errorf("Expected a flat fee of 4.95, got %v", fee)
+	}
+}
+
+func TestMockBroker_RejectsOrdersBeyondBuyingPower(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	broker.SetCashBalance(1000)
+	broker.SetMarketPrice("AAPL", 100)
+	ctx := context.Background()
+
+	order := func(id string, side entities.OrderSide, quantity float64) *entities.Order {
+		return &entities.Order{
+			ID:       entities.OrderID(id),
+			Symbol:   "AAPL",
+			Side:     side,
+			Type:     entities.OrderTypeMarket,
+			Quantity: quantity,
+		}
+	}
+
+	// 10 shares at 100 plus the $1 minimum fee is just over 1000
+	_, err := broker.PlaceOrder(ctx, order("too-big", entities.OrderSideBuy, 10))
+	var brokerErr *interfaces.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.Code != "INSUFFICIENT_FUNDS" {
+		t.Fatalf("Expected INSUFFICIENT_FUNDS, got %v", err)
+	}
+
+	if _, err := broker.PlaceOrder(ctx, order("fits", entities.OrderSideBuy, 9)); err != nil {
+		t.Errorf("Expected an affordable buy to be accepted, got %v", err)
+	}
+	if _, err := broker.PlaceOrder(ctx, order("sell", entities.OrderSideSell, 50)); err != nil {
+		t.Errorf("Expected a sell not to need buying power, got %v", err)
+	}
+}
+
+func TestMockBroker_ReservesBuyingPowerForRestingBuys(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	broker.SetCashBalance(1000)
+	broker.SetMarketPrice("AAPL", 100)
+	ctx := context.Background()
+
+	seq := 0
+	limitBuy := func(quantity, price float64) (string, error) {
+		seq++
+		result, err := broker.PlaceOrder(ctx, &entities.Order{
+			ID:       entities.OrderID(fmt.Sprintf("limit-%d", seq)),
+			Symbol:   "AAPL",
+			Side:     entities.OrderSideBuy,
+			Type:     entities.OrderTypeLimit,
+			Quantity: quantity,
+			Price:    &price,
+		})
+		if err != nil {
+			return "", err
+		}
+		return result.BrokerOrderID, nil
+	}
+	buyingPower := func() float64 {
+		account, err := broker.GetAccountInfo(ctx)
+		if err != nil {
+			t.Fatalf("GetAccountInfo failed: %v", err)
+		}
+		return account.BuyingPower
+	}
+
+	// A limit buy is priced at its limit rather than the market, and reserves
+	// 15 x 50 plus the $1 minimum fee while it rests
+	first, err := limitBuy(15, 50)
+	if err != nil {
+		t.Fatalf("Expected a limit buy within buying power at its limit, got %v", err)
+	}
+	if got := buyingPower(); math.Abs(got-249) > 1e-9 {
+		t.Errorf("Expected 249 of buying power left, got %v", got)
+	}
+
+	var brokerErr *interfaces.BrokerError
+	if _, err := limitBuy(5, 50); !errors.As(err, &brokerErr) || brokerErr.Code != "INSUFFICIENT_FUNDS" {
+		t.Fatalf("Expected a second buy past the reservation to fail with INSUFFICIENT_FUNDS, got %v", err)
+	}
+
+	// Repricing the resting buy lower releases part of its reservation
+	if err := broker.ReplaceOrder(ctx, first, 40); err != nil {
+		t.Fatalf("ReplaceOrder failed: %v", err)
+	}
+	if got := buyingPower(); math.Abs(got-399) > 1e-9 {
+		t.Errorf("Expected 399 of buying power after the replace, got %v", got)
+	}
+
+	// Cancelling releases the rest
+	if err := broker.CancelOrder(ctx, first); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if got := buyingPower(); got != 1000 {
+		t.Errorf("Expected the full 1000 back after the cancel, got %v", got)
+	}
+
+	// A fill spends cash in place of the reservation
+	second, err := limitBuy(5, 50)
+	if err != nil {
+		t.Fatalf("Expected the buy to fit once the reservation was released, got %v", err)
+	}
+	if err := broker.FillPartially(second, 5, 50); err != nil {
+		t.Fatalf("FillPartially failed: %v", err)
+	}
+	if got := buyingPower(); math.Abs(got-749) > 1e-9 {
+		t.Errorf("Expected 749 of buying power after the fill, got %v", got)
+	}
+}
+
+// sessionHours is open from Open to Close on every day, in UTC
+type sessionHours struct {
+	Open, Close time.Duration
+}
+
+func (h sessionHours) IsOpen(t time.Time) bool {
+	sinceMidnight := t.Sub(t.Truncate(24 * time.Hour))
+	return sinceMidnight >= h.Open && sinceMidnight < h.Close
+}
+
+func TestMockBroker_MarketHoursAndHalts(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC))
+	broker.SetClock(fakeClock)
+	broker.SetMarketHours(sessionHours{Open: 14*time.Hour + 30*time.Minute, Close: 21 * time.Hour})
+	ctx := context.Background()
+
+	seq := 0
+	place := func(symbol string) error {
+		seq++
+		_, err := broker.PlaceOrder(ctx, &entities.Order{
+			ID:       entities.OrderID(fmt.Sprintf("hours-%d", seq)),
+			Symbol:   entities.Symbol(symbol),
+			Side:     entities.OrderSideBuy,
+			Type:     entities.OrderTypeMarket,
+			Quantity: 1,
+		})
+		return err
+	}
+	code := func(err error) string {
+		var brokerErr *interfaces.BrokerError
+		if errors.As(err, &brokerErr) {
+			return brokerErr.Code
+		}
+		return ""
+	}
+
+	if err := place("AAPL"); code(err) != "MARKET_CLOSED" {
+		t.Errorf("Expected MARKET_CLOSED before the open, got %v", err)
+	}
+
+	fakeClock.Set(time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC))
+	if err := place("AAPL"); err != nil {
+		t.Errorf("Expected an order during open hours to be accepted, got %v", err)
+	}
+
+	broker.HaltSymbol("AAPL")
+	if err := place("AAPL"); code(err) != "SYMBOL_HALTED" {
+		t.Errorf("Expected SYMBOL_HALTED, got %v", err)
+	}
+	if err := place("MSFT"); err != nil {
+		t.Errorf("Expected other symbols to trade through a halt, got %v", err)
+	}
+	broker.ResumeSymbol("AAPL")
+	if err := place("AAPL"); err != nil {
+		t.Errorf("Expected AAPL to trade after resuming, got %v", err)
+	}
+
+	fakeClock.Set(time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC))
+	if err := place("AAPL"); code(err) != "MARKET_CLOSED" {
+		t.Errorf("Expected MARKET_CLOSED at the close, got %v", err)
+	}
+}
+
+func TestMockBroker_ReplaceAndOrderHistory(t *testing.T) {
+	broker := setupTestMockBroker(t)
+	broker.SetSynchronous(true)
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC))
+	broker.SetClock(fakeClock)
+	broker.SetMarketPrice("AAPL", 100)
+	ctx := context.Background()
+
+	limit := 90.0
+	resting, err := broker.PlaceOrder(ctx, &entities.Order{
+		ID:       "resting",
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 10,
+		Price:    &limit,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	fakeClock.Advance(time.Second)
+	broker.HaltSymbol("MSFT")
+	if _, err := broker.PlaceOrder(ctx, &entities.Order{
+		ID:       "halted",
+		Symbol:   "MSFT",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeMarket,
+		Quantity: 1,
+	}); err == nil {
+		t.Fatal("Expected the halted order to be rejected")
+	}
+
+	fakeClock.Advance(time.Second)
+	if err := broker.ReplaceOrder(ctx, resting.BrokerOrderID, 95); err != nil {
+		t.Fatalf("ReplaceOrder failed: %v", err)
+	}
+	status, err := broker.GetOrderStatus(ctx, resting.BrokerOrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != entities.OrderStatusPending {
+		t.Fatalf("Expected the replaced order to keep resting, got %s", status.Status)
+	}
+
+	// The market falls to the new limit and fills the order
+	fakeClock.Advance(time.Second)
+	broker.SetMarketPrice("AAPL", 95)
+
+	history := broker.GetOrderHistory()
+	if len(history) != 2 {
+		t.Fatalf("Expected two orders in history, got %d", len(history))
+	}
+	if history[0].Order.ID != "resting" || history[1].Order.ID != "halted" {
+		t.Fatalf("Expected history in creation order, got %s then %s", history[0].Order.ID, history[1].Order.ID)
+	}
+	if history[1].Status != entities.OrderStatusRejected {
+		t.Errorf("Expected the halted order to be recorded as rejected, got %s", history[1].Status)
+	}
+
+	replaced := history[0]
+	if replaced.Order.Price == nil || *replaced.Order.Price != 95 {
+		t.Errorf("Expected the replaced limit of 95, got %v", replaced.Order.Price)
+	}
+	var actions []string
+	for _, event := range replaced.Events {
+		actions = append(actions, event.Action)
+	}
+	if want := []string{"placed", "replaced", "filled"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("Expected events %v, got %v", want, actions)
+	}
+	if last := replaced.Events[len(replaced.Events)-1]; last.Status != entities.OrderStatusExecuted || !last.At.Equal(fakeClock.Now()) {
+		t.Errorf("Expected the fill as the last event, got %+v", last)
+	}
+
+	// The history is a copy
+	*history[0].Order.Price = 1
+	history[0].Events[0].Action = "tampered"
+	if again := broker.GetOrderHistory(); *again[0].Order.Price != 95 || again[0].Events[0].Action != "placed" {
+		t.Error("Expected changes to a history snapshot not to reach the broker")
+	}
+}
@@ -0,0 +1,52 @@
+package brokers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/interfaces"
+)
+
+// OrderEvent is one step in a mock order's life. Action is what happened:
+// placed, rejected, replaced, triggered, partially_filled, filled or
+// cancelled; Status is the order's status after it.
+type OrderEvent struct {
+	Action string
+	Status entities.OrderStatus
+	At     time.Time
+}
+
+// recordLocked moves mockOrder to status and appends action to its events.
+// Must be called with mb.mu held.
+func (mb *MockBroker) recordLocked(mockOrder *MockOrder, action string, status entities.OrderStatus) {
+	now := mb.clock.Now()
+	mockOrder.Status = status
+	mockOrder.UpdatedAt = now
+	mockOrder.Events = append(mockOrder.Events, OrderEvent{Action: action, Status: status, At: now})
+}
+
+// GetOrderHistory returns a copy of every order the broker has been sent,
+// including rejected and cancelled ones, oldest first
+func (mb *MockBroker) GetOrderHistory() []MockOrder {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	history := make([]MockOrder, len(mb.history))
+	for i, mockOrder := range mb.history {
+		history[i] = mockOrder.snapshot()
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.Before(history[j].CreatedAt)
+	})
+	return history
+}
+
+// snapshot copies mo so the copy shares no state with the broker
+func (mo *MockOrder) snapshot() MockOrder {
+	copied := *mo
+	copied.Order = mo.Order.Clone()
+	copied.Fills = append([]interfaces.Fill(nil), mo.Fills...)
+	copied.Events = append([]OrderEvent(nil), mo.Events...)
+	return copied
+}
@@ -0,0 +1,59 @@
+package brokers
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// minWalkPrice keeps a walked price positive however long it falls
+const minWalkPrice = 0.01
+
+// PriceWalker is a random walk for simulated market prices. Each step moves a
+// price uniformly by up to ±Step; a symbol with no price yet starts from its
+// base, or Base when none was set.
+type PriceWalker struct {
+	Base float64
+	Step float64
+
+	mu    sync.Mutex
+	bases map[string]float64
+	rng   *rand.Rand
+}
+
+// NewPriceWalker creates a walker. A nil source uses the global generator;
+// pass a seeded source for a repeatable walk.
+func NewPriceWalker(base, step float64, source rand.Source) *PriceWalker {
+	walker := &PriceWalker{Base: base, Step: step, bases: make(map[string]float64)}
+	if source != nil {
+		walker.rng = rand.New(source)
+	}
+	return walker
+}
+
+// SetBase sets the price symbol's walk starts from
+func (w *PriceWalker) SetBase(symbol string, base float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.bases[symbol] = base
+}
+
+// Next returns the price one step on from current, starting from symbol's base
+// when current is not positive
+func (w *PriceWalker) Next(symbol string, current float64) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if current <= 0 {
+		current = w.Base
+		if base, ok := w.bases[symbol]; ok {
+			current = base
+		}
+	}
+
+	sample := rand.Float64
+	if w.rng != nil {
+		sample = w.rng.Float64
+	}
+	return math.Max(minWalkPrice, current+(sample()-0.5)*2*w.Step)
+}
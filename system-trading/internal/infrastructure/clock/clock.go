@@ -0,0 +1,104 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RealClock reads the system wall clock
+type RealClock struct{}
+
+// NewRealClock creates a clock backed by the time package
+func NewRealClock() RealClock {
+	return RealClock{}
+}
+
+// Now returns the current wall-clock time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for d on the system clock
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a manually advanced clock for deterministic tests
+type FakeClock struct {
+	now     time.Time
+	waiters []fakeWaiter
+	mu      sync.Mutex
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock creates a fake clock starting at start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake clock's current time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that fires once the clock is advanced past d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	deadline := c.now.Add(d)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, fakeWaiter{deadline: deadline, ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing any waiters whose deadline has passed
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.fireLocked()
+	c.mu.Unlock()
+}
+
+// Set moves the clock to t, firing any waiters whose deadline has passed
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.fireLocked()
+	c.mu.Unlock()
+}
+
+// Waiters returns the number of pending After channels
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func (c *FakeClock) fireLocked() {
+	sort.Slice(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+
+	remaining := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.deadline.After(c.now) {
+			remaining = append(remaining, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = remaining
+}
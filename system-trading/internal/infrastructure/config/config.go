@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
@@ -15,6 +19,9 @@ type Config struct {
 	Risk     RiskConfig     `yaml:"risk"`
 	Trading  TradingConfig  `yaml:"trading"`
 	Logging  LoggingConfig  `yaml:"logging"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+	Shutdown ShutdownConfig `yaml:"shutdown"`
 	Security SecurityConfig `yaml:"security"`
 }
 
@@ -24,6 +31,8 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" default:"30s"`
 	WriteTimeout time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" default:"30s"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" default:"60s"`
+	// HealthCheckTimeout bounds each readiness check so a hung one cannot block the probe
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"SERVER_HEALTH_CHECK_TIMEOUT" default:"2s"`
 }
 
 type DatabaseConfig struct {
@@ -45,6 +54,7 @@ type NATSConfig struct {
 	ReconnectWait     time.Duration `yaml:"reconnect_wait" env:"NATS_RECONNECT_WAIT" default:"2s"`
 	ConnectionTimeout time.Duration `yaml:"connection_timeout" env:"NATS_CONNECTION_TIMEOUT" default:"5s"`
 	DrainTimeout      time.Duration `yaml:"drain_timeout" env:"NATS_DRAIN_TIMEOUT" default:"5s"`
+	Serialization     string        `yaml:"serialization" env:"NATS_SERIALIZATION" default:"json"`
 }
 
 type RedisConfig struct {
@@ -64,6 +74,24 @@ type RiskConfig struct {
 	MaxDailyLoss       float64 `yaml:"max_daily_loss" env:"RISK_MAX_DAILY_LOSS" default:"0.05"`
 	MaxVaR             float64 `yaml:"max_var" env:"RISK_MAX_VAR" default:"0.02"`
 	VaRConfidenceLevel float64 `yaml:"var_confidence_level" env:"RISK_VAR_CONFIDENCE" default:"0.95"`
+	WarmUpPeriod       time.Duration `yaml:"warm_up_period" env:"RISK_WARMUP_PERIOD" default:"2m"`
+	DegradedPolicy        string  `yaml:"degraded_policy" env:"RISK_DEGRADED_POLICY" default:"fail_closed"`
+	DegradedMaxOrderValue float64 `yaml:"degraded_max_order_value" env:"RISK_DEGRADED_MAX_ORDER_VALUE" default:"1000"`
+	MaxDrawdown           float64 `yaml:"max_drawdown" env:"RISK_MAX_DRAWDOWN" default:"0.2"`
+	AutoFlattenOnDrawdown bool    `yaml:"auto_flatten_on_drawdown" env:"RISK_AUTO_FLATTEN_ON_DRAWDOWN" default:"false"`
+	// StopLossThreshold is the per-position loss that triggers a stop-loss; zero disables it
+	StopLossThreshold     float64 `yaml:"stop_loss_threshold" env:"RISK_STOP_LOSS_THRESHOLD" default:"0"`
+	// CashBufferPct is the extra cash, as a fraction of order value, a buy must
+	// leave available; zero disables the buffer
+	CashBufferPct         float64 `yaml:"cash_buffer_pct" env:"RISK_CASH_BUFFER_PCT" default:"0.10"`
+	AlertSinkBuffer       int           `yaml:"alert_sink_buffer" env:"RISK_ALERT_SINK_BUFFER" default:"256"`
+	AlertSinkTimeout      time.Duration `yaml:"alert_sink_timeout" env:"RISK_ALERT_SINK_TIMEOUT" default:"2s"`
+	PriceStaleAfter       time.Duration `yaml:"price_stale_after" env:"RISK_PRICE_STALE_AFTER" default:"5s"`
+	StalePricePolicy      string        `yaml:"stale_price_policy" env:"RISK_STALE_PRICE_POLICY" default:"annotate"`
+	AlertDebounceInterval time.Duration `yaml:"alert_debounce_interval" env:"RISK_ALERT_DEBOUNCE_INTERVAL" default:"10s"`
+	DefaultCorrelation    float64       `yaml:"default_correlation" env:"RISK_DEFAULT_CORRELATION" default:"0.3"`
+	VaRMethod             string        `yaml:"var_method" env:"RISK_VAR_METHOD" default:"parametric"`
+	HistoricalVaRDays     int           `yaml:"historical_var_days" env:"RISK_HISTORICAL_VAR_DAYS" default:"250"`
 }
 
 type TradingConfig struct {
@@ -72,6 +100,44 @@ type TradingConfig struct {
 	OrderTimeout       time.Duration `yaml:"order_timeout" env:"TRADING_ORDER_TIMEOUT" default:"30s"`
 	MarketDataTimeout  time.Duration `yaml:"market_data_timeout" env:"TRADING_MARKET_DATA_TIMEOUT" default:"5s"`
 	CommissionRate     float64       `yaml:"commission_rate" env:"TRADING_COMMISSION_RATE" default:"0.001"`
+	LongTermHoldingPeriod time.Duration `yaml:"long_term_holding_period" env:"TRADING_LONG_TERM_HOLDING_PERIOD" default:"8760h"`
+	PortfolioUpdateMode   string        `yaml:"portfolio_update_mode" env:"TRADING_PORTFOLIO_UPDATE_MODE" default:"snapshot"`
+	// CostBasisMethod measures realized P&L against the oldest lots (fifo) or the average price (average)
+	CostBasisMethod       string        `yaml:"cost_basis_method" env:"TRADING_COST_BASIS_METHOD" default:"fifo"`
+	// SnapshotInterval is how often portfolio values are recorded for performance reporting
+	SnapshotInterval      time.Duration `yaml:"snapshot_interval" env:"TRADING_SNAPSHOT_INTERVAL" default:"1h"`
+	SourceOrderRate       float64       `yaml:"source_order_rate" env:"TRADING_SOURCE_ORDER_RATE" default:"10"`
+	SourceOrderBurst      int           `yaml:"source_order_burst" env:"TRADING_SOURCE_ORDER_BURST" default:"20"`
+	// InitialCash funds the default portfolio when it is created on first startup
+	InitialCash float64 `yaml:"initial_cash" env:"TRADING_INITIAL_CASH" default:"100000"`
+	// MaxConcurrentExecutions bounds how many approved orders are sent to the broker at once
+	MaxConcurrentExecutions int `yaml:"max_concurrent_executions" env:"TRADING_MAX_CONCURRENT_EXECUTIONS" default:"10"`
+	// ExecutionDedupWindow is how long approved order IDs are remembered so a
+	// redelivered approval is not executed twice; zero disables it
+	ExecutionDedupWindow time.Duration `yaml:"execution_dedup_window" env:"TRADING_EXECUTION_DEDUP_WINDOW" default:"24h"`
+
+	// Execution* tune how the execution agent retries and tracks broker orders
+	ExecutionMaxRetries          int           `yaml:"execution_max_retries" env:"TRADING_EXECUTION_MAX_RETRIES" default:"3"`
+	ExecutionInitialDelay        time.Duration `yaml:"execution_initial_delay" env:"TRADING_EXECUTION_INITIAL_DELAY" default:"1s"`
+	ExecutionMaxDelay            time.Duration `yaml:"execution_max_delay" env:"TRADING_EXECUTION_MAX_DELAY" default:"30s"`
+	ExecutionBackoffFactor       float64       `yaml:"execution_backoff_factor" env:"TRADING_EXECUTION_BACKOFF_FACTOR" default:"2"`
+	ExecutionJitterFraction      float64       `yaml:"execution_jitter_fraction" env:"TRADING_EXECUTION_JITTER_FRACTION" default:"0.1"`
+	ExecutionAttemptTimeout      time.Duration `yaml:"execution_attempt_timeout" env:"TRADING_EXECUTION_ATTEMPT_TIMEOUT" default:"10s"`
+	ExecutionStatusCheckInterval time.Duration `yaml:"execution_status_check_interval" env:"TRADING_EXECUTION_STATUS_CHECK_INTERVAL" default:"5s"`
+	ExecutionMaxTrackedOrders    int           `yaml:"execution_max_tracked_orders" env:"TRADING_EXECUTION_MAX_TRACKED_ORDERS" default:"10000"`
+	ExecutionAbandonAfter        time.Duration `yaml:"execution_abandon_after" env:"TRADING_EXECUTION_ABANDON_AFTER" default:"1h"`
+	ExecutionMaxPendingAge       time.Duration `yaml:"execution_max_pending_age" env:"TRADING_EXECUTION_MAX_PENDING_AGE" default:"24h"`
+
+	ReconcileOnShutdown     bool    `yaml:"reconcile_on_shutdown" env:"TRADING_RECONCILE_ON_SHUTDOWN" default:"true"`
+	ReconciliationTolerance float64 `yaml:"reconciliation_tolerance" env:"TRADING_RECONCILIATION_TOLERANCE" default:"0.01"`
+
+	ExpirySweepInterval  time.Duration `yaml:"expiry_sweep_interval" env:"TRADING_EXPIRY_SWEEP_INTERVAL" default:"1s"`
+	ExpirySweepBatchSize int           `yaml:"expiry_sweep_batch_size" env:"TRADING_EXPIRY_SWEEP_BATCH_SIZE" default:"100"`
+
+	// Session hours are offsets from midnight in SessionTimezone; settlement runs at SessionClose
+	SessionTimezone string        `yaml:"session_timezone" env:"TRADING_SESSION_TIMEZONE" default:"America/New_York"`
+	SessionOpen     time.Duration `yaml:"session_open" env:"TRADING_SESSION_OPEN" default:"9h30m"`
+	SessionClose    time.Duration `yaml:"session_close" env:"TRADING_SESSION_CLOSE" default:"16h"`
 }
 
 type LoggingConfig struct {
@@ -85,6 +151,35 @@ type LoggingConfig struct {
 	Compress   bool   `yaml:"compress" env:"LOG_COMPRESS" default:"true"`
 }
 
+// MetricsConfig bounds how much a misbehaving metrics backend can affect the app
+type MetricsConfig struct {
+	MaxLabelValues         int           `yaml:"max_label_values" env:"METRICS_MAX_LABEL_VALUES" default:"1000"`
+	MaxConsecutiveFailures int           `yaml:"max_consecutive_failures" env:"METRICS_MAX_CONSECUTIVE_FAILURES" default:"5"`
+	FailureCooldown        time.Duration `yaml:"failure_cooldown" env:"METRICS_FAILURE_COOLDOWN" default:"1m"`
+}
+
+// WebhookConfig configures the opt-in forwarding of order events to an external URL
+type WebhookConfig struct {
+	Enabled bool   `yaml:"enabled" env:"WEBHOOK_ENABLED" default:"false"`
+	URL     string `yaml:"url" env:"WEBHOOK_URL"`
+	// Secret signs deliveries; empty uses the JWT secret
+	Secret      string        `yaml:"secret" env:"WEBHOOK_SECRET"`
+	Timeout     time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" default:"5s"`
+	MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"5"`
+	RetryDelay  time.Duration `yaml:"retry_delay" env:"WEBHOOK_RETRY_DELAY" default:"1s"`
+	DLQTopic    string        `yaml:"dlq_topic" env:"WEBHOOK_DLQ_TOPIC" default:"dlq.webhook"`
+}
+
+// ShutdownConfig bounds graceful shutdown. Each component gets StepTimeout, or
+// its entry in ComponentTimeouts, but never more than what remains of Timeout.
+type ShutdownConfig struct {
+	Timeout     time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	StepTimeout time.Duration `yaml:"step_timeout" env:"SHUTDOWN_STEP_TIMEOUT" default:"10s"`
+	// ComponentTimeouts is read from a comma-separated list such as
+	// "execution_agent=20s,http_server=5s"
+	ComponentTimeouts map[string]time.Duration `yaml:"component_timeouts" env:"SHUTDOWN_COMPONENT_TIMEOUTS"`
+}
+
 type SecurityConfig struct {
 	JWTSecret           string        `yaml:"jwt_secret" env:"JWT_SECRET,required"`
 	APIKeyRotationDays  int           `yaml:"api_key_rotation_days" env:"API_KEY_ROTATION_DAYS" default:"30"`
@@ -95,7 +190,13 @@ type SecurityConfig struct {
 	KeyFile             string        `yaml:"key_file" env:"TLS_KEY_FILE"`
 }
 
+// Load reads configuration from the environment, layered over the YAML file
+// named by CONFIG_FILE when it is set
 func Load() (*Config, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return LoadFromFile(path)
+	}
+
 	config := &Config{}
 
 	if err := loadFromEnv(config); err != nil {
@@ -109,6 +210,82 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// LoadFromFile reads configuration from the YAML file at path. Settings the
+// file leaves out keep their defaults, and environment variables that are set
+// override the file.
+func LoadFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	config := &Config{}
+	if err := loadFromEnv(config); err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := applyEnvOverrides(reflect.ValueOf(config).Elem()); err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := validate(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return config, nil
+}
+
+// applyEnvOverrides sets each field from the environment variable named by
+// its env tag, when that variable is set. Values that do not parse are
+// ignored, as they are by loadFromEnv.
+func applyEnvOverrides(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnvOverrides(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("env"), ",")
+		value := os.Getenv(key)
+		if key == "" || value == "" {
+			continue
+		}
+
+		switch field.Interface().(type) {
+		case string:
+			field.SetString(value)
+		case int:
+			if n, err := strconv.Atoi(value); err == nil {
+				field.SetInt(int64(n))
+			}
+		case float64:
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				field.SetFloat(f)
+			}
+		case bool:
+			if b, err := strconv.ParseBool(value); err == nil {
+				field.SetBool(b)
+			}
+		case time.Duration:
+			if d, err := time.ParseDuration(value); err == nil {
+				field.SetInt(int64(d))
+			}
+		case map[string]time.Duration:
+			durations, err := parseDurationMap(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+			field.Set(reflect.ValueOf(durations))
+		}
+	}
+	return nil
+}
+
 func loadFromEnv(config *Config) error {
 	config.Server = ServerConfig{
 		Host:         getEnvOrDefault("SERVER_HOST", "localhost"),
@@ -116,6 +293,7 @@ func loadFromEnv(config *Config) error {
 		ReadTimeout:  getEnvDurationOrDefault("SERVER_READ_TIMEOUT", 30*time.Second),
 		WriteTimeout: getEnvDurationOrDefault("SERVER_WRITE_TIMEOUT", 30*time.Second),
 		IdleTimeout:  getEnvDurationOrDefault("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		HealthCheckTimeout: getEnvDurationOrDefault("SERVER_HEALTH_CHECK_TIMEOUT", 2*time.Second),
 	}
 
 	config.Database = DatabaseConfig{
@@ -137,6 +315,7 @@ func loadFromEnv(config *Config) error {
 		ReconnectWait:     getEnvDurationOrDefault("NATS_RECONNECT_WAIT", 2*time.Second),
 		ConnectionTimeout: getEnvDurationOrDefault("NATS_CONNECTION_TIMEOUT", 5*time.Second),
 		DrainTimeout:      getEnvDurationOrDefault("NATS_DRAIN_TIMEOUT", 5*time.Second),
+		Serialization:     getEnvOrDefault("NATS_SERIALIZATION", "json"),
 	}
 
 	config.Redis = RedisConfig{
@@ -156,6 +335,21 @@ func loadFromEnv(config *Config) error {
 		MaxDailyLoss:       getEnvFloatOrDefault("RISK_MAX_DAILY_LOSS", 0.05),
 		MaxVaR:             getEnvFloatOrDefault("RISK_MAX_VAR", 0.02),
 		VaRConfidenceLevel: getEnvFloatOrDefault("RISK_VAR_CONFIDENCE", 0.95),
+		WarmUpPeriod:       getEnvDurationOrDefault("RISK_WARMUP_PERIOD", 2*time.Minute),
+		DegradedPolicy:        getEnvOrDefault("RISK_DEGRADED_POLICY", "fail_closed"),
+		DegradedMaxOrderValue: getEnvFloatOrDefault("RISK_DEGRADED_MAX_ORDER_VALUE", 1000),
+		MaxDrawdown:           getEnvFloatOrDefault("RISK_MAX_DRAWDOWN", 0.2),
+		AutoFlattenOnDrawdown: getEnvBoolOrDefault("RISK_AUTO_FLATTEN_ON_DRAWDOWN", false),
+		StopLossThreshold:     getEnvFloatOrDefault("RISK_STOP_LOSS_THRESHOLD", 0),
+		CashBufferPct:         getEnvFloatOrDefault("RISK_CASH_BUFFER_PCT", 0.10),
+		AlertSinkBuffer:       getEnvIntOrDefault("RISK_ALERT_SINK_BUFFER", 256),
+		AlertSinkTimeout:      getEnvDurationOrDefault("RISK_ALERT_SINK_TIMEOUT", 2*time.Second),
+		PriceStaleAfter:       getEnvDurationOrDefault("RISK_PRICE_STALE_AFTER", 5*time.Second),
+		StalePricePolicy:      getEnvOrDefault("RISK_STALE_PRICE_POLICY", "annotate"),
+		AlertDebounceInterval: getEnvDurationOrDefault("RISK_ALERT_DEBOUNCE_INTERVAL", 10*time.Second),
+		DefaultCorrelation:    getEnvFloatOrDefault("RISK_DEFAULT_CORRELATION", 0.3),
+		VaRMethod:             getEnvOrDefault("RISK_VAR_METHOD", "parametric"),
+		HistoricalVaRDays:     getEnvIntOrDefault("RISK_HISTORICAL_VAR_DAYS", 250),
 	}
 
 	config.Trading = TradingConfig{
@@ -164,6 +358,36 @@ func loadFromEnv(config *Config) error {
 		OrderTimeout:      getEnvDurationOrDefault("TRADING_ORDER_TIMEOUT", 30*time.Second),
 		MarketDataTimeout: getEnvDurationOrDefault("TRADING_MARKET_DATA_TIMEOUT", 5*time.Second),
 		CommissionRate:    getEnvFloatOrDefault("TRADING_COMMISSION_RATE", 0.001),
+		LongTermHoldingPeriod: getEnvDurationOrDefault("TRADING_LONG_TERM_HOLDING_PERIOD", 8760*time.Hour),
+		PortfolioUpdateMode:   getEnvOrDefault("TRADING_PORTFOLIO_UPDATE_MODE", "snapshot"),
+		CostBasisMethod:       getEnvOrDefault("TRADING_COST_BASIS_METHOD", "fifo"),
+		SnapshotInterval:      getEnvDurationOrDefault("TRADING_SNAPSHOT_INTERVAL", time.Hour),
+		SourceOrderRate:       getEnvFloatOrDefault("TRADING_SOURCE_ORDER_RATE", 10),
+		SourceOrderBurst:      getEnvIntOrDefault("TRADING_SOURCE_ORDER_BURST", 20),
+		InitialCash:           getEnvFloatOrDefault("TRADING_INITIAL_CASH", 100000),
+		MaxConcurrentExecutions: getEnvIntOrDefault("TRADING_MAX_CONCURRENT_EXECUTIONS", 10),
+		ExecutionDedupWindow:    getEnvDurationOrDefault("TRADING_EXECUTION_DEDUP_WINDOW", 24*time.Hour),
+
+		ExecutionMaxRetries:          getEnvIntOrDefault("TRADING_EXECUTION_MAX_RETRIES", 3),
+		ExecutionInitialDelay:        getEnvDurationOrDefault("TRADING_EXECUTION_INITIAL_DELAY", time.Second),
+		ExecutionMaxDelay:            getEnvDurationOrDefault("TRADING_EXECUTION_MAX_DELAY", 30*time.Second),
+		ExecutionBackoffFactor:       getEnvFloatOrDefault("TRADING_EXECUTION_BACKOFF_FACTOR", 2),
+		ExecutionJitterFraction:      getEnvFloatOrDefault("TRADING_EXECUTION_JITTER_FRACTION", 0.1),
+		ExecutionAttemptTimeout:      getEnvDurationOrDefault("TRADING_EXECUTION_ATTEMPT_TIMEOUT", 10*time.Second),
+		ExecutionStatusCheckInterval: getEnvDurationOrDefault("TRADING_EXECUTION_STATUS_CHECK_INTERVAL", 5*time.Second),
+		ExecutionMaxTrackedOrders:    getEnvIntOrDefault("TRADING_EXECUTION_MAX_TRACKED_ORDERS", 10000),
+		ExecutionAbandonAfter:        getEnvDurationOrDefault("TRADING_EXECUTION_ABANDON_AFTER", time.Hour),
+		ExecutionMaxPendingAge:       getEnvDurationOrDefault("TRADING_EXECUTION_MAX_PENDING_AGE", 24*time.Hour),
+
+		ReconcileOnShutdown:     getEnvBoolOrDefault("TRADING_RECONCILE_ON_SHUTDOWN", true),
+		ReconciliationTolerance: getEnvFloatOrDefault("TRADING_RECONCILIATION_TOLERANCE", 0.01),
+
+		ExpirySweepInterval:  getEnvDurationOrDefault("TRADING_EXPIRY_SWEEP_INTERVAL", time.Second),
+		ExpirySweepBatchSize: getEnvIntOrDefault("TRADING_EXPIRY_SWEEP_BATCH_SIZE", 100),
+
+		SessionTimezone: getEnvOrDefault("TRADING_SESSION_TIMEZONE", "America/New_York"),
+		SessionOpen:     getEnvDurationOrDefault("TRADING_SESSION_OPEN", 9*time.Hour+30*time.Minute),
+		SessionClose:    getEnvDurationOrDefault("TRADING_SESSION_CLOSE", 16*time.Hour),
 	}
 
 	config.Logging = LoggingConfig{
@@ -177,6 +401,32 @@ func loadFromEnv(config *Config) error {
 		Compress:   getEnvBoolOrDefault("LOG_COMPRESS", true),
 	}
 
+	config.Metrics = MetricsConfig{
+		MaxLabelValues:         getEnvIntOrDefault("METRICS_MAX_LABEL_VALUES", 1000),
+		MaxConsecutiveFailures: getEnvIntOrDefault("METRICS_MAX_CONSECUTIVE_FAILURES", 5),
+		FailureCooldown:        getEnvDurationOrDefault("METRICS_FAILURE_COOLDOWN", time.Minute),
+	}
+
+	config.Webhook = WebhookConfig{
+		Enabled:     getEnvBoolOrDefault("WEBHOOK_ENABLED", false),
+		URL:         os.Getenv("WEBHOOK_URL"),
+		Secret:      os.Getenv("WEBHOOK_SECRET"),
+		Timeout:     getEnvDurationOrDefault("WEBHOOK_TIMEOUT", 5*time.Second),
+		MaxAttempts: getEnvIntOrDefault("WEBHOOK_MAX_ATTEMPTS", 5),
+		RetryDelay:  getEnvDurationOrDefault("WEBHOOK_RETRY_DELAY", time.Second),
+		DLQTopic:    getEnvOrDefault("WEBHOOK_DLQ_TOPIC", "dlq.webhook"),
+	}
+
+	componentTimeouts, err := parseDurationMap(os.Getenv("SHUTDOWN_COMPONENT_TIMEOUTS"))
+	if err != nil {
+		return fmt.Errorf("invalid SHUTDOWN_COMPONENT_TIMEOUTS: %w", err)
+	}
+	config.Shutdown = ShutdownConfig{
+		Timeout:           getEnvDurationOrDefault("SHUTDOWN_TIMEOUT", 30*time.Second),
+		StepTimeout:       getEnvDurationOrDefault("SHUTDOWN_STEP_TIMEOUT", 10*time.Second),
+		ComponentTimeouts: componentTimeouts,
+	}
+
 	config.Security = SecurityConfig{
 		JWTSecret:          os.Getenv("JWT_SECRET"),
 		APIKeyRotationDays: getEnvIntOrDefault("API_KEY_ROTATION_DAYS", 30),
@@ -206,6 +456,98 @@ func validate(config *Config) error {
 	if len(config.Security.JWTSecret) < 32 {
 		return fmt.Errorf("JWT secret must be at least 32 characters")
 	}
+	if config.Trading.InitialCash < 0 {
+		return fmt.Errorf("initial cash cannot be negative, got: %v", config.Trading.InitialCash)
+	}
+	if config.Trading.MaxConcurrentExecutions <= 0 {
+		return fmt.Errorf("max concurrent executions must be positive, got: %d", config.Trading.MaxConcurrentExecutions)
+	}
+	if config.Trading.ExecutionDedupWindow < 0 {
+		return fmt.Errorf("execution dedup window cannot be negative, got: %s", config.Trading.ExecutionDedupWindow)
+	}
+	if config.Trading.ExecutionMaxRetries < 0 {
+		return fmt.Errorf("execution max retries cannot be negative, got: %d", config.Trading.ExecutionMaxRetries)
+	}
+	if config.Trading.ExecutionBackoffFactor < 1 {
+		return fmt.Errorf("execution backoff factor must be at least 1, got: %v", config.Trading.ExecutionBackoffFactor)
+	}
+	if config.Trading.ExecutionStatusCheckInterval <= 0 {
+		return fmt.Errorf("execution status check interval must be positive, got: %s", config.Trading.ExecutionStatusCheckInterval)
+	}
+	switch config.Risk.VaRMethod {
+	case "parametric", "historical":
+	default:
+		return fmt.Errorf("VaR method must be parametric or historical, got: %s", config.Risk.VaRMethod)
+	}
+	if config.Risk.HistoricalVaRDays <= 0 {
+		return fmt.Errorf("historical VaR days must be positive, got: %d", config.Risk.HistoricalVaRDays)
+	}
+	if config.Risk.VaRConfidenceLevel <= 0 || config.Risk.VaRConfidenceLevel >= 1 {
+		return fmt.Errorf("risk VaR confidence level must be between 0 and 1, got: %v", config.Risk.VaRConfidenceLevel)
+	}
+	if config.Risk.StopLossThreshold < 0 || config.Risk.StopLossThreshold >= 1 {
+		return fmt.Errorf("risk stop-loss threshold must be in [0, 1), got: %v", config.Risk.StopLossThreshold)
+	}
+	if config.Risk.CashBufferPct < 0 {
+		return fmt.Errorf("risk cash buffer cannot be negative, got: %v", config.Risk.CashBufferPct)
+	}
+
+	if config.Risk.DefaultCorrelation < -1 || config.Risk.DefaultCorrelation > 1 {
+		return fmt.Errorf("risk default correlation must be between -1 and 1, got: %v", config.Risk.DefaultCorrelation)
+	}
+	if config.Webhook.Enabled {
+		if config.Webhook.URL == "" {
+			return fmt.Errorf("webhook URL is required when the webhook is enabled")
+		}
+		if config.Webhook.MaxAttempts <= 0 {
+			return fmt.Errorf("webhook max attempts must be positive, got: %d", config.Webhook.MaxAttempts)
+		}
+	}
+	if config.Shutdown.Timeout <= 0 {
+		return fmt.Errorf("shutdown timeout must be positive, got: %s", config.Shutdown.Timeout)
+	}
+	if config.Shutdown.StepTimeout <= 0 {
+		return fmt.Errorf("shutdown step timeout must be positive, got: %s", config.Shutdown.StepTimeout)
+	}
+	for component, timeout := range config.Shutdown.ComponentTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("shutdown timeout for %s must be positive, got: %s", component, timeout)
+		}
+	}
+	if config.Metrics.MaxLabelValues <= 0 {
+		return fmt.Errorf("metrics max label values must be positive, got: %d", config.Metrics.MaxLabelValues)
+	}
+	if config.Metrics.MaxConsecutiveFailures <= 0 {
+		return fmt.Errorf("metrics max consecutive failures must be positive, got: %d", config.Metrics.MaxConsecutiveFailures)
+	}
+	if config.Metrics.FailureCooldown <= 0 {
+		return fmt.Errorf("metrics failure cooldown must be positive, got: %s", config.Metrics.FailureCooldown)
+	}
+	switch config.Trading.PortfolioUpdateMode {
+	case "snapshot", "delta":
+	default:
+		return fmt.Errorf("portfolio update mode must be snapshot or delta, got: %s", config.Trading.PortfolioUpdateMode)
+	}
+	switch config.Trading.CostBasisMethod {
+	case "fifo", "average":
+	default:
+		return fmt.Errorf("cost basis method must be fifo or average, got: %s", config.Trading.CostBasisMethod)
+	}
+	if config.Trading.SnapshotInterval <= 0 {
+		return fmt.Errorf("snapshot interval must be positive, got: %s", config.Trading.SnapshotInterval)
+	}
+	switch config.Risk.StalePricePolicy {
+	case "annotate", "reject":
+	default:
+		return fmt.Errorf("stale price policy must be annotate or reject, got: %s", config.Risk.StalePricePolicy)
+	}
+	if _, err := time.LoadLocation(config.Trading.SessionTimezone); err != nil {
+		return fmt.Errorf("invalid session timezone %s: %w", config.Trading.SessionTimezone, err)
+	}
+	if config.Trading.SessionOpen >= config.Trading.SessionClose || config.Trading.SessionClose > 24*time.Hour {
+		return fmt.Errorf("session must open before it closes within one day, got %s-%s",
+			config.Trading.SessionOpen, config.Trading.SessionClose)
+	}
 
 	return nil
 }
@@ -251,4 +593,25 @@ func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Durati
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// parseDurationMap parses "name=duration" pairs separated by commas
+func parseDurationMap(value string) (map[string]time.Duration, error) {
+	durations := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("expected name=duration, got %q", pair)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for %s: %w", name, err)
+		}
+		durations[strings.TrimSpace(name)] = duration
+	}
+	return durations, nil
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadFromEnv_ExecutionRetrySettings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg := &Config{}
+		if err := loadFromEnv(cfg); err != nil {
+			t.Fatalf("loadFromEnv failed: %v", err)
+		}
+
+		trading := cfg.Trading
+		if trading.ExecutionMaxRetries != 3 {
+			t.Errorf("Expected 3 max retries, got %d", trading.ExecutionMaxRetries)
+		}
+		if trading.ExecutionInitialDelay != time.Second {
+			t.Errorf("Expected 1s initial delay, got %s", trading.ExecutionInitialDelay)
+		}
+		if trading.ExecutionMaxDelay != 30*time.Second {
+			t.Errorf("Expected 30s max delay, got %s", trading.ExecutionMaxDelay)
+		}
+		if trading.ExecutionBackoffFactor != 2 {
+			t.Errorf("Expected backoff factor 2, got %v", trading.ExecutionBackoffFactor)
+		}
+		if trading.ExecutionStatusCheckInterval != 5*time.Second {
+			t.Errorf("Expected 5s status check interval, got %s", trading.ExecutionStatusCheckInterval)
+		}
+		if trading.ExecutionMaxPendingAge != 24*time.Hour {
+			t.Errorf("Expected 24h max pending age, got %s", trading.ExecutionMaxPendingAge)
+		}
+	})
+
+	t.Run("overridden by env", func(t *testing.T) {
+		t.Setenv("TRADING_EXECUTION_MAX_RETRIES", "5")
+		t.Setenv("TRADING_EXECUTION_INITIAL_DELAY", "250ms")
+		t.Setenv("TRADING_EXECUTION_MAX_DELAY", "10s")
+		t.Setenv("TRADING_EXECUTION_BACKOFF_FACTOR", "1.5")
+		t.Setenv("TRADING_EXECUTION_JITTER_FRACTION", "0.2")
+		t.Setenv("TRADING_EXECUTION_ATTEMPT_TIMEOUT", "3s")
+		t.Setenv("TRADING_EXECUTION_STATUS_CHECK_INTERVAL", "2s")
+		t.Setenv("TRADING_EXECUTION_MAX_TRACKED_ORDERS", "500")
+		t.Setenv("TRADING_EXECUTION_ABANDON_AFTER", "30m")
+		t.Setenv("TRADING_EXECUTION_MAX_PENDING_AGE", "8h")
+
+		cfg := &Config{}
+		if err := loadFromEnv(cfg); err != nil {
+			t.Fatalf("loadFromEnv failed: %v", err)
+		}
+
+		trading := cfg.Trading
+		if trading.ExecutionMaxRetries != 5 {
+			t.Errorf("Expected 5 max retries, got %d", trading.ExecutionMaxRetries)
+		}
+		if trading.ExecutionInitialDelay != 250*time.Millisecond {
+			t.Errorf("Expected 250ms initial delay, got %s", trading.ExecutionInitialDelay)
+		}
+		if trading.ExecutionMaxDelay != 10*time.Second {
+			t.Errorf("Expected 10s max delay, got %s", trading.ExecutionMaxDelay)
+		}
+		if trading.ExecutionBackoffFactor != 1.5 {
+			t.Errorf("Expected backoff factor 1.5, got %v", trading.ExecutionBackoffFactor)
+		}
+		if trading.ExecutionJitterFraction != 0.2 {
+			t.Errorf("Expected jitter fraction 0.2, got %v", trading.ExecutionJitterFraction)
+		}
+		if trading.ExecutionAttemptTimeout != 3*time.Second {
+			t.Errorf("Expected 3s attempt timeout, got %s", trading.ExecutionAttemptTimeout)
+		}
+		if trading.ExecutionStatusCheckInterval != 2*time.Second {
+			t.Errorf("Expected 2s status check interval, got %s", trading.ExecutionStatusCheckInterval)
+		}
+		if trading.ExecutionMaxTrackedOrders != 500 {
+			t.Errorf("Expected 500 max tracked orders, got %d", trading.ExecutionMaxTrackedOrders)
+		}
+		if trading.ExecutionAbandonAfter != 30*time.Minute {
+			t.Errorf("Expected 30m abandon after, got %s", trading.ExecutionAbandonAfter)
+		}
+		if trading.ExecutionMaxPendingAge != 8*time.Hour {
+			t.Errorf("Expected 8h max pending age, got %s", trading.ExecutionMaxPendingAge)
+		}
+	})
+}
+
+func TestLoadFromEnv_ShutdownComponentTimeouts(t *testing.T) {
+	t.Setenv("SHUTDOWN_COMPONENT_TIMEOUTS", "execution_agent=20s, http_server=500ms")
+
+	cfg := &Config{}
+	if err := loadFromEnv(cfg); err != nil {
+		t.Fatalf("loadFromEnv failed: %v", err)
+	}
+
+	timeouts := cfg.Shutdown.ComponentTimeouts
+	if timeouts["execution_agent"] != 20*time.Second || timeouts["http_server"] != 500*time.Millisecond {
+		t.Errorf("Unexpected component timeouts: %v", timeouts)
+	}
+	if cfg.Shutdown.Timeout != 30*time.Second || cfg.Shutdown.StepTimeout != 10*time.Second {
+		t.Errorf("Expected 30s overall and 10s step defaults, got %s and %s", cfg.Shutdown.Timeout, cfg.Shutdown.StepTimeout)
+	}
+
+	t.Setenv("SHUTDOWN_COMPONENT_TIMEOUTS", "execution_agent")
+	if err := loadFromEnv(&Config{}); err == nil {
+		t.Error("Expected a malformed entry to be rejected")
+	}
+}
+
+func TestValidate_RiskCashBuffer(t *testing.T) {
+	t.Setenv("DB_NAME", "trading")
+	t.Setenv("DB_USER", "trader")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("JWT_SECRET", "0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0.10, false},
+		{"0", 0, false},
+		{"-0.05", -0.05, true},
+	}
+
+	for _, tt := range tests {
+		t.Run("RISK_CASH_BUFFER_PCT="+tt.value, func(t *testing.T) {
+			if tt.value != "" {
+				t.Setenv("RISK_CASH_BUFFER_PCT", tt.value)
+			}
+
+			cfg := &Config{}
+			if err := loadFromEnv(cfg); err != nil {
+				t.Fatalf("loadFromEnv failed: %v", err)
+			}
+			if cfg.Risk.CashBufferPct != tt.want {
+				t.Errorf("Expected cash buffer %v, got %v", tt.want, cfg.Risk.CashBufferPct)
+			}
+			if err := validate(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Expected validation error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: 9090
+  read_timeout: 15s
+database:
+  database: trading
+  username: trader
+  password: from-file
+logging:
+  level: debug
+  compress: false
+trading:
+  execution_max_retries: 7
+shutdown:
+  component_timeouts:
+    execution_agent: 20s
+security:
+  jwt_secret: 0123456789abcdef0123456789abcdef
+`)
+
+	t.Run("file over defaults", func(t *testing.T) {
+		cfg, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("LoadFromFile failed: %v", err)
+		}
+
+		if cfg.Server.Port != 9090 || cfg.Server.ReadTimeout != 15*time.Second {
+			t.Errorf("Expected server settings from the file, got port %d and read timeout %s", cfg.Server.Port, cfg.Server.ReadTimeout)
+		}
+		if cfg.Database.Database != "trading" || cfg.Database.Password != "from-file" {
+			t.Errorf("Expected database settings from the file, got %+v", cfg.Database)
+		}
+		if cfg.Logging.Level != "debug" || cfg.Logging.Compress {
+			t.Errorf("Expected debug logging without compression, got %+v", cfg.Logging)
+		}
+		if cfg.Trading.ExecutionMaxRetries != 7 {
+			t.Errorf("Expected 7 max retries from the file, got %d", cfg.Trading.ExecutionMaxRetries)
+		}
+		if got := cfg.Shutdown.ComponentTimeouts["execution_agent"]; got != 20*time.Second {
+			t.Errorf("Expected a 20s execution_agent shutdown timeout, got %s", got)
+		}
+
+		// Settings the file leaves out keep their defaults
+		if cfg.Server.WriteTimeout != 30*time.Second || cfg.NATS.URL != "nats://localhost:4222" {
+			t.Errorf("Expected defaults for unset settings, got write timeout %s and NATS URL %s", cfg.Server.WriteTimeout, cfg.NATS.URL)
+		}
+	})
+
+	t.Run("env over file", func(t *testing.T) {
+		t.Setenv("SERVER_PORT", "7070")
+		t.Setenv("DB_PASSWORD", "from-env")
+		t.Setenv("LOG_COMPRESS", "true")
+		t.Setenv("SHUTDOWN_COMPONENT_TIMEOUTS", "http_server=5s")
+
+		cfg, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("LoadFromFile failed: %v", err)
+		}
+
+		if cfg.Server.Port != 7070 || cfg.Database.Password != "from-env" || !cfg.Logging.Compress {
+			t.Errorf("Expected env to override the file, got port %d, password %q, compress %t",
+				cfg.Server.Port, cfg.Database.Password, cfg.Logging.Compress)
+		}
+		if cfg.Server.ReadTimeout != 15*time.Second || cfg.Trading.ExecutionMaxRetries != 7 {
+			t.Errorf("Expected file settings without an env var to stand")
+		}
+		want := map[string]time.Duration{"http_server": 5 * time.Second}
+		if !reflect.DeepEqual(cfg.Shutdown.ComponentTimeouts, want) {
+			t.Errorf("Expected component timeouts %v from env, got %v", want, cfg.Shutdown.ComponentTimeouts)
+		}
+	})
+
+	t.Run("via CONFIG_FILE", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", path)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Server.Port != 9090 {
+			t.Errorf("Expected Load to read CONFIG_FILE, got port %d", cfg.Server.Port)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+		if _, err := LoadFromFile(writeConfigFile(t, "server: [")); err == nil {
+			t.Error("Expected an error for malformed YAML")
+		}
+		if _, err := LoadFromFile(writeConfigFile(t, "server:\n  port: 9090\n")); err == nil {
+			t.Error("Expected validation to reject a file without required settings")
+		}
+	})
+}
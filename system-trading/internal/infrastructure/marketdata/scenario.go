@@ -0,0 +1,187 @@
+package marketdata
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+)
+
+// ScenarioConfig configures a ScenarioGenerator. The same config, including
+// Seed, always produces the same event stream.
+type ScenarioConfig struct {
+	Seed    uint64
+	Symbols []entities.Symbol
+	// Steps is how many rounds to generate; each round ticks every symbol and
+	// may add one news article and one strategy signal
+	Steps             int
+	NewsProbability   float64
+	SignalProbability float64
+	// MaxSignalQuantity caps the quantity of a generated signal
+	MaxSignalQuantity float64
+	InitialPrice      float64
+	// Volatility is the per-tick volatility of every symbol's price path
+	Volatility   float64
+	Start        time.Time
+	TickInterval time.Duration
+}
+
+type ScenarioEventKind string
+
+const (
+	ScenarioMarketTick ScenarioEventKind = "market_tick"
+	ScenarioNews       ScenarioEventKind = "news"
+	ScenarioSignal     ScenarioEventKind = "signal"
+)
+
+// StrategySignal is a generated request to trade, as a strategy would emit it
+type StrategySignal struct {
+	Symbol    entities.Symbol    `json:"symbol"`
+	Side      entities.OrderSide `json:"side"`
+	Quantity  float64            `json:"quantity"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// ScenarioEvent is one generated input. Exactly one of MarketData, News and
+// Signal is set, matching Kind.
+type ScenarioEvent struct {
+	Step       int
+	Kind       ScenarioEventKind
+	MarketData *entities.MarketData
+	News       *entities.NewsArticle
+	Signal     *StrategySignal
+}
+
+var scenarioHeadlines = []string{
+	"%s beats estimates as growth surges",
+	"%s shares rally on record profit",
+	"%s misses guidance and shares fall",
+	"%s faces lawsuit over product recall",
+	"%s holds annual shareholder meeting",
+}
+
+// ScenarioGenerator produces a reproducible stream of market ticks, news and
+// strategy signals for integration tests
+type ScenarioGenerator struct {
+	config ScenarioConfig
+	clock  *clock.FakeClock
+	prices *SimulatedPriceProvider
+	rng    splitMix64
+
+	step    int
+	pending []ScenarioEvent
+	newsSeq int
+}
+
+func NewScenarioGenerator(config ScenarioConfig) *ScenarioGenerator {
+	if len(config.Symbols) == 0 {
+		config.Symbols = []entities.Symbol{"AAPL"}
+	}
+	if config.MaxSignalQuantity <= 0 {
+		config.MaxSignalQuantity = 100
+	}
+	if config.InitialPrice <= 0 {
+		config.InitialPrice = 100
+	}
+	if config.TickInterval <= 0 {
+		config.TickInterval = time.Second
+	}
+	if config.Start.IsZero() {
+		config.Start = time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	}
+
+	scenarioClock := clock.NewFakeClock(config.Start)
+	prices := NewSimulatedPriceProvider(SimulatedPriceConfig{
+		Default: SymbolSimulation{
+			InitialPrice: config.InitialPrice,
+			Volatility:   config.Volatility,
+			Seed:         config.Seed,
+		},
+		TickInterval: config.TickInterval,
+		Clock:        scenarioClock,
+	})
+
+	return &ScenarioGenerator{
+		config: config,
+		clock:  scenarioClock,
+		prices: prices,
+		// Keep event choices independent of the price paths
+		rng: splitMix64(config.Seed ^ 0x5ce4a710),
+	}
+}
+
+// Next returns the next event, or false once every step has been generated
+func (g *ScenarioGenerator) Next() (ScenarioEvent, bool) {
+	for len(g.pending) == 0 {
+		if g.step >= g.config.Steps {
+			return ScenarioEvent{}, false
+		}
+		g.generateStep()
+	}
+
+	event := g.pending[0]
+	g.pending = g.pending[1:]
+	return event, true
+}
+
+// Events generates the whole remaining stream
+func (g *ScenarioGenerator) Events() []ScenarioEvent {
+	var events []ScenarioEvent
+	for event, ok := g.Next(); ok; event, ok = g.Next() {
+		events = append(events, event)
+	}
+	return events
+}
+
+func (g *ScenarioGenerator) generateStep() {
+	g.step++
+	g.clock.Advance(g.config.TickInterval)
+
+	for _, symbol := range g.config.Symbols {
+		g.pending = append(g.pending, ScenarioEvent{
+			Step:       g.step,
+			Kind:       ScenarioMarketTick,
+			MarketData: g.prices.Next(symbol),
+		})
+	}
+
+	if g.rng.float64() < g.config.NewsProbability {
+		symbol := g.pickSymbol()
+		headline := scenarioHeadlines[g.rng.next()%uint64(len(scenarioHeadlines))]
+		g.newsSeq++
+		g.pending = append(g.pending, ScenarioEvent{
+			Step: g.step,
+			Kind: ScenarioNews,
+			News: &entities.NewsArticle{
+				ID:        fmt.Sprintf("scenario-news-%d", g.newsSeq),
+				Title:     fmt.Sprintf(headline, symbol),
+				Source:    "scenario",
+				Symbols:   []entities.Symbol{symbol},
+				Timestamp: g.clock.Now(),
+			},
+		})
+	}
+
+	if g.rng.float64() < g.config.SignalProbability {
+		side := entities.OrderSideBuy
+		if g.rng.float64() < 0.5 {
+			side = entities.OrderSideSell
+		}
+		g.pending = append(g.pending, ScenarioEvent{
+			Step: g.step,
+			Kind: ScenarioSignal,
+			Signal: &StrategySignal{
+				Symbol:    g.pickSymbol(),
+				Side:      side,
+				Quantity:  1 + math.Floor(g.rng.float64()*g.config.MaxSignalQuantity),
+				Timestamp: g.clock.Now(),
+			},
+		})
+	}
+}
+
+func (g *ScenarioGenerator) pickSymbol() entities.Symbol {
+	return g.config.Symbols[g.rng.next()%uint64(len(g.config.Symbols))]
+}
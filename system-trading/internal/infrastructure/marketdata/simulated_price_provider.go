@@ -0,0 +1,263 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// SymbolSimulation parameterises the geometric Brownian motion path of one symbol.
+// Drift and Volatility are per tick.
+type SymbolSimulation struct {
+	InitialPrice float64
+	Drift        float64
+	Volatility   float64
+	Seed         uint64
+}
+
+// SimulatedPriceConfig configures the simulated provider. Symbols without an
+// entry use Default, seeded from Default.Seed mixed with the symbol name so each
+// symbol still gets its own reproducible path.
+type SimulatedPriceConfig struct {
+	Symbols      map[entities.Symbol]SymbolSimulation
+	Default      SymbolSimulation
+	TickInterval time.Duration
+	Clock        ifs.Clock
+}
+
+// SymbolState is the resumable state of one simulated symbol
+type SymbolState struct {
+	Price    float64 `json:"price"`
+	RNGState uint64  `json:"rng_state"`
+	Steps    int64   `json:"steps"`
+}
+
+// SimulationSnapshot captures every simulated symbol so a run can be resumed
+type SimulationSnapshot map[entities.Symbol]SymbolState
+
+// SimulatedPriceProvider generates deterministic per-symbol price paths for
+// backtests and local runs
+type SimulatedPriceProvider struct {
+	config SimulatedPriceConfig
+	clock  ifs.Clock
+
+	mu            sync.Mutex
+	symbols       map[entities.Symbol]*simulatedSymbol
+	subscriptions map[entities.Symbol]context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+type simulatedSymbol struct {
+	params SymbolSimulation
+	price  float64
+	rng    splitMix64
+	steps  int64
+}
+
+func NewSimulatedPriceProvider(config SimulatedPriceConfig) *SimulatedPriceProvider {
+	if config.TickInterval <= 0 {
+		config.TickInterval = time.Second
+	}
+	if config.Default.InitialPrice <= 0 {
+		config.Default.InitialPrice = 100.0
+	}
+
+	providerClock := config.Clock
+	if providerClock == nil {
+		providerClock = clock.NewRealClock()
+	}
+
+	return &SimulatedPriceProvider{
+		config:        config,
+		clock:         providerClock,
+		symbols:       make(map[entities.Symbol]*simulatedSymbol),
+		subscriptions: make(map[entities.Symbol]context.CancelFunc),
+	}
+}
+
+// GetRealTimePrice returns the current simulated price without advancing the path
+func (p *SimulatedPriceProvider) GetRealTimePrice(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := p.symbolLocked(symbol)
+	return p.marketDataLocked(symbol, state, state.price), nil
+}
+
+// Next advances symbol by one tick and returns the new price
+func (p *SimulatedPriceProvider) Next(symbol entities.Symbol) *entities.MarketData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := p.symbolLocked(symbol)
+	open := state.price
+
+	shock := state.rng.normal()
+	vol := state.params.Volatility
+	state.price *= math.Exp(state.params.Drift - vol*vol/2 + vol*shock)
+	state.steps++
+
+	return p.marketDataLocked(symbol, state, open)
+}
+
+// SubscribeToPrice calls callback with a new tick every TickInterval until unsubscribed
+func (p *SimulatedPriceProvider) SubscribeToPrice(ctx context.Context, symbol entities.Symbol, callback func(*entities.MarketData)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.subscriptions[symbol]; exists {
+		return fmt.Errorf("already subscribed to symbol: %s", symbol)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p.subscriptions[symbol] = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.clock.After(p.config.TickInterval):
+				callback(p.Next(symbol))
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (p *SimulatedPriceProvider) UnsubscribeFromPrice(ctx context.Context, symbol entities.Symbol) error {
+	p.mu.Lock()
+	cancel, exists := p.subscriptions[symbol]
+	delete(p.subscriptions, symbol)
+	p.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("not subscribed to symbol: %s", symbol)
+	}
+	cancel()
+	return nil
+}
+
+// Close stops every subscription and waits for their goroutines to exit
+func (p *SimulatedPriceProvider) Close() {
+	p.mu.Lock()
+	for symbol, cancel := range p.subscriptions {
+		cancel()
+		delete(p.subscriptions, symbol)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// Snapshot captures the price and RNG state of every symbol seen so far
+func (p *SimulatedPriceProvider) Snapshot() SimulationSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := make(SimulationSnapshot, len(p.symbols))
+	for symbol, state := range p.symbols {
+		snapshot[symbol] = SymbolState{
+			Price:    state.price,
+			RNGState: uint64(state.rng),
+			Steps:    state.steps,
+		}
+	}
+	return snapshot
+}
+
+// Restore resumes the paths captured by Snapshot; symbols absent from the
+// snapshot keep their current state
+func (p *SimulatedPriceProvider) Restore(snapshot SimulationSnapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for symbol, saved := range snapshot {
+		state := p.symbolLocked(symbol)
+		state.price = saved.Price
+		state.rng = splitMix64(saved.RNGState)
+		state.steps = saved.Steps
+	}
+}
+
+func (p *SimulatedPriceProvider) symbolLocked(symbol entities.Symbol) *simulatedSymbol {
+	if state, exists := p.symbols[symbol]; exists {
+		return state
+	}
+
+	params, configured := p.config.Symbols[symbol]
+	if !configured {
+		params = p.config.Default
+		params.Seed ^= hashSymbol(symbol)
+	}
+	if params.InitialPrice <= 0 {
+		params.InitialPrice = p.config.Default.InitialPrice
+	}
+
+	state := &simulatedSymbol{
+		params: params,
+		price:  params.InitialPrice,
+		rng:    splitMix64(params.Seed),
+	}
+	p.symbols[symbol] = state
+	return state
+}
+
+func (p *SimulatedPriceProvider) marketDataLocked(symbol entities.Symbol, state *simulatedSymbol, open float64) *entities.MarketData {
+	spread := state.price * 0.0005
+	return &entities.MarketData{
+		Symbol:    symbol,
+		Price:     state.price,
+		Bid:       state.price - spread,
+		Ask:       state.price + spread,
+		High:      math.Max(open, state.price),
+		Low:       math.Min(open, state.price),
+		Open:      open,
+		Timestamp: p.clock.Now(),
+	}
+}
+
+// splitMix64 is a tiny PRNG whose whole state is one uint64, which keeps
+// snapshots trivial compared to math/rand's opaque source
+type splitMix64 uint64
+
+func (s *splitMix64) next() uint64 {
+	*s += 0x9e3779b97f4a7c15
+	z := uint64(*s)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// float64 returns a uniform value in (0, 1)
+func (s *splitMix64) float64() float64 {
+	return (float64(s.next()>>11) + 0.5) / (1 << 53)
+}
+
+// normal returns a standard normal value via Box-Muller
+func (s *splitMix64) normal() float64 {
+	u1, u2 := s.float64(), s.float64()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+func hashSymbol(symbol entities.Symbol) uint64 {
+	// FNV-1a
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(symbol); i++ {
+		hash ^= uint64(symbol[i])
+		hash *= 1099511628211
+	}
+	return hash
+}
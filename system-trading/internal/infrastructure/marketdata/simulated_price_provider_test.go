@@ -0,0 +1,127 @@
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+)
+
+func simulatePath(provider *SimulatedPriceProvider, symbol entities.Symbol, steps int) []float64 {
+	path := make([]float64, steps)
+	for i := range path {
+		path[i] = provider.Next(symbol).Price
+	}
+	return path
+}
+
+func newSeededProvider(seed uint64) *SimulatedPriceProvider {
+	return NewSimulatedPriceProvider(SimulatedPriceConfig{
+		Symbols: map[entities.Symbol]SymbolSimulation{
+			"AAPL": {InitialPrice: 180, Drift: 0.0001, Volatility: 0.02, Seed: seed},
+		},
+		Default: SymbolSimulation{InitialPrice: 50, Volatility: 0.01, Seed: seed},
+		Clock:   clock.NewFakeClock(time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)),
+	})
+}
+
+func TestSimulatedPriceProvider_Reproducibility(t *testing.T) {
+	tests := []struct {
+		name      string
+		seedA     uint64
+		seedB     uint64
+		wantEqual bool
+	}{
+		{"same seed reproduces path", 42, 42, true},
+		{"different seed diverges", 42, 43, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, symbol := range []entities.Symbol{"AAPL", "MSFT"} {
+				pathA := simulatePath(newSeededProvider(tt.seedA), symbol, 100)
+				pathB := simulatePath(newSeededProvider(tt.seedB), symbol, 100)
+
+				equal := true
+				for i := range pathA {
+					if pathA[i] != pathB[i] {
+						equal = false
+						break
+					}
+				}
+				if equal != tt.wantEqual {
+					t.Errorf("%s: expected paths equal=%v, got %v", symbol, tt.wantEqual, equal)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatedPriceProvider_SymbolsHaveIndependentPaths(t *testing.T) {
+	provider := newSeededProvider(7)
+
+	msft := simulatePath(provider, "MSFT", 10)
+	googl := simulatePath(provider, "GOOGL", 10)
+
+	if msft[9] == googl[9] {
+		t.Error("Expected default-configured symbols to follow different paths")
+	}
+}
+
+func TestSimulatedPriceProvider_SnapshotRestore(t *testing.T) {
+	provider := newSeededProvider(42)
+	simulatePath(provider, "AAPL", 25)
+
+	snapshot := provider.Snapshot()
+	expected := simulatePath(provider, "AAPL", 25)
+
+	resumed := newSeededProvider(1)
+	resumed.Restore(snapshot)
+	got := simulatePath(resumed, "AAPL", 25)
+
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Fatalf("Step %d: resumed price %v differs from original %v", i, got[i], expected[i])
+		}
+	}
+	if steps := resumed.Snapshot()["AAPL"].Steps; steps != 50 {
+		t.Errorf("Expected 50 steps after resume, got %d", steps)
+	}
+}
+
+func TestSimulatedPriceProvider_SubscribeTicksOnClock(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC))
+	provider := NewSimulatedPriceProvider(SimulatedPriceConfig{
+		Default:      SymbolSimulation{InitialPrice: 100, Volatility: 0.01, Seed: 1},
+		TickInterval: time.Second,
+		Clock:        fakeClock,
+	})
+	defer provider.Close()
+
+	ticks := make(chan *entities.MarketData, 1)
+	if err := provider.SubscribeToPrice(context.Background(), "AAPL", func(data *entities.MarketData) {
+		ticks <- data
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	for fakeClock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fakeClock.Advance(time.Second)
+
+	select {
+	case tick := <-ticks:
+		if tick.Symbol != "AAPL" || tick.Price <= 0 {
+			t.Errorf("Unexpected tick: %+v", tick)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a tick after advancing the clock")
+	}
+
+	if err := provider.UnsubscribeFromPrice(context.Background(), "AAPL"); err != nil {
+		t.Fatalf("Unsubscribe failed: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package messagebus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentTypeHeader is the message header carrying the payload's codec
+const ContentTypeHeader = "Content-Type"
+
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeMsgPack = "application/msgpack"
+)
+
+// Codec encodes bus payloads. The bus stamps every message with the codec's
+// content type so subscribers can decode regardless of the publisher's choice.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// contentTypeCarrier is implemented by *MessageEnvelope and any message embedding it
+type contentTypeCarrier interface {
+	setContentType(contentType string)
+}
+
+// stampContentType records the codec on envelope messages so the content type
+// survives even when the payload is stored or forwarded without its headers
+func stampContentType(message interface{}, contentType string) {
+	if carrier, ok := message.(contentTypeCarrier); ok {
+		carrier.setContentType(contentType)
+	}
+}
+
+// CodecByName resolves a configured serialization format; empty means JSON
+func CodecByName(name string) (Codec, error) {
+	switch strings.ToLower(name) {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "msgpack":
+		return MsgPackCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown serialization format: %s", name)
+	}
+}
+
+// CodecForContentType resolves the codec for a received message; an empty
+// content type is treated as JSON for publishers that predate the header
+func CodecForContentType(contentType string) (Codec, error) {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return JSONCodec{}, nil
+	case ContentTypeMsgPack:
+		return MsgPackCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// MsgPackCodec encodes payloads as MessagePack straight from and into Go
+// values. Fields are named by their json tags, so a struct has the same field
+// names and omitempty behaviour under either codec.
+type MsgPackCodec struct{}
+
+func init() {
+	// The library decodes timestamps in local time; the bus carries UTC
+	msgpack.RegisterExtDecoder(msgpackTimeExtID, time.Time{}, decodeMsgPackTime)
+}
+
+func (MsgPackCodec) ContentType() string { return ContentTypeMsgPack }
+
+func (MsgPackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgPackCodec) Unmarshal(data []byte, v interface{}) error {
+	reader := bytes.NewReader(data)
+	decoder := msgpack.NewDecoder(reader)
+	decoder.SetCustomStructTag("json")
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if reader.Len() != 0 {
+		return fmt.Errorf("msgpack: %d trailing bytes", reader.Len())
+	}
+	return nil
+}
+
+// msgpackTimeExtID is MessagePack's timestamp extension type
+const msgpackTimeExtID = -1
+
+// decodeMsgPackTime decodes the 32, 64 and 96 bit forms of the timestamp
+// extension into a UTC time
+func decodeMsgPackTime(decoder *msgpack.Decoder, v reflect.Value, extLen int) error {
+	data := make([]byte, extLen)
+	if err := decoder.ReadFull(data); err != nil {
+		return err
+	}
+
+	var sec, nsec int64
+	switch extLen {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(data))
+	case 8:
+		packed := binary.BigEndian.Uint64(data)
+		nsec = int64(packed >> 34)
+		sec = int64(packed & 0x3ffffffff)
+	case 12:
+		nsec = int64(binary.BigEndian.Uint32(data))
+		sec = int64(binary.BigEndian.Uint64(data[4:]))
+	default:
+		return fmt.Errorf("msgpack: invalid timestamp length %d", extLen)
+	}
+
+	v.Set(reflect.ValueOf(time.Unix(sec, nsec).UTC()))
+	return nil
+}
@@ -0,0 +1,140 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func representativeOrder() *entities.Order {
+	price := 187.25
+	executedPrice := 187.31
+	executedQuantity := 250.0
+	createdAt := time.Date(2024, 3, 1, 14, 30, 0, 123456789, time.UTC)
+	executedAt := createdAt.Add(1500 * time.Millisecond)
+	expiresAt := createdAt.Add(24 * time.Hour)
+
+	return &entities.Order{
+		ID:               "order-8f14e45f",
+		Symbol:           "AAPL",
+		Side:             entities.OrderSideBuy,
+		Type:             entities.OrderTypeLimit,
+		Quantity:         250,
+		Price:            &price,
+		Status:           entities.OrderStatusExecuted,
+		CreatedAt:        createdAt,
+		UpdatedAt:        executedAt,
+		ExecutedAt:       &executedAt,
+		ExecutedPrice:    &executedPrice,
+		ExecutedQuantity: &executedQuantity,
+		Fees:             1.75,
+		TimeInForce:      entities.TimeInForceGTD,
+		ExpiresAt:        &expiresAt,
+		BrokerOrderID:    "broker-42",
+	}
+}
+
+func TestCodecs_RoundTripOrder(t *testing.T) {
+	codecs := []Codec{JSONCodec{}, MsgPackCodec{}}
+
+	for _, codec := range codecs {
+		t.Run(codec.ContentType(), func(t *testing.T) {
+			original := representativeOrder()
+
+			data, err := codec.Marshal(original)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var decoded entities.Order
+			if err := codec.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(*original, decoded) {
+				t.Errorf("Round trip lost fidelity:\nwant %+v\ngot  %+v", *original, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeMessage_DecodesMsgPackIntoHandlerType(t *testing.T) {
+	original := representativeOrder()
+
+	packed, err := MsgPackCodec{}.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	ctx := ifs.WithMessageDecoder(context.Background(), MsgPackCodec{}.Unmarshal)
+
+	var decoded entities.Order
+	if err := ifs.DecodeMessage(ctx, packed, &decoded); err != nil {
+		t.Fatalf("DecodeMessage failed: %v", err)
+	}
+	if !reflect.DeepEqual(*original, decoded) {
+		t.Errorf("Decoded order differs:\nwant %+v\ngot  %+v", *original, decoded)
+	}
+
+	data, err := ifs.MessageJSON(ctx, packed)
+	if err != nil {
+		t.Fatalf("MessageJSON failed: %v", err)
+	}
+	var transcoded map[string]interface{}
+	if err := json.Unmarshal(data, &transcoded); err != nil {
+		t.Fatalf("Transcoded payload is not valid JSON: %v", err)
+	}
+	if transcoded["symbol"] != string(original.Symbol) {
+		t.Errorf("Expected transcoded symbol %s, got %v", original.Symbol, transcoded["symbol"])
+	}
+
+	if _, err := CodecForContentType("application/xml"); err == nil {
+		t.Error("Expected error for unsupported content type")
+	}
+}
+
+func TestStampContentType_SetsEmbeddedEnvelope(t *testing.T) {
+	message := &OrderMessage{}
+	stampContentType(message, ContentTypeMsgPack)
+
+	if message.ContentType != ContentTypeMsgPack {
+		t.Errorf("Expected envelope content type %s, got %q", ContentTypeMsgPack, message.ContentType)
+	}
+}
+
+func BenchmarkCodecs_Order(b *testing.B) {
+	codecs := []Codec{JSONCodec{}, MsgPackCodec{}}
+	order := representativeOrder()
+
+	for _, codec := range codecs {
+		data, err := codec.Marshal(order)
+		if err != nil {
+			b.Fatalf("Marshal failed: %v", err)
+		}
+
+		b.Run(codec.ContentType()+"/encode", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.Marshal(order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(codec.ContentType()+"/decode", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var decoded entities.Order
+				if err := codec.Unmarshal(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
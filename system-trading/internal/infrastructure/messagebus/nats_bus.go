@@ -2,7 +2,6 @@ package messagebus
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -13,8 +12,9 @@ import (
 
 type NATSBus struct {
 	conn         *nats.Conn
-	subscriptions map[string]*nats.Subscription
+	subscriptions map[string][]*nats.Subscription
 	mu           sync.RWMutex
+	codec        Codec
 	logger       interfaces.Logger
 	metrics      interfaces.MetricsCollector
 }
@@ -25,6 +25,8 @@ type Config struct {
 	ReconnectWait    time.Duration
 	ConnectionTimeout time.Duration
 	DrainTimeout     time.Duration
+	// Codec encodes published messages; nil means JSON
+	Codec            Codec
 }
 
 func NewNATSBus(config Config, logger interfaces.Logger, metrics interfaces.MetricsCollector) (*NATSBus, error) {
@@ -49,9 +51,15 @@ func NewNATSBus(config Config, logger interfaces.Logger, metrics interfaces.Metr
 		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
 	}
 
+	codec := config.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
 	return &NATSBus{
 		conn:          conn,
-		subscriptions: make(map[string]*nats.Subscription),
+		subscriptions: make(map[string][]*nats.Subscription),
+		codec:         codec,
 		logger:        logger,
 		metrics:       metrics,
 	}, nil
@@ -65,7 +73,9 @@ func (nb *NATSBus) Publish(ctx context.Context, topic string, message interface{
 		})
 	}()
 
-	data, err := json.Marshal(message)
+	stampContentType(message, nb.codec.ContentType())
+
+	data, err := nb.codec.Marshal(message)
 	if err != nil {
 		nb.metrics.IncrementCounter("message_bus_publish_errors", map[string]string{
 			"topic": topic,
@@ -74,7 +84,11 @@ func (nb *NATSBus) Publish(ctx context.Context, topic string, message interface{
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	if err := nb.conn.Publish(topic, data); err != nil {
+	msg := nats.NewMsg(topic)
+	msg.Header.Set(ContentTypeHeader, nb.codec.ContentType())
+	msg.Data = data
+
+	if err := nb.conn.PublishMsg(msg); err != nil {
 		nb.metrics.IncrementCounter("message_bus_publish_errors", map[string]string{
 			"topic": topic,
 			"error": "publish_failed",
@@ -98,14 +112,12 @@ func (nb *NATSBus) Publish(ctx context.Context, topic string, message interface{
 	return nil
 }
 
+// Subscribe registers handler for topic. A topic may have several handlers; each
+// receives every message.
 func (nb *NATSBus) Subscribe(ctx context.Context, topic string, handler interfaces.MessageHandler) error {
 	nb.mu.Lock()
 	defer nb.mu.Unlock()
 
-	if _, exists := nb.subscriptions[topic]; exists {
-		return fmt.Errorf("already subscribed to topic: %s", topic)
-	}
-
 	msgHandler := func(msg *nats.Msg) {
 		start := time.Now()
 		defer func() {
@@ -114,7 +126,29 @@ func (nb *NATSBus) Subscribe(ctx context.Context, topic string, handler interfac
 			})
 		}()
 
+		contentType := ""
+		if msg.Header != nil {
+			contentType = msg.Header.Get(ContentTypeHeader)
+		}
+		codec, err := CodecForContentType(contentType)
+		if err != nil {
+			nb.metrics.IncrementCounter("message_bus_handle_errors", map[string]string{
+				"topic": topic,
+			})
+			nb.logger.Error("Failed to decode message",
+				interfaces.Field{Key: "topic", Value: topic},
+				interfaces.Field{Key: "content_type", Value: contentType},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return
+		}
+
+		// Handlers decode the payload straight into their own type with the
+		// publisher's codec
 		ctx := context.Background()
+		if codec.ContentType() != ContentTypeJSON {
+			ctx = interfaces.WithMessageDecoder(ctx, codec.Unmarshal)
+		}
 		if err := handler(ctx, msg.Data); err != nil {
 			nb.metrics.IncrementCounter("message_bus_handle_errors", map[string]string{
 				"topic": topic,
@@ -136,7 +170,7 @@ func (nb *NATSBus) Subscribe(ctx context.Context, topic string, handler interfac
 		return fmt.Errorf("failed to subscribe to topic %s: %w", topic, err)
 	}
 
-	nb.subscriptions[topic] = sub
+	nb.subscriptions[topic] = append(nb.subscriptions[topic], sub)
 
 	nb.logger.Info("Subscribed to topic",
 		interfaces.Field{Key: "topic", Value: topic},
@@ -145,17 +179,20 @@ func (nb *NATSBus) Subscribe(ctx context.Context, topic string, handler interfac
 	return nil
 }
 
+// Unsubscribe removes every handler on topic
 func (nb *NATSBus) Unsubscribe(topic string) error {
 	nb.mu.Lock()
 	defer nb.mu.Unlock()
 
-	sub, exists := nb.subscriptions[topic]
+	subs, exists := nb.subscriptions[topic]
 	if !exists {
 		return fmt.Errorf("not subscribed to topic: %s", topic)
 	}
 
-	if err := sub.Unsubscribe(); err != nil {
-		return fmt.Errorf("failed to unsubscribe from topic %s: %w", topic, err)
+	for _, sub := range subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return fmt.Errorf("failed to unsubscribe from topic %s: %w", topic, err)
+		}
 	}
 
 	delete(nb.subscriptions, topic)
@@ -171,16 +208,18 @@ func (nb *NATSBus) Close() error {
 	nb.mu.Lock()
 	defer nb.mu.Unlock()
 
-	for topic, sub := range nb.subscriptions {
-		if err := sub.Unsubscribe(); err != nil {
-			nb.logger.Warn("Failed to unsubscribe",
-				interfaces.Field{Key: "topic", Value: topic},
-				interfaces.Field{Key: "error", Value: err},
-			)
+	for topic, subs := range nb.subscriptions {
+		for _, sub := range subs {
+			if err := sub.Unsubscribe(); err != nil {
+				nb.logger.Warn("Failed to unsubscribe",
+					interfaces.Field{Key: "topic", Value: topic},
+					interfaces.Field{Key: "error", Value: err},
+				)
+			}
 		}
 	}
 
-	nb.subscriptions = make(map[string]*nats.Subscription)
+	nb.subscriptions = make(map[string][]*nats.Subscription)
 
 	if nb.conn != nil {
 		nb.conn.Close()
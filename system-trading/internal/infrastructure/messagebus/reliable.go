@@ -0,0 +1,213 @@
+package messagebus
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/infrastructure/backoff"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// DeliveryConfig controls the at-least-once-with-dedup subscription decorator
+type DeliveryConfig struct {
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// RetryJitter spreads RetryDelay by ±this fraction; Jitter supplies the
+	// randomness and defaults to a randomly seeded source
+	RetryJitter   float64
+	Jitter        ifs.JitterSource
+	DedupCapacity int
+	// DLQTopic receives messages that fail every attempt. Defaults to "dlq.<topic>".
+	DLQTopic string
+	// MessageID extracts the deduplication key from a payload, decoding it with
+	// the codec carried by ctx. Defaults to the envelope's message_id field; an
+	// empty key disables dedup for that message.
+	MessageID func(ctx context.Context, data []byte) string
+}
+
+// DeadLetter is published to the DLQ topic when a message exhausts its attempts
+type DeadLetter struct {
+	Topic     string    `json:"topic"`
+	MessageID string    `json:"message_id,omitempty"`
+	Payload   []byte    `json:"payload"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// ExactlyOnceish wraps handler with the common reliable-consumer pattern: redeliver on
+// handler error, suppress duplicates by message ID using a bounded store, and
+// dead-letter the message once MaxAttempts is exhausted. Permanent errors, such as
+// an unsupported schema version, are dead-lettered without further attempts.
+func ExactlyOnceish(
+	bus ifs.MessageBus,
+	topic string,
+	handler ifs.MessageHandler,
+	config DeliveryConfig,
+	logger ifs.Logger,
+	metrics ifs.MetricsCollector,
+) ifs.MessageHandler {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.DedupCapacity <= 0 {
+		config.DedupCapacity = 10000
+	}
+	if config.DLQTopic == "" {
+		config.DLQTopic = "dlq." + topic
+	}
+	if config.MessageID == nil {
+		config.MessageID = EnvelopeMessageID
+	}
+
+	seen := newDedupStore(config.DedupCapacity)
+	retryDelay := backoff.Backoff{
+		InitialDelay: config.RetryDelay,
+		Jitter:       config.RetryJitter,
+		Source:       config.Jitter,
+	}
+
+	return func(ctx context.Context, data []byte) error {
+		messageID := config.MessageID(ctx, data)
+		if messageID != "" && !seen.Add(messageID) {
+			metrics.IncrementCounter("message_bus_duplicates_suppressed", map[string]string{
+				"topic": topic,
+			})
+			logger.Debug("Suppressed duplicate message",
+				ifs.Field{Key: "topic", Value: topic},
+				ifs.Field{Key: "message_id", Value: messageID},
+			)
+			return nil
+		}
+
+		var err error
+		attempts := 0
+		for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+			if attempt > 1 && config.RetryDelay > 0 {
+				select {
+				case <-time.After(retryDelay.Delay(1)):
+				case <-ctx.Done():
+					seen.Remove(messageID)
+					return ctx.Err()
+				}
+			}
+
+			attempts = attempt
+			if err = handler(ctx, data); err == nil {
+				return nil
+			}
+			if isPermanent(err) {
+				break
+			}
+
+			metrics.IncrementCounter("message_bus_redeliveries", map[string]string{
+				"topic": topic,
+			})
+			logger.Warn("Message handler failed, redelivering",
+				ifs.Field{Key: "topic", Value: topic},
+				ifs.Field{Key: "message_id", Value: messageID},
+				ifs.Field{Key: "attempt", Value: attempt},
+				ifs.Field{Key: "error", Value: err},
+			)
+		}
+
+		deadLetter := DeadLetter{
+			Topic:     topic,
+			MessageID: messageID,
+			Payload:   data,
+			Error:     err.Error(),
+			Attempts:  attempts,
+			FailedAt:  time.Now(),
+		}
+
+		if publishErr := bus.Publish(ctx, config.DLQTopic, deadLetter); publishErr != nil {
+			// Let the message be processed again rather than silently dropping it
+			seen.Remove(messageID)
+			return fmt.Errorf("failed to dead-letter message after %d attempts: %w", attempts, publishErr)
+		}
+
+		metrics.IncrementCounter("message_bus_dead_lettered", map[string]string{
+			"topic": topic,
+		})
+		logger.Error("Message dead-lettered",
+			ifs.Field{Key: "topic", Value: topic},
+			ifs.Field{Key: "dlq_topic", Value: config.DLQTopic},
+			ifs.Field{Key: "message_id", Value: messageID},
+			ifs.Field{Key: "error", Value: err},
+		)
+
+		return nil
+	}
+}
+
+// SubscribeExactlyOnceish subscribes handler to topic wrapped with ExactlyOnceish
+func SubscribeExactlyOnceish(
+	ctx context.Context,
+	bus ifs.MessageBus,
+	topic string,
+	handler ifs.MessageHandler,
+	config DeliveryConfig,
+	logger ifs.Logger,
+	metrics ifs.MetricsCollector,
+) error {
+	return bus.Subscribe(ctx, topic, ExactlyOnceish(bus, topic, handler, config, logger, metrics))
+}
+
+// EnvelopeMessageID extracts the message_id field from a payload
+func EnvelopeMessageID(ctx context.Context, data []byte) string {
+	var envelope struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := ifs.DecodeMessage(ctx, data, &envelope); err != nil {
+		return ""
+	}
+	return envelope.MessageID
+}
+
+// dedupStore is a bounded set of message IDs that evicts the oldest entry when full
+type dedupStore struct {
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	mu       sync.Mutex
+}
+
+func newDedupStore(capacity int) *dedupStore {
+	return &dedupStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Add records id and reports whether it was not already present
+func (d *dedupStore) Add(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.entries[id]; exists {
+		return false
+	}
+
+	d.entries[id] = d.order.PushBack(id)
+	if d.order.Len() > d.capacity {
+		oldest := d.order.Front()
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(string))
+	}
+
+	return true
+}
+
+func (d *dedupStore) Remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if element, exists := d.entries[id]; exists {
+		d.order.Remove(element)
+		delete(d.entries, id)
+	}
+}
@@ -0,0 +1,126 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/infrastructure/metrics"
+)
+
+func setupReliableTest(t *testing.T, handler func(ctx context.Context, data []byte) error, cfg DeliveryConfig) (*MockMessageBus, func(ctx context.Context, data []byte) error) {
+	t.Helper()
+
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{
+		Level:  "error",
+		Format: "json",
+		Output: "stdout",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test logger: %v", err)
+	}
+	testMetrics := metrics.NewPrometheusMetricsWithRegistry("test-reliable", prometheus.NewRegistry())
+
+	bus := NewMockMessageBus()
+	if err := SubscribeExactlyOnceish(context.Background(), bus, "order.approved", handler, cfg, testLogger, testMetrics); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	return bus, bus.GetHandler("order.approved")
+}
+
+func envelopeBytes(t *testing.T, id string) []byte {
+	t.Helper()
+	data, err := json.Marshal(MessageEnvelope{MessageID: id, Topic: "order.approved", Data: "payload"})
+	if err != nil {
+		t.Fatalf("Failed to marshal envelope: %v", err)
+	}
+	return data
+}
+
+func TestExactlyOnceish_RedeliveryThenSuccess(t *testing.T) {
+	calls := 0
+	bus, handler := setupReliableTest(t, func(ctx context.Context, data []byte) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("transient failure %d", calls)
+		}
+		return nil
+	}, DeliveryConfig{MaxAttempts: 5})
+
+	if err := handler(context.Background(), envelopeBytes(t, "msg-1")); err != nil {
+		t.Fatalf("Expected eventual success, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 handler calls, got %d", calls)
+	}
+	if dlq := bus.GetMessagesByTopic("dlq.order.approved"); len(dlq) != 0 {
+		t.Errorf("Expected no dead letters, got %d", len(dlq))
+	}
+}
+
+func TestExactlyOnceish_DuplicateSuppression(t *testing.T) {
+	calls := 0
+	_, handler := setupReliableTest(t, func(ctx context.Context, data []byte) error {
+		calls++
+		return nil
+	}, DeliveryConfig{MaxAttempts: 3, DedupCapacity: 2})
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "a", "b", "a"} {
+		if err := handler(ctx, envelopeBytes(t, id)); err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected duplicates to be suppressed (2 calls), got %d", calls)
+	}
+
+	// Store is bounded: after two new IDs, "a" is evicted and processed again
+	handler(ctx, envelopeBytes(t, "c"))
+	handler(ctx, envelopeBytes(t, "d"))
+	handler(ctx, envelopeBytes(t, "a"))
+	if calls != 5 {
+		t.Errorf("Expected evicted ID to be processed again (5 calls), got %d", calls)
+	}
+}
+
+func TestExactlyOnceish_DLQAfterExhaustion(t *testing.T) {
+	calls := 0
+	bus, handler := setupReliableTest(t, func(ctx context.Context, data []byte) error {
+		calls++
+		return fmt.Errorf("permanent failure")
+	}, DeliveryConfig{MaxAttempts: 3})
+
+	ctx := context.Background()
+	payload := envelopeBytes(t, "msg-dead")
+	if err := handler(ctx, payload); err != nil {
+		t.Fatalf("Expected dead-lettered message to be acknowledged, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts before DLQ, got %d", calls)
+	}
+
+	dlq := bus.GetMessagesByTopic("dlq.order.approved")
+	if len(dlq) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(dlq))
+	}
+	deadLetter, ok := dlq[0].Message.(DeadLetter)
+	if !ok {
+		t.Fatalf("Expected DeadLetter message, got %T", dlq[0].Message)
+	}
+	if deadLetter.MessageID != "msg-dead" || deadLetter.Attempts != 3 || deadLetter.Error != "permanent failure" {
+		t.Errorf("Unexpected dead letter: %+v", deadLetter)
+	}
+
+	// A redelivery of the dead-lettered message is suppressed
+	handler(ctx, payload)
+	if calls != 3 {
+		t.Errorf("Expected redelivered dead letter to be suppressed, got %d calls", calls)
+	}
+}
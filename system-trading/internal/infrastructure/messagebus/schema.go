@@ -0,0 +1,150 @@
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/system-trading/core/internal/entities"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const (
+	SubjectOrder = "order"
+
+	// OrderSchemaVersion 2 added fees, time in force, GTD expiry and broker order ID
+	OrderSchemaVersion = 2
+)
+
+// ErrUnsupportedSchemaVersion is returned for payloads newer than any registered decoder
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+// SchemaDecoder turns a payload of one schema version into the current message
+// struct, decoding it with the codec carried by ctx
+type SchemaDecoder func(ctx context.Context, data []byte) (interface{}, error)
+
+// SchemaRegistry maps (subject, version) to the decoder that upgrades that version,
+// so consumers handle old payloads without knowing which publisher sent them
+type SchemaRegistry struct {
+	decoders map[string]map[int]SchemaDecoder
+	mu       sync.RWMutex
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		decoders: make(map[string]map[int]SchemaDecoder),
+	}
+}
+
+// Register adds the decoder for one version of subject
+func (r *SchemaRegistry) Register(subject string, version int, decoder SchemaDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.decoders[subject] == nil {
+		r.decoders[subject] = make(map[int]SchemaDecoder)
+	}
+	r.decoders[subject][version] = decoder
+}
+
+// Decode reads the payload's schema_version (absent means 1) and decodes it with
+// the matching decoder
+func (r *SchemaRegistry) Decode(ctx context.Context, subject string, data []byte) (interface{}, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := ifs.DecodeMessage(ctx, data, &header); err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	version := header.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+
+	r.mu.RLock()
+	decoder, exists := r.decoders[subject][version]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, &SchemaVersionError{Subject: subject, Version: version}
+	}
+
+	message, err := decoder(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s v%d: %w", subject, version, err)
+	}
+	return message, nil
+}
+
+// SchemaVersionError reports a payload version with no registered decoder. It is
+// permanent: redelivering the same payload can never succeed.
+type SchemaVersionError struct {
+	Subject string
+	Version int
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("%s: %s v%d", ErrUnsupportedSchemaVersion, e.Subject, e.Version)
+}
+
+func (e *SchemaVersionError) Unwrap() error {
+	return ErrUnsupportedSchemaVersion
+}
+
+func (e *SchemaVersionError) Permanent() bool {
+	return true
+}
+
+// VersionedHandler decodes each payload through registry before calling handler
+// with the current message struct
+func VersionedHandler(registry *SchemaRegistry, subject string, handler func(ctx context.Context, message interface{}) error) ifs.MessageHandler {
+	return func(ctx context.Context, data []byte) error {
+		message, err := registry.Decode(ctx, subject, data)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, message)
+	}
+}
+
+// RegisterOrderSchemas registers the order message decoders. Version 1 predates
+// time in force, so v1 orders are upgraded to GTC, the only behaviour they had.
+func RegisterOrderSchemas(registry *SchemaRegistry) {
+	registry.Register(SubjectOrder, 1, func(ctx context.Context, data []byte) (interface{}, error) {
+		message, err := decodeOrderMessage(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		if message.Data.TimeInForce == "" {
+			message.Data.TimeInForce = entities.TimeInForceGTC
+		}
+		message.SchemaVersion = OrderSchemaVersion
+		return message, nil
+	})
+
+	registry.Register(SubjectOrder, OrderSchemaVersion, func(ctx context.Context, data []byte) (interface{}, error) {
+		return decodeOrderMessage(ctx, data)
+	})
+}
+
+// decodeOrderMessage accepts both an OrderMessage envelope and the bare order
+// the order topics are published with, which has no data field
+func decodeOrderMessage(ctx context.Context, data []byte) (*OrderMessage, error) {
+	var message OrderMessage
+	if err := ifs.DecodeMessage(ctx, data, &message); err != nil {
+		return nil, err
+	}
+	if message.Data.ID == "" {
+		if err := ifs.DecodeMessage(ctx, data, &message.Data); err != nil {
+			return nil, err
+		}
+	}
+	return &message, nil
+}
+
+// isPermanent reports whether err is marked as not worth retrying
+func isPermanent(err error) bool {
+	var permanent interface{ Permanent() bool }
+	return errors.As(err, &permanent) && permanent.Permanent()
+}
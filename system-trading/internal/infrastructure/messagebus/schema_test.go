@@ -0,0 +1,119 @@
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+const v1OrderPayload = `{
+	"message_id": "msg-v1",
+	"topic": "order.approved",
+	"timestamp": "2024-03-01T14:30:00Z",
+	"source": "legacy-strategy",
+	"data": {
+		"id": "order-1",
+		"symbol": "AAPL",
+		"side": "BUY",
+		"type": "LIMIT",
+		"quantity": 10,
+		"price": 187.5,
+		"status": "APPROVED",
+		"created_at": "2024-03-01T14:29:59Z",
+		"updated_at": "2024-03-01T14:30:00Z"
+	}
+}`
+
+const futureOrderPayload = `{
+	"message_id": "msg-future",
+	"topic": "order.approved",
+	"schema_version": 99,
+	"data": {"id": "order-2", "symbol": "AAPL", "qty_lots": 3}
+}`
+
+func TestSchemaRegistry_DecodesV1OrderIntoCurrentStruct(t *testing.T) {
+	registry := NewSchemaRegistry()
+	RegisterOrderSchemas(registry)
+
+	decoded, err := registry.Decode(context.Background(), SubjectOrder, []byte(v1OrderPayload))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	message, ok := decoded.(*OrderMessage)
+	if !ok {
+		t.Fatalf("Expected *OrderMessage, got %T", decoded)
+	}
+	if message.SchemaVersion != OrderSchemaVersion {
+		t.Errorf("Expected upgraded schema version %d, got %d", OrderSchemaVersion, message.SchemaVersion)
+	}
+
+	order := message.Data
+	if order.ID != "order-1" || order.Symbol != "AAPL" || order.Quantity != 10 {
+		t.Errorf("Unexpected order fields: %+v", order)
+	}
+	if order.Price == nil || *order.Price != 187.5 {
+		t.Errorf("Expected price 187.5, got %v", order.Price)
+	}
+	if order.TimeInForce != entities.TimeInForceGTC {
+		t.Errorf("Expected v1 order to default to GTC, got %q", order.TimeInForce)
+	}
+}
+
+func TestSchemaRegistry_DecodesBareOrder(t *testing.T) {
+	registry := NewSchemaRegistry()
+	RegisterOrderSchemas(registry)
+
+	payload := `{"id": "order-3", "symbol": "MSFT", "side": "SELL", "type": "MARKET", "quantity": 5}`
+	decoded, err := registry.Decode(context.Background(), SubjectOrder, []byte(payload))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	order := decoded.(*OrderMessage).Data
+	if order.ID != "order-3" || order.Symbol != "MSFT" || order.Quantity != 5 {
+		t.Errorf("Unexpected order fields: %+v", order)
+	}
+	if order.TimeInForce != entities.TimeInForceGTC {
+		t.Errorf("Expected unversioned order to default to GTC, got %q", order.TimeInForce)
+	}
+}
+
+func TestSchemaRegistry_FutureVersionRoutedToDLQ(t *testing.T) {
+	registry := NewSchemaRegistry()
+	RegisterOrderSchemas(registry)
+
+	calls := 0
+	handler := VersionedHandler(registry, SubjectOrder, func(ctx context.Context, message interface{}) error {
+		calls++
+		return nil
+	})
+
+	bus, reliable := setupReliableTest(t, handler, DeliveryConfig{MaxAttempts: 3})
+
+	if err := reliable(context.Background(), []byte(futureOrderPayload)); err != nil {
+		t.Fatalf("Expected dead-lettered message to be acknowledged, got: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected handler not to see an unsupported payload, got %d calls", calls)
+	}
+
+	dlq := bus.GetMessagesByTopic("dlq.order.approved")
+	if len(dlq) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(dlq))
+	}
+	deadLetter := dlq[0].Message.(DeadLetter)
+	if deadLetter.Attempts != 1 {
+		t.Errorf("Expected unsupported version to skip retries, got %d attempts", deadLetter.Attempts)
+	}
+	if deadLetter.Error != "unsupported schema version: order v99" {
+		t.Errorf("Unexpected dead letter error: %q", deadLetter.Error)
+	}
+
+	_, err := registry.Decode(context.Background(), SubjectOrder, []byte(futureOrderPayload))
+	if !errors.Is(err, ErrUnsupportedSchemaVersion) {
+		t.Errorf("Expected ErrUnsupportedSchemaVersion, got %v", err)
+	}
+}
@@ -16,7 +16,10 @@ const (
 	TopicOrderProposed   = "order.proposed"
 	TopicOrderApproved   = "order.approved"
 	TopicOrderExecuted   = "order.executed"
+	TopicOrderPartiallyFilled = "order.partially_filled"
 	TopicOrderRejected   = "order.rejected"
+	TopicOrderExpired    = "order.expired"
+	TopicSettlementComplete = "settlement.complete"
 	TopicRiskAlert       = "risk.alert"
 	TopicPortfolioUpdate = "portfolio.update"
 	TopicSystemHealth    = "system.health"
@@ -29,6 +32,14 @@ type MessageEnvelope struct {
 	Source      string      `json:"source"`
 	Data        interface{} `json:"data"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// SchemaVersion of the payload; absent means version 1
+	SchemaVersion int     `json:"schema_version,omitempty"`
+	// ContentType records the codec the bus published the envelope with
+	ContentType string      `json:"content_type,omitempty"`
+}
+
+func (e *MessageEnvelope) setContentType(contentType string) {
+	e.ContentType = contentType
 }
 
 type MarketDataMessage struct {
@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+type dynamicKind string
+
+const (
+	dynamicCounter   dynamicKind = "counter"
+	dynamicGauge     dynamicKind = "gauge"
+	dynamicHistogram dynamicKind = "histogram"
+)
+
+// dynamicMetric is a metric registered the first time an unknown name was
+// recorded. Its kind and label names are fixed by that first call.
+type dynamicMetric struct {
+	kind       dynamicKind
+	labelNames []string
+	with       func(prometheus.Labels) (interface{}, error)
+	// warned is set once a call that cannot be recorded has been logged, so
+	// a misused name does not flood the log
+	warned bool
+}
+
+// dynamicMetric returns the counter, gauge or observer for name and labels,
+// registering a vector for name on first use. It returns nil, after logging
+// once, when name was first used as another kind or with other label names.
+func (m *PrometheusMetrics) dynamicMetric(kind dynamicKind, name string, labels map[string]string) interface{} {
+	m.dynamicMu.Lock()
+	defer m.dynamicMu.Unlock()
+
+	metric, ok := m.dynamic[name]
+	if !ok {
+		var err error
+		metric, err = m.registerDynamic(kind, name, labelNames(labels))
+		if err != nil {
+			m.dynamic[name] = &dynamicMetric{kind: kind, warned: true}
+			m.warn("Failed to register metric for unknown name",
+				ifs.Field{Key: "name", Value: name},
+				ifs.Field{Key: "kind", Value: string(kind)},
+				ifs.Field{Key: "error", Value: err.Error()},
+			)
+			return nil
+		}
+		m.dynamic[name] = metric
+		m.warn("Registered metric for unknown name",
+			ifs.Field{Key: "name", Value: name},
+			ifs.Field{Key: "kind", Value: string(kind)},
+			ifs.Field{Key: "labels", Value: metric.labelNames},
+		)
+	}
+	if metric.with == nil {
+		return nil
+	}
+
+	if metric.kind != kind {
+		m.warnOnce(metric, fmt.Sprintf("metric was registered as a %s, not a %s", metric.kind, kind), name)
+		return nil
+	}
+	recorder, err := metric.with(m.limitLabels(name, labels))
+	if err != nil {
+		m.warnOnce(metric, err.Error(), name)
+		return nil
+	}
+	return recorder
+}
+
+func (m *PrometheusMetrics) warnOnce(metric *dynamicMetric, reason, name string) {
+	if metric.warned {
+		return
+	}
+	metric.warned = true
+	m.warn("Dropped metric for unknown name",
+		ifs.Field{Key: "name", Value: name},
+		ifs.Field{Key: "reason", Value: reason},
+	)
+}
+
+// registerDynamic registers a vector of kind under the sanitized form of name,
+// reusing one already registered under the same name and labels
+func (m *PrometheusMetrics) registerDynamic(kind dynamicKind, name string, labelNames []string) (*dynamicMetric, error) {
+	promName := sanitizeMetricName(name)
+	help := fmt.Sprintf("Recorded as %q without a predefined metric", name)
+	metric := &dynamicMetric{kind: kind, labelNames: labelNames}
+
+	var collector prometheus.Collector
+	switch kind {
+	case dynamicCounter:
+		if !strings.HasSuffix(promName, "_total") {
+			promName += "_total"
+		}
+		collector = prometheus.NewCounterVec(prometheus.CounterOpts{Name: promName, Help: help, ConstLabels: m.constLabels}, labelNames)
+	case dynamicGauge:
+		collector = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: promName, Help: help, ConstLabels: m.constLabels}, labelNames)
+	case dynamicHistogram:
+		collector = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: promName, Help: help, ConstLabels: m.constLabels}, labelNames)
+	}
+
+	if err := m.registerer.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &registered) {
+			return nil, err
+		}
+		collector = registered.ExistingCollector
+	}
+
+	switch vec := collector.(type) {
+	case *prometheus.CounterVec:
+		metric.with = func(l prometheus.Labels) (interface{}, error) { return vec.GetMetricWith(l) }
+	case *prometheus.GaugeVec:
+		metric.with = func(l prometheus.Labels) (interface{}, error) { return vec.GetMetricWith(l) }
+	case *prometheus.HistogramVec:
+		metric.with = func(l prometheus.Labels) (interface{}, error) { return vec.GetMetricWith(l) }
+	default:
+		return nil, fmt.Errorf("%s is already registered as %T", promName, collector)
+	}
+	return metric, nil
+}
+
+// sanitizeMetricName maps name onto Prometheus's metric name alphabet,
+// replacing any other character with an underscore
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func labelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
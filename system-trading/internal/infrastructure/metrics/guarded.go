@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const (
+	DefaultMaxLabelValues         = 1000
+	DefaultMaxConsecutiveFailures = 5
+	DefaultFailureCooldown        = time.Minute
+)
+
+type GuardedConfig struct {
+	// MaxLabelValues caps the distinct values each label of each metric may
+	// take; calls introducing a value past the cap are dropped
+	MaxLabelValues int
+	// MaxConsecutiveFailures is how many panics in a row disable recording
+	MaxConsecutiveFailures int
+	// FailureCooldown is how long recording stays disabled before it is retried
+	FailureCooldown time.Duration
+}
+
+// GuardedStats summarises what a GuardedMetrics has protected against
+type GuardedStats struct {
+	DroppedByCardinality int64
+	Panics               int64
+	Degraded             bool
+}
+
+type labelKey struct {
+	metric string
+	label  string
+}
+
+// GuardedMetrics wraps a MetricsCollector so that a failing backend or a label
+// cardinality explosion never crashes or stalls the caller: panics are
+// recovered, new label values past a cap are dropped, and repeated failures
+// turn recording into a no-op for a cooldown.
+type GuardedMetrics struct {
+	next   ifs.MetricsCollector
+	logger ifs.Logger
+	config GuardedConfig
+	now    func() time.Time
+
+	mu            sync.Mutex
+	labelValues   map[labelKey]map[string]struct{}
+	capWarned     map[labelKey]bool
+	failures      int
+	disabledUntil time.Time
+	stats         GuardedStats
+}
+
+func NewGuardedMetrics(next ifs.MetricsCollector, logger ifs.Logger, config GuardedConfig) *GuardedMetrics {
+	if config.MaxLabelValues <= 0 {
+		config.MaxLabelValues = DefaultMaxLabelValues
+	}
+	if config.MaxConsecutiveFailures <= 0 {
+		config.MaxConsecutiveFailures = DefaultMaxConsecutiveFailures
+	}
+	if config.FailureCooldown <= 0 {
+		config.FailureCooldown = DefaultFailureCooldown
+	}
+
+	return &GuardedMetrics{
+		next:        next,
+		logger:      logger,
+		config:      config,
+		now:         time.Now,
+		labelValues: make(map[labelKey]map[string]struct{}),
+		capWarned:   make(map[labelKey]bool),
+	}
+}
+
+func (g *GuardedMetrics) IncrementCounter(name string, labels map[string]string) {
+	g.record(name, labels, func() { g.next.IncrementCounter(name, labels) })
+}
+
+func (g *GuardedMetrics) RecordDuration(name string, duration float64, labels map[string]string) {
+	g.record(name, labels, func() { g.next.RecordDuration(name, duration, labels) })
+}
+
+func (g *GuardedMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	g.record(name, labels, func() { g.next.SetGauge(name, value, labels) })
+}
+
+func (g *GuardedMetrics) ObserveValue(name string, value float64, labels map[string]string) {
+	g.record(name, labels, func() { g.next.ObserveValue(name, value, labels) })
+}
+
+// Stats returns a snapshot of the guard's counters
+func (g *GuardedMetrics) Stats() GuardedStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := g.stats
+	stats.Degraded = g.now().Before(g.disabledUntil)
+	return stats
+}
+
+func (g *GuardedMetrics) record(name string, labels map[string]string, call func()) {
+	if !g.admit(name, labels) {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			g.recordFailure(name, r)
+		}
+	}()
+	call()
+
+	g.mu.Lock()
+	g.failures = 0
+	g.mu.Unlock()
+}
+
+// admit reports whether a call may reach the backend: recording is not
+// degraded and every label value is either known or fits under the cap
+func (g *GuardedMetrics) admit(name string, labels map[string]string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.now().Before(g.disabledUntil) {
+		return false
+	}
+
+	var added []labelKey
+	for label, value := range labels {
+		key := labelKey{metric: name, label: label}
+		values := g.labelValues[key]
+		if _, known := values[value]; known {
+			continue
+		}
+		if len(values) >= g.config.MaxLabelValues {
+			g.stats.DroppedByCardinality++
+			g.warnCapLocked(key)
+			// Keep the values of this call's other labels from counting toward their caps
+			for _, k := range added {
+				delete(g.labelValues[k], labels[k.label])
+			}
+			return false
+		}
+		if values == nil {
+			values = make(map[string]struct{})
+			g.labelValues[key] = values
+		}
+		values[value] = struct{}{}
+		added = append(added, key)
+	}
+	return true
+}
+
+func (g *GuardedMetrics) warnCapLocked(key labelKey) {
+	if g.capWarned[key] || g.logger == nil {
+		return
+	}
+	g.capWarned[key] = true
+	g.logger.Warn("Metric label cardinality cap reached; dropping new values",
+		ifs.Field{Key: "metric", Value: key.metric},
+		ifs.Field{Key: "label", Value: key.label},
+		ifs.Field{Key: "max_values", Value: g.config.MaxLabelValues},
+	)
+}
+
+func (g *GuardedMetrics) recordFailure(name string, cause interface{}) {
+	g.mu.Lock()
+	g.stats.Panics++
+	g.failures++
+	degrade := g.failures >= g.config.MaxConsecutiveFailures
+	if degrade {
+		g.failures = 0
+		g.disabledUntil = g.now().Add(g.config.FailureCooldown)
+	}
+	g.mu.Unlock()
+
+	if g.logger == nil {
+		return
+	}
+	g.logger.Error("Recovered from metrics backend panic",
+		ifs.Field{Key: "metric", Value: name},
+		ifs.Field{Key: "error", Value: cause},
+	)
+	if degrade {
+		g.logger.Warn("Metrics disabled after repeated failures",
+			ifs.Field{Key: "cooldown", Value: g.config.FailureCooldown},
+		)
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countingCollector records the label values it receives and can be made to panic
+type countingCollector struct {
+	calls  int
+	values map[string]struct{}
+	panic  bool
+}
+
+func (c *countingCollector) record(labels map[string]string) {
+	c.calls++
+	if c.panic {
+		panic("backend unavailable")
+	}
+	if c.values == nil {
+		c.values = make(map[string]struct{})
+	}
+	c.values[labels["order_id"]] = struct{}{}
+}
+
+func (c *countingCollector) IncrementCounter(name string, labels map[string]string) {
+	c.record(labels)
+}
+
+func (c *countingCollector) RecordDuration(name string, duration float64, labels map[string]string) {
+	c.record(labels)
+}
+
+func (c *countingCollector) SetGauge(name string, value float64, labels map[string]string) {
+	c.record(labels)
+}
+
+func (c *countingCollector) ObserveValue(name string, value float64, labels map[string]string) {
+	c.record(labels)
+}
+
+func TestGuardedMetrics_CapsLabelCardinality(t *testing.T) {
+	backend := &countingCollector{}
+	guarded := NewGuardedMetrics(backend, nil, GuardedConfig{MaxLabelValues: 50})
+
+	const calls = 100000
+	started := time.Now()
+	for i := 0; i < calls; i++ {
+		guarded.IncrementCounter("orders_total", map[string]string{
+			"order_id": fmt.Sprintf("order-%d", i),
+			"symbol":   "AAPL",
+		})
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Expected capped recording to stay fast, %d calls took %s", calls, elapsed)
+	}
+
+	if len(backend.values) != 50 {
+		t.Errorf("Expected 50 distinct label values to reach the backend, got %d", len(backend.values))
+	}
+	if stats := guarded.Stats(); stats.DroppedByCardinality != calls-50 {
+		t.Errorf("Expected %d calls dropped by the cap, got %d", calls-50, stats.DroppedByCardinality)
+	}
+
+	// Values admitted before the cap keep recording
+	before := backend.calls
+	guarded.IncrementCounter("orders_total", map[string]string{"order_id": "order-0", "symbol": "AAPL"})
+	if backend.calls != before+1 {
+		t.Error("Expected a known label value to still be recorded past the cap")
+	}
+}
+
+func TestGuardedMetrics_DegradesAfterRepeatedPanics(t *testing.T) {
+	backend := &countingCollector{panic: true}
+	guarded := NewGuardedMetrics(backend, nil, GuardedConfig{
+		MaxConsecutiveFailures: 3,
+		FailureCooldown:        time.Minute,
+	})
+	now := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	guarded.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		guarded.SetGauge("portfolio_value", 1, map[string]string{"order_id": "a"})
+	}
+	if backend.calls != 3 {
+		t.Errorf("Expected the backend to be skipped after 3 panics, got %d calls", backend.calls)
+	}
+	if stats := guarded.Stats(); !stats.Degraded || stats.Panics != 3 {
+		t.Errorf("Expected a degraded guard with 3 panics, got %+v", stats)
+	}
+
+	now = now.Add(time.Minute)
+	backend.panic = false
+	guarded.SetGauge("portfolio_value", 1, map[string]string{"order_id": "a"})
+	if backend.calls != 4 || guarded.Stats().Degraded {
+		t.Errorf("Expected recording to resume after the cooldown, got %d calls", backend.calls)
+	}
+}
+
+func TestGuardedMetrics_RecoversPrometheusLabelPanic(t *testing.T) {
+	guarded := NewGuardedMetrics(newTestMetrics(), nil, GuardedConfig{})
+
+	// orders_total has no "bogus" label, so the Prometheus vector panics
+	guarded.IncrementCounter("orders_total", map[string]string{"bogus": "x"})
+
+	if stats := guarded.Stats(); stats.Panics != 1 {
+		t.Errorf("Expected the panic to be recovered and counted, got %+v", stats)
+	}
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"time"
+
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// Order stages passed to RecordOrderStage
+const (
+	OrderStageProposed  = "proposed"
+	OrderStageApproved  = "approved"
+	OrderStageExecuted  = "executed"
+	OrderStageRejected  = "rejected"
+	OrderStageCancelled = "cancelled"
+	OrderStageFailed    = "failed"
+	OrderStageExpired   = "expired"
+)
+
+// maxTrackedOrders bounds the orders awaiting execution; orders proposed past
+// it are not timed
+const maxTrackedOrders = 10000
+
+// maxTrackedOrderAge evicts orders whose terminal event never arrived, so lost
+// events cannot fill the tracked set and stop new orders being timed
+const maxTrackedOrderAge = 24 * time.Hour
+
+type orderTimestamps struct {
+	tracked  time.Time
+	proposed time.Time
+	approved time.Time
+}
+
+// SetClock replaces the clock RecordOrderStage timestamps stages with
+func (m *PrometheusMetrics) SetClock(clk ifs.Clock) {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	m.clock = clk
+}
+
+// RecordOrderStage notes that orderID reached stage now. Approval observes
+// proposed_to_approved, and execution observes approved_to_executed and total
+// in order_lifecycle_duration_seconds. Execution, rejection, cancellation,
+// failure and expiry end the order's tracking.
+func (m *PrometheusMetrics) RecordOrderStage(orderID, stage string) {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	now := m.clock.Now()
+	if now.Sub(m.lastStageSweep) >= time.Minute {
+		m.evictStaleOrdersLocked(now)
+	}
+	stamps := m.orderStages[orderID]
+
+	switch stage {
+	case OrderStageProposed, OrderStageApproved:
+		if stamps == nil {
+			if len(m.orderStages) >= maxTrackedOrders {
+				m.evictStaleOrdersLocked(now)
+			}
+			if len(m.orderStages) >= maxTrackedOrders {
+				return
+			}
+			stamps = &orderTimestamps{tracked: now}
+			m.orderStages[orderID] = stamps
+		}
+		if stage == OrderStageProposed {
+			stamps.proposed = now
+			return
+		}
+		stamps.approved = now
+		if !stamps.proposed.IsZero() {
+			m.observeStage("proposed_to_approved", now.Sub(stamps.proposed))
+		}
+	case OrderStageExecuted:
+		if stamps == nil {
+			return
+		}
+		if !stamps.approved.IsZero() {
+			m.observeStage("approved_to_executed", now.Sub(stamps.approved))
+		}
+		if !stamps.proposed.IsZero() {
+			m.observeStage("total", now.Sub(stamps.proposed))
+		}
+		delete(m.orderStages, orderID)
+	case OrderStageRejected, OrderStageCancelled, OrderStageFailed, OrderStageExpired:
+		delete(m.orderStages, orderID)
+	}
+}
+
+// evictStaleOrdersLocked drops orders tracked for longer than maxTrackedOrderAge
+func (m *PrometheusMetrics) evictStaleOrdersLocked(now time.Time) {
+	m.lastStageSweep = now
+	for orderID, stamps := range m.orderStages {
+		if now.Sub(stamps.tracked) > maxTrackedOrderAge {
+			delete(m.orderStages, orderID)
+		}
+	}
+}
+
+func (m *PrometheusMetrics) observeStage(stage string, elapsed time.Duration) {
+	m.orderLifecycle.WithLabelValues(stage).Observe(elapsed.Seconds())
+}
@@ -1,10 +1,18 @@
 package metrics
 
 import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
 )
 
 type PrometheusMetrics struct {
@@ -21,6 +29,9 @@ type PrometheusMetrics struct {
 	ordersFilled          *prometheus.CounterVec
 	ordersRejected        *prometheus.CounterVec
 	orderFillDuration     *prometheus.HistogramVec
+	orderLifecycle        *prometheus.HistogramVec
+	executionRetries      *prometheus.HistogramVec
+	executionErrors       *prometheus.CounterVec
 	tradingVolume         *prometheus.CounterVec
 	portfolioValue        *prometheus.GaugeVec
 	positionCount         *prometheus.GaugeVec
@@ -36,19 +47,58 @@ type PrometheusMetrics struct {
 	connectionStatus      *prometheus.GaugeVec
 	errorRate             *prometheus.CounterVec
 	responseTime          *prometheus.HistogramVec
+	startupStepDuration   *prometheus.HistogramVec
+	startupDuration       *prometheus.GaugeVec
+	invalidMetricValues   *prometheus.CounterVec
+	labelOverflow         *prometheus.CounterVec
 
 	// Market Data Metrics
 	marketDataLatency     *prometheus.HistogramVec
 	priceUpdates          *prometheus.CounterVec
 	newsArticlesProcessed *prometheus.CounterVec
+	newsDuplicatesSkipped *prometheus.CounterVec
+	priceResubscribes     *prometheus.CounterVec
+	marketDataRejected    *prometheus.CounterVec
+
+	logger ifs.Logger
+
+	registerer prometheus.Registerer
+
+	// Metrics recorded under names with no field above are registered on
+	// first use
+	constLabels prometheus.Labels
+	dynamicMu   sync.Mutex
+	dynamic     map[string]*dynamicMetric
+
+	maxLabelValues int
+	labelMu        sync.Mutex
+	labelValues    map[labelKey]map[string]struct{}
+
+	clock          ifs.Clock
+	lifecycleMu    sync.Mutex
+	orderStages    map[string]*orderTimestamps
+	lastStageSweep time.Time
 }
 
+// overflowLabelValue stands in for label values past the distinct value cap
+const overflowLabelValue = "__overflow__"
+
+// NewPrometheusMetrics registers the service's metrics with the global
+// registry
 func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
+	return NewPrometheusMetricsWithRegistry(serviceName, prometheus.DefaultRegisterer)
+}
+
+// NewPrometheusMetricsWithRegistry registers the service's metrics with reg,
+// so tests can use a fresh prometheus.NewRegistry() and read it back with
+// Gather
+func NewPrometheusMetricsWithRegistry(serviceName string, reg prometheus.Registerer) *PrometheusMetrics {
 	labels := prometheus.Labels{"service": serviceName}
+	factory := promauto.With(reg)
 
 	return &PrometheusMetrics{
 		// Message Bus Metrics
-		messagesPublished: promauto.NewCounterVec(
+		messagesPublished: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "messages_published_total",
 				Help:        "Total number of messages published to message bus",
@@ -56,7 +106,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"topic"},
 		),
-		messagesHandled: promauto.NewCounterVec(
+		messagesHandled: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "messages_handled_total",
 				Help:        "Total number of messages handled from message bus",
@@ -64,7 +114,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"topic"},
 		),
-		messagePublishErrors: promauto.NewCounterVec(
+		messagePublishErrors: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "message_publish_errors_total",
 				Help:        "Total number of message publish errors",
@@ -72,7 +122,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"topic", "error"},
 		),
-		messageHandleErrors: promauto.NewCounterVec(
+		messageHandleErrors: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "message_handle_errors_total",
 				Help:        "Total number of message handle errors",
@@ -80,7 +130,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"topic"},
 		),
-		publishDuration: promauto.NewHistogramVec(
+		publishDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:        "message_publish_duration_seconds",
 				Help:        "Time taken to publish messages",
@@ -89,7 +139,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"topic"},
 		),
-		handleDuration: promauto.NewHistogramVec(
+		handleDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:        "message_handle_duration_seconds",
 				Help:        "Time taken to handle messages",
@@ -100,7 +150,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 		),
 
 		// Trading Metrics
-		ordersTotal: promauto.NewCounterVec(
+		ordersTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "orders_total",
 				Help:        "Total number of orders created",
@@ -108,7 +158,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "side", "type", "status"},
 		),
-		ordersFilled: promauto.NewCounterVec(
+		ordersFilled: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "orders_filled_total",
 				Help:        "Total number of orders filled",
@@ -116,7 +166,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "side"},
 		),
-		ordersRejected: promauto.NewCounterVec(
+		ordersRejected: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "orders_rejected_total",
 				Help:        "Total number of orders rejected",
@@ -124,7 +174,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "reason"},
 		),
-		orderFillDuration: promauto.NewHistogramVec(
+		orderFillDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:        "order_fill_duration_seconds",
 				Help:        "Time taken to fill orders",
@@ -133,7 +183,16 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol"},
 		),
-		tradingVolume: promauto.NewCounterVec(
+		orderLifecycle: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "order_lifecycle_duration_seconds",
+				Help:        "Time between order stages, from proposal to execution",
+				ConstLabels: labels,
+				Buckets:     []float64{0.01, 0.05, 0.1, 0.5, 1.0, 5.0, 10.0, 30.0, 60.0, 300.0},
+			},
+			[]string{"stage"},
+		),
+		tradingVolume: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "trading_volume_total",
 				Help:        "Total trading volume",
@@ -141,7 +200,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "side"},
 		),
-		portfolioValue: promauto.NewGaugeVec(
+		portfolioValue: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "portfolio_value",
 				Help:        "Current portfolio value",
@@ -149,7 +208,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"portfolio_id"},
 		),
-		positionCount: promauto.NewGaugeVec(
+		positionCount: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "position_count",
 				Help:        "Number of open positions",
@@ -159,7 +218,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 		),
 
 		// Risk Metrics
-		riskAlerts: promauto.NewCounterVec(
+		riskAlerts: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "risk_alerts_total",
 				Help:        "Total number of risk alerts triggered",
@@ -167,7 +226,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"alert_type", "severity"},
 		),
-		portfolioRisk: promauto.NewGaugeVec(
+		portfolioRisk: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "portfolio_risk",
 				Help:        "Current portfolio risk metrics",
@@ -175,7 +234,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"portfolio_id", "metric"},
 		),
-		positionRisk: promauto.NewGaugeVec(
+		positionRisk: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "position_risk",
 				Help:        "Current position risk metrics",
@@ -183,7 +242,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "metric"},
 		),
-		varValue: promauto.NewGaugeVec(
+		varValue: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "var_value",
 				Help:        "Value at Risk",
@@ -193,7 +252,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 		),
 
 		// System Metrics
-		agentHealth: promauto.NewGaugeVec(
+		agentHealth: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "agent_health",
 				Help:        "Agent health status (1=healthy, 0=unhealthy)",
@@ -201,7 +260,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"agent_name"},
 		),
-		connectionStatus: promauto.NewGaugeVec(
+		connectionStatus: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name:        "connection_status",
 				Help:        "Connection status (1=connected, 0=disconnected)",
@@ -209,7 +268,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"connection_type", "target"},
 		),
-		errorRate: promauto.NewCounterVec(
+		errorRate: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "errors_total",
 				Help:        "Total number of errors",
@@ -217,7 +276,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"component", "error_type"},
 		),
-		responseTime: promauto.NewHistogramVec(
+		responseTime: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:        "response_time_seconds",
 				Help:        "Response time for operations",
@@ -226,9 +285,59 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"operation"},
 		),
+		executionRetries: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "execution_agent_order_retries",
+				Help:        "Retries used per successful order submission",
+				ConstLabels: labels,
+				Buckets:     []float64{0, 1, 2, 3, 5, 10},
+			},
+			[]string{"broker"},
+		),
+		executionErrors: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "execution_agent_errors_total",
+				Help:        "Execution agent errors by type and broker error code",
+				ConstLabels: labels,
+			},
+			[]string{"type", "broker_code"},
+		),
+		startupStepDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "startup_step_duration_seconds",
+				Help:        "Duration of each application startup step",
+				ConstLabels: labels,
+				Buckets:     []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"step", "status"},
+		),
+		startupDuration: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "startup_duration_seconds",
+				Help:        "Total time taken by the last application startup",
+				ConstLabels: labels,
+			},
+			[]string{"status"},
+		),
+		invalidMetricValues: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "invalid_metric_value_total",
+				Help:        "NaN or infinite values rejected instead of being recorded",
+				ConstLabels: labels,
+			},
+			[]string{"name"},
+		),
+		labelOverflow: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "metric_label_overflow_total",
+				Help:        "Label values collapsed to __overflow__ past the distinct value cap",
+				ConstLabels: labels,
+			},
+			[]string{"metric", "label"},
+		),
 
 		// Market Data Metrics
-		marketDataLatency: promauto.NewHistogramVec(
+		marketDataLatency: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:        "market_data_latency_seconds",
 				Help:        "Market data latency",
@@ -237,7 +346,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol", "data_type"},
 		),
-		priceUpdates: promauto.NewCounterVec(
+		priceUpdates: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "price_updates_total",
 				Help:        "Total number of price updates received",
@@ -245,7 +354,7 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"symbol"},
 		),
-		newsArticlesProcessed: promauto.NewCounterVec(
+		newsArticlesProcessed: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "news_articles_processed_total",
 				Help:        "Total number of news articles processed",
@@ -253,69 +362,242 @@ func NewPrometheusMetrics(serviceName string) *PrometheusMetrics {
 			},
 			[]string{"source"},
 		),
+		newsDuplicatesSkipped: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "news_duplicates_skipped_total",
+				Help:        "Total number of duplicate news articles dropped before publishing",
+				ConstLabels: labels,
+			},
+			[]string{"source"},
+		),
+		priceResubscribes: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "price_subscription_resubscribes_total",
+				Help:        "Total number of attempts to resubscribe a stalled price feed",
+				ConstLabels: labels,
+			},
+			[]string{"symbol", "status"},
+		),
+		marketDataRejected: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "market_data_rejected_total",
+				Help:        "Total number of market data updates dropped by validation",
+				ConstLabels: labels,
+			},
+			[]string{"reason"},
+		),
+		registerer:  reg,
+		constLabels: labels,
+		dynamic:     make(map[string]*dynamicMetric),
+
+		maxLabelValues: DefaultMaxLabelValues,
+		labelValues:    make(map[labelKey]map[string]struct{}),
+
+		clock:       clock.NewRealClock(),
+		orderStages: make(map[string]*orderTimestamps),
 	}
 }
 
 func (m *PrometheusMetrics) IncrementCounter(name string, labels map[string]string) {
 	switch name {
 	case "message_bus_published":
-		m.messagesPublished.With(prometheus.Labels(labels)).Inc()
+		m.messagesPublished.With(m.limitLabels(name, labels)).Inc()
 	case "message_bus_handled":
-		m.messagesHandled.With(prometheus.Labels(labels)).Inc()
+		m.messagesHandled.With(m.limitLabels(name, labels)).Inc()
 	case "message_bus_publish_errors":
-		m.messagePublishErrors.With(prometheus.Labels(labels)).Inc()
+		m.messagePublishErrors.With(m.limitLabels(name, labels)).Inc()
 	case "message_bus_handle_errors":
-		m.messageHandleErrors.With(prometheus.Labels(labels)).Inc()
+		m.messageHandleErrors.With(m.limitLabels(name, labels)).Inc()
 	case "orders_total":
-		m.ordersTotal.With(prometheus.Labels(labels)).Inc()
+		m.ordersTotal.With(m.limitLabels(name, labels)).Inc()
 	case "orders_filled":
-		m.ordersFilled.With(prometheus.Labels(labels)).Inc()
+		m.ordersFilled.With(m.limitLabels(name, labels)).Inc()
 	case "orders_rejected":
-		m.ordersRejected.With(prometheus.Labels(labels)).Inc()
+		m.ordersRejected.With(m.limitLabels(name, labels)).Inc()
 	case "trading_volume":
-		m.tradingVolume.With(prometheus.Labels(labels)).Inc()
+		m.tradingVolume.With(m.limitLabels(name, labels)).Inc()
 	case "risk_alerts":
-		m.riskAlerts.With(prometheus.Labels(labels)).Inc()
+		m.riskAlerts.With(m.limitLabels(name, labels)).Inc()
 	case "errors_total":
-		m.errorRate.With(prometheus.Labels(labels)).Inc()
+		m.errorRate.With(m.limitLabels(name, labels)).Inc()
 	case "price_updates":
-		m.priceUpdates.With(prometheus.Labels(labels)).Inc()
+		m.priceUpdates.With(m.limitLabels(name, labels)).Inc()
 	case "news_articles_processed":
-		m.newsArticlesProcessed.With(prometheus.Labels(labels)).Inc()
+		m.newsArticlesProcessed.With(m.limitLabels(name, labels)).Inc()
+	case "news_duplicates_skipped":
+		m.newsDuplicatesSkipped.With(m.limitLabels(name, labels)).Inc()
+	case "price_subscription_resubscribes":
+		m.priceResubscribes.With(m.limitLabels(name, labels)).Inc()
+	case "market_data_rejected":
+		m.marketDataRejected.With(m.limitLabels(name, labels)).Inc()
+	case "execution_agent_errors":
+		m.executionErrors.With(m.limitLabels(name, labels)).Inc()
+	default:
+		if counter, ok := m.dynamicMetric(dynamicCounter, name, labels).(prometheus.Counter); ok {
+			counter.Inc()
+		}
+	}
+}
+
+// Gather collects the current value of every metric in the registry the
+// metrics were registered with, or the global one when that registry cannot
+// be gathered from
+func (m *PrometheusMetrics) Gather() ([]*dto.MetricFamily, error) {
+	if gatherer, ok := m.registerer.(prometheus.Gatherer); ok {
+		return gatherer.Gather()
+	}
+	return prometheus.DefaultGatherer.Gather()
+}
+
+// SetMaxLabelValues caps the distinct values each label of each metric may
+// take; values past the cap are recorded as __overflow__
+func (m *PrometheusMetrics) SetMaxLabelValues(max int) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if max <= 0 {
+		max = DefaultMaxLabelValues
+	}
+	m.maxLabelValues = max
+}
+
+// limitLabels returns labels with any value that would take its label past
+// the distinct value cap replaced by __overflow__, so a bug that labels by
+// something unbounded, such as an order ID, cannot grow the series without
+// limit
+func (m *PrometheusMetrics) limitLabels(name string, labels map[string]string) prometheus.Labels {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+
+	var limited prometheus.Labels
+	for label, value := range labels {
+		key := labelKey{metric: name, label: label}
+		values := m.labelValues[key]
+		if _, known := values[value]; known {
+			continue
+		}
+		if len(values) >= m.maxLabelValues {
+			if limited == nil {
+				limited = make(prometheus.Labels, len(labels))
+				for l, v := range labels {
+					limited[l] = v
+				}
+			}
+			limited[label] = overflowLabelValue
+			m.labelOverflow.WithLabelValues(name, label).Inc()
+			continue
+		}
+		if values == nil {
+			values = make(map[string]struct{})
+			m.labelValues[key] = values
+		}
+		values[value] = struct{}{}
+	}
+
+	if limited == nil {
+		return prometheus.Labels(labels)
+	}
+	return limited
+}
+
+// SetLogger sets where rejected values and unknown names are reported;
+// without one they go to the standard logger
+func (m *PrometheusMetrics) SetLogger(logger ifs.Logger) {
+	m.logger = logger
+}
+
+// validValue reports whether value can be recorded. NaN and ±Inf would corrupt
+// the exposition output, so they are counted and logged instead.
+func (m *PrometheusMetrics) validValue(name string, value float64) bool {
+	if !math.IsNaN(value) && !math.IsInf(value, 0) {
+		return true
+	}
+
+	m.invalidMetricValues.WithLabelValues(name).Inc()
+	m.warn("Rejected invalid metric value",
+		ifs.Field{Key: "name", Value: name},
+		ifs.Field{Key: "value", Value: value},
+	)
+	return false
+}
+
+// warn logs to the configured logger, or else the standard logger
+func (m *PrometheusMetrics) warn(msg string, fields ...ifs.Field) {
+	if m.logger != nil {
+		m.logger.Warn(msg, fields...)
+		return
 	}
+	for _, field := range fields {
+		msg += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+	}
+	log.Print(msg)
 }
 
 func (m *PrometheusMetrics) RecordDuration(name string, duration float64, labels map[string]string) {
+	if !m.validValue(name, duration) {
+		return
+	}
+
 	switch name {
 	case "message_bus_publish_duration":
-		m.publishDuration.With(prometheus.Labels(labels)).Observe(duration)
+		m.publishDuration.With(m.limitLabels(name, labels)).Observe(duration)
 	case "message_bus_handle_duration":
-		m.handleDuration.With(prometheus.Labels(labels)).Observe(duration)
+		m.handleDuration.With(m.limitLabels(name, labels)).Observe(duration)
 	case "order_fill_duration":
-		m.orderFillDuration.With(prometheus.Labels(labels)).Observe(duration)
+		m.orderFillDuration.With(m.limitLabels(name, labels)).Observe(duration)
 	case "response_time":
-		m.responseTime.With(prometheus.Labels(labels)).Observe(duration)
+		m.responseTime.With(m.limitLabels(name, labels)).Observe(duration)
 	case "market_data_latency":
-		m.marketDataLatency.With(prometheus.Labels(labels)).Observe(duration)
+		m.marketDataLatency.With(m.limitLabels(name, labels)).Observe(duration)
+	case "startup_step_duration":
+		m.startupStepDuration.With(m.limitLabels(name, labels)).Observe(duration)
+	default:
+		if histogram, ok := m.dynamicMetric(dynamicHistogram, name, labels).(prometheus.Observer); ok {
+			histogram.Observe(duration)
+		}
+	}
+}
+
+func (m *PrometheusMetrics) ObserveValue(name string, value float64, labels map[string]string) {
+	if !m.validValue(name, value) {
+		return
+	}
+
+	switch name {
+	case "execution_agent_order_retries":
+		m.executionRetries.With(m.limitLabels(name, labels)).Observe(value)
+	default:
+		if histogram, ok := m.dynamicMetric(dynamicHistogram, name, labels).(prometheus.Observer); ok {
+			histogram.Observe(value)
+		}
 	}
 }
 
 func (m *PrometheusMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	if !m.validValue(name, value) {
+		return
+	}
+
 	switch name {
 	case "portfolio_value":
-		m.portfolioValue.With(prometheus.Labels(labels)).Set(value)
+		m.portfolioValue.With(m.limitLabels(name, labels)).Set(value)
 	case "position_count":
-		m.positionCount.With(prometheus.Labels(labels)).Set(value)
+		m.positionCount.With(m.limitLabels(name, labels)).Set(value)
 	case "portfolio_risk":
-		m.portfolioRisk.With(prometheus.Labels(labels)).Set(value)
+		m.portfolioRisk.With(m.limitLabels(name, labels)).Set(value)
 	case "position_risk":
-		m.positionRisk.With(prometheus.Labels(labels)).Set(value)
+		m.positionRisk.With(m.limitLabels(name, labels)).Set(value)
 	case "var_value":
-		m.varValue.With(prometheus.Labels(labels)).Set(value)
+		m.varValue.With(m.limitLabels(name, labels)).Set(value)
 	case "agent_health":
-		m.agentHealth.With(prometheus.Labels(labels)).Set(value)
+		m.agentHealth.With(m.limitLabels(name, labels)).Set(value)
 	case "connection_status":
-		m.connectionStatus.With(prometheus.Labels(labels)).Set(value)
+		m.connectionStatus.With(m.limitLabels(name, labels)).Set(value)
+	case "startup_duration":
+		m.startupDuration.With(m.limitLabels(name, labels)).Set(value)
+	default:
+		if gauge, ok := m.dynamicMetric(dynamicGauge, name, labels).(prometheus.Gauge); ok {
+			gauge.Set(value)
+		}
 	}
 }
 
@@ -346,7 +628,9 @@ func (m *PrometheusMetrics) RecordOrderMetrics(order map[string]interface{}) {
 
 func (m *PrometheusMetrics) RecordPortfolioMetrics(portfolioID string, value float64, positionCount int) {
 	portfolioLabels := prometheus.Labels{"portfolio_id": portfolioID}
-	m.portfolioValue.With(portfolioLabels).Set(value)
+	if m.validValue("portfolio_value", value) {
+		m.portfolioValue.With(portfolioLabels).Set(value)
+	}
 	m.positionCount.With(portfolioLabels).Set(float64(positionCount))
 }
 
@@ -364,9 +648,15 @@ func (m *PrometheusMetrics) RecordRiskMetrics(portfolioID string, var95, var99,
 		"metric":       "leverage",
 	}
 
-	m.varValue.With(var95Labels).Set(var95)
-	m.varValue.With(var99Labels).Set(var99)
-	m.portfolioRisk.With(leverageLabels).Set(leverage)
+	if m.validValue("var_value", var95) {
+		m.varValue.With(var95Labels).Set(var95)
+	}
+	if m.validValue("var_value", var99) {
+		m.varValue.With(var99Labels).Set(var99)
+	}
+	if m.validValue("portfolio_risk", leverage) {
+		m.portfolioRisk.With(leverageLabels).Set(leverage)
+	}
 }
 
 func (m *PrometheusMetrics) RecordAgentHealth(agentName string, isHealthy bool) {
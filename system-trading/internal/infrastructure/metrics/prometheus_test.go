@@ -0,0 +1,276 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/system-trading/core/internal/infrastructure/clock"
+)
+
+func newTestMetrics() *PrometheusMetrics {
+	return NewPrometheusMetricsWithRegistry("test-metrics", prometheus.NewRegistry())
+}
+
+func TestPrometheusMetrics_RejectsNonFiniteValues(t *testing.T) {
+	m := newTestMetrics()
+	labels := map[string]string{"portfolio_id": "default"}
+
+	m.SetGauge("portfolio_value", 1000, labels)
+
+	tests := []struct {
+		name   string
+		record func(value float64)
+		metric string
+	}{
+		{"gauge", func(v float64) { m.SetGauge("portfolio_value", v, labels) }, "portfolio_value"},
+		{"histogram", func(v float64) { m.RecordDuration("response_time", v, map[string]string{"operation": "var"}) }, "response_time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range []float64{math.NaN(), math.Inf(1)} {
+				tt.record(value)
+			}
+
+			if got := testutil.ToFloat64(m.invalidMetricValues.WithLabelValues(tt.metric)); got != 2 {
+				t.Errorf("Expected 2 rejected values for %s, got %v", tt.metric, got)
+			}
+		})
+	}
+
+	if got := testutil.ToFloat64(m.portfolioValue.With(labels)); got != 1000 {
+		t.Errorf("Expected gauge to keep its last valid value 1000, got %v", got)
+	}
+	if count := testutil.CollectAndCount(m.responseTime); count != 0 {
+		t.Errorf("Expected no histogram series from rejected observations, got %d", count)
+	}
+
+	m.RecordDuration("response_time", 0.25, map[string]string{"operation": "var"})
+	if count := testutil.CollectAndCount(m.responseTime); count != 1 {
+		t.Errorf("Expected a valid observation to be recorded, got %d series", count)
+	}
+}
+
+func TestPrometheusMetrics_RiskMetricsSkipNonFiniteValues(t *testing.T) {
+	m := newTestMetrics()
+
+	m.RecordRiskMetrics("default", 0.02, math.Inf(-1), math.NaN())
+
+	if got := testutil.ToFloat64(m.varValue.WithLabelValues("default", "95")); got != 0.02 {
+		t.Errorf("Expected finite VaR to be recorded, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.invalidMetricValues.WithLabelValues("var_value")); got != 1 {
+		t.Errorf("Expected 1 rejected var_value, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.invalidMetricValues.WithLabelValues("portfolio_risk")); got != 1 {
+		t.Errorf("Expected 1 rejected portfolio_risk, got %v", got)
+	}
+}
+
+func TestPrometheusMetrics_RegistersUnknownNames(t *testing.T) {
+	m := newTestMetrics()
+
+	m.IncrementCounter("market_data_processed", map[string]string{"symbol": "AAPL"})
+	m.IncrementCounter("market_data_processed", map[string]string{"symbol": "AAPL"})
+	m.SetGauge("queue.depth", 7, map[string]string{"queue": "orders"})
+	m.RecordDuration("2fa_check_duration", 0.5, nil)
+
+	// A second use with other labels or as another kind is dropped, not a panic
+	m.IncrementCounter("market_data_processed", map[string]string{"venue": "NASDAQ"})
+	m.SetGauge("market_data_processed", 1, map[string]string{"symbol": "AAPL"})
+
+	families, err := m.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	scraped := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.Counter != nil:
+				scraped[family.GetName()] = metric.GetCounter().GetValue()
+			case metric.Gauge != nil:
+				scraped[family.GetName()] = metric.GetGauge().GetValue()
+			case metric.Histogram != nil:
+				scraped[family.GetName()] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"market_data_processed_total": 2,
+		"queue_depth":                 7,
+		"_2fa_check_duration":         1,
+	}
+	for name, value := range want {
+		got, ok := scraped[name]
+		if !ok {
+			t.Errorf("Expected %s in the scrape", name)
+			continue
+		}
+		if got != value {
+			t.Errorf("Expected %s to be %v, got %v", name, value, got)
+		}
+	}
+}
+
+func TestPrometheusMetrics_IsolatedRegistry(t *testing.T) {
+	// Two collectors for the same service would collide in the global registry
+	first := NewPrometheusMetricsWithRegistry("isolated", prometheus.NewRegistry())
+	second := NewPrometheusMetricsWithRegistry("isolated", prometheus.NewRegistry())
+
+	labels := map[string]string{"symbol": "AAPL", "side": "BUY"}
+	first.IncrementCounter("orders_filled", labels)
+	first.IncrementCounter("orders_filled", labels)
+	second.IncrementCounter("orders_filled", labels)
+
+	filled := func(m *PrometheusMetrics) float64 {
+		t.Helper()
+		families, err := m.Gather()
+		if err != nil {
+			t.Fatalf("Gather failed: %v", err)
+		}
+		for _, family := range families {
+			if family.GetName() != "orders_filled_total" {
+				continue
+			}
+			total := 0.0
+			for _, metric := range family.GetMetric() {
+				total += metric.GetCounter().GetValue()
+			}
+			return total
+		}
+		t.Fatal("Expected orders_filled_total in the registry")
+		return 0
+	}
+
+	if got := filled(first); got != 2 {
+		t.Errorf("Expected 2 fills in the first registry, got %v", got)
+	}
+	if got := filled(second); got != 1 {
+		t.Errorf("Expected 1 fill in the second registry, got %v", got)
+	}
+}
+
+func TestPrometheusMetrics_CollapsesLabelsPastCap(t *testing.T) {
+	m := newTestMetrics()
+	m.SetMaxLabelValues(100)
+
+	for i := 0; i < 10000; i++ {
+		m.IncrementCounter("price_updates", map[string]string{"symbol": fmt.Sprintf("SYM%d", i)})
+	}
+	// A value seen before the cap keeps its own series
+	m.IncrementCounter("price_updates", map[string]string{"symbol": "SYM0"})
+
+	if count := testutil.CollectAndCount(m.priceUpdates); count != 101 {
+		t.Errorf("Expected 100 symbols plus the overflow series, got %d series", count)
+	}
+	if got := testutil.ToFloat64(m.priceUpdates.WithLabelValues("__overflow__")); got != 9900 {
+		t.Errorf("Expected 9900 updates under __overflow__, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.priceUpdates.WithLabelValues("SYM0")); got != 2 {
+		t.Errorf("Expected SYM0 to keep counting, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.labelOverflow.WithLabelValues("price_updates", "symbol")); got != 9900 {
+		t.Errorf("Expected 9900 overflows counted, got %v", got)
+	}
+}
+
+func TestPrometheusMetrics_RecordOrderStage(t *testing.T) {
+	m := newTestMetrics()
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC))
+	m.SetClock(fakeClock)
+
+	m.RecordOrderStage("order-1", OrderStageProposed)
+	fakeClock.Advance(2 * time.Second)
+	m.RecordOrderStage("order-1", OrderStageApproved)
+	fakeClock.Advance(500 * time.Millisecond)
+	m.RecordOrderStage("order-1", OrderStageExecuted)
+
+	// A rejected order is dropped without observing the later stages
+	m.RecordOrderStage("order-2", OrderStageProposed)
+	fakeClock.Advance(time.Second)
+	m.RecordOrderStage("order-2", OrderStageRejected)
+	m.RecordOrderStage("order-2", OrderStageExecuted)
+
+	families, err := m.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	observed := make(map[string][2]float64)
+	for _, family := range families {
+		if family.GetName() != "order_lifecycle_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "stage" {
+					histogram := metric.GetHistogram()
+					observed[label.GetValue()] = [2]float64{float64(histogram.GetSampleCount()), histogram.GetSampleSum()}
+				}
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"proposed_to_approved": 2,
+		"approved_to_executed": 0.5,
+		"total":                2.5,
+	}
+	if len(observed) != len(want) {
+		t.Errorf("Expected stages %v, got %v", want, observed)
+	}
+	for stage, seconds := range want {
+		got := observed[stage]
+		if got[0] != 1 || math.Abs(got[1]-seconds) > 1e-9 {
+			t.Errorf("Expected one %s observation of %vs, got %v samples summing to %v", stage, seconds, got[0], got[1])
+		}
+	}
+	if len(m.orderStages) != 0 {
+		t.Errorf("Expected finished orders to stop being tracked, %d remain", len(m.orderStages))
+	}
+}
+
+func TestPrometheusMetrics_RecordOrderStageEndsTrackingOnFailureAndExpiry(t *testing.T) {
+	m := newTestMetrics()
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC))
+	m.SetClock(fakeClock)
+
+	m.RecordOrderStage("failed", OrderStageApproved)
+	m.RecordOrderStage("expired", OrderStageApproved)
+	m.RecordOrderStage("stale", OrderStageProposed)
+
+	m.RecordOrderStage("failed", OrderStageFailed)
+	m.RecordOrderStage("expired", OrderStageExpired)
+	if _, tracked := m.orderStages["failed"]; tracked {
+		t.Error("Expected a failed order to stop being tracked")
+	}
+	if _, tracked := m.orderStages["expired"]; tracked {
+		t.Error("Expected an expired order to stop being tracked")
+	}
+
+	// An order whose terminal event was lost is evicted once it ages out
+	fakeClock.Advance(maxTrackedOrderAge + time.Minute)
+	m.RecordOrderStage("fresh", OrderStageProposed)
+	if _, tracked := m.orderStages["stale"]; tracked {
+		t.Error("Expected an order past maxTrackedOrderAge to be evicted")
+	}
+	if _, tracked := m.orderStages["fresh"]; !tracked {
+		t.Error("Expected a new order to be tracked")
+	}
+}
+
+func TestPrometheusMetrics_CountsRiskAlertsByType(t *testing.T) {
+	m := newTestMetrics()
+
+	m.IncrementCounter("risk_alerts", map[string]string{"alert_type": "concentration", "severity": "HIGH"})
+
+	if got := testutil.ToFloat64(m.riskAlerts.WithLabelValues("concentration", "HIGH")); got != 1 {
+		t.Errorf("Expected one concentration alert, got %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// InMemoryAuditRepository is an append-only audit store kept in process memory
+type InMemoryAuditRepository struct {
+	records []*interfaces.AuditRecord
+	nextID  int
+	mu      sync.RWMutex
+}
+
+// NewInMemoryAuditRepository creates an empty in-memory audit store
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+// SaveAuditRecord appends a record, assigning an ID if it has none
+func (r *InMemoryAuditRepository) SaveAuditRecord(ctx context.Context, record *interfaces.AuditRecord) error {
+	if record == nil {
+		return fmt.Errorf("audit record cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *record
+	if stored.ID == "" {
+		r.nextID++
+		stored.ID = fmt.Sprintf("audit-%d", r.nextID)
+	}
+	if stored.Timestamp.IsZero() {
+		stored.Timestamp = time.Now()
+	}
+
+	r.records = append(r.records, &stored)
+	return nil
+}
+
+// ListAuditRecords returns records of the given type at or after from.
+// An empty recordType matches every record.
+func (r *InMemoryAuditRepository) ListAuditRecords(ctx context.Context, recordType string, from time.Time) ([]*interfaces.AuditRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*interfaces.AuditRecord
+	for _, record := range r.records {
+		if recordType != "" && record.Type != recordType {
+			continue
+		}
+		if record.Timestamp.Before(from) {
+			continue
+		}
+		recordCopy := *record
+		result = append(result, &recordCopy)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// InMemoryEventStore is an append-only event log kept in process memory
+type InMemoryEventStore struct {
+	events []*interfaces.StoredEvent
+	mu     sync.RWMutex
+}
+
+// NewInMemoryEventStore creates an empty in-memory event store
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{}
+}
+
+// Append stores a copy of event and assigns it the next sequence number
+func (s *InMemoryEventStore) Append(ctx context.Context, event *interfaces.StoredEvent) error {
+	if event == nil {
+		return fmt.Errorf("event cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *event
+	stored.Data = append([]byte(nil), event.Data...)
+	stored.Sequence = int64(len(s.events)) + 1
+	if stored.RecordedAt.IsZero() {
+		stored.RecordedAt = time.Now()
+	}
+
+	s.events = append(s.events, &stored)
+	event.Sequence = stored.Sequence
+	return nil
+}
+
+// Load returns copies of all events with a sequence greater than afterSequence, in order
+func (s *InMemoryEventStore) Load(ctx context.Context, afterSequence int64) ([]*interfaces.StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if afterSequence < 0 {
+		afterSequence = 0
+	}
+	if afterSequence >= int64(len(s.events)) {
+		return nil, nil
+	}
+
+	result := make([]*interfaces.StoredEvent, 0, int64(len(s.events))-afterSequence)
+	for _, event := range s.events[afterSequence:] {
+		eventCopy := *event
+		result = append(result, &eventCopy)
+	}
+
+	return result, nil
+}
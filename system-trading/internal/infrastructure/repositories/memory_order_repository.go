@@ -0,0 +1,351 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// orderTimeBucket is the width of the creation-time index buckets
+const orderTimeBucket = time.Hour
+
+// InMemoryOrderRepository stores orders in process memory with secondary indexes on
+// symbol, status, creation-time bucket and GTD/DAY expiry, all maintained on write under
+// the same lock so queries never see an index out of step with the orders.
+// Orders are copied on the way in and out so callers can't mutate stored state.
+type InMemoryOrderRepository struct {
+	orders   map[entities.OrderID]*entities.Order
+	bySymbol map[entities.Symbol]orderIDSet
+	byStatus map[entities.OrderStatus]orderIDSet
+	byBucket map[int64]orderIDSet
+	expiry   []expiryEntry
+	mu       sync.RWMutex
+}
+
+type orderIDSet map[entities.OrderID]struct{}
+
+type expiryEntry struct {
+	expiresAt time.Time
+	id        entities.OrderID
+}
+
+// NewInMemoryOrderRepository creates an empty in-memory order repository
+func NewInMemoryOrderRepository() *InMemoryOrderRepository {
+	return &InMemoryOrderRepository{
+		orders:   make(map[entities.OrderID]*entities.Order),
+		bySymbol: make(map[entities.Symbol]orderIDSet),
+		byStatus: make(map[entities.OrderStatus]orderIDSet),
+		byBucket: make(map[int64]orderIDSet),
+	}
+}
+
+// Create stores a new order
+func (r *InMemoryOrderRepository) Create(ctx context.Context, order *entities.Order) error {
+	if order == nil {
+		return fmt.Errorf("order cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; exists {
+		return fmt.Errorf("order %s already exists", order.ID)
+	}
+
+	r.put(order.Clone())
+	return nil
+}
+
+// GetByID returns a copy of the stored order
+func (r *InMemoryOrderRepository) GetByID(ctx context.Context, id entities.OrderID) (*entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return nil, fmt.Errorf("order %s: %w", id, entities.ErrOrderNotFound)
+	}
+	return order.Clone(), nil
+}
+
+// Update replaces a stored order
+func (r *InMemoryOrderRepository) Update(ctx context.Context, order *entities.Order) error {
+	if order == nil {
+		return fmt.Errorf("order cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.orders[order.ID]
+	if !exists {
+		return fmt.Errorf("order %s: %w", order.ID, entities.ErrOrderNotFound)
+	}
+
+	r.unindex(existing)
+	r.put(order.Clone())
+	return nil
+}
+
+// List returns orders matching filters, oldest first. Candidates come from the
+// smallest applicable index rather than a scan of every order.
+func (r *InMemoryOrderRepository) List(ctx context.Context, filters interfaces.OrderFilters) ([]*entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.Order
+	r.eachCandidate(filters, func(order *entities.Order) {
+		if matchesFilters(order, filters) {
+			result = append(result, order.Clone())
+		}
+	})
+
+	sortOrders(result)
+
+	if filters.Offset > 0 {
+		if filters.Offset >= len(result) {
+			return nil, nil
+		}
+		result = result[filters.Offset:]
+	}
+	if filters.Limit > 0 && len(result) > filters.Limit {
+		result = result[:filters.Limit]
+	}
+
+	return result, nil
+}
+
+// Delete removes an order
+func (r *InMemoryOrderRepository) Delete(ctx context.Context, id entities.OrderID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return fmt.Errorf("order %s: %w", id, entities.ErrOrderNotFound)
+	}
+
+	r.unindex(order)
+	delete(r.orders, id)
+	return nil
+}
+
+// ListByStatus returns orders whose status is in statuses, oldest first, touching
+// only the status index buckets involved
+func (r *InMemoryOrderRepository) ListByStatus(ctx context.Context, statuses ...entities.OrderStatus) ([]*entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.Order
+	for _, status := range statuses {
+		for id := range r.byStatus[status] {
+			result = append(result, r.orders[id].Clone())
+		}
+	}
+
+	sortOrders(result)
+	return result, nil
+}
+
+// HasStatus reports in O(1) whether order id is currently in any of statuses
+func (r *InMemoryOrderRepository) HasStatus(id entities.OrderID, statuses ...entities.OrderStatus) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, status := range statuses {
+		if _, ok := r.byStatus[status][id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ListExpiring walks the expiry index up to before, so the cost is proportional to
+// the number of due orders rather than the size of the repository
+func (r *InMemoryOrderRepository) ListExpiring(ctx context.Context, before time.Time, limit int) ([]*entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.Order
+	for _, entry := range r.expiry {
+		if entry.expiresAt.After(before) {
+			break
+		}
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		result = append(result, r.orders[entry.id].Clone())
+	}
+
+	return result, nil
+}
+
+// eachCandidate calls fn for every order in the smallest index that can satisfy
+// filters, falling back to all orders. Must be called with r.mu held.
+func (r *InMemoryOrderRepository) eachCandidate(filters interfaces.OrderFilters, fn func(*entities.Order)) {
+	var best orderIDSet
+	useIndex := false
+	consider := func(set orderIDSet) {
+		if !useIndex || len(set) < len(best) {
+			best = set
+			useIndex = true
+		}
+	}
+
+	if filters.Symbol != nil {
+		consider(r.bySymbol[*filters.Symbol])
+	}
+	if filters.Status != nil {
+		consider(r.byStatus[*filters.Status])
+	}
+
+	if filters.DateFrom != nil || filters.DateTo != nil {
+		var buckets []orderIDSet
+		size := 0
+		for bucket, set := range r.byBucket {
+			if bucketInRange(bucket, filters.DateFrom, filters.DateTo) {
+				buckets = append(buckets, set)
+				size += len(set)
+			}
+		}
+		if !useIndex || size < len(best) {
+			for _, set := range buckets {
+				for id := range set {
+					fn(r.orders[id])
+				}
+			}
+			return
+		}
+	}
+
+	if useIndex {
+		for id := range best {
+			fn(r.orders[id])
+		}
+		return
+	}
+
+	for _, order := range r.orders {
+		fn(order)
+	}
+}
+
+func matchesFilters(order *entities.Order, filters interfaces.OrderFilters) bool {
+	if filters.Symbol != nil && order.Symbol != *filters.Symbol {
+		return false
+	}
+	if filters.Status != nil && order.Status != *filters.Status {
+		return false
+	}
+	if filters.Side != nil && order.Side != *filters.Side {
+		return false
+	}
+	if filters.DateFrom != nil && order.CreatedAt.Before(*filters.DateFrom) {
+		return false
+	}
+	if filters.DateTo != nil && order.CreatedAt.After(*filters.DateTo) {
+		return false
+	}
+	return true
+}
+
+func sortOrders(orders []*entities.Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+}
+
+func timeBucket(t time.Time) int64 {
+	return t.UnixNano() / int64(orderTimeBucket)
+}
+
+func bucketInRange(bucket int64, from, to *time.Time) bool {
+	if from != nil && bucket < timeBucket(*from) {
+		return false
+	}
+	if to != nil && bucket > timeBucket(*to) {
+		return false
+	}
+	return true
+}
+
+func (s orderIDSet) add(id entities.OrderID) {
+	s[id] = struct{}{}
+}
+
+// put stores order and adds it to every index. Must be called with r.mu held.
+func (r *InMemoryOrderRepository) put(order *entities.Order) {
+	r.orders[order.ID] = order
+
+	addToIndex(r.bySymbol, order.Symbol, order.ID)
+	addToIndex(r.byStatus, order.Status, order.ID)
+	addToIndex(r.byBucket, timeBucket(order.CreatedAt), order.ID)
+
+	if !order.HasExpiry() || !order.IsLive() {
+		return
+	}
+
+	entry := expiryEntry{expiresAt: *order.ExpiresAt, id: order.ID}
+	i := sort.Search(len(r.expiry), func(i int) bool {
+		return entry.less(r.expiry[i])
+	})
+	r.expiry = append(r.expiry, expiryEntry{})
+	copy(r.expiry[i+1:], r.expiry[i:])
+	r.expiry[i] = entry
+}
+
+// unindex removes order from every index. Must be called with r.mu held.
+func (r *InMemoryOrderRepository) unindex(order *entities.Order) {
+	removeFromIndex(r.bySymbol, order.Symbol, order.ID)
+	removeFromIndex(r.byStatus, order.Status, order.ID)
+	removeFromIndex(r.byBucket, timeBucket(order.CreatedAt), order.ID)
+
+	if order.ExpiresAt == nil {
+		return
+	}
+
+	entry := expiryEntry{expiresAt: *order.ExpiresAt, id: order.ID}
+	i := sort.Search(len(r.expiry), func(i int) bool {
+		return !r.expiry[i].less(entry)
+	})
+	if i < len(r.expiry) && r.expiry[i].id == entry.id {
+		r.expiry = append(r.expiry[:i], r.expiry[i+1:]...)
+	}
+}
+
+func (e expiryEntry) less(other expiryEntry) bool {
+	if !e.expiresAt.Equal(other.expiresAt) {
+		return e.expiresAt.Before(other.expiresAt)
+	}
+	return e.id < other.id
+}
+
+func addToIndex[K comparable](index map[K]orderIDSet, key K, id entities.OrderID) {
+	set, exists := index[key]
+	if !exists {
+		set = make(orderIDSet)
+		index[key] = set
+	}
+	set.add(id)
+}
+
+// removeFromIndex drops id and deletes emptied sets so the index doesn't grow with
+// symbols or buckets that no longer hold orders
+func removeFromIndex[K comparable](index map[K]orderIDSet, key K, id entities.OrderID) {
+	set, exists := index[key]
+	if !exists {
+		return
+	}
+	delete(set, id)
+	if len(set) == 0 {
+		delete(index, key)
+	}
+}
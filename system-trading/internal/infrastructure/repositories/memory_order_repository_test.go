@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+var (
+	testSymbols  = []entities.Symbol{"AAPL", "MSFT", "GOOGL"}
+	testStatuses = []entities.OrderStatus{
+		entities.OrderStatusPending,
+		entities.OrderStatusApproved,
+		entities.OrderStatusExecuted,
+		entities.OrderStatusCancelled,
+	}
+)
+
+func testOrder(i int, base time.Time) *entities.Order {
+	side := entities.OrderSideBuy
+	if i%2 == 1 {
+		side = entities.OrderSideSell
+	}
+	order := entities.NewOrder(testSymbols[i%len(testSymbols)], side, entities.OrderTypeMarket, float64(i+1), nil)
+	order.ID = entities.OrderID(fmt.Sprintf("order-%04d", i))
+	order.CreatedAt = base.Add(time.Duration(i) * 17 * time.Minute)
+	return order
+}
+
+// populateConcurrently creates, approves, executes and cancels orders from many goroutines
+func populateConcurrently(t *testing.T, repo *InMemoryOrderRepository, count int, base time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			order := testOrder(i, base)
+			if err := repo.Create(ctx, order); err != nil {
+				t.Errorf("Create %s failed: %v", order.ID, err)
+				return
+			}
+
+			switch i % 4 {
+			case 1:
+				order.Approve()
+			case 2:
+				order.Approve()
+				order.Execute(100, order.Quantity)
+			case 3:
+				order.Cancel()
+			default:
+				return
+			}
+			if err := repo.Update(ctx, order); err != nil {
+				t.Errorf("Update %s failed: %v", order.ID, err)
+			}
+		}(i)
+
+		// Readers run alongside writers so -race sees index reads under contention
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := entities.OrderStatusPending
+			repo.List(ctx, interfaces.OrderFilters{Status: &status})
+		}()
+	}
+	wg.Wait()
+}
+
+func assertIndexesConsistent(t *testing.T, repo *InMemoryOrderRepository) {
+	t.Helper()
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	indexed := 0
+	for symbol, set := range repo.bySymbol {
+		for id := range set {
+			if repo.orders[id] == nil || repo.orders[id].Symbol != symbol {
+				t.Errorf("Symbol index has stale entry %s under %s", id, symbol)
+			}
+			indexed++
+		}
+	}
+	if indexed != len(repo.orders) {
+		t.Errorf("Symbol index holds %d entries, want %d", indexed, len(repo.orders))
+	}
+
+	indexed = 0
+	for status, set := range repo.byStatus {
+		for id := range set {
+			if repo.orders[id] == nil || repo.orders[id].Status != status {
+				t.Errorf("Status index has stale entry %s under %s", id, status)
+			}
+			indexed++
+		}
+	}
+	if indexed != len(repo.orders) {
+		t.Errorf("Status index holds %d entries, want %d", indexed, len(repo.orders))
+	}
+
+	indexed = 0
+	for bucket, set := range repo.byBucket {
+		for id := range set {
+			if repo.orders[id] == nil || timeBucket(repo.orders[id].CreatedAt) != bucket {
+				t.Errorf("Time index has stale entry %s under bucket %d", id, bucket)
+			}
+			indexed++
+		}
+	}
+	if indexed != len(repo.orders) {
+		t.Errorf("Time index holds %d entries, want %d", indexed, len(repo.orders))
+	}
+}
+
+func TestInMemoryOrderRepository_IndexesConsistentUnderConcurrency(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	populateConcurrently(t, repo, 400, base)
+	assertIndexesConsistent(t, repo)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 400; i += 8 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := repo.Delete(ctx, entities.OrderID(fmt.Sprintf("order-%04d", i))); err != nil {
+				t.Errorf("Delete failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assertIndexesConsistent(t, repo)
+
+	if !repo.HasStatus("order-0003", entities.OrderStatusCancelled, entities.OrderStatusRejected) {
+		t.Error("Expected order-0003 to be in the cancelled/rejected status set")
+	}
+	if repo.HasStatus("order-0003", entities.OrderStatusPending) {
+		t.Error("Expected order-0003 not to be pending")
+	}
+	if repo.HasStatus("order-0000", entities.OrderStatusPending) {
+		t.Error("Expected deleted order-0000 to be absent from the status index")
+	}
+}
+
+func TestInMemoryOrderRepository_FilteredListMatchesBruteForce(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	populateConcurrently(t, repo, 300, base)
+
+	ctx := context.Background()
+	all, err := repo.List(ctx, interfaces.OrderFilters{})
+	if err != nil {
+		t.Fatalf("List all failed: %v", err)
+	}
+	if len(all) != 300 {
+		t.Fatalf("Expected 300 orders, got %d", len(all))
+	}
+
+	symbol := entities.Symbol("MSFT")
+	status := entities.OrderStatusApproved
+	sell := entities.OrderSideSell
+	from := base.Add(10 * time.Hour)
+	to := base.Add(30*time.Hour + 15*time.Minute)
+	narrowTo := base.Add(90 * time.Minute)
+
+	tests := []struct {
+		name    string
+		filters interfaces.OrderFilters
+	}{
+		{"symbol", interfaces.OrderFilters{Symbol: &symbol}},
+		{"status", interfaces.OrderFilters{Status: &status}},
+		{"side", interfaces.OrderFilters{Side: &sell}},
+		{"time range", interfaces.OrderFilters{DateFrom: &from, DateTo: &to}},
+		{"open-ended from", interfaces.OrderFilters{DateFrom: &to}},
+		{"narrow range with symbol", interfaces.OrderFilters{Symbol: &symbol, DateTo: &narrowTo}},
+		{"symbol and status in range", interfaces.OrderFilters{Symbol: &symbol, Status: &status, DateFrom: &from, DateTo: &to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected []entities.OrderID
+			for _, order := range all {
+				if matchesFilters(order, tt.filters) {
+					expected = append(expected, order.ID)
+				}
+			}
+
+			got, err := repo.List(ctx, tt.filters)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(got) != len(expected) {
+				t.Fatalf("Expected %d orders, got %d", len(expected), len(got))
+			}
+			for i := range got {
+				if got[i].ID != expected[i] {
+					t.Errorf("Position %d: expected %s, got %s", i, expected[i], got[i].ID)
+				}
+			}
+		})
+	}
+
+	for _, status := range testStatuses {
+		got, err := repo.ListByStatus(ctx, status)
+		if err != nil {
+			t.Fatalf("ListByStatus failed: %v", err)
+		}
+		want := 0
+		for _, order := range all {
+			if order.Status == status {
+				want++
+			}
+		}
+		if len(got) != want {
+			t.Errorf("ListByStatus(%s): expected %d, got %d", status, want, len(got))
+		}
+	}
+}
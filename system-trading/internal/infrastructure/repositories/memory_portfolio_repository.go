@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// InMemoryPortfolioRepository stores portfolios in process memory.
+// Portfolios are copied on the way in and out so callers can't mutate stored state.
+type InMemoryPortfolioRepository struct {
+	portfolios map[string]*entities.Portfolio
+	mu         sync.RWMutex
+}
+
+// NewInMemoryPortfolioRepository creates an empty in-memory portfolio repository
+func NewInMemoryPortfolioRepository() *InMemoryPortfolioRepository {
+	return &InMemoryPortfolioRepository{
+		portfolios: make(map[string]*entities.Portfolio),
+	}
+}
+
+// Save stores a portfolio, replacing any existing portfolio with the same ID
+func (r *InMemoryPortfolioRepository) Save(ctx context.Context, portfolio *entities.Portfolio) error {
+	if portfolio == nil {
+		return fmt.Errorf("portfolio cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.portfolios[portfolio.ID] = clonePortfolio(portfolio)
+	return nil
+}
+
+// GetByID returns a copy of the stored portfolio
+func (r *InMemoryPortfolioRepository) GetByID(ctx context.Context, id string) (*entities.Portfolio, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	portfolio, exists := r.portfolios[id]
+	if !exists {
+		return nil, fmt.Errorf("portfolio %s: %w", id, entities.ErrPortfolioNotFound)
+	}
+
+	return clonePortfolio(portfolio), nil
+}
+
+// UpdatePositions stores the portfolio's current state
+func (r *InMemoryPortfolioRepository) UpdatePositions(ctx context.Context, portfolio *entities.Portfolio) error {
+	if portfolio == nil {
+		return fmt.Errorf("portfolio cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.portfolios[portfolio.ID]; !exists {
+		return fmt.Errorf("portfolio %s: %w", portfolio.ID, entities.ErrPortfolioNotFound)
+	}
+
+	r.portfolios[portfolio.ID] = clonePortfolio(portfolio)
+	return nil
+}
+
+func clonePortfolio(portfolio *entities.Portfolio) *entities.Portfolio {
+	clone := *portfolio
+	clone.Positions = make(map[entities.Symbol]*entities.Position, len(portfolio.Positions))
+	for symbol, position := range portfolio.Positions {
+		positionCopy := *position
+		positionCopy.Lots = append([]entities.Lot(nil), position.Lots...)
+		clone.Positions[symbol] = &positionCopy
+	}
+	clone.RealizedLots = append([]entities.RealizedLot(nil), portfolio.RealizedLots...)
+	clone.PnLHistory = append([]entities.DailyPnL(nil), portfolio.PnLHistory...)
+	if portfolio.ClosedPositions != nil {
+		clone.ClosedPositions = make(map[entities.Symbol]*entities.Position, len(portfolio.ClosedPositions))
+		for symbol, position := range portfolio.ClosedPositions {
+			positionCopy := *position
+			clone.ClosedPositions[symbol] = &positionCopy
+		}
+	}
+	return &clone
+}
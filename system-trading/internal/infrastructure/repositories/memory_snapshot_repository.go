@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// InMemoryPortfolioSnapshotRepository keeps portfolio snapshots in process memory
+type InMemoryPortfolioSnapshotRepository struct {
+	snapshots map[string][]*entities.PortfolioSnapshot
+	mu        sync.RWMutex
+}
+
+// NewInMemoryPortfolioSnapshotRepository creates an empty in-memory snapshot store
+func NewInMemoryPortfolioSnapshotRepository() *InMemoryPortfolioSnapshotRepository {
+	return &InMemoryPortfolioSnapshotRepository{
+		snapshots: make(map[string][]*entities.PortfolioSnapshot),
+	}
+}
+
+// SaveSnapshot appends a copy of snapshot to its portfolio's series
+func (r *InMemoryPortfolioSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot *entities.PortfolioSnapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("portfolio snapshot cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *snapshot
+	r.snapshots[stored.PortfolioID] = append(r.snapshots[stored.PortfolioID], &stored)
+	return nil
+}
+
+// ListSnapshots returns portfolioID's snapshots taken in [from, to], oldest first
+func (r *InMemoryPortfolioSnapshotRepository) ListSnapshots(ctx context.Context, portfolioID string, from, to time.Time) ([]*entities.PortfolioSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.PortfolioSnapshot
+	for _, snapshot := range r.snapshots[portfolioID] {
+		if snapshot.Timestamp.Before(from) || snapshot.Timestamp.After(to) {
+			continue
+		}
+		snapshotCopy := *snapshot
+		result = append(result, &snapshotCopy)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// DefaultCapacity is how many finished spans a SpanRecorder keeps
+const DefaultCapacity = 10000
+
+// SpanRecord is a finished span
+type SpanRecord struct {
+	CorrelationID string        `json:"correlation_id"`
+	Name          string        `json:"name"`
+	Start         time.Time     `json:"start"`
+	End           time.Time     `json:"end"`
+	Duration      time.Duration `json:"duration"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// SpanRecorder is an in-memory Tracer that keeps the most recent finished
+// spans in a fixed-size ring, overwriting the oldest when full
+type SpanRecorder struct {
+	mu    sync.RWMutex
+	ring  []SpanRecord
+	next  int
+	count int
+	now   func() time.Time
+}
+
+func NewSpanRecorder(capacity int) *SpanRecorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &SpanRecorder{
+		ring: make([]SpanRecord, capacity),
+		now:  time.Now,
+	}
+}
+
+func (r *SpanRecorder) StartSpan(correlationID, name string, start time.Time) ifs.Span {
+	return &span{
+		recorder: r,
+		record:   SpanRecord{CorrelationID: correlationID, Name: name, Start: start},
+	}
+}
+
+// Trace returns the recorded spans for correlationID, ordered by start time
+func (r *SpanRecorder) Trace(correlationID string) []SpanRecord {
+	r.mu.RLock()
+	var spans []SpanRecord
+	for i := 0; i < r.count; i++ {
+		if record := r.ring[i]; record.CorrelationID == correlationID {
+			spans = append(spans, record)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans
+}
+
+func (r *SpanRecorder) add(record SpanRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ring[r.next] = record
+	r.next = (r.next + 1) % len(r.ring)
+	if r.count < len(r.ring) {
+		r.count++
+	}
+}
+
+type span struct {
+	recorder *SpanRecorder
+	once     sync.Once
+	record   SpanRecord
+}
+
+// End records the span; only the first call has any effect
+func (s *span) End(err error) {
+	s.once.Do(func() {
+		s.record.End = s.recorder.now()
+		s.record.Duration = s.record.End.Sub(s.record.Start)
+		if err != nil {
+			s.record.Error = err.Error()
+		}
+		s.recorder.add(s.record)
+	})
+}
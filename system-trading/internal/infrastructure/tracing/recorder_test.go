@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSpanRecorder_KeepsMostRecentSpans(t *testing.T) {
+	recorder := NewSpanRecorder(3)
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		recorder.StartSpan("order-1", fmt.Sprintf("step-%d", i), start.Add(time.Duration(i)*time.Millisecond)).End(nil)
+	}
+
+	trace := recorder.Trace("order-1")
+	if len(trace) != 3 {
+		t.Fatalf("Expected the ring to keep 3 spans, got %d", len(trace))
+	}
+	for i, span := range trace {
+		if want := fmt.Sprintf("step-%d", i+2); span.Name != want {
+			t.Errorf("Span %d: expected %s, got %s", i, want, span.Name)
+		}
+	}
+}
+
+func TestSpanRecorder_EndRecordsOnce(t *testing.T) {
+	recorder := NewSpanRecorder(10)
+	span := recorder.StartSpan("order-1", "order.submitted", time.Now())
+
+	span.End(errors.New("broker unavailable"))
+	span.End(nil)
+
+	trace := recorder.Trace("order-1")
+	if len(trace) != 1 {
+		t.Fatalf("Expected one span, got %d", len(trace))
+	}
+	if trace[0].Error != "broker unavailable" {
+		t.Errorf("Expected the span to carry its error, got %q", trace[0].Error)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/system-trading/core/internal/entities"
 )
@@ -47,7 +49,7 @@ func (v *Validator) ValidateOrder(order *entities.Order) error {
 		return fmt.Errorf("quantity must be positive, got: %f", order.Quantity)
 	}
 
-	if order.Type == entities.OrderTypeLimit || order.Type == entities.OrderTypeStop {
+	if order.Type == entities.OrderTypeLimit {
 		if order.Price == nil {
 			return fmt.Errorf("price is required for %s orders", order.Type)
 		}
@@ -56,6 +58,15 @@ func (v *Validator) ValidateOrder(order *entities.Order) error {
 		}
 	}
 
+	if order.Type == entities.OrderTypeStop {
+		if order.StopPrice == nil {
+			return fmt.Errorf("stop price is required for %s orders", order.Type)
+		}
+		if *order.StopPrice <= 0 {
+			return fmt.Errorf("stop price must be positive, got: %f", *order.StopPrice)
+		}
+	}
+
 	return nil
 }
 
@@ -65,40 +76,46 @@ func (v *Validator) ValidateMarketData(data *entities.MarketData) error {
 	}
 
 	if data.Symbol == "" {
-		return fmt.Errorf("symbol is required")
+		return rejectMarketData("missing_symbol", "symbol is required")
 	}
 
 	if !v.isValidSymbol(string(data.Symbol)) {
-		return fmt.Errorf("invalid symbol format: %s", data.Symbol)
+		return rejectMarketData("invalid_symbol", "invalid symbol format: %s", data.Symbol)
 	}
 
 	if data.Price <= 0 {
-		return fmt.Errorf("price must be positive, got: %f", data.Price)
+		return rejectMarketData("non_positive_price", "price must be positive, got: %f", data.Price)
 	}
 
 	if data.Volume < 0 {
-		return fmt.Errorf("volume cannot be negative, got: %f", data.Volume)
+		return rejectMarketData("negative_volume", "volume cannot be negative, got: %f", data.Volume)
 	}
 
 	if data.Bid <= 0 || data.Ask <= 0 {
-		return fmt.Errorf("bid and ask prices must be positive, got bid: %f, ask: %f", data.Bid, data.Ask)
+		return rejectMarketData("non_positive_quote", "bid and ask prices must be positive, got bid: %f, ask: %f", data.Bid, data.Ask)
 	}
 
 	if data.Bid >= data.Ask {
-		return fmt.Errorf("bid price (%f) must be less than ask price (%f)", data.Bid, data.Ask)
+		return rejectMarketData("crossed_quote", "bid price (%f) must be less than ask price (%f)", data.Bid, data.Ask)
 	}
 
 	if data.High < data.Low {
-		return fmt.Errorf("high price (%f) cannot be less than low price (%f)", data.High, data.Low)
+		return rejectMarketData("inverted_range", "high price (%f) cannot be less than low price (%f)", data.High, data.Low)
 	}
 
 	if data.Price < data.Low || data.Price > data.High {
-		return fmt.Errorf("current price (%f) must be between low (%f) and high (%f)", data.Price, data.Low, data.High)
+		return rejectMarketData("price_out_of_range", "current price (%f) must be between low (%f) and high (%f)", data.Price, data.Low, data.High)
 	}
 
 	return nil
 }
 
+// rejectMarketData builds the error ValidateMarketData returns, labelled with
+// reason so callers can count rejections by cause
+func rejectMarketData(reason, format string, args ...interface{}) error {
+	return &entities.MarketDataRejection{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
 func (v *Validator) ValidatePortfolio(portfolio *entities.Portfolio) error {
 	if portfolio == nil {
 		return fmt.Errorf("portfolio cannot be nil")
@@ -261,6 +278,10 @@ func (v *Validator) validateField(field reflect.Value, fieldName, tag string) er
 		rule = strings.TrimSpace(rule)
 
 		switch {
+		case rule == "omitempty":
+			if v.isEmptyValue(field) {
+				return nil
+			}
 		case rule == "required":
 			if v.isEmptyValue(field) {
 				return fmt.Errorf("is required")
@@ -309,14 +330,66 @@ func (v *Validator) isEmptyValue(field reflect.Value) bool {
 	return false
 }
 
+// validateMin checks a number is at least value, or a string, slice or map has
+// at least value elements. Nil pointers are left to required and omitempty.
 func (v *Validator) validateMin(field reflect.Value, value string) error {
-	// Implementation for min validation
-	return nil
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid min rule %q: %w", value, err)
+	}
+
+	n, isLength, ok := measure(field)
+	if !ok || n >= bound {
+		return nil
+	}
+	if isLength {
+		return fmt.Errorf("length must be >= %s, got %d", value, int(n))
+	}
+	return fmt.Errorf("must be >= %s, got %v", value, n)
 }
 
+// validateMax checks a number is at most value, or a string, slice or map has
+// at most value elements. Nil pointers are left to required and omitempty.
 func (v *Validator) validateMax(field reflect.Value, value string) error {
-	// Implementation for max validation
-	return nil
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid max rule %q: %w", value, err)
+	}
+
+	n, isLength, ok := measure(field)
+	if !ok || n <= bound {
+		return nil
+	}
+	if isLength {
+		return fmt.Errorf("length must be <= %s, got %d", value, int(n))
+	}
+	return fmt.Errorf("must be <= %s, got %v", value, n)
+}
+
+// measure returns what min and max compare against: a numeric field's value,
+// or the length of a string (in characters), slice, array or map. Pointers are
+// followed; ok is false for nil pointers and kinds with no measure.
+func measure(field reflect.Value) (n float64, isLength bool, ok bool) {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return 0, false, false
+		}
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), false, true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(field.Len()), true, true
+	}
+	return 0, false, false
 }
 
 func (v *Validator) isValidSymbol(symbol string) bool {
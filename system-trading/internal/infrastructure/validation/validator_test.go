@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidator_ValidateStructMinMax(t *testing.T) {
+	type bounds struct {
+		Quantity float64  `validate:"min=0.000001,max=1000"`
+		Lots     int      `validate:"min=1,max=10"`
+		Retries  uint     `validate:"max=3"`
+		Name     string   `validate:"min=3,max=5"`
+		Tags     []string `validate:"max=2"`
+		Price    *float64 `validate:"omitempty,min=0.000001"`
+		Limit    *float64 `validate:"min=1"`
+	}
+	valid := func() bounds {
+		return bounds{Quantity: 1, Lots: 1, Name: "abc"}
+	}
+	price := func(p float64) *float64 { return &p }
+
+	tests := []struct {
+		name    string
+		modify  func(b *bounds)
+		wantErr string
+	}{
+		{"valid", func(b *bounds) {}, ""},
+		{"float at min", func(b *bounds) { b.Quantity = 0.000001 }, ""},
+		{"float below min", func(b *bounds) { b.Quantity = 0.0000009 }, "Quantity: must be >= 0.000001"},
+		{"float at max", func(b *bounds) { b.Quantity = 1000 }, ""},
+		{"float above max", func(b *bounds) { b.Quantity = 1000.5 }, "Quantity: must be <= 1000"},
+		{"int below min", func(b *bounds) { b.Lots = 0 }, "Lots: must be >= 1"},
+		{"int at max", func(b *bounds) { b.Lots = 10 }, ""},
+		{"int above max", func(b *bounds) { b.Lots = 11 }, "Lots: must be <= 10"},
+		{"uint above max", func(b *bounds) { b.Retries = 4 }, "Retries: must be <= 3"},
+		{"string below min length", func(b *bounds) { b.Name = "ab" }, "Name: length must be >= 3"},
+		{"string at max length", func(b *bounds) { b.Name = "abcde" }, ""},
+		{"string above max length", func(b *bounds) { b.Name = "abcdef" }, "Name: length must be <= 5"},
+		{"string length counts characters", func(b *bounds) { b.Name = "ééé" }, ""},
+		{"slice above max length", func(b *bounds) { b.Tags = []string{"a", "b", "c"} }, "Tags: length must be <= 2"},
+		{"nil pointer skipped", func(b *bounds) { b.Price, b.Limit = nil, nil }, ""},
+		{"pointer dereferenced", func(b *bounds) { b.Limit = price(0.5) }, "Limit: must be >= 1"},
+		{"omitempty pointer set", func(b *bounds) { b.Price = price(0) }, "Price: must be >= 0.000001"},
+		{"omitempty pointer valid", func(b *bounds) { b.Price = price(150) }, ""},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := valid()
+			tt.modify(&b)
+			err := v.ValidateStruct(b)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateStructInvalidBound(t *testing.T) {
+	type badRule struct {
+		Quantity float64 `validate:"min=abc"`
+	}
+	if err := NewValidator().ValidateStruct(badRule{Quantity: 1}); err == nil {
+		t.Fatal("Expected an error for an unparsable min rule")
+	}
+}
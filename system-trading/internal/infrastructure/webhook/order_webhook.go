@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	ifs "github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC of "<timestamp>.<body>"
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	TopicHeader     = "X-Webhook-Topic"
+
+	DefaultDLQTopic = "dlq.webhook"
+)
+
+// Config configures an OrderWebhook
+type Config struct {
+	URL string
+	// Secret keys the HMAC-SHA256 signature of every delivery
+	Secret string
+	// Topics defaults to order.executed and order.failed
+	Topics      []string
+	Timeout     time.Duration
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// DLQTopic receives deliveries that fail every attempt
+	DLQTopic string
+}
+
+// OrderWebhook forwards order events to an external HTTP endpoint. Each event
+// is POSTed as received from the bus, retried on failure and dead-lettered once
+// retries are exhausted.
+type OrderWebhook struct {
+	messageBus ifs.MessageBus
+	client     *http.Client
+	config     Config
+	logger     ifs.Logger
+	metrics    ifs.MetricsCollector
+	now        func() time.Time
+}
+
+func NewOrderWebhook(messageBus ifs.MessageBus, config Config, logger ifs.Logger, metrics ifs.MetricsCollector) *OrderWebhook {
+	if len(config.Topics) == 0 {
+		config.Topics = []string{messagebus.TopicOrderExecuted, "order.failed"}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.DLQTopic == "" {
+		config.DLQTopic = DefaultDLQTopic
+	}
+
+	return &OrderWebhook{
+		messageBus: messageBus,
+		client:     &http.Client{Timeout: config.Timeout},
+		config:     config,
+		logger:     logger,
+		metrics:    metrics,
+		now:        time.Now,
+	}
+}
+
+func (w *OrderWebhook) Start(ctx context.Context) error {
+	for _, topic := range w.config.Topics {
+		delivery := messagebus.DeliveryConfig{
+			MaxAttempts: w.config.MaxAttempts,
+			RetryDelay:  w.config.RetryDelay,
+			RetryJitter: 0.2,
+			DLQTopic:    w.config.DLQTopic,
+		}
+		if err := messagebus.SubscribeExactlyOnceish(ctx, w.messageBus, topic, w.handler(topic), delivery, w.logger, w.metrics); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	w.logger.Info("Order webhook started",
+		ifs.Field{Key: "url", Value: w.config.URL},
+		ifs.Field{Key: "topics", Value: w.config.Topics},
+	)
+	return nil
+}
+
+func (w *OrderWebhook) handler(topic string) ifs.MessageHandler {
+	return func(ctx context.Context, data []byte) error {
+		if err := w.deliver(ctx, topic, data); err != nil {
+			w.metrics.IncrementCounter("webhook_delivery_failures", map[string]string{"topic": topic})
+			return err
+		}
+		w.metrics.IncrementCounter("webhook_deliveries", map[string]string{"topic": topic})
+		return nil
+	}
+}
+
+func (w *OrderWebhook) deliver(ctx context.Context, topic string, body []byte) error {
+	timestamp := strconv.FormatInt(w.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return &deliveryError{err: fmt.Errorf("failed to build webhook request: %w", err), permanent: true}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TopicHeader, topic)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(w.config.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &deliveryError{
+		err: fmt.Errorf("webhook returned status %d", resp.StatusCode),
+		// Other client errors will not succeed on retry
+		permanent: resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests,
+	}
+}
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body sent at timestamp
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// deliveryError marks failures that retrying cannot fix as permanent so they
+// are dead-lettered straight away
+type deliveryError struct {
+	err       error
+	permanent bool
+}
+
+func (e *deliveryError) Error() string   { return e.err.Error() }
+func (e *deliveryError) Unwrap() error   { return e.err }
+func (e *deliveryError) Permanent() bool { return e.permanent }
@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/metrics"
+)
+
+const testSecret = "test-webhook-secret-0123456789abcdef"
+
+type receivedRequest struct {
+	topic     string
+	timestamp string
+	signature string
+	body      []byte
+}
+
+// webhookServer records every request and answers with the next queued status,
+// then 200 once the queue is empty
+type webhookServer struct {
+	mu       sync.Mutex
+	statuses []int
+	received []receivedRequest
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.received = append(s.received, receivedRequest{
+		topic:     r.Header.Get(TopicHeader),
+		timestamp: r.Header.Get(TimestampHeader),
+		signature: r.Header.Get(SignatureHeader),
+		body:      body,
+	})
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func setupWebhook(t *testing.T, server *webhookServer) (*messagebus.MockMessageBus, *httptest.Server) {
+	t.Helper()
+
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{Level: "error", Format: "json", Output: "stdout"})
+	if err != nil {
+		t.Fatalf("Failed to create test logger: %v", err)
+	}
+	testMetrics := metrics.NewPrometheusMetricsWithRegistry("test-webhook", prometheus.NewRegistry())
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	bus := messagebus.NewMockMessageBus()
+	hook := NewOrderWebhook(bus, Config{
+		URL:         httpServer.URL,
+		Secret:      testSecret,
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+	}, testLogger, testMetrics)
+	if err := hook.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start webhook: %v", err)
+	}
+	return bus, httpServer
+}
+
+func executedOrderPayload(t *testing.T, orderID string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"order_id":          orderID,
+		"symbol":            "AAPL",
+		"side":              "BUY",
+		"quantity":          10,
+		"executed_price":    150.25,
+		"executed_quantity": 10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal payload: %v", err)
+	}
+	return data
+}
+
+func TestOrderWebhook_DeliversSignedExecutedOrder(t *testing.T) {
+	server := &webhookServer{}
+	bus, _ := setupWebhook(t, server)
+
+	payload := executedOrderPayload(t, "order-1")
+	if err := bus.GetHandler("order.executed")(context.Background(), payload); err != nil {
+		t.Fatalf("Delivery failed: %v", err)
+	}
+
+	if len(server.received) != 1 {
+		t.Fatalf("Expected one webhook request, got %d", len(server.received))
+	}
+	request := server.received[0]
+	if request.topic != "order.executed" {
+		t.Errorf("Expected topic header order.executed, got %q", request.topic)
+	}
+	if string(request.body) != string(payload) {
+		t.Errorf("Expected the event payload to be forwarded unchanged, got %s", request.body)
+	}
+	if !Verify(testSecret, request.timestamp, request.body, request.signature) {
+		t.Errorf("Signature %q does not verify", request.signature)
+	}
+	if Verify("wrong-secret", request.timestamp, request.body, request.signature) {
+		t.Error("Expected the signature not to verify under another secret")
+	}
+}
+
+func TestOrderWebhook_RetriesThenDeadLetters(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		attempts int
+		dlq      int
+	}{
+		{"recovers after transient failures", []int{503, 503}, 3, 0},
+		{"dead-letters once retries are exhausted", []int{503, 500, 502}, 3, 1},
+		{"dead-letters client errors without retrying", []int{400}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &webhookServer{statuses: tt.statuses}
+			bus, _ := setupWebhook(t, server)
+
+			if err := bus.GetHandler("order.failed")(context.Background(), executedOrderPayload(t, "order-2")); err != nil {
+				t.Fatalf("Expected the delivery to be acknowledged, got %v", err)
+			}
+
+			if len(server.received) != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, len(server.received))
+			}
+			dlq := bus.GetMessagesByTopic(DefaultDLQTopic)
+			if len(dlq) != tt.dlq {
+				t.Fatalf("Expected %d dead letters, got %d", tt.dlq, len(dlq))
+			}
+			if tt.dlq > 0 {
+				deadLetter := dlq[0].Message.(messagebus.DeadLetter)
+				if deadLetter.Topic != "order.failed" || deadLetter.Attempts != tt.attempts {
+					t.Errorf("Unexpected dead letter: %+v", deadLetter)
+				}
+			}
+		})
+	}
+}
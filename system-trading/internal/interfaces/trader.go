@@ -22,6 +22,9 @@ type Trader interface {
 	// CancelOrder cancels an existing order
 	CancelOrder(ctx context.Context, orderID string) error
 	
+	// ReplaceOrder changes the limit price of a resting order
+	ReplaceOrder(ctx context.Context, orderID string, newPrice float64) error
+	
 	// GetOrderStatus retrieves the current status of an order
 	GetOrderStatus(ctx context.Context, orderID string) (*OrderStatus, error)
 	
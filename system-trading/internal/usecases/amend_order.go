@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// AmendOrder changes the quantity and/or price of a live order and publishes
+// order.amended so the execution agent can replace it at the broker. Nil
+// arguments are left unchanged. newPrice is the limit price of a limit order
+// or the stop price of a stop order; market orders have no price to amend.
+func (s *OrderService) AmendOrder(ctx context.Context, orderID entities.OrderID, newQuantity *float64, newPrice *float64) error {
+	if newQuantity == nil && newPrice == nil {
+		return fmt.Errorf("amendment changes nothing")
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	switch order.Status {
+	case entities.OrderStatusExecuted:
+		return entities.ErrOrderAlreadyExecuted
+	case entities.OrderStatusCancelled:
+		return entities.ErrOrderAlreadyCancelled
+	}
+	if !order.IsLive() {
+		return fmt.Errorf("order is %s and cannot be amended", order.Status)
+	}
+
+	amended := order.Clone()
+	if newQuantity != nil {
+		amended.Quantity = *newQuantity
+	}
+	if newPrice != nil {
+		price := *newPrice
+		switch amended.Type {
+		case entities.OrderTypeLimit:
+			amended.Price = &price
+		case entities.OrderTypeStop:
+			amended.StopPrice = &price
+		default:
+			return fmt.Errorf("%s orders have no price to amend", amended.Type)
+		}
+	}
+
+	if err := s.validateAmendedOrder(amended); err != nil {
+		s.metrics.IncrementCounter("order_validation_errors", map[string]string{
+			"symbol": string(order.Symbol),
+			"error":  "amendment_invalid",
+		})
+		return fmt.Errorf("amendment validation failed: %w", err)
+	}
+
+	amended.UpdatedAt = time.Now()
+	if err := s.orderRepo.Update(ctx, amended); err != nil {
+		s.logger.Error("Failed to amend order",
+			interfaces.Field{Key: "order_id", Value: orderID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		return fmt.Errorf("failed to amend order: %w", err)
+	}
+
+	if err := s.messageBus.Publish(ctx, "order.amended", amended); err != nil {
+		s.logger.Warn("Failed to publish order amended message",
+			interfaces.Field{Key: "order_id", Value: orderID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+
+	s.metrics.IncrementCounter("orders_amended", map[string]string{
+		"symbol": string(order.Symbol),
+	})
+
+	s.logger.Info("Order amended",
+		interfaces.Field{Key: "order_id", Value: orderID},
+		interfaces.Field{Key: "quantity", Value: amended.Quantity},
+		interfaces.Field{Key: "previous_quantity", Value: order.Quantity},
+	)
+
+	return nil
+}
+
+// validateAmendedOrder applies the request checks that an amendment can break
+func (s *OrderService) validateAmendedOrder(order *entities.Order) error {
+	if order.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if order.Price != nil && *order.Price <= 0 {
+		return fmt.Errorf("price must be positive")
+	}
+	if order.StopPrice != nil && *order.StopPrice <= 0 {
+		return fmt.Errorf("stop price must be positive")
+	}
+	if s.validator != nil {
+		return s.validator.ValidateOrder(order)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func TestOrderService_AmendOrder(t *testing.T) {
+	orderRepo := repositories.NewInMemoryOrderRepository()
+	bus := messagebus.NewMockMessageBus()
+	service := NewOrderService(orderRepo, bus, newTestLogger(t), newTestMetrics("order"), nil, nil)
+	ctx := context.Background()
+
+	price := 150.0
+	order, err := service.CreateOrder(ctx, CreateOrderRequest{
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 100,
+		Price:    &price,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	t.Run("price amendment", func(t *testing.T) {
+		newPrice := 148.5
+		if err := service.AmendOrder(ctx, order.ID, nil, &newPrice); err != nil {
+			t.Fatalf("Expected the amendment to succeed, got %v", err)
+		}
+
+		stored, _ := orderRepo.GetByID(ctx, order.ID)
+		if stored.Price == nil || *stored.Price != 148.5 || stored.Quantity != 100 {
+			t.Errorf("Expected price 148.5 and unchanged quantity 100, got %v and %v", stored.Price, stored.Quantity)
+		}
+
+		amended := bus.GetMessagesByTopic("order.amended")
+		if len(amended) != 1 {
+			t.Fatalf("Expected one order.amended message, got %d", len(amended))
+		}
+		if published, ok := amended[0].Message.(*entities.Order); !ok || *published.Price != 148.5 {
+			t.Errorf("Expected the amended order to be published, got %v", amended[0].Message)
+		}
+	})
+
+	t.Run("invalid amendment rejected", func(t *testing.T) {
+		zero := 0.0
+		if err := service.AmendOrder(ctx, order.ID, &zero, nil); err == nil {
+			t.Fatal("Expected a zero quantity amendment to be rejected")
+		}
+
+		stored, _ := orderRepo.GetByID(ctx, order.ID)
+		if stored.Quantity != 100 {
+			t.Errorf("Expected the rejected amendment to leave quantity at 100, got %v", stored.Quantity)
+		}
+		if len(bus.GetMessagesByTopic("order.amended")) != 1 {
+			t.Error("Expected no order.amended message for a rejected amendment")
+		}
+	})
+
+	t.Run("executed order", func(t *testing.T) {
+		if err := service.UpdateOrderStatus(ctx, order.ID, entities.OrderStatusApproved); err != nil {
+			t.Fatalf("Failed to approve order: %v", err)
+		}
+		if err := service.ExecuteOrder(ctx, order.ID, 148.5, 100); err != nil {
+			t.Fatalf("Failed to execute order: %v", err)
+		}
+
+		quantity := 50.0
+		err := service.AmendOrder(ctx, order.ID, &quantity, nil)
+		if !errors.Is(err, entities.ErrOrderAlreadyExecuted) {
+			t.Errorf("Expected ErrOrderAlreadyExecuted, got %v", err)
+		}
+	})
+}
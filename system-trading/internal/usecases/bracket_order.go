@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// BracketOrder is an entry order with a take-profit and a stop-loss that exit
+// it. The exits are held until the entry fills and are one-cancels-other: the
+// execution agent cancels one as soon as the other fills.
+type BracketOrder struct {
+	Entry      *entities.Order `json:"entry"`
+	TakeProfit *entities.Order `json:"take_profit"`
+	StopLoss   *entities.Order `json:"stop_loss"`
+}
+
+// CreateBracketOrder creates entry like CreateOrder, plus a limit order at
+// takeProfit and a stop order at stopLoss on the opposite side for the same
+// quantity. Only the entry is proposed now; the exits are proposed by
+// ExecuteOrder once the entry fills and are cancelled with it.
+func (s *OrderService) CreateBracketOrder(ctx context.Context, entry CreateOrderRequest, takeProfit, stopLoss float64) (*BracketOrder, error) {
+	if err := validateBracketPrices(entry, takeProfit, stopLoss); err != nil {
+		return nil, fmt.Errorf("bracket validation failed: %w", err)
+	}
+
+	exitSide := entities.OrderSideSell
+	if entry.Side == entities.OrderSideSell {
+		exitSide = entities.OrderSideBuy
+	}
+	exit := func(orderType entities.OrderType) *entities.Order {
+		order := entities.NewOrder(entry.Symbol, exitSide, orderType, entry.Quantity, nil)
+		order.TimeInForce = entities.TimeInForceGTC
+		order.Source = entry.Source
+		order.PortfolioID = entry.PortfolioID
+		return order
+	}
+	takeProfitOrder := exit(entities.OrderTypeLimit)
+	takeProfitOrder.Price = &takeProfit
+	stopLossOrder := exit(entities.OrderTypeStop)
+	stopLossOrder.StopPrice = &stopLoss
+	takeProfitOrder.OCOSiblingID = stopLossOrder.ID
+	stopLossOrder.OCOSiblingID = takeProfitOrder.ID
+
+	parent, err := s.createOrder(ctx, entry, func(order *entities.Order) {
+		order.ChildOrderIDs = []entities.OrderID{takeProfitOrder.ID, stopLossOrder.ID}
+		takeProfitOrder.ParentOrderID = order.ID
+		stopLossOrder.ParentOrderID = order.ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range []*entities.Order{takeProfitOrder, stopLossOrder} {
+		if err := s.orderRepo.Create(ctx, child); err != nil {
+			s.logger.Error("Failed to create bracket exit order, cancelling entry",
+				interfaces.Field{Key: "order_id", Value: parent.ID},
+				interfaces.Field{Key: "child_order_id", Value: child.ID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			if cancelErr := s.CancelOrder(ctx, parent.ID); cancelErr != nil {
+				s.logger.Error("Failed to cancel bracket entry",
+					interfaces.Field{Key: "order_id", Value: parent.ID},
+					interfaces.Field{Key: "error", Value: cancelErr},
+				)
+			}
+			return nil, fmt.Errorf("failed to create bracket exit order: %w", err)
+		}
+	}
+
+	s.metrics.IncrementCounter("bracket_orders_created", map[string]string{
+		"symbol": string(entry.Symbol),
+		"side":   string(entry.Side),
+	})
+
+	return &BracketOrder{Entry: parent, TakeProfit: takeProfitOrder, StopLoss: stopLossOrder}, nil
+}
+
+// validateBracketPrices checks the take-profit is on the profitable side of the
+// stop-loss, and of the entry price when the entry has one
+func validateBracketPrices(entry CreateOrderRequest, takeProfit, stopLoss float64) error {
+	if takeProfit <= 0 || stopLoss <= 0 {
+		return fmt.Errorf("take-profit and stop-loss must be positive, got: %v and %v", takeProfit, stopLoss)
+	}
+
+	var reference *float64
+	switch entry.Type {
+	case entities.OrderTypeLimit:
+		reference = entry.Price
+	case entities.OrderTypeStop:
+		reference = entry.StopPrice
+	}
+
+	low, high := stopLoss, takeProfit
+	if entry.Side == entities.OrderSideSell {
+		low, high = takeProfit, stopLoss
+	}
+	if low >= high {
+		return fmt.Errorf("%s bracket needs take-profit %v and stop-loss %v on opposite sides of the entry", entry.Side, takeProfit, stopLoss)
+	}
+	if reference != nil && (*reference <= low || *reference >= high) {
+		return fmt.Errorf("entry price %v must lie between %v and %v", *reference, low, high)
+	}
+	return nil
+}
+
+// releaseBracketChildren proposes a filled entry's exit orders
+func (s *OrderService) releaseBracketChildren(ctx context.Context, parent *entities.Order) {
+	for _, childID := range parent.ChildOrderIDs {
+		child, err := s.orderRepo.GetByID(ctx, childID)
+		if err != nil {
+			s.logger.Error("Failed to load bracket exit order",
+				interfaces.Field{Key: "order_id", Value: parent.ID},
+				interfaces.Field{Key: "child_order_id", Value: childID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			continue
+		}
+		if child.Status != entities.OrderStatusPending {
+			continue
+		}
+
+		if err := s.publishOrderProposed(ctx, child); err != nil {
+			s.logger.Warn("Failed to publish bracket exit order",
+				interfaces.Field{Key: "order_id", Value: parent.ID},
+				interfaces.Field{Key: "child_order_id", Value: childID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+		}
+	}
+}
+
+// cancelBracketChildren cancels the exits of a cancelled entry
+func (s *OrderService) cancelBracketChildren(ctx context.Context, parent *entities.Order) {
+	for _, childID := range parent.ChildOrderIDs {
+		err := s.CancelOrder(ctx, childID)
+		if err == nil || errors.Is(err, entities.ErrOrderAlreadyCancelled) {
+			continue
+		}
+		s.logger.Error("Failed to cancel bracket exit order",
+			interfaces.Field{Key: "order_id", Value: parent.ID},
+			interfaces.Field{Key: "child_order_id", Value: childID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func TestOrderService_CreateBracketOrder(t *testing.T) {
+	bus := messagebus.NewMockMessageBus()
+	orderRepo := repositories.NewInMemoryOrderRepository()
+	service := NewOrderService(orderRepo, bus, newTestLogger(t), newTestMetrics("order"), nil, nil)
+	ctx := context.Background()
+
+	price := 150.0
+	entry := CreateOrderRequest{
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 100,
+		Price:    &price,
+	}
+
+	t.Run("rejects exits on the wrong side of the entry", func(t *testing.T) {
+		for _, prices := range [][2]float64{{140, 160}, {155, 152}, {170, 160}} {
+			if _, err := service.CreateBracketOrder(ctx, entry, prices[0], prices[1]); err == nil {
+				t.Errorf("Expected take-profit %v and stop-loss %v to be rejected", prices[0], prices[1])
+			}
+		}
+		if messages := bus.GetMessages(); len(messages) != 0 {
+			t.Errorf("Expected no orders proposed, got %d", len(messages))
+		}
+	})
+
+	t.Run("exits are held until the entry fills", func(t *testing.T) {
+		bracket, err := service.CreateBracketOrder(ctx, entry, 160, 140)
+		if err != nil {
+			t.Fatalf("CreateBracketOrder failed: %v", err)
+		}
+
+		takeProfit, stopLoss := bracket.TakeProfit, bracket.StopLoss
+		if takeProfit.Side != entities.OrderSideSell || takeProfit.Type != entities.OrderTypeLimit || *takeProfit.Price != 160 {
+			t.Errorf("Expected a sell limit at 160, got %s %s", takeProfit.Side, takeProfit.Type)
+		}
+		if stopLoss.Side != entities.OrderSideSell || stopLoss.Type != entities.OrderTypeStop || *stopLoss.StopPrice != 140 {
+			t.Errorf("Expected a sell stop at 140, got %s %s", stopLoss.Side, stopLoss.Type)
+		}
+		if takeProfit.OCOSiblingID != stopLoss.ID || stopLoss.OCOSiblingID != takeProfit.ID {
+			t.Error("Expected the exits to name each other as OCO siblings")
+		}
+		if takeProfit.ParentOrderID != bracket.Entry.ID || len(bracket.Entry.ChildOrderIDs) != 2 {
+			t.Errorf("Expected the entry linked to both exits, got %v", bracket.Entry.ChildOrderIDs)
+		}
+
+		proposed := bus.GetMessagesByTopic("order.proposed")
+		if len(proposed) != 1 || proposed[0].Message.(*entities.Order).ID != bracket.Entry.ID {
+			t.Fatalf("Expected only the entry proposed, got %d proposals", len(proposed))
+		}
+
+		if err := service.UpdateOrderStatus(ctx, bracket.Entry.ID, entities.OrderStatusApproved); err != nil {
+			t.Fatalf("UpdateOrderStatus failed: %v", err)
+		}
+		if err := service.ExecuteOrder(ctx, bracket.Entry.ID, 150, 100); err != nil {
+			t.Fatalf("ExecuteOrder failed: %v", err)
+		}
+
+		proposed = bus.GetMessagesByTopic("order.proposed")
+		if len(proposed) != 3 {
+			t.Fatalf("Expected both exits proposed once the entry filled, got %d proposals", len(proposed))
+		}
+		for i, want := range []entities.OrderID{takeProfit.ID, stopLoss.ID} {
+			if got := proposed[i+1].Message.(*entities.Order).ID; got != want {
+				t.Errorf("Expected proposal %d for %s, got %s", i+1, want, got)
+			}
+		}
+	})
+
+	t.Run("cancelling the entry cancels its exits", func(t *testing.T) {
+		bracket, err := service.CreateBracketOrder(ctx, entry, 160, 140)
+		if err != nil {
+			t.Fatalf("CreateBracketOrder failed: %v", err)
+		}
+		if err := service.CancelOrder(ctx, bracket.Entry.ID); err != nil {
+			t.Fatalf("CancelOrder failed: %v", err)
+		}
+
+		for _, id := range bracket.Entry.ChildOrderIDs {
+			child, err := orderRepo.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			if child.Status != entities.OrderStatusCancelled {
+				t.Errorf("Expected exit %s cancelled, got %s", id, child.Status)
+			}
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+var ErrBroadcastClosed = errors.New("broadcast closed")
+
+// BroadcastSink receives every value published to a Broadcast
+type BroadcastSink[T any] interface {
+	Name() string
+	Deliver(ctx context.Context, value T) error
+}
+
+type BroadcastConfig struct {
+	// BufferSize bounds how many values may queue for a single sink before
+	// further values are dropped for that sink
+	BufferSize int
+	// DeliveryTimeout bounds each individual Deliver call
+	DeliveryTimeout time.Duration
+}
+
+func DefaultBroadcastConfig() BroadcastConfig {
+	return BroadcastConfig{
+		BufferSize:      256,
+		DeliveryTimeout: 2 * time.Second,
+	}
+}
+
+// SinkStats counts what happened to values published to one sink
+type SinkStats struct {
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`
+	Dropped   int64 `json:"dropped"`
+}
+
+type broadcastSubscriber[T any] struct {
+	sink      BroadcastSink[T]
+	queue     chan T
+	delivered atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+}
+
+// Broadcast fans each published value out to every registered sink. Each sink
+// has its own bounded queue and delivery goroutine, so a slow sink only drops
+// its own values and never delays the others.
+type Broadcast[T any] struct {
+	name    string
+	config  BroadcastConfig
+	logger  interfaces.Logger
+	metrics interfaces.MetricsCollector
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu          sync.RWMutex
+	subscribers []*broadcastSubscriber[T]
+	closed      bool
+}
+
+func NewBroadcast[T any](name string, config BroadcastConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *Broadcast[T] {
+	defaults := DefaultBroadcastConfig()
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaults.BufferSize
+	}
+	if config.DeliveryTimeout <= 0 {
+		config.DeliveryTimeout = defaults.DeliveryTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Broadcast[T]{
+		name:    name,
+		config:  config,
+		logger:  logger,
+		metrics: metrics,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register adds a sink and starts its delivery goroutine
+func (b *Broadcast[T]) Register(sink BroadcastSink[T]) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBroadcastClosed
+	}
+	for _, sub := range b.subscribers {
+		if sub.sink.Name() == sink.Name() {
+			return fmt.Errorf("sink %s already registered", sink.Name())
+		}
+	}
+
+	sub := &broadcastSubscriber[T]{
+		sink:  sink,
+		queue: make(chan T, b.config.BufferSize),
+	}
+	b.subscribers = append(b.subscribers, sub)
+
+	b.wg.Add(1)
+	go b.deliverLoop(sub)
+	return nil
+}
+
+// Publish queues value for every sink without blocking. Sinks whose queue is
+// full drop the value.
+func (b *Broadcast[T]) Publish(value T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBroadcastClosed
+	}
+
+	for _, sub := range b.subscribers {
+		select {
+		case sub.queue <- value:
+		default:
+			sub.dropped.Add(1)
+			b.metrics.IncrementCounter("broadcast_dropped", b.labels(sub))
+			b.logger.Warn("Broadcast sink queue full, dropping value",
+				interfaces.Field{Key: "broadcast", Value: b.name},
+				interfaces.Field{Key: "sink", Value: sub.sink.Name()},
+				interfaces.Field{Key: "buffer_size", Value: b.config.BufferSize},
+			)
+		}
+	}
+	return nil
+}
+
+// Stats returns delivery counts keyed by sink name
+func (b *Broadcast[T]) Stats() map[string]SinkStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make(map[string]SinkStats, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		stats[sub.sink.Name()] = SinkStats{
+			Delivered: sub.delivered.Load(),
+			Failed:    sub.failed.Load(),
+			Dropped:   sub.dropped.Load(),
+		}
+	}
+	return stats
+}
+
+// Close stops accepting values and lets sinks flush their queues until ctx is
+// done, after which in-flight deliveries are cancelled
+func (b *Broadcast[T]) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, sub := range b.subscribers {
+		close(sub.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		<-done
+		return fmt.Errorf("broadcast %s did not flush before deadline: %w", b.name, ctx.Err())
+	}
+}
+
+func (b *Broadcast[T]) deliverLoop(sub *broadcastSubscriber[T]) {
+	defer b.wg.Done()
+
+	for value := range sub.queue {
+		if b.ctx.Err() != nil {
+			// Shutdown deadline passed; count what is left as dropped
+			sub.dropped.Add(1)
+			b.metrics.IncrementCounter("broadcast_dropped", b.labels(sub))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(b.ctx, b.config.DeliveryTimeout)
+		err := sub.sink.Deliver(ctx, value)
+		cancel()
+
+		if err != nil {
+			sub.failed.Add(1)
+			b.metrics.IncrementCounter("broadcast_failed", b.labels(sub))
+			b.logger.Error("Broadcast sink delivery failed",
+				interfaces.Field{Key: "broadcast", Value: b.name},
+				interfaces.Field{Key: "sink", Value: sub.sink.Name()},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			continue
+		}
+
+		sub.delivered.Add(1)
+		b.metrics.IncrementCounter("broadcast_delivered", b.labels(sub))
+	}
+}
+
+func (b *Broadcast[T]) labels(sub *broadcastSubscriber[T]) map[string]string {
+	return map[string]string{
+		"broadcast": b.name,
+		"sink":      sub.sink.Name(),
+	}
+}
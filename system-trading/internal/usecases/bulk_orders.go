@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// OrderError reports why the request at Index of a CreateOrders batch failed
+type OrderError struct {
+	Index  int
+	Symbol entities.Symbol
+	Err    error
+}
+
+func (e OrderError) Error() string {
+	return fmt.Sprintf("order %d (%s): %v", e.Index, e.Symbol, e.Err)
+}
+
+func (e OrderError) Unwrap() error {
+	return e.Err
+}
+
+// CreateOrders creates each request like CreateOrder without letting one bad
+// request abort the rest: it returns the orders that were created, in request
+// order, and an OrderError for each request that was not. Only created orders
+// are proposed. The error is for the batch as a whole, such as ctx being
+// cancelled before every request was attempted.
+func (s *OrderService) CreateOrders(ctx context.Context, reqs []CreateOrderRequest) ([]*entities.Order, []OrderError, error) {
+	if len(reqs) == 0 {
+		return nil, nil, errors.New("order batch is empty")
+	}
+
+	created := make([]*entities.Order, 0, len(reqs))
+	var failed []OrderError
+	for i, req := range reqs {
+		if err := ctx.Err(); err != nil {
+			s.logger.Warn("Order batch interrupted",
+				interfaces.Field{Key: "attempted", Value: i},
+				interfaces.Field{Key: "total", Value: len(reqs)},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return created, failed, fmt.Errorf("order batch interrupted after %d of %d requests: %w", i, len(reqs), err)
+		}
+
+		order, err := s.createOrder(ctx, req, nil)
+		if err != nil {
+			failed = append(failed, OrderError{Index: i, Symbol: req.Symbol, Err: err})
+			continue
+		}
+		created = append(created, order)
+	}
+
+	s.metrics.IncrementCounter("order_batches", map[string]string{
+		"outcome": batchOutcome(len(created), len(failed)),
+	})
+	if len(failed) > 0 {
+		s.logger.Warn("Order batch partially failed",
+			interfaces.Field{Key: "created", Value: len(created)},
+			interfaces.Field{Key: "failed", Value: len(failed)},
+		)
+	}
+
+	return created, failed, nil
+}
+
+func batchOutcome(created, failed int) string {
+	switch {
+	case failed == 0:
+		return "complete"
+	case created == 0:
+		return "failed"
+	default:
+		return "partial"
+	}
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func TestOrderService_CreateOrdersReportsFailuresByIndex(t *testing.T) {
+	orderRepo := repositories.NewInMemoryOrderRepository()
+	bus := messagebus.NewMockMessageBus()
+	service := NewOrderService(orderRepo, bus, newTestLogger(t), newTestMetrics("order"), nil, nil)
+
+	price := 150.0
+	reqs := []CreateOrderRequest{
+		{Symbol: "AAPL", Side: entities.OrderSideBuy, Type: entities.OrderTypeMarket, Quantity: 10},
+		{Symbol: "MSFT", Side: entities.OrderSideBuy, Type: entities.OrderTypeMarket, Quantity: 0},
+		{Symbol: "GOOG", Side: entities.OrderSideSell, Type: entities.OrderTypeLimit, Quantity: 5, Price: &price},
+		{Symbol: "TSLA", Side: entities.OrderSideBuy, Type: entities.OrderTypeLimit, Quantity: 5},
+	}
+
+	created, failed, err := service.CreateOrders(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("Expected the batch to complete, got %v", err)
+	}
+
+	if len(created) != 2 || created[0].Symbol != "AAPL" || created[1].Symbol != "GOOG" {
+		t.Fatalf("Expected AAPL and GOOG to be created, got %v", created)
+	}
+	if len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 3 {
+		t.Fatalf("Expected failures at indices 1 and 3, got %v", failed)
+	}
+	if failed[0].Symbol != "MSFT" || failed[1].Symbol != "TSLA" {
+		t.Errorf("Expected failures to name MSFT and TSLA, got %s and %s", failed[0].Symbol, failed[1].Symbol)
+	}
+
+	proposed := bus.GetMessagesByTopic("order.proposed")
+	if len(proposed) != 2 {
+		t.Errorf("Expected only the 2 created orders to be proposed, got %d", len(proposed))
+	}
+	for _, order := range created {
+		if _, err := orderRepo.GetByID(context.Background(), order.ID); err != nil {
+			t.Errorf("Expected order %s to be persisted: %v", order.ID, err)
+		}
+	}
+}
+
+func TestOrderService_CreateOrdersStopsWhenCancelled(t *testing.T) {
+	service := NewOrderService(repositories.NewInMemoryOrderRepository(), messagebus.NewMockMessageBus(),
+		newTestLogger(t), newTestMetrics("order"), nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	created, failed, err := service.CreateOrders(ctx, []CreateOrderRequest{
+		{Symbol: "AAPL", Side: entities.OrderSideBuy, Type: entities.OrderTypeMarket, Quantity: 10},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the batch to stop on a cancelled context, got %v", err)
+	}
+	if len(created) != 0 || len(failed) != 0 {
+		t.Errorf("Expected nothing attempted, got %d created and %d failed", len(created), len(failed))
+	}
+
+	if _, _, err := service.CreateOrders(context.Background(), nil); err == nil {
+		t.Error("Expected an empty batch to be rejected")
+	}
+}
@@ -0,0 +1,14 @@
+package usecases
+
+import "time"
+
+// systemClock is the default interfaces.Clock used when none is injected
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// ApplyDividend credits perShare for every share of symbol held in the
+// portfolio, recording the payment as a cash flow. The position's cost basis
+// is left alone.
+func (s *PortfolioService) ApplyDividend(ctx context.Context, portfolioID string, symbol entities.Symbol, perShare float64) error {
+	if perShare <= 0 {
+		return fmt.Errorf("dividend per share must be positive, got: %v", perShare)
+	}
+
+	var amount float64
+	portfolio, err := s.applyCorporateAction(ctx, portfolioID, func(portfolio *entities.Portfolio) error {
+		var err error
+		amount, err = portfolio.CreditDividend(symbol, perShare, time.Now())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply %s dividend: %w", symbol, err)
+	}
+
+	s.metrics.IncrementCounter("portfolio_dividends_applied", map[string]string{
+		"portfolio_id": portfolio.ID,
+		"symbol":       string(symbol),
+	})
+	s.logger.Info("Dividend applied",
+		interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+		interfaces.Field{Key: "symbol", Value: symbol},
+		interfaces.Field{Key: "per_share", Value: perShare},
+		interfaces.Field{Key: "amount", Value: amount},
+	)
+
+	return nil
+}
+
+// ApplySplit multiplies the symbol's quantity by ratio and divides its average
+// price by the same, e.g. a ratio of 2 for a 2:1 split
+func (s *PortfolioService) ApplySplit(ctx context.Context, portfolioID string, symbol entities.Symbol, ratio float64) error {
+	if ratio <= 0 {
+		return fmt.Errorf("split ratio must be positive, got: %v", ratio)
+	}
+
+	portfolio, err := s.applyCorporateAction(ctx, portfolioID, func(portfolio *entities.Portfolio) error {
+		return portfolio.Split(symbol, ratio, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply %s split: %w", symbol, err)
+	}
+
+	s.metrics.IncrementCounter("portfolio_splits_applied", map[string]string{
+		"portfolio_id": portfolio.ID,
+		"symbol":       string(symbol),
+	})
+	s.logger.Info("Split applied",
+		interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+		interfaces.Field{Key: "symbol", Value: symbol},
+		interfaces.Field{Key: "ratio", Value: ratio},
+	)
+
+	return nil
+}
+
+// applyCorporateAction loads the portfolio, applies action and saves and
+// republishes the result, holding the portfolio's lock throughout
+func (s *PortfolioService) applyCorporateAction(ctx context.Context, portfolioID string, action func(*entities.Portfolio) error) (*entities.Portfolio, error) {
+	defer s.lockPortfolio(portfolioID)()
+
+	portfolio, err := s.portfolioRepo.GetByID(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	if err := action(portfolio); err != nil {
+		return nil, err
+	}
+
+	if err := s.portfolioRepo.Save(ctx, portfolio); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio: %w", err)
+	}
+
+	if err := s.publishPortfolioUpdate(ctx, portfolio); err != nil {
+		s.logger.Warn("Failed to publish portfolio update",
+			interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+
+	s.updatePortfolioMetrics(portfolio)
+
+	return portfolio, nil
+}
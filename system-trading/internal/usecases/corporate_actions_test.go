@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func TestPortfolioService_ApplyDividend(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	bus := service.messageBus.(*messagebus.MockMessageBus)
+	ctx := context.Background()
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 100, 150, 0)
+	published := len(bus.GetMessagesByTopic("portfolio.update"))
+
+	if err := service.ApplyDividend(ctx, "default", "AAPL", 0.24); err != nil {
+		t.Fatalf("ApplyDividend failed: %v", err)
+	}
+
+	portfolio, err := repo.GetByID(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+	if math.Abs(portfolio.Cash-(85000+24)) > 1e-9 {
+		t.Errorf("Expected cash of 85024, got %v", portfolio.Cash)
+	}
+	if position := portfolio.Positions["AAPL"]; position.AveragePrice != 150 || position.Quantity != 100 {
+		t.Errorf("Expected the position unchanged at 100 @ 150, got %v @ %v", position.Quantity, position.AveragePrice)
+	}
+	if len(portfolio.CashFlows) != 1 || portfolio.CashFlows[0].Type != entities.CashFlowDividend || math.Abs(portfolio.CashFlows[0].Amount-24) > 1e-9 {
+		t.Errorf("Expected a 24 dividend cash flow, got %+v", portfolio.CashFlows)
+	}
+	if got := len(bus.GetMessagesByTopic("portfolio.update")); got != published+1 {
+		t.Errorf("Expected the dividend to publish a portfolio update, got %d new", got-published)
+	}
+
+	if err := service.ApplyDividend(ctx, "default", "MSFT", 0.5); err == nil {
+		t.Error("Expected an error for a symbol with no position")
+	}
+}
+
+func TestPortfolioService_ApplySplitPreservesMarketValue(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	ctx := context.Background()
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 100, 150, 0)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 100, 170, 0)
+	if err := service.UpdatePositionPrices(ctx, "default", &entities.MarketData{Symbol: "AAPL", Price: 180}); err != nil {
+		t.Fatalf("UpdatePositionPrices failed: %v", err)
+	}
+	before, err := repo.GetByID(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+	totalValue := before.TotalValue
+	marketValue := before.Positions["AAPL"].MarketValue
+
+	if err := service.ApplySplit(ctx, "default", "AAPL", 2); err != nil {
+		t.Fatalf("ApplySplit failed: %v", err)
+	}
+
+	portfolio, err := repo.GetByID(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+	position := portfolio.Positions["AAPL"]
+	if position.Quantity != 400 || position.AveragePrice != 80 {
+		t.Errorf("Expected 400 @ 80 after a 2:1 split, got %v @ %v", position.Quantity, position.AveragePrice)
+	}
+	if position.MarketValue != marketValue || portfolio.TotalValue != totalValue {
+		t.Errorf("Expected market value %v and total %v preserved, got %v and %v",
+			marketValue, totalValue, position.MarketValue, portfolio.TotalValue)
+	}
+
+	// The split lots still realize the original cost basis
+	executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 200, 90, 0)
+	portfolio, err = repo.GetByID(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+	if math.Abs(portfolio.TotalPnL-3000) > 1e-9 {
+		t.Errorf("Expected 3000 realized on the first lot's 200 split shares, got %v", portfolio.TotalPnL)
+	}
+}
+
+func TestPortfolioService_CorporateActionsDoNotLoseConcurrentFills(t *testing.T) {
+	repo := &slowPortfolioRepository{InMemoryPortfolioRepository: repositories.NewInMemoryPortfolioRepository(), delay: time.Millisecond}
+	portfolio := entities.NewPortfolio(100000)
+	portfolio.ID = "default"
+	if err := repo.Save(context.Background(), portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+	service := NewPortfolioService(repo, messagebus.NewMockMessageBus(), newTestLogger(t), newTestMetrics("portfolio"))
+	executeTestOrder(t, service, "MSFT", entities.OrderSideBuy, 100, 100, 0)
+
+	const rounds = 10
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 1, nil)
+			order.Execute(100, 1)
+			if err := service.ProcessOrderExecution(context.Background(), order); err != nil {
+				t.Errorf("ProcessOrderExecution failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := service.ApplyDividend(context.Background(), "default", "MSFT", 1); err != nil {
+				t.Errorf("ApplyDividend failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	final, err := service.GetPortfolio(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPortfolio failed: %v", err)
+	}
+	if position := final.Positions["AAPL"]; position == nil || position.Quantity != rounds {
+		t.Fatalf("Expected all %d fills to survive the dividends, got position %+v", rounds, position)
+	}
+	if len(final.CashFlows) != rounds {
+		t.Errorf("Expected %d dividend cash flows, got %d", rounds, len(final.CashFlows))
+	}
+	// Each round buys 100 of AAPL and receives a 100 MSFT dividend
+	if math.Abs(final.Cash-90000) > 1e-9 {
+		t.Errorf("Expected cash of 90000, got %.2f", final.Cash)
+	}
+}
@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// DayPnLResetConfig schedules the start-of-day DayPnL reset
+type DayPnLResetConfig struct {
+	// Calendar supplies the session open, in the exchange's timezone
+	Calendar     *TradingCalendar
+	PortfolioIDs []string
+	Clock        interfaces.Clock
+}
+
+// ResetDayPnL snapshots portfolioID's start-of-day value and zeroes its DayPnL,
+// leaving TotalPnL untouched
+func (s *PortfolioService) ResetDayPnL(ctx context.Context, portfolioID string) (*entities.Portfolio, error) {
+	return s.resetDayPnL(ctx, portfolioID, time.Now(), time.Time{})
+}
+
+// StartDayPnLReset resets each portfolio's DayPnL at every session open until
+// StopDayPnLReset. A reset missed while the service was down is caught up on
+// start; one already persisted for the current session is not repeated.
+func (s *PortfolioService) StartDayPnLReset(ctx context.Context, config DayPnLResetConfig) error {
+	if config.Calendar == nil {
+		return fmt.Errorf("day P&L reset requires a trading calendar")
+	}
+	resetClock := config.Clock
+	if resetClock == nil {
+		resetClock = systemClock{}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.resetCancel = cancel
+
+	s.resetWG.Add(1)
+	go func() {
+		defer s.resetWG.Done()
+		for {
+			now := resetClock.Now()
+			s.resetDueDayPnL(ctx, config.PortfolioIDs, config.Calendar.PreviousOpen(now), now)
+
+			nextOpen := config.Calendar.NextOpen(now)
+			select {
+			case <-ctx.Done():
+				return
+			case <-resetClock.After(nextOpen.Sub(now)):
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopDayPnLReset stops the reset scheduler and waits for a running reset
+func (s *PortfolioService) StopDayPnLReset() {
+	if s.resetCancel != nil {
+		s.resetCancel()
+	}
+	s.resetWG.Wait()
+}
+
+func (s *PortfolioService) resetDueDayPnL(ctx context.Context, portfolioIDs []string, sessionOpen, now time.Time) {
+	for _, portfolioID := range portfolioIDs {
+		if _, err := s.resetDayPnL(ctx, portfolioID, now, sessionOpen); err != nil {
+			s.metrics.IncrementCounter("day_pnl_reset_failures", map[string]string{
+				"portfolio_id": portfolioID,
+			})
+			s.logger.Error("Failed to reset day P&L",
+				interfaces.Field{Key: "portfolio_id", Value: portfolioID},
+				interfaces.Field{Key: "session_open", Value: sessionOpen},
+				interfaces.Field{Key: "error", Value: err},
+			)
+		}
+	}
+}
+
+// resetDayPnL starts a new day for portfolioID at at, unless its day already
+// started at or after since
+func (s *PortfolioService) resetDayPnL(ctx context.Context, portfolioID string, at, since time.Time) (*entities.Portfolio, error) {
+	defer s.lockPortfolio(portfolioID)()
+
+	portfolio, err := s.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() && !portfolio.DayStartedAt.Before(since) {
+		return portfolio, nil
+	}
+
+	previousDayPnL := portfolio.DayPnL
+	portfolio.StartDay(at)
+	if err := s.portfolioRepo.Save(ctx, portfolio); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio: %w", err)
+	}
+
+	s.metrics.IncrementCounter("day_pnl_resets", map[string]string{
+		"portfolio_id": portfolioID,
+	})
+	s.logger.Info("Day P&L reset",
+		interfaces.Field{Key: "portfolio_id", Value: portfolioID},
+		interfaces.Field{Key: "previous_day_pnl", Value: previousDayPnL},
+		interfaces.Field{Key: "start_of_day_value", Value: portfolio.StartOfDayValue},
+	)
+	return portfolio, nil
+}
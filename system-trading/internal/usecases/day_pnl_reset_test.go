@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func TestPortfolioService_ResetDayPnLKeepsTotalPnL(t *testing.T) {
+	service, _ := setupPortfolioService(t, 100000)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 10, 120, 1)
+
+	before, err := service.GetPortfolio(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPortfolio failed: %v", err)
+	}
+	totalPnL := before.TotalPnL
+	if before.DayPnL == 0 {
+		t.Fatal("Expected the round trip to leave day P&L")
+	}
+
+	portfolio, err := service.ResetDayPnL(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("ResetDayPnL failed: %v", err)
+	}
+	if portfolio.DayPnL != 0 {
+		t.Errorf("Expected day P&L to reset to zero, got %.2f", portfolio.DayPnL)
+	}
+	if math.Abs(portfolio.TotalPnL-totalPnL) > 1e-9 {
+		t.Errorf("Expected total P&L %.2f to be untouched, got %.2f", totalPnL, portfolio.TotalPnL)
+	}
+	if portfolio.StartOfDayValue != portfolio.TotalValue || portfolio.DayStartedAt.IsZero() {
+		t.Errorf("Expected a start-of-day snapshot, got value %.2f at %s", portfolio.StartOfDayValue, portfolio.DayStartedAt)
+	}
+}
+
+func TestPortfolioService_DayPnLResetsOncePerSessionOpen(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	calendar := NewTradingCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour)
+
+	setDayPnL := func(pnl float64) {
+		portfolio, err := repo.GetByID(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("Failed to get portfolio: %v", err)
+		}
+		portfolio.DayPnL = pnl
+		if err := repo.Save(context.Background(), portfolio); err != nil {
+			t.Fatalf("Failed to save portfolio: %v", err)
+		}
+	}
+	dayPnL := func() float64 {
+		portfolio, err := repo.GetByID(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("Failed to get portfolio: %v", err)
+		}
+		return portfolio.DayPnL
+	}
+	start := func(fakeClock *clock.FakeClock) {
+		t.Helper()
+		err := service.StartDayPnLReset(context.Background(), DayPnLResetConfig{
+			Calendar:     calendar,
+			PortfolioIDs: []string{"default"},
+			Clock:        fakeClock,
+		})
+		if err != nil {
+			t.Fatalf("StartDayPnLReset failed: %v", err)
+		}
+		waitFor(t, func() bool { return fakeClock.Waiters() == 1 })
+	}
+
+	// Starting mid-session catches up on the reset missed at today's open
+	setDayPnL(-500)
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 5, 11, 0, 0, 0, time.UTC))
+	start(fakeClock)
+	if pnl := dayPnL(); pnl != 0 {
+		t.Fatalf("Expected the missed open to reset day P&L, got %.2f", pnl)
+	}
+
+	// A restart later the same session keeps the day's P&L
+	setDayPnL(-200)
+	service.StopDayPnLReset()
+	fakeClock = clock.NewFakeClock(time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC))
+	start(fakeClock)
+	if pnl := dayPnL(); pnl != -200 {
+		t.Fatalf("Expected a mid-session restart not to reset again, got %.2f", pnl)
+	}
+
+	// The next open resets it
+	fakeClock.Set(time.Date(2024, 3, 6, 9, 30, 0, 0, time.UTC))
+	waitFor(t, func() bool { return fakeClock.Waiters() == 1 })
+	if pnl := dayPnL(); pnl != 0 {
+		t.Errorf("Expected the next open to reset day P&L, got %.2f", pnl)
+	}
+	service.StopDayPnLReset()
+}
+
+// slowPortfolioRepository widens the window between loading and saving a
+// portfolio so concurrent read-modify-writes overlap
+type slowPortfolioRepository struct {
+	*repositories.InMemoryPortfolioRepository
+	delay time.Duration
+}
+
+func (r *slowPortfolioRepository) GetByID(ctx context.Context, id string) (*entities.Portfolio, error) {
+	portfolio, err := r.InMemoryPortfolioRepository.GetByID(ctx, id)
+	time.Sleep(r.delay)
+	return portfolio, err
+}
+
+func TestPortfolioService_ResetDoesNotLoseConcurrentFills(t *testing.T) {
+	repo := &slowPortfolioRepository{InMemoryPortfolioRepository: repositories.NewInMemoryPortfolioRepository(), delay: time.Millisecond}
+	portfolio := entities.NewPortfolio(100000)
+	portfolio.ID = "default"
+	if err := repo.Save(context.Background(), portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+	service := NewPortfolioService(repo, messagebus.NewMockMessageBus(), newTestLogger(t), newTestMetrics("portfolio"))
+
+	const fills = 10
+	var wg sync.WaitGroup
+	for i := 0; i < fills; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 1, nil)
+			order.Execute(100, 1)
+			if err := service.ProcessOrderExecution(context.Background(), order); err != nil {
+				t.Errorf("ProcessOrderExecution failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := service.ResetDayPnL(context.Background(), "default"); err != nil {
+				t.Errorf("ResetDayPnL failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	final, err := service.GetPortfolio(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPortfolio failed: %v", err)
+	}
+	if position := final.Positions["AAPL"]; position == nil || position.Quantity != fills {
+		t.Fatalf("Expected all %d fills to survive the resets, got position %+v", fills, position)
+	}
+	if math.Abs(final.Cash-(100000-fills*100)) > 1e-9 {
+		t.Errorf("Expected cash %.2f after %d fills, got %.2f", 100000.0-fills*100, fills, final.Cash)
+	}
+}
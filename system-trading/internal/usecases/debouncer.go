@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// Debouncer collapses bursts of events per key. The first event for a quiet key
+// fires immediately and opens a window of Interval; events submitted while the
+// window is open are held, and the latest of them fires when it closes, opening
+// a new window. A key therefore fires at most once per interval, and its most
+// recent event always fires eventually.
+type Debouncer[K comparable, V any] struct {
+	interval time.Duration
+	clock    interfaces.Clock
+	fire     func(K, V)
+
+	mu      sync.Mutex
+	windows map[K]*debounceWindow[V]
+	done    chan struct{}
+	stopped bool
+}
+
+type debounceWindow[V any] struct {
+	pending    V
+	hasPending bool
+	suppressed int
+}
+
+func NewDebouncer[K comparable, V any](interval time.Duration, clock interfaces.Clock, fire func(K, V)) *Debouncer[K, V] {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &Debouncer[K, V]{
+		interval: interval,
+		clock:    clock,
+		fire:     fire,
+		windows:  make(map[K]*debounceWindow[V]),
+		done:     make(chan struct{}),
+	}
+}
+
+// Submit fires value now if key is quiet, otherwise holds it as the key's
+// trailing event. It reports whether the value fired immediately.
+func (d *Debouncer[K, V]) Submit(key K, value V) bool {
+	d.mu.Lock()
+	if d.stopped || d.interval <= 0 {
+		d.mu.Unlock()
+		d.fire(key, value)
+		return true
+	}
+
+	if window, open := d.windows[key]; open {
+		if window.hasPending {
+			window.suppressed++
+		}
+		window.pending = value
+		window.hasPending = true
+		d.mu.Unlock()
+		return false
+	}
+
+	d.windows[key] = &debounceWindow[V]{}
+	closes := d.clock.After(d.interval)
+	d.mu.Unlock()
+
+	go d.run(key, closes)
+	d.fire(key, value)
+	return true
+}
+
+// Suppressed returns how many events for key have been replaced by a later one
+// in the current window without firing
+func (d *Debouncer[K, V]) Suppressed(key K) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if window, open := d.windows[key]; open {
+		return window.suppressed
+	}
+	return 0
+}
+
+// Stop fires every held trailing event and disables debouncing; later
+// submissions fire immediately
+func (d *Debouncer[K, V]) Stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	close(d.done)
+
+	pending := make(map[K]V)
+	for key, window := range d.windows {
+		if window.hasPending {
+			pending[key] = window.pending
+		}
+	}
+	d.windows = make(map[K]*debounceWindow[V])
+	d.mu.Unlock()
+
+	for key, value := range pending {
+		d.fire(key, value)
+	}
+}
+
+// run closes key's window when closes fires, emitting the trailing event if any
+func (d *Debouncer[K, V]) run(key K, closes <-chan time.Time) {
+	for {
+		select {
+		case <-closes:
+		case <-d.done:
+			return
+		}
+
+		d.mu.Lock()
+		window, open := d.windows[key]
+		if !open || !window.hasPending {
+			delete(d.windows, key)
+			d.mu.Unlock()
+			return
+		}
+
+		value := window.pending
+		d.windows[key] = &debounceWindow[V]{}
+		closes = d.clock.After(d.interval)
+		d.mu.Unlock()
+
+		d.fire(key, value)
+	}
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/infrastructure/clock"
+)
+
+type firedEvent struct {
+	key   string
+	value int
+}
+
+type fireRecorder struct {
+	mu    sync.Mutex
+	fired []firedEvent
+}
+
+func (r *fireRecorder) record(key string, value int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fired = append(r.fired, firedEvent{key, value})
+}
+
+func (r *fireRecorder) events() []firedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]firedEvent(nil), r.fired...)
+}
+
+func TestDebouncer_CollapsesBurstAndFiresTrailingEvent(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	recorder := &fireRecorder{}
+	debouncer := NewDebouncer(time.Minute, fakeClock, recorder.record)
+	defer debouncer.Stop()
+
+	for value := 1; value <= 5; value++ {
+		fired := debouncer.Submit("AAPL", value)
+		if fired != (value == 1) {
+			t.Errorf("Submit(%d): expected immediate fire only for the first event, got %v", value, fired)
+		}
+	}
+	if got := recorder.events(); len(got) != 1 || got[0].value != 1 {
+		t.Fatalf("Expected the burst to collapse to the first event, got %v", got)
+	}
+	if suppressed := debouncer.Suppressed("AAPL"); suppressed != 3 {
+		t.Errorf("Expected 3 events replaced by later ones, got %d", suppressed)
+	}
+
+	// Another key is debounced independently
+	if !debouncer.Submit("MSFT", 100) {
+		t.Error("Expected first MSFT event to fire immediately")
+	}
+
+	waitFor(t, func() bool { return fakeClock.Waiters() == 2 })
+	fakeClock.Advance(time.Minute)
+	waitFor(t, func() bool { return len(recorder.events()) == 3 })
+
+	got := recorder.events()
+	if got[2] != (firedEvent{"AAPL", 5}) {
+		t.Errorf("Expected the latest AAPL event to fire on the trailing edge, got %v", got[2])
+	}
+
+	// After a full quiet window the key is idle again and fires immediately
+	waitFor(t, func() bool { return fakeClock.Waiters() == 1 })
+	fakeClock.Advance(time.Minute)
+	waitFor(t, func() bool {
+		debouncer.mu.Lock()
+		defer debouncer.mu.Unlock()
+		return len(debouncer.windows) == 0
+	})
+	if !debouncer.Submit("AAPL", 6) {
+		t.Error("Expected event after the quiet period to fire immediately")
+	}
+	if n := len(recorder.events()); n != 4 {
+		t.Errorf("Expected 4 events in total, got %d", n)
+	}
+}
+
+func TestDebouncer_StopFlushesHeldEvents(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	recorder := &fireRecorder{}
+	debouncer := NewDebouncer(time.Minute, fakeClock, recorder.record)
+
+	debouncer.Submit("AAPL", 1)
+	debouncer.Submit("AAPL", 2)
+	debouncer.Stop()
+
+	got := recorder.events()
+	if len(got) != 2 || got[1].value != 2 {
+		t.Fatalf("Expected Stop to deliver the held event, got %v", got)
+	}
+	if !debouncer.Submit("AAPL", 3) || len(recorder.events()) != 3 {
+		t.Error("Expected submissions after Stop to fire immediately")
+	}
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import "sync"
+
+// DrawdownTracker follows peak equity and the peak-to-trough drawdown from it
+type DrawdownTracker struct {
+	mu          sync.Mutex
+	peak        float64
+	current     float64
+	maxDrawdown float64
+}
+
+func NewDrawdownTracker() *DrawdownTracker {
+	return &DrawdownTracker{}
+}
+
+// Observe records an equity reading and returns the current drawdown as a
+// fraction of the peak
+func (t *DrawdownTracker) Observe(equity float64) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if equity > t.peak {
+		t.peak = equity
+	}
+	t.current = 0
+	if t.peak > 0 {
+		t.current = (t.peak - equity) / t.peak
+	}
+	if t.current > t.maxDrawdown {
+		t.maxDrawdown = t.current
+	}
+	return t.current
+}
+
+func (t *DrawdownTracker) Peak() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.peak
+}
+
+func (t *DrawdownTracker) Drawdown() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+// MaxDrawdown is the deepest drawdown observed since the last Reset
+func (t *DrawdownTracker) MaxDrawdown() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.maxDrawdown
+}
+
+// Reset starts tracking afresh from equity, e.g. after an operator resumes trading
+func (t *DrawdownTracker) Reset(equity float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.peak = equity
+	t.current = 0
+	t.maxDrawdown = 0
+}
@@ -0,0 +1,269 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// ProjectedTopics are the events folded into the read-side view
+var ProjectedTopics = []string{
+	"order.proposed",
+	"order.approved",
+	"order.executed",
+	"order.rejected",
+	"order.failed",
+	"order.cancelled",
+	"order.abandoned",
+	"order.expired",
+	"portfolio.update",
+}
+
+// EventProjector is the CQRS read side: it records order and portfolio events in
+// an event store and folds them into an in-memory view of open orders and positions.
+type EventProjector struct {
+	messageBus interfaces.MessageBus
+	eventStore interfaces.EventStore
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+
+	mu         sync.RWMutex
+	openOrders map[entities.OrderID]*OrderView
+	positions  map[string]map[entities.Symbol]float64
+}
+
+func NewEventProjector(
+	messageBus interfaces.MessageBus,
+	eventStore interfaces.EventStore,
+	logger interfaces.Logger,
+	metrics interfaces.MetricsCollector,
+) *EventProjector {
+	return &EventProjector{
+		messageBus: messageBus,
+		eventStore: eventStore,
+		logger:     logger,
+		metrics:    metrics,
+		openOrders: make(map[entities.OrderID]*OrderView),
+		positions:  make(map[string]map[entities.Symbol]float64),
+	}
+}
+
+// Start rebuilds the view from the event store and then subscribes to live events
+func (p *EventProjector) Start(ctx context.Context) error {
+	if err := p.Rebuild(ctx); err != nil {
+		return fmt.Errorf("failed to rebuild projection: %w", err)
+	}
+
+	for _, topic := range ProjectedTopics {
+		if err := p.messageBus.Subscribe(ctx, topic, p.handlerFor(topic)); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+// Rebuild discards the current view and replays every stored event
+func (p *EventProjector) Rebuild(ctx context.Context) error {
+	events, err := p.eventStore.Load(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to load events: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.openOrders = make(map[entities.OrderID]*OrderView)
+	p.positions = make(map[string]map[entities.Symbol]float64)
+
+	for _, event := range events {
+		if err := p.applyLocked(event); err != nil {
+			p.logger.Warn("Skipping unprojectable event during rebuild",
+				interfaces.Field{Key: "sequence", Value: event.Sequence},
+				interfaces.Field{Key: "topic", Value: event.Topic},
+				interfaces.Field{Key: "error", Value: err},
+			)
+		}
+	}
+
+	p.logger.Info("Projection rebuilt from event store",
+		interfaces.Field{Key: "events", Value: len(events)},
+		interfaces.Field{Key: "open_orders", Value: len(p.openOrders)},
+	)
+
+	return nil
+}
+
+// OpenOrders returns the orders that have been proposed or approved but not yet
+// reached a terminal state, least recently updated first
+func (p *EventProjector) OpenOrders() []OrderView {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	orders := make([]OrderView, 0, len(p.openOrders))
+	for _, order := range p.openOrders {
+		view := *order
+		if order.Price != nil {
+			price := *order.Price
+			view.Price = &price
+		}
+		orders = append(orders, view)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].UpdatedAt.Equal(orders[j].UpdatedAt) {
+			return orders[i].UpdatedAt.Before(orders[j].UpdatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+
+	return orders
+}
+
+// Positions returns the last projected position quantities for a portfolio
+func (p *EventProjector) Positions(portfolioID string) map[entities.Symbol]float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	positions := make(map[entities.Symbol]float64, len(p.positions[portfolioID]))
+	for symbol, quantity := range p.positions[portfolioID] {
+		positions[symbol] = quantity
+	}
+	return positions
+}
+
+func (p *EventProjector) handlerFor(topic string) interfaces.MessageHandler {
+	return func(ctx context.Context, data []byte) error {
+		// The store keeps JSON whatever codec the event was published with
+		data, err := interfaces.MessageJSON(ctx, data)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", topic, err)
+		}
+		event := &interfaces.StoredEvent{
+			Topic:      topic,
+			Data:       json.RawMessage(data),
+			RecordedAt: time.Now(),
+		}
+		if err := p.eventStore.Append(ctx, event); err != nil {
+			return fmt.Errorf("failed to store %s event: %w", topic, err)
+		}
+
+		p.mu.Lock()
+		err = p.applyLocked(event)
+		p.mu.Unlock()
+
+		if err != nil {
+			return fmt.Errorf("failed to project %s event: %w", topic, err)
+		}
+
+		p.metrics.IncrementCounter("projector_events_applied", map[string]string{
+			"topic": topic,
+		})
+		return nil
+	}
+}
+
+func (p *EventProjector) applyLocked(event *interfaces.StoredEvent) error {
+	if event.Topic == "portfolio.update" {
+		var update PortfolioUpdateMessage
+		if err := json.Unmarshal(event.Data, &update); err != nil {
+			return fmt.Errorf("invalid portfolio update: %w", err)
+		}
+
+		if update.Mode == PortfolioUpdateDelta {
+			positions, ok := p.positions[update.PortfolioID]
+			if !ok {
+				positions = make(map[entities.Symbol]float64, len(update.Positions))
+				p.positions[update.PortfolioID] = positions
+			}
+			for symbol, quantity := range update.Positions {
+				if quantity == 0 {
+					delete(positions, symbol)
+					continue
+				}
+				positions[symbol] = quantity
+			}
+			return nil
+		}
+
+		positions := make(map[entities.Symbol]float64, len(update.Positions))
+		for symbol, quantity := range update.Positions {
+			positions[symbol] = quantity
+		}
+		p.positions[update.PortfolioID] = positions
+		return nil
+	}
+
+	var payload struct {
+		ID       entities.OrderID   `json:"id"`
+		OrderID  entities.OrderID   `json:"order_id"`
+		Symbol   entities.Symbol    `json:"symbol"`
+		Side     entities.OrderSide `json:"side"`
+		Type     entities.OrderType `json:"type"`
+		Quantity float64            `json:"quantity"`
+		Price    *float64           `json:"price"`
+	}
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		return fmt.Errorf("invalid order event: %w", err)
+	}
+
+	orderID := payload.ID
+	if orderID == "" {
+		orderID = payload.OrderID
+	}
+	if orderID == "" {
+		return fmt.Errorf("order event has no order ID")
+	}
+
+	var status entities.OrderStatus
+	switch event.Topic {
+	case "order.proposed":
+		status = entities.OrderStatusPending
+	case "order.approved":
+		status = entities.OrderStatusApproved
+	default:
+		delete(p.openOrders, orderID)
+		return nil
+	}
+
+	view, exists := p.openOrders[orderID]
+	if !exists {
+		view = &OrderView{ID: orderID}
+		p.openOrders[orderID] = view
+	}
+	if payload.Symbol != "" {
+		view.Symbol = payload.Symbol
+	}
+	if payload.Side != "" {
+		view.Side = payload.Side
+	}
+	if payload.Type != "" {
+		view.Type = payload.Type
+	}
+	if payload.Quantity > 0 {
+		view.Quantity = payload.Quantity
+	}
+	if payload.Price != nil {
+		view.Price = payload.Price
+	}
+	view.Status = status
+	view.UpdatedAt = event.RecordedAt
+
+	return nil
+}
+
+type OrderView struct {
+	ID        entities.OrderID     `json:"id"`
+	Symbol    entities.Symbol      `json:"symbol"`
+	Side      entities.OrderSide   `json:"side"`
+	Type      entities.OrderType   `json:"type"`
+	Quantity  float64              `json:"quantity"`
+	Price     *float64             `json:"price,omitempty"`
+	Status    entities.OrderStatus `json:"status"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
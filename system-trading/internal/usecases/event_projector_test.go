@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func deliver(t *testing.T, bus *messagebus.MockMessageBus, topic string, message interface{}) {
+	t.Helper()
+
+	handler := bus.GetHandler(topic)
+	if handler == nil {
+		t.Fatalf("No handler registered for %s", topic)
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Failed to marshal %s event: %v", topic, err)
+	}
+	if err := handler(context.Background(), data); err != nil {
+		t.Fatalf("Handler for %s failed: %v", topic, err)
+	}
+}
+
+func TestEventProjector_ProjectsAndRebuilds(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewInMemoryEventStore()
+	bus := messagebus.NewMockMessageBus()
+
+	projector := NewEventProjector(bus, store, newTestLogger(t), newTestMetrics("projector"))
+	if err := projector.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	price := 150.0
+	aapl := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, 10, &price)
+	msft := entities.NewOrder("MSFT", entities.OrderSideBuy, entities.OrderTypeMarket, 5, nil)
+	tsla := entities.NewOrder("TSLA", entities.OrderSideSell, entities.OrderTypeMarket, 3, nil)
+
+	deliver(t, bus, "order.proposed", aapl)
+	deliver(t, bus, "order.proposed", msft)
+	deliver(t, bus, "order.proposed", tsla)
+	deliver(t, bus, "order.approved", aapl)
+	deliver(t, bus, "order.approved", msft)
+	deliver(t, bus, "order.executed", map[string]interface{}{"order_id": string(msft.ID), "symbol": "MSFT"})
+	deliver(t, bus, "order.rejected", tsla)
+	deliver(t, bus, "portfolio.update", PortfolioUpdateMessage{
+		PortfolioID: "default",
+		Positions:   map[entities.Symbol]float64{"MSFT": 5},
+	})
+
+	assertView := func(t *testing.T, projector *EventProjector) {
+		t.Helper()
+
+		open := projector.OpenOrders()
+		if len(open) != 1 {
+			t.Fatalf("Expected 1 open order, got %d: %+v", len(open), open)
+		}
+		if open[0].ID != aapl.ID || open[0].Status != entities.OrderStatusApproved || open[0].Symbol != "AAPL" {
+			t.Errorf("Unexpected open order: %+v", open[0])
+		}
+		if open[0].Price == nil || *open[0].Price != 150 || open[0].Quantity != 10 {
+			t.Errorf("Expected open order price 150 and quantity 10, got %+v", open[0])
+		}
+
+		positions := projector.Positions("default")
+		if len(positions) != 1 || positions["MSFT"] != 5 {
+			t.Errorf("Expected MSFT position of 5, got %v", positions)
+		}
+	}
+
+	assertView(t, projector)
+
+	// A fresh projector over the same store rebuilds the same view on startup
+	rebuilt := NewEventProjector(messagebus.NewMockMessageBus(), store, newTestLogger(t), newTestMetrics("projector"))
+	if err := rebuilt.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	assertView(t, rebuilt)
+}
+
+func TestEventProjector_AppliesDeltaPortfolioUpdates(t *testing.T) {
+	bus := messagebus.NewMockMessageBus()
+	projector := NewEventProjector(bus, repositories.NewInMemoryEventStore(), newTestLogger(t), newTestMetrics("projector"))
+	if err := projector.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	deliver(t, bus, "portfolio.update", PortfolioUpdateMessage{
+		PortfolioID: "default",
+		Mode:        PortfolioUpdateSnapshot,
+		Positions:   map[entities.Symbol]float64{"AAPL": 10, "MSFT": 5},
+	})
+	deliver(t, bus, "portfolio.update", PortfolioUpdateMessage{
+		PortfolioID: "default",
+		Mode:        PortfolioUpdateDelta,
+		Positions:   map[entities.Symbol]float64{"AAPL": 0, "TSLA": 3},
+	})
+
+	positions := projector.Positions("default")
+	if len(positions) != 2 || positions["MSFT"] != 5 || positions["TSLA"] != 3 {
+		t.Errorf("Expected MSFT 5 and TSLA 3 after delta, got %v", positions)
+	}
+}
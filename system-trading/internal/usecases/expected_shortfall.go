@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// CalculateExpectedShortfall returns the average loss beyond the VaR cutoff at
+// confidenceLevel, using the parametric closed form ES = σ·φ(z)/(1-c). The
+// confidence level must lie in (0.5, 1): at 0.5 the z-score is zero and σ
+// cannot be recovered from VaR.
+func (s *RiskService) CalculateExpectedShortfall(ctx context.Context, portfolioID string, confidenceLevel float64) (float64, error) {
+	if confidenceLevel <= 0.5 || confidenceLevel >= 1 {
+		return 0, fmt.Errorf("confidence level must be between 0.5 and 1, got %v", confidenceLevel)
+	}
+
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	return s.calculateExpectedShortfall(ctx, portfolio, confidenceLevel), nil
+}
+
+func (s *RiskService) calculateExpectedShortfall(ctx context.Context, portfolio *entities.Portfolio, confidenceLevel float64) float64 {
+	zScore := s.getZScore(confidenceLevel)
+	// Parametric VaR is σ·z, so dividing out z recovers the portfolio σ
+	sigma := s.calculateVaR(ctx, portfolio, confidenceLevel) / zScore
+
+	return sigma * normalPDF(zScore) / (1 - confidenceLevel)
+}
+
+// normalPDF is the standard normal density
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
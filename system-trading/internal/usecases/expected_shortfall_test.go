@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+func TestRiskService_ExpectedShortfallExceedsVaR(t *testing.T) {
+	// A $10,000 position at the default 2% daily volatility has σ = $200
+	const sigma = 10000 * 0.02
+
+	tests := []struct {
+		confidence float64
+		zScore     float64
+		// density is φ(z)
+		density float64
+	}{
+		{0.95, z95, 0.103136},
+		{0.99, z99, 0.026652},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0f%%", tt.confidence*100), func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+			portfolio := seedPortfolio(t, f.portfolioRepo, "default", 0, map[entities.Symbol][2]float64{
+				"AAPL": {100, 100},
+			})
+
+			es, err := f.service.CalculateExpectedShortfall(context.Background(), "default", tt.confidence)
+			if err != nil {
+				t.Fatalf("CalculateExpectedShortfall failed: %v", err)
+			}
+
+			want := sigma * tt.density / (1 - tt.confidence)
+			if math.Abs(es-want) > 0.01 {
+				t.Errorf("Expected ES %.2f at %.0f%%, got %.2f", want, tt.confidence*100, es)
+			}
+			if vaR := f.service.calculateVaR(context.Background(), portfolio, tt.confidence); es <= vaR {
+				t.Errorf("Expected ES %.2f to exceed VaR %.2f at %.0f%%", es, vaR, tt.confidence*100)
+			} else if math.Abs(vaR-sigma*tt.zScore) > 1e-6 {
+				t.Errorf("Expected VaR %.2f, got %.2f", sigma*tt.zScore, vaR)
+			}
+		})
+	}
+}
+
+func TestRiskService_PortfolioRiskIncludesExpectedShortfall(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	seedPortfolio(t, f.portfolioRepo, "default", 0, map[entities.Symbol][2]float64{
+		"AAPL": {100, 100},
+	})
+
+	risk, err := f.service.CalculatePortfolioRisk(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("CalculatePortfolioRisk failed: %v", err)
+	}
+	es, err := f.service.CalculateExpectedShortfall(context.Background(), "default", 0.95)
+	if err != nil {
+		t.Fatalf("CalculateExpectedShortfall failed: %v", err)
+	}
+	if risk.ExpectedShortfall != es {
+		t.Errorf("Expected PortfolioRisk to carry the 95%% ES %.2f, got %.2f", es, risk.ExpectedShortfall)
+	}
+
+	for _, confidence := range []float64{0.3, 0.5, 1} {
+		es, err := f.service.CalculateExpectedShortfall(context.Background(), "default", confidence)
+		if err == nil {
+			t.Errorf("Expected a confidence level of %v to be rejected, got ES %v", confidence, es)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// HealthRegistry is the single place component health is defined. Readiness
+// probes and the system.health publisher both read it through Check.
+type HealthRegistry struct {
+	defaultTimeout time.Duration
+	clock          interfaces.Clock
+
+	mu     sync.RWMutex
+	checks []registeredCheck
+}
+
+type registeredCheck struct {
+	name    string
+	timeout time.Duration
+	check   interfaces.CheckFunc
+}
+
+// NewHealthRegistry creates a registry whose checks time out after
+// defaultTimeout unless registered with their own timeout
+func NewHealthRegistry(defaultTimeout time.Duration, clock interfaces.Clock) *HealthRegistry {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &HealthRegistry{
+		defaultTimeout: defaultTimeout,
+		clock:          clock,
+	}
+}
+
+// Register adds a check that runs with the registry's default timeout
+func (r *HealthRegistry) Register(name string, check interfaces.CheckFunc) {
+	r.RegisterWithTimeout(name, r.defaultTimeout, check)
+}
+
+// RegisterWithTimeout adds a check that is reported unhealthy if it has not
+// returned within timeout. Registering a name again replaces its check.
+func (r *HealthRegistry) RegisterWithTimeout(name string, timeout time.Duration, check interfaces.CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	registered := registeredCheck{name: name, timeout: timeout, check: check}
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks[i] = registered
+			return
+		}
+	}
+	r.checks = append(r.checks, registered)
+}
+
+// Check runs every registered check concurrently and aggregates the results in
+// registration order. A hung check only costs its own timeout.
+func (r *HealthRegistry) Check(ctx context.Context) interfaces.HealthReport {
+	r.mu.RLock()
+	checks := make([]registeredCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]interfaces.HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check registeredCheck) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := interfaces.HealthReport{
+		Status:    interfaces.HealthStatusHealthy,
+		Checks:    results,
+		Timestamp: r.clock.Now(),
+	}
+	for _, result := range results {
+		report.Status = worseHealth(report.Status, result.Status)
+	}
+	return report
+}
+
+func (r *HealthRegistry) run(ctx context.Context, check registeredCheck) interfaces.HealthCheckResult {
+	if check.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		status interfaces.HealthStatus
+		detail string
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		status, detail := check.check(ctx)
+		done <- outcome{status, detail}
+	}()
+
+	result := interfaces.HealthCheckResult{Name: check.name}
+	select {
+	case out := <-done:
+		result.Status, result.Detail = out.status, out.detail
+	case <-ctx.Done():
+		result.Status = interfaces.HealthStatusUnhealthy
+		result.Detail = fmt.Sprintf("check did not complete: %v", ctx.Err())
+	}
+	result.Latency = time.Since(start)
+	return result
+}
+
+func worseHealth(a, b interfaces.HealthStatus) interfaces.HealthStatus {
+	rank := func(status interfaces.HealthStatus) int {
+		switch status {
+		case interfaces.HealthStatusHealthy:
+			return 0
+		case interfaces.HealthStatusDegraded:
+			return 1
+		default:
+			// Unknown statuses are treated as failures
+			return 2
+		}
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
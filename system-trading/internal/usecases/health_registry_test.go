@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func staticCheck(status interfaces.HealthStatus, detail string) interfaces.CheckFunc {
+	return func(ctx context.Context) (interfaces.HealthStatus, string) {
+		return status, detail
+	}
+}
+
+func TestHealthRegistry_AggregatesWorstStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []interfaces.HealthStatus
+		want   interfaces.HealthStatus
+	}{
+		{"no checks", nil, interfaces.HealthStatusHealthy},
+		{"all healthy", []interfaces.HealthStatus{interfaces.HealthStatusHealthy, interfaces.HealthStatusHealthy}, interfaces.HealthStatusHealthy},
+		{"one degraded", []interfaces.HealthStatus{interfaces.HealthStatusHealthy, interfaces.HealthStatusDegraded}, interfaces.HealthStatusDegraded},
+		{"unhealthy wins", []interfaces.HealthStatus{interfaces.HealthStatusUnhealthy, interfaces.HealthStatusDegraded}, interfaces.HealthStatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewHealthRegistry(time.Second, nil)
+			for i, status := range tt.checks {
+				registry.Register(string(rune('a'+i)), staticCheck(status, ""))
+			}
+
+			report := registry.Check(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Expected aggregate %s, got %s", tt.want, report.Status)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("Expected %d check results, got %d", len(tt.checks), len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestHealthRegistry_HungCheckTimesOut(t *testing.T) {
+	registry := NewHealthRegistry(time.Second, nil)
+	registry.Register("bus", staticCheck(interfaces.HealthStatusHealthy, "connected"))
+
+	release := make(chan struct{})
+	defer close(release)
+	registry.RegisterWithTimeout("hung", 20*time.Millisecond, func(ctx context.Context) (interfaces.HealthStatus, string) {
+		<-release
+		return interfaces.HealthStatusHealthy, ""
+	})
+
+	start := time.Now()
+	report := registry.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Hung check blocked the aggregate for %v", elapsed)
+	}
+
+	if report.Status != interfaces.HealthStatusUnhealthy {
+		t.Errorf("Expected aggregate unhealthy, got %s", report.Status)
+	}
+	if report.Checks[0].Name != "bus" || report.Checks[0].Status != interfaces.HealthStatusHealthy || report.Checks[0].Detail != "connected" {
+		t.Errorf("Unexpected bus result: %+v", report.Checks[0])
+	}
+	if report.Checks[1].Name != "hung" || report.Checks[1].Status != interfaces.HealthStatusUnhealthy {
+		t.Errorf("Expected hung check reported unhealthy, got %+v", report.Checks[1])
+	}
+}
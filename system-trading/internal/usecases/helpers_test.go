@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/system-trading/core/internal/infrastructure/config"
+	"github.com/system-trading/core/internal/infrastructure/logger"
+	"github.com/system-trading/core/internal/infrastructure/metrics"
+)
+
+func newTestLogger(t *testing.T) *logger.ZapLogger {
+	t.Helper()
+
+	testLogger, err := logger.NewZapLogger(config.LoggingConfig{
+		Level:  "error",
+		Format: "json",
+		Output: "stdout",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test logger: %v", err)
+	}
+	return testLogger
+}
+
+// newTestMetrics uses a unique service name to avoid registration conflicts
+func newTestMetrics(name string) *metrics.PrometheusMetrics {
+	return metrics.NewPrometheusMetricsWithRegistry("test-"+name, prometheus.NewRegistry())
+}
+
+// waitFor polls cond until it holds, failing the test after two seconds
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
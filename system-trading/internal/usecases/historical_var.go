@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+type VaRMethod string
+
+const (
+	// VaRMethodParametric assumes normally distributed returns and scales
+	// volatility by a z-score
+	VaRMethodParametric VaRMethod = "parametric"
+	// VaRMethodHistorical revalues the portfolio against past daily returns and
+	// takes a percentile of the resulting losses
+	VaRMethodHistorical VaRMethod = "historical"
+)
+
+// DefaultHistoricalVaRDays is how many days of returns historical VaR replays
+const DefaultHistoricalVaRDays = 250
+
+// portfolioVaR computes VaR with the configured method. Historical VaR falls
+// back to parametric when there is no repository or no usable history.
+func (s *RiskService) portfolioVaR(ctx context.Context, portfolio *entities.Portfolio, confidenceLevel float64) float64 {
+	if s.config.VaRMethod == VaRMethodHistorical {
+		if value, ok := s.calculateHistoricalVaR(ctx, portfolio, confidenceLevel); ok {
+			return value
+		}
+	}
+	return s.calculateVaR(ctx, portfolio, confidenceLevel)
+}
+
+// calculateHistoricalVaR applies each of the last HistoricalVaRDays days of
+// returns to the current positions and returns the loss at confidenceLevel.
+// Only days with a return for every held symbol are used.
+func (s *RiskService) calculateHistoricalVaR(ctx context.Context, portfolio *entities.Portfolio, confidenceLevel float64) (float64, bool) {
+	if len(portfolio.Positions) == 0 {
+		return 0, true
+	}
+	if s.marketData == nil {
+		return 0, false
+	}
+
+	to := s.clock.Now()
+	// One extra day supplies the close the first return is measured from
+	from := to.AddDate(0, 0, -(s.config.HistoricalVaRDays + 1))
+
+	var scenarioDays []time.Time
+	returns := make(map[entities.Symbol]map[time.Time]float64, len(portfolio.Positions))
+	for symbol := range portfolio.Positions {
+		history, err := s.marketData.GetMarketDataHistory(ctx, symbol, from, to)
+		if err != nil {
+			s.logger.Warn("Falling back to parametric VaR",
+				interfaces.Field{Key: "symbol", Value: symbol},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			return 0, false
+		}
+		symbolReturns, days := dailyReturns(history)
+		returns[symbol] = symbolReturns
+		if scenarioDays == nil {
+			scenarioDays = days
+		}
+	}
+
+	var losses []float64
+	for _, day := range scenarioDays {
+		pnl := 0.0
+		complete := true
+		for symbol, position := range portfolio.Positions {
+			r, exists := returns[symbol][day]
+			if !exists {
+				complete = false
+				break
+			}
+			pnl += position.MarketValue * r
+		}
+		if complete {
+			losses = append(losses, -pnl)
+		}
+	}
+	if len(losses) == 0 {
+		return 0, false
+	}
+
+	sort.Float64s(losses)
+	index := int(math.Ceil(confidenceLevel*float64(len(losses)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return math.Max(losses[index], 0), true
+}
+
+// dailyReturns turns price history into simple returns between consecutive
+// daily closes, keyed by the day of the later close. It also returns those
+// days in order.
+func dailyReturns(history []*entities.MarketData) (map[time.Time]float64, []time.Time) {
+	closes := make(map[time.Time]*entities.MarketData)
+	for _, data := range history {
+		if data == nil || data.Price <= 0 {
+			continue
+		}
+		day := data.Timestamp.UTC().Truncate(24 * time.Hour)
+		if last, exists := closes[day]; !exists || !data.Timestamp.Before(last.Timestamp) {
+			closes[day] = data
+		}
+	}
+
+	days := make([]time.Time, 0, len(closes))
+	for day := range closes {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	returns := make(map[time.Time]float64, len(days))
+	for i := 1; i < len(days); i++ {
+		returns[days[i]] = closes[days[i]].Price/closes[days[i-1]].Price - 1
+	}
+	if len(days) > 0 {
+		days = days[1:]
+	}
+	return returns, days
+}
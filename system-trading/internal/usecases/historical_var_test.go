@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+func TestRiskService_HistoricalVaRTakesLossPercentile(t *testing.T) {
+	// Twenty daily returns on a $10,000 position: eighteen gains, a 3% loss and
+	// a 5% loss. The sorted losses end in ..., $300, $500, so the 95th percentile
+	// (the 19th of 20) is $300 and the 99th (the 20th) is $500.
+	returns := []float64{
+		0.005, 0.005, 0.005, 0.005, 0.005, 0.005, 0.005, -0.05, 0.005, 0.005,
+		0.005, 0.005, 0.005, -0.03, 0.005, 0.005, 0.005, 0.005, 0.005, 0.005,
+	}
+
+	tests := []struct {
+		name   string
+		method VaRMethod
+		want95 float64
+		want99 float64
+	}{
+		{"historical", VaRMethodHistorical, 300, 500},
+		{"parametric ignores the history", VaRMethodParametric, 10000 * 0.02 * z95, 10000 * 0.02 * z99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{VaRMethod: tt.method})
+			seedPortfolio(t, f.portfolioRepo, "default", 0, map[entities.Symbol][2]float64{
+				"AAPL": {100, 100},
+			})
+			lastClose := f.clock.Now().Truncate(24 * time.Hour).Add(-8 * time.Hour)
+			f.service.SetMarketDataRepository(&historyRepository{history: dailySimplePrices("AAPL", lastClose, returns)})
+
+			risk, err := f.service.CalculatePortfolioRisk(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("CalculatePortfolioRisk failed: %v", err)
+			}
+			if math.Abs(risk.TotalVaR-tt.want95) > 1e-6 {
+				t.Errorf("Expected 95%% VaR %.4f, got %.4f", tt.want95, risk.TotalVaR)
+			}
+
+			portfolio, err := f.portfolios.GetPortfolio(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("Failed to get portfolio: %v", err)
+			}
+			if var99 := f.service.portfolioVaR(context.Background(), portfolio, 0.99); math.Abs(var99-tt.want99) > 1e-6 {
+				t.Errorf("Expected 99%% VaR %.4f, got %.4f", tt.want99, var99)
+			}
+		})
+	}
+}
+
+// dailySimplePrices builds one close per day ending at end that moves by the
+// given simple returns
+func dailySimplePrices(symbol entities.Symbol, end time.Time, returns []float64) []*entities.MarketData {
+	start := end.AddDate(0, 0, -len(returns))
+	price := 100.0
+	history := []*entities.MarketData{{Symbol: symbol, Price: price, Timestamp: start}}
+	for i, r := range returns {
+		price *= 1 + r
+		history = append(history, &entities.MarketData{
+			Symbol:    symbol,
+			Price:     price,
+			Timestamp: start.AddDate(0, 0, i+1),
+		})
+	}
+	return history
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
@@ -13,6 +14,9 @@ type OrderRepository interface {
 	Update(ctx context.Context, order *entities.Order) error
 	List(ctx context.Context, filters OrderFilters) ([]*entities.Order, error)
 	Delete(ctx context.Context, id entities.OrderID) error
+	// ListExpiring returns live GTD and DAY orders expiring at or before the given time,
+	// earliest first, up to limit (zero means no limit)
+	ListExpiring(ctx context.Context, before time.Time, limit int) ([]*entities.Order, error)
 }
 
 type PortfolioRepository interface {
@@ -21,6 +25,14 @@ type PortfolioRepository interface {
 	UpdatePositions(ctx context.Context, portfolio *entities.Portfolio) error
 }
 
+// PortfolioSnapshotRepository stores point-in-time portfolio values for
+// performance reporting
+type PortfolioSnapshotRepository interface {
+	SaveSnapshot(ctx context.Context, snapshot *entities.PortfolioSnapshot) error
+	// ListSnapshots returns portfolioID's snapshots taken in [from, to]
+	ListSnapshots(ctx context.Context, portfolioID string, from, to time.Time) ([]*entities.PortfolioSnapshot, error)
+}
+
 type MarketDataRepository interface {
 	SaveMarketData(ctx context.Context, data *entities.MarketData) error
 	GetLatestMarketData(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error)
@@ -38,6 +50,30 @@ type AnalysisRepository interface {
 	GetSentimentScores(ctx context.Context, symbol entities.Symbol, from time.Time) ([]*entities.SentimentScore, error)
 }
 
+type AuditRepository interface {
+	SaveAuditRecord(ctx context.Context, record *AuditRecord) error
+	ListAuditRecords(ctx context.Context, recordType string, from time.Time) ([]*AuditRecord, error)
+}
+
+type AuditRecord struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+type EventStore interface {
+	Append(ctx context.Context, event *StoredEvent) error
+	Load(ctx context.Context, afterSequence int64) ([]*StoredEvent, error)
+}
+
+type StoredEvent struct {
+	Sequence   int64           `json:"sequence"`
+	Topic      string          `json:"topic"`
+	Data       json.RawMessage `json:"data"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
+
 type OrderFilters struct {
 	Symbol     *entities.Symbol
 	Status     *entities.OrderStatus
@@ -2,6 +2,8 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/system-trading/core/internal/entities"
 )
@@ -14,6 +16,40 @@ type MessageBus interface {
 
 type MessageHandler func(ctx context.Context, message []byte) error
 
+// MessageDecoder decodes a message payload into v
+type MessageDecoder func(data []byte, v interface{}) error
+
+type messageDecoderKey struct{}
+
+// WithMessageDecoder attaches the decoder for the payload a bus hands to a
+// MessageHandler along with ctx
+func WithMessageDecoder(ctx context.Context, decode MessageDecoder) context.Context {
+	return context.WithValue(ctx, messageDecoderKey{}, decode)
+}
+
+// DecodeMessage decodes a MessageHandler's payload into v with the decoder the
+// bus attached to ctx; a payload without one is JSON
+func DecodeMessage(ctx context.Context, data []byte, v interface{}) error {
+	if decode, ok := ctx.Value(messageDecoderKey{}).(MessageDecoder); ok {
+		return decode(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// MessageJSON returns a MessageHandler's payload as JSON, for handlers that
+// store or forward it rather than decode it
+func MessageJSON(ctx context.Context, data []byte) ([]byte, error) {
+	decode, ok := ctx.Value(messageDecoderKey{}).(MessageDecoder)
+	if !ok {
+		return data, nil
+	}
+	var value interface{}
+	if err := decode(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
 type Logger interface {
 	Info(msg string, fields ...Field)
 	Error(msg string, fields ...Field)
@@ -30,6 +66,86 @@ type MetricsCollector interface {
 	IncrementCounter(name string, labels map[string]string)
 	RecordDuration(name string, duration float64, labels map[string]string)
 	SetGauge(name string, value float64, labels map[string]string)
+	// ObserveValue records a sample that is not a duration, such as a count,
+	// into a histogram
+	ObserveValue(name string, value float64, labels map[string]string)
+}
+
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Tracer records named spans grouped by a correlation ID, such as an order ID,
+// so a flow across services can be read back as one timeline
+type Tracer interface {
+	StartSpan(correlationID, name string, start time.Time) Span
+}
+
+// Span is an operation in progress; End records it, with err if it failed
+type Span interface {
+	End(err error)
+}
+
+// NoopTracer discards every span
+type NoopTracer struct{}
+
+func (NoopTracer) StartSpan(correlationID, name string, start time.Time) Span { return noopSpan{} }
+
+type noopSpan struct{}
+
+func (noopSpan) End(err error) {}
+
+// VolatilityProvider estimates a symbol's annualized volatility over lookback
+type VolatilityProvider interface {
+	GetVolatility(ctx context.Context, symbol entities.Symbol, lookback time.Duration) (float64, error)
+}
+
+// CorrelationProvider returns pairwise return correlations among symbols.
+// Pairs absent from the matrix are treated as unknown.
+type CorrelationProvider interface {
+	GetCorrelations(ctx context.Context, symbols []entities.Symbol) (map[entities.Symbol]map[entities.Symbol]float64, error)
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "healthy"
+	HealthStatusDegraded  HealthStatus = "degraded"
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+)
+
+// CheckFunc reports a component's health and a short human-readable detail
+type CheckFunc func(ctx context.Context) (HealthStatus, string)
+
+type HealthCheckResult struct {
+	Name    string        `json:"name"`
+	Status  HealthStatus  `json:"status"`
+	Detail  string        `json:"detail,omitempty"`
+	Latency time.Duration `json:"latency"`
+	// Metrics carries component figures such as uptime, when the component
+	// attaches them
+	Metrics map[string]interface{} `json:"metrics,omitempty"`
+}
+
+// HealthReport is the aggregate of all registered checks; Status is the worst
+// status among them
+type HealthReport struct {
+	Status    HealthStatus        `json:"status"`
+	Checks    []HealthCheckResult `json:"checks"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// HealthAggregator collects named health checks so every consumer reports the
+// same view of system health
+type HealthAggregator interface {
+	Register(name string, check CheckFunc)
+	Check(ctx context.Context) HealthReport
+}
+
+// JitterSource supplies random values in [0, 1) for retry jitter
+type JitterSource interface {
+	Float64() float64
 }
 
 type RiskCalculator interface {
@@ -46,6 +162,11 @@ type Trader interface {
 	GetMarketData(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error)
 }
 
+// LastPriceProvider returns a symbol's latest price; every PriceProvider is one
+type LastPriceProvider interface {
+	GetRealTimePrice(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error)
+}
+
 type PriceProvider interface {
 	GetRealTimePrice(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error)
 	SubscribeToPrice(ctx context.Context, symbol entities.Symbol, callback func(*entities.MarketData)) error
@@ -57,6 +178,27 @@ type NewsProvider interface {
 	SubscribeToNews(ctx context.Context, callback func(*entities.NewsArticle)) error
 }
 
+// SentimentAnalyzer scores a news article's sentiment in [-1, 1] and its
+// relevance to the article's symbols in [0, 1]
+type SentimentAnalyzer interface {
+	Analyze(ctx context.Context, article *entities.NewsArticle) (SentimentScore, error)
+}
+
+type SentimentScore struct {
+	Sentiment float64
+	Relevance float64
+}
+
+// MacroDataProvider returns the latest value of a country's macroeconomic
+// indicator, such as GDP growth or the policy interest rate
+type MacroDataProvider interface {
+	GetIndicatorValue(ctx context.Context, name, country string) (float64, error)
+}
+
+type HistoricalDataProvider interface {
+	GetHistoricalBars(ctx context.Context, symbol entities.Symbol, from, to time.Time) ([]*entities.MarketData, error)
+}
+
 type Validator interface {
 	ValidateOrder(order *entities.Order) error
 	ValidateMarketData(data *entities.MarketData) error
@@ -75,10 +217,12 @@ type RiskMetrics struct {
 }
 
 type PortfolioRisk struct {
-	TotalVaR      float64
-	Concentration map[entities.Symbol]float64
-	Leverage      float64
-	DrawdownRisk  float64
+	TotalVaR float64
+	// ExpectedShortfall is the average loss beyond the 95% VaR
+	ExpectedShortfall float64
+	Concentration     map[entities.Symbol]float64
+	Leverage          float64
+	DrawdownRisk      float64
 }
 
 type RiskLimits struct {
@@ -88,6 +232,14 @@ type RiskLimits struct {
 	MaxDailyLoss         float64
 	MaxVaR               float64
 	VaRConfidenceLevel   float64
+	// MaxDrawdown is the peak-to-trough equity drop that halts trading; zero disables it
+	MaxDrawdown          float64
+	// StopLossThreshold is the loss from average price, as a fraction of cost
+	// basis, at which a position should be closed; zero disables it
+	StopLossThreshold    float64
+	// CashBufferPct is the margin of cash, as a fraction of order value, a buy
+	// must leave on top of its cost; zero disables the buffer
+	CashBufferPct        float64
 }
 
 type TradeResult struct {
@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// OrderCanceler is the subset of the broker Trader used to cancel expired orders
+type OrderCanceler interface {
+	CancelOrder(ctx context.Context, orderID string) error
+}
+
+type ExpirySweeperConfig struct {
+	Interval time.Duration
+	// BatchSize bounds how many orders a single sweep expires
+	BatchSize int
+	Clock     interfaces.Clock
+}
+
+// OrderExpirySweeper periodically expires GTD and DAY orders whose expiry has passed
+type OrderExpirySweeper struct {
+	orderRepo  interfaces.OrderRepository
+	broker     OrderCanceler
+	messageBus interfaces.MessageBus
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+	config     ExpirySweeperConfig
+	clock      interfaces.Clock
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewOrderExpirySweeper(
+	orderRepo interfaces.OrderRepository,
+	broker OrderCanceler,
+	messageBus interfaces.MessageBus,
+	logger interfaces.Logger,
+	metrics interfaces.MetricsCollector,
+	config ExpirySweeperConfig,
+) *OrderExpirySweeper {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	sweeperClock := config.Clock
+	if sweeperClock == nil {
+		sweeperClock = systemClock{}
+	}
+
+	return &OrderExpirySweeper{
+		orderRepo:  orderRepo,
+		broker:     broker,
+		messageBus: messageBus,
+		logger:     logger,
+		metrics:    metrics,
+		config:     config,
+		clock:      sweeperClock,
+	}
+}
+
+// Start runs Sweep every Interval on a single background goroutine until Stop
+func (s *OrderExpirySweeper) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.clock.After(s.config.Interval):
+			}
+
+			if _, err := s.Sweep(ctx); err != nil {
+				s.logger.Error("Order expiry sweep failed",
+					interfaces.Field{Key: "error", Value: err},
+				)
+			}
+		}
+	}()
+}
+
+// Stop halts the background sweep and waits for it to finish
+func (s *OrderExpirySweeper) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// Sweep expires up to BatchSize orders that are due at the current clock time and
+// returns how many were expired. Orders that are no longer live are skipped, so
+// running Sweep repeatedly never expires an order twice.
+func (s *OrderExpirySweeper) Sweep(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+
+	due, err := s.orderRepo.ListExpiring(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expiring orders: %w", err)
+	}
+
+	expired := 0
+	for _, order := range due {
+		if !order.ExpiresBy(now) {
+			continue
+		}
+
+		if err := s.expire(ctx, order); err != nil {
+			s.logger.Error("Failed to expire order",
+				interfaces.Field{Key: "order_id", Value: order.ID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (s *OrderExpirySweeper) expire(ctx context.Context, order *entities.Order) error {
+	if order.BrokerOrderID != "" && s.broker != nil {
+		if err := s.broker.CancelOrder(ctx, order.BrokerOrderID); err != nil {
+			return fmt.Errorf("failed to cancel order at broker: %w", err)
+		}
+	}
+
+	order.Expire()
+
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	if err := s.messageBus.Publish(ctx, "order.expired", order); err != nil {
+		s.logger.Warn("Failed to publish order expired message",
+			interfaces.Field{Key: "order_id", Value: order.ID},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+
+	s.metrics.IncrementCounter("orders_expired_total", map[string]string{
+		"symbol": string(order.Symbol),
+	})
+
+	s.logger.Info("Order expired",
+		interfaces.Field{Key: "order_id", Value: order.ID},
+		interfaces.Field{Key: "symbol", Value: order.Symbol},
+		interfaces.Field{Key: "expires_at", Value: *order.ExpiresAt},
+	)
+
+	return nil
+}
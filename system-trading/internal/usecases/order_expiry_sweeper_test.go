@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+type fakeOrderCanceler struct {
+	cancelled []string
+}
+
+func (c *fakeOrderCanceler) CancelOrder(ctx context.Context, orderID string) error {
+	c.cancelled = append(c.cancelled, orderID)
+	return nil
+}
+
+func seedGTDOrder(t *testing.T, repo *repositories.InMemoryOrderRepository, symbol entities.Symbol, expiresAt time.Time, brokerOrderID string) *entities.Order {
+	t.Helper()
+
+	price := 100.0
+	order := entities.NewOrder(symbol, entities.OrderSideBuy, entities.OrderTypeLimit, 10, &price)
+	order.ID = entities.OrderID("gtd-" + string(symbol))
+	order.TimeInForce = entities.TimeInForceGTD
+	order.ExpiresAt = &expiresAt
+	order.BrokerOrderID = brokerOrderID
+
+	if err := repo.Create(context.Background(), order); err != nil {
+		t.Fatalf("Failed to seed order: %v", err)
+	}
+	return order
+}
+
+func TestOrderExpirySweeper_ExpiresAtExactTimes(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(start)
+	repo := repositories.NewInMemoryOrderRepository()
+	bus := messagebus.NewMockMessageBus()
+	canceler := &fakeOrderCanceler{}
+
+	first := seedGTDOrder(t, repo, "AAPL", start.Add(time.Minute), "")
+	second := seedGTDOrder(t, repo, "MSFT", start.Add(2*time.Minute), "MOCK_2")
+	third := seedGTDOrder(t, repo, "TSLA", start.Add(3*time.Minute), "")
+
+	gtc := entities.NewOrder("NVDA", entities.OrderSideBuy, entities.OrderTypeMarket, 1, nil)
+	if err := repo.Create(ctx, gtc); err != nil {
+		t.Fatalf("Failed to seed order: %v", err)
+	}
+
+	cancelled := seedGTDOrder(t, repo, "AMD", start.Add(time.Minute), "")
+	cancelled.Cancel()
+	if err := repo.Update(ctx, cancelled); err != nil {
+		t.Fatalf("Failed to cancel order: %v", err)
+	}
+
+	sweeper := NewOrderExpirySweeper(repo, canceler, bus, newTestLogger(t), newTestMetrics("expiry"),
+		ExpirySweeperConfig{Clock: fakeClock})
+
+	steps := []struct {
+		advance  time.Duration
+		expected []*entities.Order
+	}{
+		{0, nil},
+		{time.Minute - time.Nanosecond, nil},
+		{time.Nanosecond, []*entities.Order{first}},
+		{0, nil},
+		{time.Minute, []*entities.Order{second}},
+		{30 * time.Second, nil},
+		{30 * time.Second, []*entities.Order{third}},
+		{time.Hour, nil},
+	}
+
+	events := 0
+	for i, step := range steps {
+		fakeClock.Advance(step.advance)
+
+		expired, err := sweeper.Sweep(ctx)
+		if err != nil {
+			t.Fatalf("Step %d: Sweep failed: %v", i, err)
+		}
+		if expired != len(step.expected) {
+			t.Fatalf("Step %d at %s: expected %d expired, got %d", i, fakeClock.Now().Sub(start), len(step.expected), expired)
+		}
+
+		for _, order := range step.expected {
+			stored, err := repo.GetByID(ctx, order.ID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			if stored.Status != entities.OrderStatusExpired {
+				t.Errorf("Step %d: expected %s to be expired, got %s", i, order.ID, stored.Status)
+			}
+		}
+
+		events += len(step.expected)
+		if got := len(bus.GetMessagesByTopic("order.expired")); got != events {
+			t.Fatalf("Step %d: expected %d order.expired events, got %d", i, events, got)
+		}
+	}
+
+	if len(canceler.cancelled) != 1 || canceler.cancelled[0] != "MOCK_2" {
+		t.Errorf("Expected only the live broker order to be cancelled, got %v", canceler.cancelled)
+	}
+
+	for id, status := range map[entities.OrderID]entities.OrderStatus{
+		gtc.ID:       entities.OrderStatusPending,
+		cancelled.ID: entities.OrderStatusCancelled,
+	} {
+		stored, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if stored.Status != status {
+			t.Errorf("Expected %s to stay %s, got %s", id, status, stored.Status)
+		}
+	}
+}
+
+func TestOrderExpirySweeper_BackgroundLoopUsesClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(start)
+	repo := repositories.NewInMemoryOrderRepository()
+	bus := messagebus.NewMockMessageBus()
+
+	seedGTDOrder(t, repo, "AAPL", start.Add(time.Minute), "")
+
+	sweeper := NewOrderExpirySweeper(repo, nil, bus, newTestLogger(t), newTestMetrics("expiry"),
+		ExpirySweeperConfig{Interval: time.Minute, Clock: fakeClock})
+	sweeper.Start(context.Background())
+	defer sweeper.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for fakeClock.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Sweeper never waited on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fakeClock.Advance(time.Minute)
+
+	for len(bus.GetMessagesByTopic("order.expired")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the background sweep to expire the order")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
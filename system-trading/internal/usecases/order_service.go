@@ -3,18 +3,49 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
 	"github.com/system-trading/core/internal/usecases/interfaces"
 )
 
+// OrderImpactEstimator previews the risk impact of an order without side effects
+type OrderImpactEstimator interface {
+	EstimateOrderImpact(ctx context.Context, order *entities.Order) (*OrderImpact, error)
+}
+
 type OrderService struct {
-	orderRepo  interfaces.OrderRepository
-	messageBus interfaces.MessageBus
-	logger     interfaces.Logger
-	metrics    interfaces.MetricsCollector
-	validator  interfaces.Validator
+	orderRepo       interfaces.OrderRepository
+	messageBus      interfaces.MessageBus
+	logger          interfaces.Logger
+	metrics         interfaces.MetricsCollector
+	validator       interfaces.Validator
+	impactEstimator OrderImpactEstimator
+	sourceThrottle  *RateLimiter
+	tracer          interfaces.Tracer
+	calendar        *TradingCalendar
+}
+
+// UnknownOrderSource is the throttle key for orders submitted without a source
+const UnknownOrderSource = "unknown"
+
+// OrderThrottledError rejects an order whose source exceeded its submission rate
+type OrderThrottledError struct {
+	Source     string
+	RetryAfter time.Duration
+}
+
+func (e *OrderThrottledError) Error() string {
+	return fmt.Sprintf("THROTTLED: source %s exceeded its order rate, retry after %s", e.Source, e.RetryAfter)
+}
+
+func (e *OrderThrottledError) Code() string {
+	return "THROTTLED"
+}
+
+func (e *OrderThrottledError) Unwrap() error {
+	return entities.ErrRateLimitExceeded
 }
 
 func NewOrderService(
@@ -23,17 +54,43 @@ func NewOrderService(
 	logger interfaces.Logger,
 	metrics interfaces.MetricsCollector,
 	validator interfaces.Validator,
+	impactEstimator OrderImpactEstimator,
 ) *OrderService {
 	return &OrderService{
-		orderRepo:  orderRepo,
-		messageBus: messageBus,
-		logger:     logger,
-		metrics:    metrics,
-		validator:  validator,
+		orderRepo:       orderRepo,
+		messageBus:      messageBus,
+		logger:          logger,
+		metrics:         metrics,
+		validator:       validator,
+		impactEstimator: impactEstimator,
+		tracer:          interfaces.NoopTracer{},
 	}
 }
 
+// SetSourceThrottle limits how fast each order source may submit orders, so one
+// runaway strategy cannot crowd out the others
+func (s *OrderService) SetSourceThrottle(limiter *RateLimiter) {
+	s.sourceThrottle = limiter
+}
+
+// SetTracer records a span per order lifecycle step, keyed by order ID
+func (s *OrderService) SetTracer(tracer interfaces.Tracer) {
+	s.tracer = tracer
+}
+
+// SetTradingCalendar supplies the session close DAY orders expire at. Without
+// it DAY orders are rejected.
+func (s *OrderService) SetTradingCalendar(calendar *TradingCalendar) {
+	s.calendar = calendar
+}
+
 func (s *OrderService) CreateOrder(ctx context.Context, req CreateOrderRequest) (*entities.Order, error) {
+	return s.createOrder(ctx, req, nil)
+}
+
+// createOrder validates, persists and proposes the order for req, letting link
+// fill in fields the request cannot carry before the order is saved
+func (s *OrderService) createOrder(ctx context.Context, req CreateOrderRequest, link func(*entities.Order)) (*entities.Order, error) {
 	start := time.Now()
 	defer func() {
 		s.metrics.RecordDuration("order_creation_duration", time.Since(start).Seconds(), map[string]string{
@@ -54,9 +111,27 @@ func (s *OrderService) CreateOrder(ctx context.Context, req CreateOrderRequest)
 		return nil, fmt.Errorf("order validation failed: %w", err)
 	}
 
+	if err := s.checkSourceThrottle(req); err != nil {
+		return nil, err
+	}
+
 	order := entities.NewOrder(req.Symbol, req.Side, req.Type, req.Quantity, req.Price)
+	order.StopPrice = req.StopPrice
+	order.TimeInForce = req.TimeInForce
+	order.ExpiresAt = req.ExpiresAt
+	order.Source = req.Source
+	order.PortfolioID = req.PortfolioID
+	if req.TimeInForce == entities.TimeInForceDay {
+		sessionClose := s.calendar.NextClose(order.CreatedAt)
+		order.ExpiresAt = &sessionClose
+	}
+	if link != nil {
+		link(order)
+	}
+	span := s.tracer.StartSpan(string(order.ID), "order.created", start)
 
 	if err := s.orderRepo.Create(ctx, order); err != nil {
+		span.End(err)
 		s.metrics.IncrementCounter("order_creation_errors", map[string]string{
 			"symbol": string(req.Symbol),
 			"error":  "repository_failed",
@@ -75,6 +150,8 @@ func (s *OrderService) CreateOrder(ctx context.Context, req CreateOrderRequest)
 		)
 	}
 
+	span.End(nil)
+
 	s.metrics.IncrementCounter("orders_created", map[string]string{
 		"symbol": string(req.Symbol),
 		"side":   string(req.Side),
@@ -91,6 +168,35 @@ func (s *OrderService) CreateOrder(ctx context.Context, req CreateOrderRequest)
 	return order, nil
 }
 
+// PreviewOrder is the dry-run path of CreateOrder: it runs request validation and
+// every risk check, and returns the projected impact without persisting the order
+// or publishing any events
+func (s *OrderService) PreviewOrder(ctx context.Context, req CreateOrderRequest) (*OrderPreview, error) {
+	if err := s.validateCreateOrderRequest(req); err != nil {
+		return nil, fmt.Errorf("order validation failed: %w", err)
+	}
+
+	if s.impactEstimator == nil {
+		return nil, fmt.Errorf("order preview is unavailable: no risk estimator configured")
+	}
+
+	order := entities.NewOrder(req.Symbol, req.Side, req.Type, req.Quantity, req.Price)
+	order.StopPrice = req.StopPrice
+	order.TimeInForce = req.TimeInForce
+	order.ExpiresAt = req.ExpiresAt
+	order.PortfolioID = req.PortfolioID
+
+	impact, err := s.impactEstimator.EstimateOrderImpact(ctx, order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate order impact: %w", err)
+	}
+
+	return &OrderPreview{
+		Order:  order,
+		Impact: impact,
+	}, nil
+}
+
 func (s *OrderService) GetOrder(ctx context.Context, orderID entities.OrderID) (*entities.Order, error) {
 	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
@@ -104,7 +210,10 @@ func (s *OrderService) GetOrder(ctx context.Context, orderID entities.OrderID) (
 	return order, nil
 }
 
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID entities.OrderID, status entities.OrderStatus) error {
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID entities.OrderID, status entities.OrderStatus) (err error) {
+	span := s.tracer.StartSpan(string(orderID), "order."+strings.ToLower(string(status)), time.Now())
+	defer func() { span.End(err) }()
+
 	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to get order: %w", err)
@@ -178,6 +287,10 @@ func (s *OrderService) ExecuteOrder(ctx context.Context, orderID entities.OrderI
 		)
 	}
 
+	if len(order.ChildOrderIDs) > 0 {
+		s.releaseBracketChildren(ctx, order)
+	}
+
 	s.metrics.IncrementCounter("orders_executed", map[string]string{
 		"symbol": string(order.Symbol),
 		"side":   string(order.Side),
@@ -236,10 +349,46 @@ func (s *OrderService) CancelOrder(ctx context.Context, orderID entities.OrderID
 		interfaces.Field{Key: "order_id", Value: orderID},
 	)
 
+	if len(order.ChildOrderIDs) > 0 {
+		s.cancelBracketChildren(ctx, order)
+	}
+
 	return nil
 }
 
+func (s *OrderService) checkSourceThrottle(req CreateOrderRequest) error {
+	if s.sourceThrottle == nil {
+		return nil
+	}
+
+	source := req.Source
+	if source == "" {
+		source = UnknownOrderSource
+	}
+
+	allowed, retryAfter := s.sourceThrottle.Allow(source)
+	if allowed {
+		return nil
+	}
+
+	s.metrics.IncrementCounter("orders_throttled", map[string]string{
+		"source": source,
+	})
+	s.logger.Warn("Order throttled",
+		interfaces.Field{Key: "source", Value: source},
+		interfaces.Field{Key: "symbol", Value: req.Symbol},
+		interfaces.Field{Key: "retry_after", Value: retryAfter},
+	)
+	return &OrderThrottledError{Source: source, RetryAfter: retryAfter}
+}
+
 func (s *OrderService) validateCreateOrderRequest(req CreateOrderRequest) error {
+	if s.validator != nil {
+		if err := s.validator.ValidateStruct(req); err != nil {
+			return err
+		}
+	}
+
 	if req.Symbol == "" {
 		return fmt.Errorf("symbol is required")
 	}
@@ -258,7 +407,7 @@ func (s *OrderService) validateCreateOrderRequest(req CreateOrderRequest) error
 		return fmt.Errorf("quantity must be positive")
 	}
 
-	if (req.Type == entities.OrderTypeLimit || req.Type == entities.OrderTypeStop) && req.Price == nil {
+	if req.Type == entities.OrderTypeLimit && req.Price == nil {
 		return fmt.Errorf("price is required for %s orders", req.Type)
 	}
 
@@ -266,6 +415,37 @@ func (s *OrderService) validateCreateOrderRequest(req CreateOrderRequest) error
 		return fmt.Errorf("price must be positive")
 	}
 
+	if req.Type == entities.OrderTypeStop && req.StopPrice == nil {
+		return fmt.Errorf("stop price is required for %s orders", req.Type)
+	}
+
+	if req.StopPrice != nil && *req.StopPrice <= 0 {
+		return fmt.Errorf("stop price must be positive")
+	}
+
+	switch req.TimeInForce {
+	case "", entities.TimeInForceGTC, entities.TimeInForceIOC, entities.TimeInForceFOK:
+		if req.ExpiresAt != nil {
+			return fmt.Errorf("expires_at is only valid for GTD orders")
+		}
+	case entities.TimeInForceDay:
+		if req.ExpiresAt != nil {
+			return fmt.Errorf("expires_at is only valid for GTD orders")
+		}
+		if s.calendar == nil {
+			return fmt.Errorf("DAY orders need a trading calendar to expire at the close")
+		}
+	case entities.TimeInForceGTD:
+		if req.ExpiresAt == nil {
+			return fmt.Errorf("expires_at is required for GTD orders")
+		}
+		if !req.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("expires_at must be in the future")
+		}
+	default:
+		return fmt.Errorf("invalid time in force: %s", req.TimeInForce)
+	}
+
 	return nil
 }
 
@@ -287,4 +467,15 @@ type CreateOrderRequest struct {
 	Type     entities.OrderType `json:"type" validate:"required"`
 	Quantity float64           `json:"quantity" validate:"required,min=0.000001"`
 	Price    *float64          `json:"price,omitempty" validate:"omitempty,min=0.000001"`
+	StopPrice *float64         `json:"stop_price,omitempty" validate:"omitempty,min=0.000001"`
+	TimeInForce entities.TimeInForce `json:"time_in_force,omitempty"`
+	ExpiresAt   *time.Time           `json:"expires_at,omitempty"`
+	Source      string               `json:"source,omitempty"`
+	// PortfolioID routes the order to a portfolio; empty uses the default portfolio
+	PortfolioID string               `json:"portfolio_id,omitempty"`
+}
+
+type OrderPreview struct {
+	Order  *entities.Order `json:"order"`
+	Impact *OrderImpact    `json:"impact"`
 }
\ No newline at end of file
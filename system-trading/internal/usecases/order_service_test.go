@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/infrastructure/validation"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func TestOrderService_PreviewOrderReportsBreachWithoutSideEffects(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	seedPortfolio(t, f.portfolioRepo, "default", 100000, nil)
+
+	orderRepo := repositories.NewInMemoryOrderRepository()
+	service := NewOrderService(orderRepo, f.bus, newTestLogger(t), newTestMetrics("order"), nil, f.service)
+
+	// 200 shares at 100 is 20% of the portfolio, above the 10% position-size limit
+	price := 100.0
+	preview, err := service.PreviewOrder(context.Background(), CreateOrderRequest{
+		Symbol:   "AAPL",
+		Side:     entities.OrderSideBuy,
+		Type:     entities.OrderTypeLimit,
+		Quantity: 200,
+		Price:    &price,
+	})
+	if err != nil {
+		t.Fatalf("PreviewOrder failed: %v", err)
+	}
+
+	impact := preview.Impact
+	if impact.WouldPass {
+		t.Error("Expected preview to report the order would not pass")
+	}
+	if len(impact.Breaches) != 1 || impact.Breaches[0].Check != "position_size" {
+		t.Fatalf("Expected a single position_size breach, got %+v", impact.Breaches)
+	}
+	if impact.EstimatedCost != 20000 || impact.RemainingCash != 80000 || impact.ResultingPositionQuantity != 200 {
+		t.Errorf("Unexpected projected impact: %+v", impact)
+	}
+
+	orders, err := orderRepo.List(context.Background(), interfaces.OrderFilters{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(orders) != 0 {
+		t.Errorf("Expected dry run not to persist an order, found %d", len(orders))
+	}
+	if messages := f.bus.GetMessages(); len(messages) != 0 {
+		t.Errorf("Expected dry run not to publish events, got %d", len(messages))
+	}
+}
+
+func TestOrderService_ThrottlesPerSource(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC))
+	limiter := NewRateLimiter(RateLimit{Rate: 1, Burst: 3}, fakeClock)
+
+	service := NewOrderService(repositories.NewInMemoryOrderRepository(), messagebus.NewMockMessageBus(),
+		newTestLogger(t), newTestMetrics("order"), nil, nil)
+	service.SetSourceThrottle(limiter)
+
+	submit := func(source string) error {
+		_, err := service.CreateOrder(context.Background(), CreateOrderRequest{
+			Symbol:   "AAPL",
+			Side:     entities.OrderSideBuy,
+			Type:     entities.OrderTypeMarket,
+			Quantity: 1,
+			Source:   source,
+		})
+		return err
+	}
+
+	// The runaway strategy spends its burst of 3 and is throttled after that
+	for i := 0; i < 3; i++ {
+		if err := submit("runaway"); err != nil {
+			t.Fatalf("Order %d within burst was rejected: %v", i+1, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		err := submit("runaway")
+		var throttled *OrderThrottledError
+		if !errors.As(err, &throttled) {
+			t.Fatalf("Expected OrderThrottledError, got %v", err)
+		}
+		if throttled.Code() != "THROTTLED" || throttled.RetryAfter != time.Second {
+			t.Errorf("Expected THROTTLED with a 1s retry hint, got %s after %v", throttled.Code(), throttled.RetryAfter)
+		}
+		if !errors.Is(err, entities.ErrRateLimitExceeded) {
+			t.Errorf("Expected throttle error to wrap ErrRateLimitExceeded")
+		}
+	}
+
+	// The other strategy has its own bucket
+	for i := 0; i < 3; i++ {
+		if err := submit("steady"); err != nil {
+			t.Fatalf("Unaffected source was rejected on order %d: %v", i+1, err)
+		}
+	}
+
+	rejections := limiter.Rejections()
+	if rejections["runaway"] != 2 || rejections["steady"] != 0 {
+		t.Errorf("Expected 2 rejections for runaway only, got %v", rejections)
+	}
+
+	// Once a token refills, the throttled source may submit again
+	fakeClock.Advance(time.Second)
+	if err := submit("runaway"); err != nil {
+		t.Errorf("Expected runaway source to recover after refill, got %v", err)
+	}
+}
+
+func TestOrderService_TimeInForce(t *testing.T) {
+	service := NewOrderService(repositories.NewInMemoryOrderRepository(), messagebus.NewMockMessageBus(),
+		newTestLogger(t), newTestMetrics("order"), nil, nil)
+	ctx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	create := func(tif entities.TimeInForce, expiresAt *time.Time) (*entities.Order, error) {
+		return service.CreateOrder(ctx, CreateOrderRequest{
+			Symbol:      "AAPL",
+			Side:        entities.OrderSideBuy,
+			Type:        entities.OrderTypeMarket,
+			Quantity:    1,
+			TimeInForce: tif,
+			ExpiresAt:   expiresAt,
+		})
+	}
+
+	if _, err := create(entities.TimeInForceDay, nil); err == nil {
+		t.Error("Expected a DAY order to be rejected without a trading calendar")
+	}
+
+	calendar := NewTradingCalendar(time.UTC, 14*time.Hour+30*time.Minute, 21*time.Hour)
+	service.SetTradingCalendar(calendar)
+
+	tests := []struct {
+		tif       entities.TimeInForce
+		expiresAt *time.Time
+		wantErr   bool
+	}{
+		{entities.TimeInForceDay, nil, false},
+		{entities.TimeInForceDay, &tomorrow, true},
+		{entities.TimeInForceIOC, nil, false},
+		{entities.TimeInForceIOC, &tomorrow, true},
+		{entities.TimeInForceFOK, nil, false},
+		{"GTX", nil, true},
+	}
+	for _, tt := range tests {
+		order, err := create(tt.tif, tt.expiresAt)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with expiry %v: expected error %t, got %v", tt.tif, tt.expiresAt, tt.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case tt.tif == entities.TimeInForceDay:
+			if order.ExpiresAt == nil || !order.ExpiresAt.Equal(calendar.NextClose(order.CreatedAt)) {
+				t.Errorf("Expected the DAY order to expire at the next close, got %v", order.ExpiresAt)
+			}
+		case order.ExpiresAt != nil:
+			t.Errorf("Expected no expiry on a %s order, got %v", tt.tif, order.ExpiresAt)
+		}
+	}
+}
+
+func TestOrderService_ValidatesRequestTags(t *testing.T) {
+	service := NewOrderService(repositories.NewInMemoryOrderRepository(), messagebus.NewMockMessageBus(),
+		newTestLogger(t), newTestMetrics("order"), validation.NewValidator(), nil)
+	zero := 0.0
+
+	tests := []struct {
+		name     string
+		quantity float64
+		price    *float64
+		wantErr  bool
+	}{
+		{"quantity at min", 0.000001, nil, false},
+		{"quantity below min", 0.0000005, nil, true},
+		{"zero limit price", 1, &zero, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateOrderRequest{
+				Symbol:   "AAPL",
+				Side:     entities.OrderSideBuy,
+				Type:     entities.OrderTypeMarket,
+				Quantity: tt.quantity,
+			}
+			if tt.price != nil {
+				req.Type = entities.OrderTypeLimit
+				req.Price = tt.price
+			}
+			_, err := service.CreateOrder(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
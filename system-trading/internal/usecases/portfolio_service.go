@@ -2,7 +2,10 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
@@ -14,6 +17,44 @@ type PortfolioService struct {
 	messageBus    interfaces.MessageBus
 	logger        interfaces.Logger
 	metrics       interfaces.MetricsCollector
+	taxModel      TaxModel
+	costBasis     entities.CostBasisMethod
+
+	updateMode    PortfolioUpdateMode
+	publishMu     sync.Mutex
+	lastPublished map[string]publishedPortfolio
+
+	// portfolioLocks holds one mutex per portfolio, taken by every
+	// read-modify-write of it so concurrent updates cannot overwrite each other
+	portfolioLocksMu sync.Mutex
+	portfolioLocks   map[string]*sync.Mutex
+
+	// resetCancel stops the open-of-day scheduler
+	resetCancel context.CancelFunc
+	resetWG     sync.WaitGroup
+
+	snapshots      interfaces.PortfolioSnapshotRepository
+	marketData     interfaces.MarketDataRepository
+	snapshotCancel context.CancelFunc
+	snapshotWG     sync.WaitGroup
+}
+
+// PortfolioUpdateMode selects how much of the portfolio each portfolio.update carries
+type PortfolioUpdateMode string
+
+const (
+	// PortfolioUpdateSnapshot sends every position on each update
+	PortfolioUpdateSnapshot PortfolioUpdateMode = "snapshot"
+	// PortfolioUpdateDelta sends only positions that changed since the last
+	// update, plus cash and value deltas. The first update is a snapshot.
+	PortfolioUpdateDelta PortfolioUpdateMode = "delta"
+)
+
+// publishedPortfolio is what was last published for a portfolio, kept to compute deltas
+type publishedPortfolio struct {
+	totalValue float64
+	cash       float64
+	positions  map[entities.Symbol]float64
 }
 
 func NewPortfolioService(
@@ -23,20 +64,86 @@ func NewPortfolioService(
 	metrics interfaces.MetricsCollector,
 ) *PortfolioService {
 	return &PortfolioService{
-		portfolioRepo: portfolioRepo,
-		messageBus:    messageBus,
-		logger:        logger,
-		metrics:       metrics,
+		portfolioRepo:  portfolioRepo,
+		messageBus:     messageBus,
+		logger:         logger,
+		metrics:        metrics,
+		taxModel:       NewHoldingPeriodTaxModel(DefaultLongTermHoldingPeriod),
+		updateMode:     PortfolioUpdateSnapshot,
+		costBasis:      entities.CostBasisFIFO,
+		lastPublished:  make(map[string]publishedPortfolio),
+		portfolioLocks: make(map[string]*sync.Mutex),
 	}
 }
 
+// lockPortfolio locks portfolioID against other read-modify-writes and returns
+// the function that unlocks it
+func (s *PortfolioService) lockPortfolio(portfolioID string) func() {
+	s.portfolioLocksMu.Lock()
+	mu, ok := s.portfolioLocks[portfolioID]
+	if !ok {
+		mu = &sync.Mutex{}
+		s.portfolioLocks[portfolioID] = mu
+	}
+	s.portfolioLocksMu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// SetPortfolioUpdateMode switches portfolio.update between full snapshots and deltas
+func (s *PortfolioService) SetPortfolioUpdateMode(mode PortfolioUpdateMode) {
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+	s.updateMode = mode
+}
+
+// SetCostBasisMethod selects how sells realize P&L: against the oldest lots
+// (FIFO, the default) or the position's average price
+func (s *PortfolioService) SetCostBasisMethod(method entities.CostBasisMethod) {
+	s.costBasis = method
+}
+
+// SetTaxModel replaces the model used to classify realized gains in performance reports
+func (s *PortfolioService) SetTaxModel(model TaxModel) {
+	s.taxModel = model
+}
+
 func (s *PortfolioService) CreatePortfolio(ctx context.Context, initialCash float64) (*entities.Portfolio, error) {
 	if initialCash < 0 {
 		return nil, fmt.Errorf("initial cash cannot be negative: %f", initialCash)
 	}
 
-	portfolio := entities.NewPortfolio(initialCash)
+	return s.createPortfolio(ctx, entities.NewPortfolio(initialCash))
+}
+
+// EnsurePortfolio returns the portfolio with the given ID, creating it with
+// initialCash if it does not exist yet. created reports whether it was created;
+// an existing portfolio is returned untouched.
+func (s *PortfolioService) EnsurePortfolio(ctx context.Context, portfolioID string, initialCash float64) (portfolio *entities.Portfolio, created bool, err error) {
+	defer s.lockPortfolio(portfolioID)()
 
+	portfolio, err = s.portfolioRepo.GetByID(ctx, portfolioID)
+	if err == nil {
+		return portfolio, false, nil
+	}
+	if !errors.Is(err, entities.ErrPortfolioNotFound) {
+		return nil, false, fmt.Errorf("failed to look up portfolio: %w", err)
+	}
+
+	if initialCash < 0 {
+		return nil, false, fmt.Errorf("initial cash cannot be negative: %f", initialCash)
+	}
+
+	portfolio = entities.NewPortfolio(initialCash)
+	portfolio.ID = portfolioID
+	if portfolio, err = s.createPortfolio(ctx, portfolio); err != nil {
+		return nil, false, err
+	}
+	return portfolio, true, nil
+}
+
+func (s *PortfolioService) createPortfolio(ctx context.Context, portfolio *entities.Portfolio) (*entities.Portfolio, error) {
 	if err := s.portfolioRepo.Save(ctx, portfolio); err != nil {
 		s.logger.Error("Failed to create portfolio",
 			interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
@@ -51,7 +158,7 @@ func (s *PortfolioService) CreatePortfolio(ctx context.Context, initialCash floa
 
 	s.logger.Info("Portfolio created",
 		interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
-		interfaces.Field{Key: "initial_cash", Value: initialCash},
+		interfaces.Field{Key: "initial_cash", Value: portfolio.Cash},
 	)
 
 	return portfolio, nil
@@ -70,6 +177,27 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, portfolioID string)
 	return portfolio, nil
 }
 
+// RollDay moves the portfolio's DayPnL into its P&L history under tradingDay and
+// returns the rolled day together with the updated portfolio
+func (s *PortfolioService) RollDay(ctx context.Context, portfolioID string, tradingDay string, at time.Time) (*entities.Portfolio, entities.DailyPnL, error) {
+	defer s.lockPortfolio(portfolioID)()
+
+	portfolio, err := s.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, entities.DailyPnL{}, err
+	}
+
+	day := portfolio.RollDay(tradingDay, at)
+
+	if err := s.portfolioRepo.Save(ctx, portfolio); err != nil {
+		return nil, entities.DailyPnL{}, fmt.Errorf("failed to save portfolio: %w", err)
+	}
+
+	s.updatePortfolioMetrics(portfolio)
+
+	return portfolio, day, nil
+}
+
 func (s *PortfolioService) ProcessOrderExecution(ctx context.Context, order *entities.Order) error {
 	if order.Status != entities.OrderStatusExecuted {
 		return fmt.Errorf("order must be executed status, got: %s", order.Status)
@@ -79,7 +207,9 @@ func (s *PortfolioService) ProcessOrderExecution(ctx context.Context, order *ent
 		return fmt.Errorf("executed price and quantity are required")
 	}
 
-	portfolio, err := s.getDefaultPortfolio(ctx)
+	defer s.lockPortfolio(order.PortfolioOrDefault())()
+
+	portfolio, err := s.portfolioRepo.GetByID(ctx, order.PortfolioOrDefault())
 	if err != nil {
 		return fmt.Errorf("failed to get portfolio: %w", err)
 	}
@@ -132,6 +262,8 @@ func (s *PortfolioService) ProcessOrderExecution(ctx context.Context, order *ent
 }
 
 func (s *PortfolioService) UpdatePositionPrices(ctx context.Context, portfolioID string, marketData *entities.MarketData) error {
+	defer s.lockPortfolio(portfolioID)()
+
 	portfolio, err := s.portfolioRepo.GetByID(ctx, portfolioID)
 	if err != nil {
 		return fmt.Errorf("failed to get portfolio: %w", err)
@@ -168,13 +300,15 @@ func (s *PortfolioService) GetPortfolioPerformance(ctx context.Context, portfoli
 		return nil, fmt.Errorf("failed to get portfolio: %w", err)
 	}
 
+	attribution := attributePnL(portfolio)
+
 	totalUnrealizedPnL := 0.0
 	totalRealizedPnL := 0.0
-	positionCount := len(portfolio.Positions)
-
-	for _, position := range portfolio.Positions {
-		totalUnrealizedPnL += position.UnrealizedPnL
-		totalRealizedPnL += position.RealizedPnL
+	totalFees := 0.0
+	for _, symbolPnL := range attribution {
+		totalUnrealizedPnL += symbolPnL.UnrealizedPnL
+		totalRealizedPnL += symbolPnL.RealizedPnL
+		totalFees += symbolPnL.Fees
 	}
 
 	performance := &PortfolioPerformance{
@@ -184,7 +318,10 @@ func (s *PortfolioService) GetPortfolioPerformance(ctx context.Context, portfoli
 		TotalPnL:          portfolio.TotalPnL,
 		UnrealizedPnL:     totalUnrealizedPnL,
 		RealizedPnL:       totalRealizedPnL,
-		PositionCount:     positionCount,
+		Fees:              totalFees,
+		PositionCount:     len(portfolio.Positions),
+		Attribution:       attribution,
+		RealizedByTerm:    classifyRealizedLots(s.taxModel, portfolio.RealizedLots),
 		LastUpdated:       portfolio.LastUpdated,
 	}
 
@@ -192,13 +329,14 @@ func (s *PortfolioService) GetPortfolioPerformance(ctx context.Context, portfoli
 }
 
 func (s *PortfolioService) processBuyOrder(portfolio *entities.Portfolio, order *entities.Order) error {
-	totalCost := (*order.ExecutedQuantity) * (*order.ExecutedPrice)
+	totalCost := (*order.ExecutedQuantity)*(*order.ExecutedPrice) + order.Fees
 
 	if portfolio.Cash < totalCost {
 		return entities.ErrInsufficientCash
 	}
 
-	portfolio.AddPosition(order.Symbol, *order.ExecutedQuantity, *order.ExecutedPrice)
+	portfolio.AddPositionAt(order.Symbol, *order.ExecutedQuantity, *order.ExecutedPrice, executionTime(order))
+	portfolio.ChargeFees(order.Symbol, order.Fees)
 
 	s.metrics.IncrementCounter("buy_orders_processed", map[string]string{
 		"symbol": string(order.Symbol),
@@ -217,9 +355,10 @@ func (s *PortfolioService) processSellOrder(portfolio *entities.Portfolio, order
 		return entities.ErrInsufficientQuantity
 	}
 
-	if err := portfolio.RemovePosition(order.Symbol, *order.ExecutedQuantity, *order.ExecutedPrice); err != nil {
+	if err := portfolio.SellAt(order.Symbol, *order.ExecutedQuantity, *order.ExecutedPrice, executionTime(order), s.costBasis); err != nil {
 		return err
 	}
+	portfolio.ChargeFees(order.Symbol, order.Fees)
 
 	s.metrics.IncrementCounter("sell_orders_processed", map[string]string{
 		"symbol": string(order.Symbol),
@@ -228,26 +367,108 @@ func (s *PortfolioService) processSellOrder(portfolio *entities.Portfolio, order
 	return nil
 }
 
-func (s *PortfolioService) getDefaultPortfolio(ctx context.Context) (*entities.Portfolio, error) {
-	return s.portfolioRepo.GetByID(ctx, "default")
+// executionTime dates lots by when the order filled, falling back to now
+func executionTime(order *entities.Order) time.Time {
+	if order.ExecutedAt != nil {
+		return *order.ExecutedAt
+	}
+	return time.Now()
+}
+
+// attributePnL breaks portfolio P&L down by symbol, combining open positions with
+// the realized P&L and fees of positions that have been closed
+func attributePnL(portfolio *entities.Portfolio) []SymbolPnL {
+	bySymbol := make(map[entities.Symbol]*SymbolPnL)
+	get := func(symbol entities.Symbol) *SymbolPnL {
+		if _, exists := bySymbol[symbol]; !exists {
+			bySymbol[symbol] = &SymbolPnL{Symbol: symbol}
+		}
+		return bySymbol[symbol]
+	}
+
+	for symbol, position := range portfolio.Positions {
+		symbolPnL := get(symbol)
+		symbolPnL.RealizedPnL += position.RealizedPnL
+		symbolPnL.UnrealizedPnL += position.UnrealizedPnL
+		symbolPnL.Fees += position.Fees
+		symbolPnL.MarketValue += position.MarketValue
+	}
+
+	for symbol, closed := range portfolio.ClosedPositions {
+		symbolPnL := get(symbol)
+		symbolPnL.RealizedPnL += closed.RealizedPnL
+		symbolPnL.Fees += closed.Fees
+	}
+
+	attribution := make([]SymbolPnL, 0, len(bySymbol))
+	for _, symbolPnL := range bySymbol {
+		symbolPnL.NetPnL = symbolPnL.RealizedPnL + symbolPnL.UnrealizedPnL - symbolPnL.Fees
+		attribution = append(attribution, *symbolPnL)
+	}
+	sort.Slice(attribution, func(i, j int) bool {
+		return attribution[i].Symbol < attribution[j].Symbol
+	})
+
+	return attribution
 }
 
 func (s *PortfolioService) publishPortfolioUpdate(ctx context.Context, portfolio *entities.Portfolio) error {
+	current := publishedPortfolio{
+		totalValue: portfolio.TotalValue,
+		cash:       portfolio.Cash,
+		positions:  make(map[entities.Symbol]float64, len(portfolio.Positions)),
+	}
+	for symbol, position := range portfolio.Positions {
+		current.positions[symbol] = position.Quantity
+	}
+
 	update := PortfolioUpdateMessage{
 		PortfolioID: portfolio.ID,
+		Mode:        PortfolioUpdateSnapshot,
 		TotalValue:  portfolio.TotalValue,
 		Cash:        portfolio.Cash,
 		TotalPnL:    portfolio.TotalPnL,
 		DayPnL:      portfolio.DayPnL,
-		Positions:   make(map[entities.Symbol]float64),
+		Positions:   current.positions,
 		Timestamp:   time.Now(),
 	}
 
-	for symbol, position := range portfolio.Positions {
-		update.Positions[symbol] = position.Quantity
+	s.publishMu.Lock()
+	previous, published := s.lastPublished[portfolio.ID]
+	if s.updateMode == PortfolioUpdateDelta && published {
+		update.Mode = PortfolioUpdateDelta
+		update.Positions = diffPositions(previous.positions, current.positions)
+		update.CashDelta = current.cash - previous.cash
+		update.TotalValueDelta = current.totalValue - previous.totalValue
 	}
+	s.publishMu.Unlock()
 
-	return s.messageBus.Publish(ctx, "portfolio.update", update)
+	if err := s.messageBus.Publish(ctx, "portfolio.update", update); err != nil {
+		return err
+	}
+
+	// Only a delivered update becomes the baseline for the next delta
+	s.publishMu.Lock()
+	s.lastPublished[portfolio.ID] = current
+	s.publishMu.Unlock()
+	return nil
+}
+
+// diffPositions returns the quantities that differ between two position maps,
+// reporting closed positions with a quantity of zero
+func diffPositions(previous, current map[entities.Symbol]float64) map[entities.Symbol]float64 {
+	changed := make(map[entities.Symbol]float64)
+	for symbol, quantity := range current {
+		if before, ok := previous[symbol]; !ok || before != quantity {
+			changed[symbol] = quantity
+		}
+	}
+	for symbol := range previous {
+		if _, ok := current[symbol]; !ok {
+			changed[symbol] = 0
+		}
+	}
+	return changed
 }
 
 func (s *PortfolioService) updatePortfolioMetrics(portfolio *entities.Portfolio) {
@@ -291,16 +512,35 @@ type PortfolioPerformance struct {
 	TotalPnL      float64   `json:"total_pnl"`
 	UnrealizedPnL float64   `json:"unrealized_pnl"`
 	RealizedPnL   float64   `json:"realized_pnl"`
+	Fees          float64   `json:"fees"`
 	PositionCount int       `json:"position_count"`
+	Attribution   []SymbolPnL `json:"attribution"`
+	RealizedByTerm RealizedGainsByTerm `json:"realized_by_term"`
 	LastUpdated   time.Time `json:"last_updated"`
 }
 
+// SymbolPnL is one symbol's contribution to portfolio P&L. NetPnL is realized plus
+// unrealized P&L less fees, so the NetPnL values sum to the portfolio's net P&L.
+type SymbolPnL struct {
+	Symbol        entities.Symbol `json:"symbol"`
+	RealizedPnL   float64         `json:"realized_pnl"`
+	UnrealizedPnL float64         `json:"unrealized_pnl"`
+	Fees          float64         `json:"fees"`
+	NetPnL        float64         `json:"net_pnl"`
+	MarketValue   float64         `json:"market_value"`
+}
+
+// PortfolioUpdateMessage is published on portfolio.update. In delta mode
+// Positions holds only changed symbols, with zero for closed positions.
 type PortfolioUpdateMessage struct {
-	PortfolioID string                      `json:"portfolio_id"`
-	TotalValue  float64                     `json:"total_value"`
-	Cash        float64                     `json:"cash"`
-	TotalPnL    float64                     `json:"total_pnl"`
-	DayPnL      float64                     `json:"day_pnl"`
-	Positions   map[entities.Symbol]float64 `json:"positions"`
-	Timestamp   time.Time                   `json:"timestamp"`
+	PortfolioID     string                      `json:"portfolio_id"`
+	Mode            PortfolioUpdateMode         `json:"mode,omitempty"`
+	TotalValue      float64                     `json:"total_value"`
+	Cash            float64                     `json:"cash"`
+	TotalPnL        float64                     `json:"total_pnl"`
+	DayPnL          float64                     `json:"day_pnl"`
+	Positions       map[entities.Symbol]float64 `json:"positions"`
+	CashDelta       float64                     `json:"cash_delta,omitempty"`
+	TotalValueDelta float64                     `json:"total_value_delta,omitempty"`
+	Timestamp       time.Time                   `json:"timestamp"`
 }
\ No newline at end of file
@@ -0,0 +1,363 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+)
+
+func setupPortfolioService(t *testing.T, initialCash float64) (*PortfolioService, *repositories.InMemoryPortfolioRepository) {
+	t.Helper()
+
+	repo := repositories.NewInMemoryPortfolioRepository()
+	portfolio := entities.NewPortfolio(initialCash)
+	portfolio.ID = "default"
+	if err := repo.Save(context.Background(), portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+
+	service := NewPortfolioService(repo, messagebus.NewMockMessageBus(), newTestLogger(t), newTestMetrics("portfolio"))
+	return service, repo
+}
+
+func executeTestOrder(t *testing.T, service *PortfolioService, symbol entities.Symbol, side entities.OrderSide, quantity, price, fees float64) {
+	t.Helper()
+
+	order := entities.NewOrder(symbol, side, entities.OrderTypeMarket, quantity, nil)
+	order.Execute(price, quantity)
+	order.Fees = fees
+
+	if err := service.ProcessOrderExecution(context.Background(), order); err != nil {
+		t.Fatalf("ProcessOrderExecution(%s %s) failed: %v", side, symbol, err)
+	}
+}
+
+func TestPortfolioService_PerformanceAttribution(t *testing.T) {
+	service, _ := setupPortfolioService(t, 100000)
+	ctx := context.Background()
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+	executeTestOrder(t, service, "MSFT", entities.OrderSideBuy, 10, 200, 1)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 5, 120, 1)
+
+	for symbol, price := range map[entities.Symbol]float64{"AAPL": 130, "MSFT": 180} {
+		if err := service.UpdatePositionPrices(ctx, "default", &entities.MarketData{Symbol: symbol, Price: price}); err != nil {
+			t.Fatalf("UpdatePositionPrices failed: %v", err)
+		}
+	}
+
+	performance, err := service.GetPortfolioPerformance(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetPortfolioPerformance failed: %v", err)
+	}
+
+	if len(performance.Attribution) != 2 {
+		t.Fatalf("Expected attribution for 2 symbols, got %d", len(performance.Attribution))
+	}
+
+	aapl, msft := performance.Attribution[0], performance.Attribution[1]
+	if aapl.Symbol != "AAPL" || msft.Symbol != "MSFT" {
+		t.Fatalf("Expected attribution sorted by symbol, got %s, %s", aapl.Symbol, msft.Symbol)
+	}
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"AAPL realized", aapl.RealizedPnL, 100},
+		{"AAPL unrealized", aapl.UnrealizedPnL, 150},
+		{"AAPL fees", aapl.Fees, 2},
+		{"AAPL net", aapl.NetPnL, 248},
+		{"MSFT realized", msft.RealizedPnL, 0},
+		{"MSFT unrealized", msft.UnrealizedPnL, -200},
+		{"MSFT fees", msft.Fees, 1},
+		{"MSFT net", msft.NetPnL, -201},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	if aapl.NetPnL <= 0 || msft.NetPnL >= 0 {
+		t.Errorf("Expected AAPL to be the winner and MSFT the loser, got %f and %f", aapl.NetPnL, msft.NetPnL)
+	}
+
+	netSum := aapl.NetPnL + msft.NetPnL
+	if math.Abs(netSum-(performance.TotalPnL+performance.UnrealizedPnL)) > 1e-9 {
+		t.Errorf("Attribution %f does not reconcile with total P&L %f + unrealized %f",
+			netSum, performance.TotalPnL, performance.UnrealizedPnL)
+	}
+	if math.Abs(netSum-(performance.TotalValue-100000)) > 1e-9 {
+		t.Errorf("Attribution %f does not reconcile with change in portfolio value %f",
+			netSum, performance.TotalValue-100000)
+	}
+}
+
+func TestPortfolioService_AttributionKeepsClosedPositions(t *testing.T) {
+	service, _ := setupPortfolioService(t, 10000)
+	ctx := context.Background()
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 10, 90, 1)
+
+	performance, err := service.GetPortfolioPerformance(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetPortfolioPerformance failed: %v", err)
+	}
+
+	if performance.PositionCount != 0 {
+		t.Errorf("Expected no open positions, got %d", performance.PositionCount)
+	}
+	if len(performance.Attribution) != 1 || performance.Attribution[0].NetPnL != -102 {
+		t.Fatalf("Expected closed AAPL position to be attributed -102, got %+v", performance.Attribution)
+	}
+	if performance.TotalPnL != -102 {
+		t.Errorf("Expected total P&L -102, got %f", performance.TotalPnL)
+	}
+}
+
+func TestPortfolioService_RealizedGainsSplitByHoldingPeriod(t *testing.T) {
+	service, _ := setupPortfolioService(t, 100000)
+	service.SetTaxModel(NewHoldingPeriodTaxModel(DefaultLongTermHoldingPeriod))
+	ctx := context.Background()
+	now := time.Now()
+
+	executeAt := func(side entities.OrderSide, quantity, price float64, at time.Time) {
+		t.Helper()
+		order := entities.NewOrder("AAPL", side, entities.OrderTypeMarket, quantity, nil)
+		order.Execute(price, quantity)
+		order.ExecutedAt = &at
+		if err := service.ProcessOrderExecution(ctx, order); err != nil {
+			t.Fatalf("ProcessOrderExecution(%s) failed: %v", side, err)
+		}
+	}
+
+	executeAt(entities.OrderSideBuy, 10, 100, now.AddDate(0, 0, -400))
+	executeAt(entities.OrderSideBuy, 10, 110, now.AddDate(0, 0, -100))
+	// FIFO: all of the 400-day lot and half of the 100-day lot are sold
+	executeAt(entities.OrderSideSell, 15, 130, now)
+
+	performance, err := service.GetPortfolioPerformance(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetPortfolioPerformance failed: %v", err)
+	}
+
+	split := performance.RealizedByTerm
+	if len(split.Lots) != 2 {
+		t.Fatalf("Expected 2 realized lots, got %d", len(split.Lots))
+	}
+
+	tests := []struct {
+		term     TaxTerm
+		quantity float64
+		gain     float64
+	}{
+		{TaxTermLong, 10, 300},
+		{TaxTermShort, 5, 100},
+	}
+	for i, tt := range tests {
+		lot := split.Lots[i]
+		if lot.Term != tt.term || math.Abs(lot.Quantity-tt.quantity) > 1e-9 || math.Abs(lot.Gain-tt.gain) > 1e-9 {
+			t.Errorf("Lot %d: expected %s %.0f shares gaining %.2f, got %s %.0f shares gaining %.2f",
+				i, tt.term, tt.quantity, tt.gain, lot.Term, lot.Quantity, lot.Gain)
+		}
+	}
+
+	if math.Abs(split.LongTerm-300) > 1e-9 {
+		t.Errorf("Expected long-term gains 300, got %.2f", split.LongTerm)
+	}
+	if math.Abs(split.ShortTerm-100) > 1e-9 {
+		t.Errorf("Expected short-term gains 100, got %.2f", split.ShortTerm)
+	}
+}
+
+func TestPortfolioService_DeltaPortfolioUpdates(t *testing.T) {
+	service, _ := setupPortfolioService(t, 100000)
+	service.SetPortfolioUpdateMode(PortfolioUpdateDelta)
+	bus := service.messageBus.(*messagebus.MockMessageBus)
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+	executeTestOrder(t, service, "MSFT", entities.OrderSideBuy, 5, 200, 2)
+	executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 10, 110, 1)
+
+	updates := bus.GetMessagesByTopic("portfolio.update")
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 portfolio updates, got %d", len(updates))
+	}
+
+	first := updates[0].Message.(PortfolioUpdateMessage)
+	if first.Mode != PortfolioUpdateSnapshot || len(first.Positions) != 1 || first.Positions["AAPL"] != 10 {
+		t.Errorf("Expected the first update to be a baseline snapshot, got %+v", first)
+	}
+
+	second := updates[1].Message.(PortfolioUpdateMessage)
+	if second.Mode != PortfolioUpdateDelta {
+		t.Fatalf("Expected a delta update, got mode %q", second.Mode)
+	}
+	if len(second.Positions) != 1 || second.Positions["MSFT"] != 5 {
+		t.Errorf("Expected delta to contain only MSFT, got %v", second.Positions)
+	}
+	if math.Abs(second.CashDelta-(-1002)) > 1e-9 {
+		t.Errorf("Expected cash delta -1002, got %.2f", second.CashDelta)
+	}
+	if second.Cash != first.Cash+second.CashDelta {
+		t.Errorf("Expected cash %.2f to equal previous cash plus delta, got %.2f", first.Cash+second.CashDelta, second.Cash)
+	}
+
+	third := updates[2].Message.(PortfolioUpdateMessage)
+	if len(third.Positions) != 1 {
+		t.Fatalf("Expected delta to contain only the closed AAPL position, got %v", third.Positions)
+	}
+	if quantity, ok := third.Positions["AAPL"]; !ok || quantity != 0 {
+		t.Errorf("Expected closed AAPL position reported as 0, got %v", third.Positions)
+	}
+}
+
+// flakyPublishBus fails every Publish while failing is set
+type flakyPublishBus struct {
+	*messagebus.MockMessageBus
+	failing bool
+}
+
+func (b *flakyPublishBus) Publish(ctx context.Context, topic string, message interface{}) error {
+	if b.failing {
+		return errors.New("bus unavailable")
+	}
+	return b.MockMessageBus.Publish(ctx, topic, message)
+}
+
+func TestPortfolioService_FailedPublishKeepsDeltaBaseline(t *testing.T) {
+	service, _ := setupPortfolioService(t, 100000)
+	bus := &flakyPublishBus{MockMessageBus: messagebus.NewMockMessageBus()}
+	service.messageBus = bus
+	service.SetPortfolioUpdateMode(PortfolioUpdateDelta)
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+
+	bus.failing = true
+	executeTestOrder(t, service, "MSFT", entities.OrderSideBuy, 5, 200, 2)
+	bus.failing = false
+
+	executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 10, 100, 1)
+
+	updates := bus.GetMessagesByTopic("portfolio.update")
+	if len(updates) != 2 {
+		t.Fatalf("Expected 2 delivered portfolio updates, got %d", len(updates))
+	}
+	first := updates[0].Message.(PortfolioUpdateMessage)
+	second := updates[1].Message.(PortfolioUpdateMessage)
+	if second.Positions["MSFT"] != 5 || second.Positions["AAPL"] != 20 {
+		t.Errorf("Expected the delta to include the undelivered MSFT change, got %v", second.Positions)
+	}
+	if second.Cash != first.Cash+second.CashDelta {
+		t.Errorf("Expected cash %.2f to equal the last delivered cash plus delta, got %.2f", first.Cash+second.CashDelta, second.Cash)
+	}
+}
+
+func TestPortfolioService_ExecutionsRouteToTheOrdersPortfolio(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	ctx := context.Background()
+	if _, _, err := service.EnsurePortfolio(ctx, "desk-b", 50000); err != nil {
+		t.Fatalf("EnsurePortfolio failed: %v", err)
+	}
+
+	executions := []struct {
+		portfolioID string
+		symbol      entities.Symbol
+		quantity    float64
+		price       float64
+	}{
+		{"", "AAPL", 10, 100},
+		{"desk-b", "MSFT", 20, 200},
+		{"default", "AAPL", 5, 110},
+	}
+	for _, execution := range executions {
+		order := entities.NewOrder(execution.symbol, entities.OrderSideBuy, entities.OrderTypeMarket, execution.quantity, nil)
+		order.PortfolioID = execution.portfolioID
+		order.Execute(execution.price, execution.quantity)
+		if err := service.ProcessOrderExecution(ctx, order); err != nil {
+			t.Fatalf("ProcessOrderExecution(%s) failed: %v", execution.portfolioID, err)
+		}
+	}
+
+	tests := []struct {
+		portfolioID string
+		wantCash    float64
+		wantQty     map[entities.Symbol]float64
+	}{
+		{"default", 100000 - 1000 - 550, map[entities.Symbol]float64{"AAPL": 15}},
+		{"desk-b", 50000 - 4000, map[entities.Symbol]float64{"MSFT": 20}},
+	}
+	for _, tt := range tests {
+		portfolio, err := repo.GetByID(ctx, tt.portfolioID)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", tt.portfolioID, err)
+		}
+		if math.Abs(portfolio.Cash-tt.wantCash) > 1e-9 {
+			t.Errorf("Expected %s cash %.2f, got %.2f", tt.portfolioID, tt.wantCash, portfolio.Cash)
+		}
+		if len(portfolio.Positions) != len(tt.wantQty) {
+			t.Errorf("Expected %s to hold only %v, got %d positions", tt.portfolioID, tt.wantQty, len(portfolio.Positions))
+		}
+		for symbol, quantity := range tt.wantQty {
+			if position, exists := portfolio.Positions[symbol]; !exists || position.Quantity != quantity {
+				t.Errorf("Expected %s to hold %.0f %s", tt.portfolioID, quantity, symbol)
+			}
+		}
+	}
+
+	order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 1, nil)
+	order.PortfolioID = "missing"
+	order.Execute(100, 1)
+	if err := service.ProcessOrderExecution(ctx, order); err == nil {
+		t.Error("Expected an execution for an unknown portfolio to fail")
+	}
+}
+
+func TestPortfolioService_CostBasisMethods(t *testing.T) {
+	// Buy 100@10 and 100@20, then sell 150@25
+	tests := []struct {
+		method       entities.CostBasisMethod
+		wantRealized float64
+		wantAverage  float64
+	}{
+		// FIFO: 3750 proceeds less 100×10 + 50×20 of cost; 50@20 remain
+		{entities.CostBasisFIFO, 1750, 20},
+		// Average cost: (25 - 15) × 150; the remaining 50 keep the 15 average
+		{entities.CostBasisAverage, 1500, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			service, repo := setupPortfolioService(t, 100000)
+			service.SetCostBasisMethod(tt.method)
+
+			executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 100, 10, 0)
+			executeTestOrder(t, service, "AAPL", entities.OrderSideBuy, 100, 20, 0)
+			executeTestOrder(t, service, "AAPL", entities.OrderSideSell, 150, 25, 0)
+
+			portfolio, err := repo.GetByID(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("Failed to get portfolio: %v", err)
+			}
+			position := portfolio.Positions["AAPL"]
+			if math.Abs(position.RealizedPnL-tt.wantRealized) > 1e-9 {
+				t.Errorf("Expected realized P&L %.2f, got %.2f", tt.wantRealized, position.RealizedPnL)
+			}
+			if math.Abs(position.AveragePrice-tt.wantAverage) > 1e-9 {
+				t.Errorf("Expected remaining average price %.2f, got %.2f", tt.wantAverage, position.AveragePrice)
+			}
+			if len(position.Lots) != 1 || position.Lots[0].Quantity != 50 || position.Lots[0].Price != 20 {
+				t.Errorf("Expected one open lot of 50@20, got %+v", position.Lots)
+			}
+		})
+	}
+}
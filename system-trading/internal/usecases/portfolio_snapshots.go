@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// PortfolioSnapshotConfig schedules periodic portfolio snapshots
+type PortfolioSnapshotConfig struct {
+	Interval     time.Duration
+	PortfolioIDs []string
+	Clock        interfaces.Clock
+}
+
+// PerformanceSeries is a portfolio's snapshots over a range, oldest first,
+// with the time-weighted return they imply
+type PerformanceSeries struct {
+	PortfolioID        string                       `json:"portfolio_id"`
+	From               time.Time                    `json:"from"`
+	To                 time.Time                    `json:"to"`
+	Snapshots          []entities.PortfolioSnapshot `json:"snapshots"`
+	TimeWeightedReturn float64                      `json:"time_weighted_return"`
+}
+
+// SetSnapshotRepository stores the snapshots SnapshotPortfolio takes
+func (s *PortfolioService) SetSnapshotRepository(repo interfaces.PortfolioSnapshotRepository) {
+	s.snapshots = repo
+}
+
+// SnapshotPortfolio records portfolioID's current value, cash and P&L
+func (s *PortfolioService) SnapshotPortfolio(ctx context.Context, portfolioID string) (*entities.PortfolioSnapshot, error) {
+	return s.snapshotPortfolio(ctx, portfolioID, time.Now())
+}
+
+func (s *PortfolioService) snapshotPortfolio(ctx context.Context, portfolioID string, at time.Time) (*entities.PortfolioSnapshot, error) {
+	if s.snapshots == nil {
+		return nil, fmt.Errorf("no portfolio snapshot repository configured")
+	}
+
+	portfolio, err := s.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &entities.PortfolioSnapshot{
+		PortfolioID: portfolioID,
+		Timestamp:   at,
+		TotalValue:  portfolio.TotalValue,
+		Cash:        portfolio.Cash,
+		TotalPnL:    portfolio.TotalPnL,
+	}
+	if err := s.snapshots.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio snapshot: %w", err)
+	}
+
+	s.metrics.IncrementCounter("portfolio_snapshots", map[string]string{
+		"portfolio_id": portfolioID,
+	})
+	return snapshot, nil
+}
+
+// GetPerformanceSeries returns portfolioID's snapshots taken in [from, to] and
+// their time-weighted return
+func (s *PortfolioService) GetPerformanceSeries(ctx context.Context, portfolioID string, from, to time.Time) (*PerformanceSeries, error) {
+	if s.snapshots == nil {
+		return nil, fmt.Errorf("no portfolio snapshot repository configured")
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: %s is before %s", to, from)
+	}
+
+	stored, err := s.snapshots.ListSnapshots(ctx, portfolioID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list portfolio snapshots: %w", err)
+	}
+
+	series := &PerformanceSeries{
+		PortfolioID: portfolioID,
+		From:        from,
+		To:          to,
+		Snapshots:   make([]entities.PortfolioSnapshot, 0, len(stored)),
+	}
+	for _, snapshot := range stored {
+		series.Snapshots = append(series.Snapshots, *snapshot)
+	}
+	sort.Slice(series.Snapshots, func(i, j int) bool {
+		return series.Snapshots[i].Timestamp.Before(series.Snapshots[j].Timestamp)
+	})
+	series.TimeWeightedReturn = timeWeightedReturn(series.Snapshots)
+
+	return series, nil
+}
+
+// timeWeightedReturn chains the return of each period between consecutive
+// snapshots. Portfolios take no deposits or withdrawals, so every change in
+// value between snapshots is return. Periods starting from a non-positive value
+// are skipped.
+func timeWeightedReturn(snapshots []entities.PortfolioSnapshot) float64 {
+	growth := 1.0
+	for i := 1; i < len(snapshots); i++ {
+		start := snapshots[i-1].TotalValue
+		if start <= 0 {
+			continue
+		}
+		growth *= snapshots[i].TotalValue / start
+	}
+	return growth - 1
+}
+
+// StartSnapshots snapshots each portfolio every Interval until StopSnapshots
+func (s *PortfolioService) StartSnapshots(ctx context.Context, config PortfolioSnapshotConfig) error {
+	if s.snapshots == nil {
+		return fmt.Errorf("no portfolio snapshot repository configured")
+	}
+	if config.Interval <= 0 {
+		return fmt.Errorf("snapshot interval must be positive, got: %s", config.Interval)
+	}
+	snapshotClock := config.Clock
+	if snapshotClock == nil {
+		snapshotClock = systemClock{}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.snapshotCancel = cancel
+
+	s.snapshotWG.Add(1)
+	go func() {
+		defer s.snapshotWG.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-snapshotClock.After(config.Interval):
+			}
+
+			now := snapshotClock.Now()
+			for _, portfolioID := range config.PortfolioIDs {
+				if _, err := s.snapshotPortfolio(ctx, portfolioID, now); err != nil {
+					s.logger.Error("Failed to snapshot portfolio",
+						interfaces.Field{Key: "portfolio_id", Value: portfolioID},
+						interfaces.Field{Key: "error", Value: err},
+					)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopSnapshots stops periodic snapshotting and waits for a running snapshot
+func (s *PortfolioService) StopSnapshots() {
+	if s.snapshotCancel != nil {
+		s.snapshotCancel()
+	}
+	s.snapshotWG.Wait()
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// reversedSnapshotRepository returns snapshots newest first to check the
+// service does its own ordering
+type reversedSnapshotRepository struct {
+	saved []*entities.PortfolioSnapshot
+}
+
+func (r *reversedSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot *entities.PortfolioSnapshot) error {
+	stored := *snapshot
+	r.saved = append(r.saved, &stored)
+	return nil
+}
+
+func (r *reversedSnapshotRepository) ListSnapshots(ctx context.Context, portfolioID string, from, to time.Time) ([]*entities.PortfolioSnapshot, error) {
+	var result []*entities.PortfolioSnapshot
+	for i := len(r.saved) - 1; i >= 0; i-- {
+		snapshot := r.saved[i]
+		if snapshot.PortfolioID == portfolioID && !snapshot.Timestamp.Before(from) && !snapshot.Timestamp.After(to) {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
+
+func TestPortfolioService_PerformanceSeriesTimeWeightedReturn(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	snapshots := &reversedSnapshotRepository{}
+	service.SetSnapshotRepository(snapshots)
+	ctx := context.Background()
+
+	// Value goes 100k -> 110k -> 99k: +10% then -10%
+	day := time.Date(2024, 3, 4, 16, 0, 0, 0, time.UTC)
+	for i, cash := range []float64{100000, 110000, 99000} {
+		portfolio, err := repo.GetByID(ctx, "default")
+		if err != nil {
+			t.Fatalf("Failed to get portfolio: %v", err)
+		}
+		portfolio.Cash, portfolio.TotalValue = cash, cash
+		if err := repo.Save(ctx, portfolio); err != nil {
+			t.Fatalf("Failed to save portfolio: %v", err)
+		}
+		if _, err := service.snapshotPortfolio(ctx, "default", day.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("snapshotPortfolio failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		wantCount int
+		wantTWR   float64
+	}{
+		{"whole range", day, day.AddDate(0, 0, 2), 3, 1.1*0.9 - 1},
+		{"first period only", day, day.AddDate(0, 0, 1), 2, 0.1},
+		{"single snapshot", day.AddDate(0, 0, 2), day.AddDate(0, 0, 5), 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := service.GetPerformanceSeries(ctx, "default", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetPerformanceSeries failed: %v", err)
+			}
+			if len(series.Snapshots) != tt.wantCount {
+				t.Fatalf("Expected %d snapshots, got %d", tt.wantCount, len(series.Snapshots))
+			}
+			for i := 1; i < len(series.Snapshots); i++ {
+				if !series.Snapshots[i-1].Timestamp.Before(series.Snapshots[i].Timestamp) {
+					t.Errorf("Expected snapshots oldest first, got %v", series.Snapshots)
+					break
+				}
+			}
+			if math.Abs(series.TimeWeightedReturn-tt.wantTWR) > 1e-12 {
+				t.Errorf("Expected TWR %.6f, got %.6f", tt.wantTWR, series.TimeWeightedReturn)
+			}
+		})
+	}
+}
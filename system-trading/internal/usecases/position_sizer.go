@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+type SizingMethod string
+
+const (
+	SizingMethodFixedFractional SizingMethod = "FIXED_FRACTIONAL"
+	SizingMethodKelly           SizingMethod = "KELLY"
+)
+
+type PositionSizingConfig struct {
+	Method SizingMethod
+	// RiskFraction is the share of equity put at risk per trade in fixed-fractional mode.
+	RiskFraction float64
+	// KellyFraction scales the full Kelly fraction (0.5 = half-Kelly).
+	KellyFraction float64
+	// LotSize rounds quantities down to a tradable multiple; zero or less disables rounding.
+	LotSize float64
+}
+
+type PositionSizingRequest struct {
+	Equity               float64
+	AvailableCash        float64
+	Price                float64
+	CurrentPositionValue float64
+	// StopDistance is the per-unit loss if the stop is hit. When zero, fixed-fractional
+	// sizing allocates RiskFraction of equity as notional instead.
+	StopDistance   float64
+	WinProbability float64
+	WinLossRatio   float64
+}
+
+type PositionSizer struct {
+	config     PositionSizingConfig
+	riskLimits *interfaces.RiskLimits
+}
+
+func NewPositionSizer(config PositionSizingConfig, riskLimits *interfaces.RiskLimits) *PositionSizer {
+	return &PositionSizer{
+		config:     config,
+		riskLimits: riskLimits,
+	}
+}
+
+// CalculateQuantity returns the target order quantity for the configured method,
+// capped by the position size limit, available cash and lot size.
+func (s *PositionSizer) CalculateQuantity(req PositionSizingRequest) (float64, error) {
+	if req.Equity <= 0 {
+		return 0, fmt.Errorf("equity must be positive, got: %f", req.Equity)
+	}
+	if req.Price <= 0 {
+		return 0, fmt.Errorf("price must be positive, got: %f", req.Price)
+	}
+
+	var quantity float64
+	switch s.config.Method {
+	case SizingMethodFixedFractional:
+		quantity = s.fixedFractionalQuantity(req)
+	case SizingMethodKelly:
+		q, err := s.kellyQuantity(req)
+		if err != nil {
+			return 0, err
+		}
+		quantity = q
+	default:
+		return 0, fmt.Errorf("unsupported sizing method: %s", s.config.Method)
+	}
+
+	return s.applyCaps(req, quantity), nil
+}
+
+// KellyFraction returns the full Kelly fraction f* = p - (1-p)/b, floored at zero.
+func KellyFraction(winProbability, winLossRatio float64) float64 {
+	if winLossRatio <= 0 {
+		return 0
+	}
+	f := winProbability - (1-winProbability)/winLossRatio
+	if f < 0 {
+		return 0
+	}
+	return f
+}
+
+func (s *PositionSizer) fixedFractionalQuantity(req PositionSizingRequest) float64 {
+	riskAmount := req.Equity * s.config.RiskFraction
+	if req.StopDistance > 0 {
+		return riskAmount / req.StopDistance
+	}
+	return riskAmount / req.Price
+}
+
+func (s *PositionSizer) kellyQuantity(req PositionSizingRequest) (float64, error) {
+	if req.WinProbability < 0 || req.WinProbability > 1 {
+		return 0, fmt.Errorf("win probability must be between 0 and 1, got: %f", req.WinProbability)
+	}
+	if req.WinLossRatio <= 0 {
+		return 0, fmt.Errorf("win/loss ratio must be positive, got: %f", req.WinLossRatio)
+	}
+
+	fraction := KellyFraction(req.WinProbability, req.WinLossRatio) * s.config.KellyFraction
+	return req.Equity * fraction / req.Price, nil
+}
+
+func (s *PositionSizer) applyCaps(req PositionSizingRequest, quantity float64) float64 {
+	if s.riskLimits != nil && s.riskLimits.MaxPositionSize > 0 {
+		maxValue := req.Equity*s.riskLimits.MaxPositionSize - req.CurrentPositionValue
+		quantity = math.Min(quantity, maxValue/req.Price)
+	}
+
+	quantity = math.Min(quantity, req.AvailableCash/req.Price)
+
+	if s.config.LotSize > 0 {
+		quantity = math.Floor(quantity/s.config.LotSize) * s.config.LotSize
+	}
+
+	if quantity < 0 {
+		return 0
+	}
+	return quantity
+}
@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"math"
+	"testing"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func TestPositionSizer_FixedFractionalVsKelly(t *testing.T) {
+	limits := &interfaces.RiskLimits{MaxPositionSize: 1.0}
+
+	req := PositionSizingRequest{
+		Equity:         100000,
+		AvailableCash:  100000,
+		Price:          50,
+		StopDistance:   5,
+		WinProbability: 0.6,
+		WinLossRatio:   2.0,
+	}
+
+	fixed := NewPositionSizer(PositionSizingConfig{
+		Method:       SizingMethodFixedFractional,
+		RiskFraction: 0.01,
+		LotSize:      1,
+	}, limits)
+
+	kelly := NewPositionSizer(PositionSizingConfig{
+		Method:        SizingMethodKelly,
+		KellyFraction: 0.5,
+		LotSize:       1,
+	}, limits)
+
+	fixedQty, err := fixed.CalculateQuantity(req)
+	if err != nil {
+		t.Fatalf("Fixed-fractional sizing failed: %v", err)
+	}
+	// 1% of 100k = 1000 at risk, 5 per share stop distance -> 200 shares
+	if fixedQty != 200 {
+		t.Errorf("Expected fixed-fractional quantity 200, got %f", fixedQty)
+	}
+
+	kellyQty, err := kelly.CalculateQuantity(req)
+	if err != nil {
+		t.Fatalf("Kelly sizing failed: %v", err)
+	}
+	// f* = 0.6 - 0.4/2 = 0.4, half-Kelly = 0.2 of equity = 20000 / 50 -> 400 shares
+	if kellyQty != 400 {
+		t.Errorf("Expected half-Kelly quantity 400, got %f", kellyQty)
+	}
+
+	if kellyQty <= fixedQty {
+		t.Errorf("Expected Kelly to size larger than 1%% fixed-fractional for a 60%%/2:1 edge, got kelly=%f fixed=%f",
+			kellyQty, fixedQty)
+	}
+}
+
+func TestPositionSizer_KellyNoEdge(t *testing.T) {
+	sizer := NewPositionSizer(PositionSizingConfig{
+		Method:        SizingMethodKelly,
+		KellyFraction: 1.0,
+	}, &interfaces.RiskLimits{MaxPositionSize: 1.0})
+
+	qty, err := sizer.CalculateQuantity(PositionSizingRequest{
+		Equity:         100000,
+		AvailableCash:  100000,
+		Price:          100,
+		WinProbability: 0.4,
+		WinLossRatio:   1.0,
+	})
+	if err != nil {
+		t.Fatalf("Kelly sizing failed: %v", err)
+	}
+	if qty != 0 {
+		t.Errorf("Expected zero quantity for negative edge, got %f", qty)
+	}
+}
+
+func TestPositionSizer_Caps(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   *interfaces.RiskLimits
+		req      PositionSizingRequest
+		lotSize  float64
+		expected float64
+	}{
+		{
+			name:   "Capped by max position size",
+			limits: &interfaces.RiskLimits{MaxPositionSize: 0.1},
+			req: PositionSizingRequest{
+				Equity:         100000,
+				AvailableCash:  100000,
+				Price:          100,
+				WinProbability: 0.7,
+				WinLossRatio:   3.0,
+			},
+			lotSize:  1,
+			expected: 100, // 10% of 100k / 100
+		},
+		{
+			name:   "Existing position consumes limit",
+			limits: &interfaces.RiskLimits{MaxPositionSize: 0.1},
+			req: PositionSizingRequest{
+				Equity:               100000,
+				AvailableCash:        100000,
+				Price:                100,
+				CurrentPositionValue: 6000,
+				WinProbability:       0.7,
+				WinLossRatio:         3.0,
+			},
+			lotSize:  1,
+			expected: 40,
+		},
+		{
+			name:   "Capped by available cash",
+			limits: &interfaces.RiskLimits{MaxPositionSize: 1.0},
+			req: PositionSizingRequest{
+				Equity:         100000,
+				AvailableCash:  2500,
+				Price:          100,
+				WinProbability: 0.7,
+				WinLossRatio:   3.0,
+			},
+			lotSize:  1,
+			expected: 25,
+		},
+		{
+			name:   "Rounded down to lot size",
+			limits: &interfaces.RiskLimits{MaxPositionSize: 0.1},
+			req: PositionSizingRequest{
+				Equity:         100000,
+				AvailableCash:  100000,
+				Price:          30,
+				WinProbability: 0.7,
+				WinLossRatio:   3.0,
+			},
+			lotSize:  100,
+			expected: 300, // 333.33 shares allowed -> 300
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizer := NewPositionSizer(PositionSizingConfig{
+				Method:        SizingMethodKelly,
+				KellyFraction: 1.0,
+				LotSize:       tt.lotSize,
+			}, tt.limits)
+
+			qty, err := sizer.CalculateQuantity(tt.req)
+			if err != nil {
+				t.Fatalf("Sizing failed: %v", err)
+			}
+			if math.Abs(qty-tt.expected) > 1e-9 {
+				t.Errorf("Expected quantity %f, got %f", tt.expected, qty)
+			}
+			if qty*tt.req.Price+tt.req.CurrentPositionValue > tt.req.Equity*tt.limits.MaxPositionSize+1e-9 {
+				t.Errorf("Position size cap violated: %f", qty*tt.req.Price)
+			}
+		})
+	}
+}
+
+func TestPositionSizer_InvalidInputs(t *testing.T) {
+	sizer := NewPositionSizer(PositionSizingConfig{Method: SizingMethodKelly, KellyFraction: 1}, nil)
+
+	if _, err := sizer.CalculateQuantity(PositionSizingRequest{Equity: 0, Price: 100}); err == nil {
+		t.Error("Expected error for zero equity")
+	}
+	if _, err := sizer.CalculateQuantity(PositionSizingRequest{Equity: 1000, Price: 0}); err == nil {
+		t.Error("Expected error for zero price")
+	}
+	if _, err := sizer.CalculateQuantity(PositionSizingRequest{Equity: 1000, Price: 10, WinProbability: 1.5, WinLossRatio: 1}); err == nil {
+		t.Error("Expected error for out-of-range win probability")
+	}
+}
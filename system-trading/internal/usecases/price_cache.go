@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// CachedPrice is a cached last price annotated with how old it was when read
+type CachedPrice struct {
+	Symbol    entities.Symbol `json:"symbol"`
+	Price     float64         `json:"price"`
+	Timestamp time.Time       `json:"timestamp"`
+	Age       time.Duration   `json:"age"`
+	// Stale is set when Age exceeds the cache's staleness threshold
+	Stale bool `json:"stale"`
+}
+
+// PriceCache keeps the latest price per symbol from raw.market_data so that risk
+// checks and validation can price orders without a provider round trip
+type PriceCache struct {
+	messageBus interfaces.MessageBus
+	staleAfter time.Duration
+	clock      interfaces.Clock
+
+	mu     sync.RWMutex
+	prices map[entities.Symbol]*entities.MarketData
+}
+
+// NewPriceCache creates a cache whose entries are reported stale once they are
+// older than staleAfter. A zero staleAfter never marks prices stale.
+func NewPriceCache(messageBus interfaces.MessageBus, staleAfter time.Duration, clock interfaces.Clock) *PriceCache {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &PriceCache{
+		messageBus: messageBus,
+		staleAfter: staleAfter,
+		clock:      clock,
+		prices:     make(map[entities.Symbol]*entities.MarketData),
+	}
+}
+
+func (c *PriceCache) Start(ctx context.Context) error {
+	if err := c.messageBus.Subscribe(ctx, "raw.market_data", c.handleMarketData); err != nil {
+		return fmt.Errorf("failed to subscribe to raw.market_data: %w", err)
+	}
+	return nil
+}
+
+// Update stores data unless the cache already holds a newer price for the symbol
+func (c *PriceCache) Update(data *entities.MarketData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, exists := c.prices[data.Symbol]; exists && current.Timestamp.After(data.Timestamp) {
+		return
+	}
+	stored := *data
+	c.prices[data.Symbol] = &stored
+}
+
+// Get returns the cached price for symbol annotated with its current age
+func (c *PriceCache) Get(symbol entities.Symbol) (CachedPrice, bool) {
+	c.mu.RLock()
+	data, exists := c.prices[symbol]
+	c.mu.RUnlock()
+
+	if !exists {
+		return CachedPrice{}, false
+	}
+
+	age := c.clock.Now().Sub(data.Timestamp)
+	return CachedPrice{
+		Symbol:    symbol,
+		Price:     data.Price,
+		Timestamp: data.Timestamp,
+		Age:       age,
+		Stale:     c.staleAfter > 0 && age > c.staleAfter,
+	}, true
+}
+
+func (c *PriceCache) handleMarketData(ctx context.Context, data []byte) error {
+	var marketData entities.MarketData
+	if err := interfaces.DecodeMessage(ctx, data, &marketData); err != nil {
+		return fmt.Errorf("invalid market data: %w", err)
+	}
+	c.Update(&marketData)
+	return nil
+}
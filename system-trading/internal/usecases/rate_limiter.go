@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// RateLimit is a token bucket allowing Burst requests at once and refilling at
+// Rate requests per second
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type tokenBucket struct {
+	limit    RateLimit
+	tokens   float64
+	updated  time.Time
+	rejected int64
+}
+
+// RateLimiter applies an independent token bucket per key, e.g. per strategy
+type RateLimiter struct {
+	clock     interfaces.Clock
+	defaults  RateLimit
+	overrides map[string]RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func NewRateLimiter(defaults RateLimit, clock interfaces.Clock) *RateLimiter {
+	return &RateLimiter{
+		clock:     clock,
+		defaults:  defaults,
+		overrides: make(map[string]RateLimit),
+		buckets:   make(map[string]*tokenBucket),
+	}
+}
+
+// SetLimit overrides the default limit for one key
+func (l *RateLimiter) SetLimit(key string, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[key] = limit
+	if bucket, ok := l.buckets[key]; ok {
+		bucket.limit = limit
+		bucket.tokens = math.Min(bucket.tokens, float64(limit.Burst))
+	}
+}
+
+// Allow takes a token for key. When none is left it returns false and how long
+// until the next token is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		limit, ok := l.overrides[key]
+		if !ok {
+			limit = l.defaults
+		}
+		bucket = &tokenBucket{limit: limit, tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens = math.Min(float64(bucket.limit.Burst), bucket.tokens+elapsed*bucket.limit.Rate)
+	}
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	bucket.rejected++
+	if bucket.limit.Rate <= 0 {
+		return false, 0
+	}
+	wait := (1 - bucket.tokens) / bucket.limit.Rate
+	return false, time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+// Rejections returns how many requests each key has had rejected
+func (l *RateLimiter) Rejections() map[string]int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rejections := make(map[string]int64, len(l.buckets))
+	for key, bucket := range l.buckets {
+		if bucket.rejected > 0 {
+			rejections[key] = bucket.rejected
+		}
+	}
+	return rejections
+}
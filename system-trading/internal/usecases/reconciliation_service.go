@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	brokerifs "github.com/system-trading/core/internal/interfaces"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const AuditTypeReconciliation = "reconciliation"
+
+// AccountInfoProvider is the subset of the broker Trader used for reconciliation
+type AccountInfoProvider interface {
+	GetAccountInfo(ctx context.Context) (*brokerifs.AccountInfo, error)
+}
+
+type ReconciliationService struct {
+	portfolioService *PortfolioService
+	broker           AccountInfoProvider
+	auditRepo        interfaces.AuditRepository
+	logger           interfaces.Logger
+	metrics          interfaces.MetricsCollector
+	tolerance        float64
+
+	mu         sync.RWMutex
+	lastReport *ReconciliationReport
+}
+
+func NewReconciliationService(
+	portfolioService *PortfolioService,
+	broker AccountInfoProvider,
+	auditRepo interfaces.AuditRepository,
+	logger interfaces.Logger,
+	metrics interfaces.MetricsCollector,
+	tolerance float64,
+) *ReconciliationService {
+	return &ReconciliationService{
+		portfolioService: portfolioService,
+		broker:           broker,
+		auditRepo:        auditRepo,
+		logger:           logger,
+		metrics:          metrics,
+		tolerance:        tolerance,
+	}
+}
+
+// Reconcile compares the internal portfolio's cash and positions against the broker
+// account. Differences larger than the configured tolerance are reported as
+// discrepancies, logged, and written to the audit store.
+func (s *ReconciliationService) Reconcile(ctx context.Context, portfolioID string) (*ReconciliationReport, error) {
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	account, err := s.broker.GetAccountInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker account info: %w", err)
+	}
+
+	report := &ReconciliationReport{
+		PortfolioID:     portfolioID,
+		BrokerAccountID: account.AccountID,
+		Tolerance:       s.tolerance,
+		GeneratedAt:     time.Now(),
+		Discrepancies:   []ReconciliationDiscrepancy{},
+	}
+
+	s.compare(report, "cash", "", portfolio.Cash, account.CashBalance)
+
+	brokerQuantities := make(map[entities.Symbol]float64, len(account.Positions))
+	for _, position := range account.Positions {
+		brokerQuantities[entities.Symbol(position.Symbol)] += position.Quantity
+	}
+
+	symbols := make(map[entities.Symbol]bool)
+	for symbol := range portfolio.Positions {
+		symbols[symbol] = true
+	}
+	for symbol := range brokerQuantities {
+		symbols[symbol] = true
+	}
+
+	sorted := make([]entities.Symbol, 0, len(symbols))
+	for symbol := range symbols {
+		sorted = append(sorted, symbol)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, symbol := range sorted {
+		internalQty := 0.0
+		if position, exists := portfolio.Positions[symbol]; exists {
+			internalQty = position.Quantity
+		}
+		s.compare(report, "position_quantity", symbol, internalQty, brokerQuantities[symbol])
+	}
+
+	report.Reconciled = len(report.Discrepancies) == 0
+
+	s.mu.Lock()
+	s.lastReport = report
+	s.mu.Unlock()
+
+	s.recordReport(ctx, report)
+
+	return report, nil
+}
+
+// LastReport returns the most recently generated report, or nil if none has run
+func (s *ReconciliationService) LastReport() *ReconciliationReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastReport
+}
+
+func (s *ReconciliationService) compare(report *ReconciliationReport, field string, symbol entities.Symbol, internal, broker float64) {
+	diff := broker - internal
+	if math.Abs(diff) <= s.tolerance {
+		return
+	}
+
+	report.Discrepancies = append(report.Discrepancies, ReconciliationDiscrepancy{
+		Field:      field,
+		Symbol:     symbol,
+		Internal:   internal,
+		Broker:     broker,
+		Difference: diff,
+	})
+}
+
+func (s *ReconciliationService) recordReport(ctx context.Context, report *ReconciliationReport) {
+	for _, discrepancy := range report.Discrepancies {
+		s.logger.Warn("Reconciliation discrepancy detected",
+			interfaces.Field{Key: "portfolio_id", Value: report.PortfolioID},
+			interfaces.Field{Key: "field", Value: discrepancy.Field},
+			interfaces.Field{Key: "symbol", Value: discrepancy.Symbol},
+			interfaces.Field{Key: "internal", Value: discrepancy.Internal},
+			interfaces.Field{Key: "broker", Value: discrepancy.Broker},
+			interfaces.Field{Key: "difference", Value: discrepancy.Difference},
+		)
+	}
+
+	s.metrics.SetGauge("reconciliation_discrepancies", float64(len(report.Discrepancies)), map[string]string{
+		"portfolio_id": report.PortfolioID,
+	})
+
+	if s.auditRepo != nil {
+		record := &interfaces.AuditRecord{
+			Type:      AuditTypeReconciliation,
+			Timestamp: report.GeneratedAt,
+			Data:      report,
+		}
+		if err := s.auditRepo.SaveAuditRecord(ctx, record); err != nil {
+			s.logger.Error("Failed to write reconciliation report to audit store",
+				interfaces.Field{Key: "portfolio_id", Value: report.PortfolioID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+		}
+	}
+
+	s.logger.Info("Reconciliation completed",
+		interfaces.Field{Key: "portfolio_id", Value: report.PortfolioID},
+		interfaces.Field{Key: "reconciled", Value: report.Reconciled},
+		interfaces.Field{Key: "discrepancies", Value: len(report.Discrepancies)},
+	)
+}
+
+type ReconciliationReport struct {
+	PortfolioID     string                      `json:"portfolio_id"`
+	BrokerAccountID string                      `json:"broker_account_id"`
+	Tolerance       float64                     `json:"tolerance"`
+	Reconciled      bool                        `json:"reconciled"`
+	Discrepancies   []ReconciliationDiscrepancy `json:"discrepancies"`
+	GeneratedAt     time.Time                   `json:"generated_at"`
+}
+
+type ReconciliationDiscrepancy struct {
+	Field      string          `json:"field"`
+	Symbol     entities.Symbol `json:"symbol,omitempty"`
+	Internal   float64         `json:"internal"`
+	Broker     float64         `json:"broker"`
+	Difference float64         `json:"difference"`
+}
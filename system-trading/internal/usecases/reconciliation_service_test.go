@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	brokerifs "github.com/system-trading/core/internal/interfaces"
+)
+
+type fakeAccountProvider struct {
+	account *brokerifs.AccountInfo
+}
+
+func (p *fakeAccountProvider) GetAccountInfo(ctx context.Context) (*brokerifs.AccountInfo, error) {
+	return p.account, nil
+}
+
+func TestReconciliationService_FlagsDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	portfolioRepo := repositories.NewInMemoryPortfolioRepository()
+	auditRepo := repositories.NewInMemoryAuditRepository()
+	testLogger := newTestLogger(t)
+
+	portfolio := entities.NewPortfolio(100000)
+	portfolio.ID = "default"
+	portfolio.AddPosition("AAPL", 100, 150)
+	portfolio.AddPosition("MSFT", 50, 300)
+	if err := portfolioRepo.Save(ctx, portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+
+	// Broker saw an extra 10 AAPL fill that the portfolio missed, and cash differs accordingly
+	broker := &fakeAccountProvider{account: &brokerifs.AccountInfo{
+		AccountID:   "ACC-1",
+		CashBalance: portfolio.Cash - 1500,
+		Positions: []brokerifs.Position{
+			{Symbol: "AAPL", Quantity: 110},
+			{Symbol: "MSFT", Quantity: 50.001},
+		},
+	}}
+
+	portfolioService := NewPortfolioService(portfolioRepo, messagebus.NewMockMessageBus(), testLogger, newTestMetrics("reconciliation"))
+	service := NewReconciliationService(portfolioService, broker, auditRepo, testLogger, newTestMetrics("reconciliation"), 0.01)
+
+	report, err := service.Reconcile(ctx, "default")
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if report.Reconciled {
+		t.Fatal("Expected report to flag discrepancies")
+	}
+	if len(report.Discrepancies) != 2 {
+		t.Fatalf("Expected 2 discrepancies (cash, AAPL), got %d: %+v", len(report.Discrepancies), report.Discrepancies)
+	}
+
+	cash := report.Discrepancies[0]
+	if cash.Field != "cash" || math.Abs(cash.Difference-(-1500)) > 1e-9 {
+		t.Errorf("Unexpected cash discrepancy: %+v", cash)
+	}
+
+	aapl := report.Discrepancies[1]
+	if aapl.Field != "position_quantity" || aapl.Symbol != "AAPL" ||
+		aapl.Internal != 100 || aapl.Broker != 110 || aapl.Difference != 10 {
+		t.Errorf("Unexpected AAPL discrepancy: %+v", aapl)
+	}
+
+	if service.LastReport() != report {
+		t.Error("Expected LastReport to return the generated report")
+	}
+
+	records, err := auditRepo.ListAuditRecords(ctx, AuditTypeReconciliation, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to list audit records: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 reconciliation audit record, got %d", len(records))
+	}
+}
+
+func TestReconciliationService_CleanReport(t *testing.T) {
+	ctx := context.Background()
+	portfolioRepo := repositories.NewInMemoryPortfolioRepository()
+	testLogger := newTestLogger(t)
+
+	portfolio := entities.NewPortfolio(50000)
+	portfolio.ID = "default"
+	if err := portfolioRepo.Save(ctx, portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+
+	broker := &fakeAccountProvider{account: &brokerifs.AccountInfo{CashBalance: 50000.005}}
+
+	portfolioService := NewPortfolioService(portfolioRepo, messagebus.NewMockMessageBus(), testLogger, newTestMetrics("reconciliation"))
+	service := NewReconciliationService(portfolioService, broker, nil, testLogger, newTestMetrics("reconciliation"), 0.01)
+
+	report, err := service.Reconcile(ctx, "default")
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if !report.Reconciled || len(report.Discrepancies) != 0 {
+		t.Errorf("Expected clean report within tolerance, got %+v", report.Discrepancies)
+	}
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// RelativeReturn compares a portfolio's return with a benchmark's over the
+// days both have a closing value for. Beta and Alpha regress the portfolio's
+// daily returns on the benchmark's; Alpha is per day.
+type RelativeReturn struct {
+	PortfolioID     string          `json:"portfolio_id"`
+	Benchmark       entities.Symbol `json:"benchmark"`
+	From            time.Time       `json:"from"`
+	To              time.Time       `json:"to"`
+	Days            int             `json:"days"`
+	PortfolioReturn float64         `json:"portfolio_return"`
+	BenchmarkReturn float64         `json:"benchmark_return"`
+	ExcessReturn    float64         `json:"excess_return"`
+	Beta            float64         `json:"beta"`
+	Alpha           float64         `json:"alpha"`
+}
+
+// SetMarketDataRepository supplies the benchmark price history
+// CalculateRelativeReturn compares against
+func (s *PortfolioService) SetMarketDataRepository(repo interfaces.MarketDataRepository) {
+	s.marketData = repo
+}
+
+// CalculateRelativeReturn measures portfolioID against benchmarkSymbol over
+// [from, to] using the portfolio's snapshots and the benchmark's price history.
+// Each series is reduced to its last value per UTC day and only days present in
+// both are compared.
+func (s *PortfolioService) CalculateRelativeReturn(ctx context.Context, portfolioID string, benchmarkSymbol entities.Symbol, from, to time.Time) (*RelativeReturn, error) {
+	if s.marketData == nil {
+		return nil, fmt.Errorf("no market data repository configured")
+	}
+
+	series, err := s.GetPerformanceSeries(ctx, portfolioID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.marketData.GetMarketDataHistory(ctx, benchmarkSymbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s history: %w", benchmarkSymbol, err)
+	}
+
+	portfolioCloses := make(map[time.Time]dailyClose)
+	for _, snapshot := range series.Snapshots {
+		addDailyClose(portfolioCloses, snapshot.Timestamp, snapshot.TotalValue)
+	}
+	benchmarkCloses := make(map[time.Time]dailyClose)
+	for _, data := range history {
+		if data != nil {
+			addDailyClose(benchmarkCloses, data.Timestamp, data.Price)
+		}
+	}
+
+	var days []time.Time
+	for day := range portfolioCloses {
+		if _, exists := benchmarkCloses[day]; exists {
+			days = append(days, day)
+		}
+	}
+	if len(days) < 2 {
+		return nil, fmt.Errorf("need at least 2 days with both portfolio and %s values, got %d", benchmarkSymbol, len(days))
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	portfolioReturns := make([]float64, 0, len(days)-1)
+	benchmarkReturns := make([]float64, 0, len(days)-1)
+	for i := 1; i < len(days); i++ {
+		portfolioReturns = append(portfolioReturns, portfolioCloses[days[i]].value/portfolioCloses[days[i-1]].value-1)
+		benchmarkReturns = append(benchmarkReturns, benchmarkCloses[days[i]].value/benchmarkCloses[days[i-1]].value-1)
+	}
+
+	first, last := days[0], days[len(days)-1]
+	result := &RelativeReturn{
+		PortfolioID:     portfolioID,
+		Benchmark:       benchmarkSymbol,
+		From:            from,
+		To:              to,
+		Days:            len(days),
+		PortfolioReturn: portfolioCloses[last].value/portfolioCloses[first].value - 1,
+		BenchmarkReturn: benchmarkCloses[last].value/benchmarkCloses[first].value - 1,
+	}
+	result.ExcessReturn = result.PortfolioReturn - result.BenchmarkReturn
+	result.Beta, result.Alpha = regressReturns(portfolioReturns, benchmarkReturns)
+
+	return result, nil
+}
+
+// dailyClose is the last value seen on a day
+type dailyClose struct {
+	at    time.Time
+	value float64
+}
+
+func addDailyClose(closes map[time.Time]dailyClose, at time.Time, value float64) {
+	if value <= 0 {
+		return
+	}
+	day := at.UTC().Truncate(24 * time.Hour)
+	if last, exists := closes[day]; !exists || !at.Before(last.at) {
+		closes[day] = dailyClose{at: at, value: value}
+	}
+}
+
+// regressReturns fits y = alpha + beta·x by least squares. Beta is zero when x
+// does not vary.
+func regressReturns(y, x []float64) (beta, alpha float64) {
+	n := float64(len(x))
+	if n == 0 {
+		return 0, 0
+	}
+
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var covariance, variance float64
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+		variance += (x[i] - meanX) * (x[i] - meanX)
+	}
+	if variance > 0 {
+		beta = covariance / variance
+	}
+	return beta, meanY - beta*meanX
+}
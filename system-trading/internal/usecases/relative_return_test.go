@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+func TestPortfolioService_RelativeReturnOfTrackingPortfolio(t *testing.T) {
+	service, repo := setupPortfolioService(t, 100000)
+	snapshots := &reversedSnapshotRepository{}
+	service.SetSnapshotRepository(snapshots)
+	ctx := context.Background()
+
+	day := time.Date(2024, 3, 4, 16, 0, 0, 0, time.UTC)
+	prices := []float64{100, 103, 99, 104, 110, 107}
+	var history []*entities.MarketData
+	for i, price := range prices {
+		history = append(history, &entities.MarketData{Symbol: "SPY", Price: price, Timestamp: day.AddDate(0, 0, i)})
+	}
+	service.SetMarketDataRepository(&historyRepository{history: history})
+
+	// The portfolio is worth 1000 shares of the benchmark, with no snapshot on
+	// the third day and a stale intraday snapshot before each close
+	for i, price := range prices {
+		if i == 2 {
+			continue
+		}
+		for _, snap := range []struct {
+			at    time.Time
+			value float64
+		}{
+			{day.AddDate(0, 0, i).Add(-4 * time.Hour), 1},
+			{day.AddDate(0, 0, i), price * 1000},
+		} {
+			portfolio, err := repo.GetByID(ctx, "default")
+			if err != nil {
+				t.Fatalf("Failed to get portfolio: %v", err)
+			}
+			portfolio.Cash, portfolio.TotalValue = snap.value, snap.value
+			if err := repo.Save(ctx, portfolio); err != nil {
+				t.Fatalf("Failed to save portfolio: %v", err)
+			}
+			if _, err := service.snapshotPortfolio(ctx, "default", snap.at); err != nil {
+				t.Fatalf("snapshotPortfolio failed: %v", err)
+			}
+		}
+	}
+
+	result, err := service.CalculateRelativeReturn(ctx, "default", "SPY", day, day.AddDate(0, 0, len(prices)))
+	if err != nil {
+		t.Fatalf("CalculateRelativeReturn failed: %v", err)
+	}
+
+	if result.Days != 5 {
+		t.Errorf("Expected 5 aligned days, got %d", result.Days)
+	}
+	if math.Abs(result.BenchmarkReturn-0.07) > 1e-9 || math.Abs(result.PortfolioReturn-0.07) > 1e-9 {
+		t.Errorf("Expected 7%% returns, got portfolio %v and benchmark %v", result.PortfolioReturn, result.BenchmarkReturn)
+	}
+	if math.Abs(result.ExcessReturn) > 1e-9 {
+		t.Errorf("Expected no excess return, got %v", result.ExcessReturn)
+	}
+	if math.Abs(result.Beta-1) > 1e-9 || math.Abs(result.Alpha) > 1e-9 {
+		t.Errorf("Expected beta 1 and alpha 0, got beta %v and alpha %v", result.Beta, result.Alpha)
+	}
+
+	if _, err := service.CalculateRelativeReturn(ctx, "default", "QQQ", day, day.AddDate(0, 0, len(prices))); err == nil {
+		t.Error("Expected an error when the benchmark has no overlapping history")
+	}
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// RiskAlertSink is a destination for risk alerts, e.g. a log, webhook or store
+type RiskAlertSink = BroadcastSink[RiskAlertMessage]
+
+// RiskAlertFanout delivers every risk.alert to all registered sinks concurrently
+type RiskAlertFanout struct {
+	messageBus interfaces.MessageBus
+	broadcast  *Broadcast[RiskAlertMessage]
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+}
+
+func NewRiskAlertFanout(
+	messageBus interfaces.MessageBus,
+	logger interfaces.Logger,
+	metrics interfaces.MetricsCollector,
+	config BroadcastConfig,
+) *RiskAlertFanout {
+	return &RiskAlertFanout{
+		messageBus: messageBus,
+		broadcast:  NewBroadcast[RiskAlertMessage]("risk_alert", config, logger, metrics),
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (f *RiskAlertFanout) AddSink(sink RiskAlertSink) error {
+	return f.broadcast.Register(sink)
+}
+
+func (f *RiskAlertFanout) Start(ctx context.Context) error {
+	if err := f.messageBus.Subscribe(ctx, "risk.alert", f.handleRiskAlert); err != nil {
+		return fmt.Errorf("failed to subscribe to risk.alert: %w", err)
+	}
+	return nil
+}
+
+// Stop flushes queued alerts to sinks until ctx is done
+func (f *RiskAlertFanout) Stop(ctx context.Context) error {
+	return f.broadcast.Close(ctx)
+}
+
+// Stats returns per-sink delivery counts
+func (f *RiskAlertFanout) Stats() map[string]SinkStats {
+	return f.broadcast.Stats()
+}
+
+func (f *RiskAlertFanout) handleRiskAlert(ctx context.Context, data []byte) error {
+	var alert RiskAlertMessage
+	if err := interfaces.DecodeMessage(ctx, data, &alert); err != nil {
+		return fmt.Errorf("invalid risk alert: %w", err)
+	}
+	return f.broadcast.Publish(alert)
+}
+
+// LogAlertSink writes risk alerts to the application log
+type LogAlertSink struct {
+	logger interfaces.Logger
+}
+
+func NewLogAlertSink(logger interfaces.Logger) *LogAlertSink {
+	return &LogAlertSink{logger: logger}
+}
+
+func (s *LogAlertSink) Name() string {
+	return "log"
+}
+
+func (s *LogAlertSink) Deliver(ctx context.Context, alert RiskAlertMessage) error {
+	s.logger.Warn("Risk alert",
+		interfaces.Field{Key: "alert_type", Value: alert.AlertType},
+		interfaces.Field{Key: "severity", Value: alert.Severity},
+		interfaces.Field{Key: "symbol", Value: alert.Symbol},
+		interfaces.Field{Key: "message", Value: alert.Message},
+		interfaces.Field{Key: "timestamp", Value: alert.Timestamp},
+	)
+	return nil
+}
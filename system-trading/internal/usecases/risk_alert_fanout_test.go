@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+)
+
+type recordingAlertSink struct {
+	name string
+	mu   sync.Mutex
+	got  []RiskAlertMessage
+}
+
+func (s *recordingAlertSink) Name() string { return s.name }
+
+func (s *recordingAlertSink) Deliver(ctx context.Context, alert RiskAlertMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.got = append(s.got, alert)
+	return nil
+}
+
+func (s *recordingAlertSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.got)
+}
+
+// stuckAlertSink never finishes a delivery before its timeout
+type stuckAlertSink struct{}
+
+func (stuckAlertSink) Name() string { return "stuck" }
+
+func (stuckAlertSink) Deliver(ctx context.Context, alert RiskAlertMessage) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRiskAlertFanout_SlowSinkDoesNotStarveFastSink(t *testing.T) {
+	bus := messagebus.NewMockMessageBus()
+	fanout := NewRiskAlertFanout(bus, newTestLogger(t), newTestMetrics("fanout"), BroadcastConfig{
+		BufferSize:      2,
+		DeliveryTimeout: 20 * time.Millisecond,
+	})
+
+	fast := &recordingAlertSink{name: "fast"}
+	if err := fanout.AddSink(fast); err != nil {
+		t.Fatalf("AddSink(fast) failed: %v", err)
+	}
+	if err := fanout.AddSink(stuckAlertSink{}); err != nil {
+		t.Fatalf("AddSink(stuck) failed: %v", err)
+	}
+	if err := fanout.AddSink(&recordingAlertSink{name: "fast"}); err == nil {
+		t.Error("Expected duplicate sink name to be rejected")
+	}
+
+	ctx := context.Background()
+	if err := fanout.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	handler := bus.GetHandler("risk.alert")
+
+	const alerts = 20
+	for i := 0; i < alerts; i++ {
+		data, _ := json.Marshal(RiskAlertMessage{
+			AlertType: "VAR_LIMIT",
+			Severity:  "HIGH",
+			Message:   fmt.Sprintf("alert %d", i),
+		})
+		if err := handler(ctx, data); err != nil {
+			t.Fatalf("Handler failed on alert %d: %v", i, err)
+		}
+
+		// Publish at the pace the fast sink keeps up with; the stuck sink is
+		// still blocked on its first alerts the whole time
+		deadline := time.Now().Add(time.Second)
+		for fast.count() <= i && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Microsecond)
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := fanout.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if fast.count() != alerts {
+		t.Fatalf("Expected fast sink to receive all %d alerts, got %d", alerts, fast.count())
+	}
+	for i, alert := range fast.got {
+		if want := fmt.Sprintf("alert %d", i); alert.Message != want {
+			t.Errorf("Fast sink alert %d out of order: got %q", i, alert.Message)
+		}
+	}
+
+	stats := fanout.Stats()
+	if stats["fast"] != (SinkStats{Delivered: alerts}) {
+		t.Errorf("Unexpected fast sink stats: %+v", stats["fast"])
+	}
+
+	stuck := stats["stuck"]
+	if stuck.Dropped == 0 {
+		t.Errorf("Expected the slow sink to drop alerts once its buffer filled, got %+v", stuck)
+	}
+	if stuck.Delivered != 0 {
+		t.Errorf("Expected no successful deliveries to the slow sink, got %d", stuck.Delivered)
+	}
+	if total := stuck.Delivered + stuck.Failed + stuck.Dropped; total != alerts {
+		t.Errorf("Expected every alert accounted for on the slow sink, got %d of %d", total, alerts)
+	}
+
+	if err := fanout.AddSink(&recordingAlertSink{name: "late"}); err != ErrBroadcastClosed {
+		t.Errorf("Expected ErrBroadcastClosed after Stop, got %v", err)
+	}
+}
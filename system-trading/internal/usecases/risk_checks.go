@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+// DefaultMaxConcurrentRiskChecks is how many risk sub-checks run at once
+// unless RiskServiceConfig.MaxConcurrentChecks is set
+const DefaultMaxConcurrentRiskChecks = 4
+
+var riskSeverityRank = map[string]int{
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// riskCheck is one independent sub-check of ValidateOrder and the alert raised
+// when it fails
+type riskCheck struct {
+	alertType string
+	severity  string
+	validate  func() error
+}
+
+type riskCheckFailure struct {
+	check riskCheck
+	index int
+	err   error
+}
+
+// outrankedBy reports whether a failure of checks[index] takes precedence over f:
+// higher severity wins, and equal severities go to the earlier check
+func (f *riskCheckFailure) outrankedBy(check riskCheck, index int) bool {
+	rank, current := riskSeverityRank[check.severity], riskSeverityRank[f.check.severity]
+	return rank > current || (rank == current && index < f.index)
+}
+
+// orderRiskChecks lists the sub-checks that only read portfolio and order and
+// so can run concurrently
+func (s *RiskService) orderRiskChecks(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) []riskCheck {
+	return []riskCheck{
+		{"SHORT_SALE", "HIGH", func() error { return s.validateNoShortSale(portfolio, order) }},
+		{"INSUFFICIENT_CASH", "HIGH", func() error { return s.validateCashBalance(ctx, portfolio, order) }},
+		{"POSITION_SIZE_LIMIT", "HIGH", func() error { return s.validatePositionSize(ctx, portfolio, order) }},
+		{"CONCENTRATION_LIMIT", "MEDIUM", func() error { return s.validateConcentration(ctx, portfolio, order) }},
+		{"VAR_LIMIT", "HIGH", func() error { return s.validateVaRLimit(ctx, portfolio, order) }},
+		{"DAILY_LOSS_LIMIT", "CRITICAL", func() error { return s.validateDailyLossLimit(portfolio) }},
+	}
+}
+
+// runRiskChecks runs checks at most MaxConcurrentChecks at a time and returns
+// the failure with the highest precedence. A check that could not outrank a
+// failure already found is not started. Cancelling ctx stops starting checks.
+func (s *RiskService) runRiskChecks(ctx context.Context, checks []riskCheck) (*riskCheckFailure, error) {
+	limit := s.config.MaxConcurrentChecks
+	if limit <= 0 {
+		limit = DefaultMaxConcurrentRiskChecks
+	}
+	slots := make(chan struct{}, limit)
+
+	var (
+		mu    sync.Mutex
+		worst *riskCheckFailure
+		wg    sync.WaitGroup
+	)
+
+	for i, check := range checks {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		mu.Lock()
+		skip := worst != nil && !worst.outrankedBy(check, i)
+		mu.Unlock()
+		if skip {
+			<-slots
+			s.metrics.IncrementCounter("risk_checks_skipped", map[string]string{
+				"check": strings.ToLower(check.alertType),
+			})
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, check riskCheck) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			start := time.Now()
+			err := check.validate()
+			s.metrics.RecordDuration("risk_check_duration", time.Since(start).Seconds(), map[string]string{
+				"check": strings.ToLower(check.alertType),
+			})
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if worst == nil || worst.outrankedBy(check, i) {
+				worst = &riskCheckFailure{check: check, index: i, err: err}
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	if worst != nil {
+		return worst, nil
+	}
+	return nil, ctx.Err()
+}
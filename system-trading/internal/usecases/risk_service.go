@@ -2,8 +2,11 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/system-trading/core/internal/entities"
@@ -16,22 +19,226 @@ type RiskService struct {
 	logger           interfaces.Logger
 	metrics          interfaces.MetricsCollector
 	riskLimits       *interfaces.RiskLimits
+	config           RiskServiceConfig
+	clock            interfaces.Clock
+	startedAt        time.Time
+	// drawdowns tracks equity per portfolio so one portfolio's peak never masks
+	// another's losses
+	drawdowns        map[string]*DrawdownTracker
+	drawdownMu       sync.Mutex
+	priceCache       *PriceCache
+	prices           interfaces.LastPriceProvider
+	volatility       interfaces.VolatilityProvider
+	correlations     interfaces.CorrelationProvider
+	marketData       interfaces.MarketDataRepository
+	alertDebouncer   *Debouncer[riskAlertKey, RiskAlertMessage]
+	// stopLosses remembers the level each position's stop-loss last fired at so
+	// MonitorStopLosses publishes it once rather than on every pass
+	stopLosses   map[stopLossKey]stopLossLevel
+	stopLossesMu sync.Mutex
+
+	haltMu     sync.RWMutex
+	haltReason string
 }
 
+type RiskServiceConfig struct {
+	// WarmUpPeriod suppresses risk alerts right after startup while price and
+	// volatility estimates are still cold. Validation results are unaffected.
+	WarmUpPeriod time.Duration
+	// DegradedPolicy decides how ValidateOrder behaves when the portfolio cannot be
+	// fetched. DegradedMaxOrderValue is the notional cap applied under fail-open.
+	DegradedPolicy        DegradedPolicy
+	DegradedMaxOrderValue float64
+	// AutoFlattenOnDrawdown submits market orders closing every position when the
+	// max-drawdown breaker trips. Off by default: halting alone is the safe action.
+	AutoFlattenOnDrawdown bool
+	// StalePricePolicy decides whether market orders priced from a stale cached
+	// quote are only flagged or rejected outright
+	StalePricePolicy StalePricePolicy
+	// AlertDebounceInterval collapses repeated alerts of one type for one symbol
+	// to at most one per interval, always delivering the latest; zero disables it
+	AlertDebounceInterval time.Duration
+	// MaxConcurrentChecks bounds how many of ValidateOrder's sub-checks run at
+	// once; zero uses DefaultMaxConcurrentRiskChecks
+	MaxConcurrentChecks int
+	// VolatilityLookback is the price history the volatility provider estimates
+	// from; zero uses DefaultVolatilityLookback
+	VolatilityLookback time.Duration
+	// DefaultDailyVolatility is used without a provider or when it fails; zero
+	// uses DefaultDailyVolatility
+	DefaultDailyVolatility float64
+	// DefaultCorrelation is assumed between positions whose correlation is
+	// unknown; zero treats them as independent
+	DefaultCorrelation float64
+	// VaRMethod selects how CalculatePortfolioRisk computes VaR; empty is
+	// parametric. HistoricalVaRDays is the history the historical method
+	// replays; zero uses DefaultHistoricalVaRDays.
+	VaRMethod         VaRMethod
+	HistoricalVaRDays int
+	// SymbolLimits overrides MaxPositionSize, MaxConcentration and
+	// StopLossThreshold for individual symbols; a zero field inherits the global
+	// limit
+	SymbolLimits map[entities.Symbol]interfaces.RiskLimits
+	Clock        interfaces.Clock
+}
+
+const (
+	DefaultVolatilityLookback = 30 * 24 * time.Hour
+	DefaultDailyVolatility    = 0.02
+	// DefaultEstimatedPrice values market orders for which no price is known
+	DefaultEstimatedPrice = 100.0
+)
+
+type DegradedPolicy string
+
+const (
+	// DegradedPolicyFailClosed rejects every order while the portfolio is unavailable
+	DegradedPolicyFailClosed DegradedPolicy = "fail_closed"
+	// DegradedPolicyFailOpenReducedLimits allows orders up to DegradedMaxOrderValue
+	DegradedPolicyFailOpenReducedLimits DegradedPolicy = "fail_open_reduced_limits"
+)
+
+type StalePricePolicy string
+
+const (
+	// StalePricePolicyAnnotate allows the order but marks it PriceStale
+	StalePricePolicyAnnotate StalePricePolicy = "annotate"
+	// StalePricePolicyReject rejects orders that would be priced from a stale quote
+	StalePricePolicyReject StalePricePolicy = "reject"
+)
+
 func NewRiskService(
 	portfolioService *PortfolioService,
 	messageBus interfaces.MessageBus,
 	logger interfaces.Logger,
 	metrics interfaces.MetricsCollector,
 	riskLimits *interfaces.RiskLimits,
+	config RiskServiceConfig,
 ) *RiskService {
-	return &RiskService{
+	riskClock := config.Clock
+	if riskClock == nil {
+		riskClock = systemClock{}
+	}
+	if config.VolatilityLookback <= 0 {
+		config.VolatilityLookback = DefaultVolatilityLookback
+	}
+	if config.DefaultDailyVolatility <= 0 {
+		config.DefaultDailyVolatility = DefaultDailyVolatility
+	}
+	if config.HistoricalVaRDays <= 0 {
+		config.HistoricalVaRDays = DefaultHistoricalVaRDays
+	}
+
+	service := &RiskService{
 		portfolioService: portfolioService,
 		messageBus:       messageBus,
 		logger:           logger,
 		metrics:          metrics,
 		riskLimits:       riskLimits,
+		config:           config,
+		clock:            riskClock,
+		startedAt:        riskClock.Now(),
+		drawdowns:        make(map[string]*DrawdownTracker),
+		stopLosses:       make(map[stopLossKey]stopLossLevel),
+	}
+	if config.AlertDebounceInterval > 0 {
+		// Trailing alerts fire from the debouncer's timer, outside any request context
+		service.alertDebouncer = NewDebouncer(config.AlertDebounceInterval, riskClock,
+			func(key riskAlertKey, alert RiskAlertMessage) {
+				service.emitAlert(context.Background(), alert)
+			})
+	}
+	return service
+}
+
+// Stop delivers any risk alerts still held by the debouncer
+func (s *RiskService) Stop() {
+	if s.alertDebouncer != nil {
+		s.alertDebouncer.Stop()
+	}
+}
+
+// SetPriceCache prices market orders from cached quotes and enables staleness checks
+func (s *RiskService) SetPriceCache(cache *PriceCache) {
+	s.priceCache = cache
+}
+
+// SetPriceProvider prices market orders from the provider's latest price when
+// the price cache has no quote for the symbol
+func (s *RiskService) SetPriceProvider(provider interfaces.LastPriceProvider) {
+	s.prices = provider
+}
+
+// SetVolatilityProvider estimates VaR and expected loss from per-symbol
+// volatility instead of DefaultDailyVolatility
+func (s *RiskService) SetVolatilityProvider(provider interfaces.VolatilityProvider) {
+	s.volatility = provider
+}
+
+// SetCorrelationProvider makes portfolio VaR account for correlation between
+// positions instead of assuming DefaultCorrelation for every pair
+func (s *RiskService) SetCorrelationProvider(provider interfaces.CorrelationProvider) {
+	s.correlations = provider
+}
+
+// SetMarketDataRepository supplies the price history historical VaR replays
+func (s *RiskService) SetMarketDataRepository(repo interfaces.MarketDataRepository) {
+	s.marketData = repo
+}
+
+// IsHalted reports whether a circuit breaker has halted trading, and why
+func (s *RiskService) IsHalted() (bool, string) {
+	s.haltMu.RLock()
+	defer s.haltMu.RUnlock()
+	return s.haltReason != "", s.haltReason
+}
+
+// ResumeTrading clears a halt and restarts drawdown tracking of every portfolio
+// from its current equity
+func (s *RiskService) ResumeTrading(ctx context.Context) error {
+	s.drawdownMu.Lock()
+	defer s.drawdownMu.Unlock()
+
+	equity := make(map[string]float64, len(s.drawdowns))
+	for portfolioID := range s.drawdowns {
+		portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+		if err != nil {
+			return fmt.Errorf("failed to get portfolio %s: %w", portfolioID, err)
+		}
+		equity[portfolioID] = portfolio.TotalValue
+	}
+
+	s.haltMu.Lock()
+	s.haltReason = ""
+	s.haltMu.Unlock()
+
+	for portfolioID, tracker := range s.drawdowns {
+		tracker.Reset(equity[portfolioID])
+	}
+	s.metrics.SetGauge("trading_halted", 0, map[string]string{})
+
+	s.logger.Info("Trading resumed",
+		interfaces.Field{Key: "portfolios", Value: len(equity)},
+	)
+	return nil
+}
+
+// drawdownTracker returns portfolioID's tracker, creating it on first use
+func (s *RiskService) drawdownTracker(portfolioID string) *DrawdownTracker {
+	s.drawdownMu.Lock()
+	defer s.drawdownMu.Unlock()
+
+	tracker, exists := s.drawdowns[portfolioID]
+	if !exists {
+		tracker = NewDrawdownTracker()
+		s.drawdowns[portfolioID] = tracker
 	}
+	return tracker
+}
+
+// IsWarmingUp reports whether the service is still inside its startup warm-up window
+func (s *RiskService) IsWarmingUp() bool {
+	return s.clock.Now().Sub(s.startedAt) < s.config.WarmUpPeriod
 }
 
 func (s *RiskService) ValidateOrder(ctx context.Context, order *entities.Order) error {
@@ -42,39 +249,37 @@ func (s *RiskService) ValidateOrder(ctx context.Context, order *entities.Order)
 		})
 	}()
 
-	portfolio, err := s.portfolioService.GetPortfolio(ctx, "default")
-	if err != nil {
-		s.logger.Error("Failed to get portfolio for risk validation",
-			interfaces.Field{Key: "order_id", Value: order.ID},
-			interfaces.Field{Key: "error", Value: err},
-		)
-		return fmt.Errorf("failed to get portfolio: %w", err)
-	}
-
-	if err := s.validateCashBalance(portfolio, order); err != nil {
-		s.publishRiskAlert(ctx, "INSUFFICIENT_CASH", "HIGH", order.Symbol, err.Error())
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, order.PortfolioOrDefault())
+	if errors.Is(err, entities.ErrPortfolioNotFound) {
+		// A missing portfolio is not an outage; there is nothing to trade against
 		return err
 	}
-
-	if err := s.validatePositionSize(portfolio, order); err != nil {
-		s.publishRiskAlert(ctx, "POSITION_SIZE_LIMIT", "HIGH", order.Symbol, err.Error())
-		return err
+	if err != nil {
+		return s.validateDegraded(ctx, order, err)
 	}
 
-	if err := s.validateConcentration(portfolio, order); err != nil {
-		s.publishRiskAlert(ctx, "CONCENTRATION_LIMIT", "MEDIUM", order.Symbol, err.Error())
-		return err
+	s.checkDrawdown(ctx, portfolio)
+	if halted, reason := s.IsHalted(); halted {
+		s.metrics.IncrementCounter("risk_violations", map[string]string{
+			"type":   "trading_halted",
+			"symbol": string(order.Symbol),
+		})
+		return fmt.Errorf("%w: %s", entities.ErrTradingHalted, reason)
 	}
 
-	if err := s.validateVaRLimit(portfolio, order); err != nil {
-		s.publishRiskAlert(ctx, "VAR_LIMIT", "HIGH", order.Symbol, err.Error())
+	if err := s.checkPriceFreshness(order); err != nil {
+		s.publishOrderRiskAlert(ctx, order, "STALE_PRICE", "MEDIUM", err.Error())
 		return err
 	}
 
-	if err := s.validateDailyLossLimit(portfolio); err != nil {
-		s.publishRiskAlert(ctx, "DAILY_LOSS_LIMIT", "CRITICAL", order.Symbol, err.Error())
+	failure, err := s.runRiskChecks(ctx, s.orderRiskChecks(ctx, portfolio, order))
+	if err != nil {
 		return err
 	}
+	if failure != nil {
+		s.publishOrderRiskAlert(ctx, order, failure.check.alertType, failure.check.severity, failure.err.Error())
+		return failure.err
+	}
 
 	s.metrics.IncrementCounter("risk_validations_passed", map[string]string{
 		"symbol": string(order.Symbol),
@@ -84,31 +289,156 @@ func (s *RiskService) ValidateOrder(ctx context.Context, order *entities.Order)
 	s.logger.Info("Order passed risk validation",
 		interfaces.Field{Key: "order_id", Value: order.ID},
 		interfaces.Field{Key: "symbol", Value: order.Symbol},
+		interfaces.Field{Key: "price_stale", Value: order.PriceStale},
 	)
 
 	return nil
 }
 
+// validateDegraded applies the configured DegradedPolicy when the portfolio could
+// not be fetched, so a transient repository failure need not halt all trading.
+// It is never used for a portfolio that does not exist.
+func (s *RiskService) validateDegraded(ctx context.Context, order *entities.Order, fetchErr error) error {
+	s.publishRiskAlert(ctx, "RISK_DEPENDENCY_UNAVAILABLE", "CRITICAL", order.Symbol, fetchErr.Error())
+
+	if s.config.DegradedPolicy != DegradedPolicyFailOpenReducedLimits || s.config.DegradedMaxOrderValue <= 0 {
+		s.recordDegradedDecision(order, "rejected")
+		s.logger.Error("Portfolio unavailable for risk validation, rejecting order (fail-closed)",
+			interfaces.Field{Key: "order_id", Value: order.ID},
+			interfaces.Field{Key: "symbol", Value: order.Symbol},
+			interfaces.Field{Key: "error", Value: fetchErr},
+		)
+		return fmt.Errorf("failed to get portfolio: %w", fetchErr)
+	}
+
+	orderValue, err := s.estimateOrderValue(ctx, order)
+	if err != nil {
+		s.recordDegradedDecision(order, "rejected")
+		return err
+	}
+
+	if orderValue > s.config.DegradedMaxOrderValue {
+		s.recordDegradedDecision(order, "rejected")
+		s.logger.Error("Portfolio unavailable for risk validation, order exceeds degraded-mode cap",
+			interfaces.Field{Key: "order_id", Value: order.ID},
+			interfaces.Field{Key: "symbol", Value: order.Symbol},
+			interfaces.Field{Key: "order_value", Value: orderValue},
+			interfaces.Field{Key: "max_order_value", Value: s.config.DegradedMaxOrderValue},
+			interfaces.Field{Key: "error", Value: fetchErr},
+		)
+		return fmt.Errorf("order value %.2f exceeds degraded-mode limit %.2f while portfolio is unavailable: %w",
+			orderValue, s.config.DegradedMaxOrderValue, fetchErr)
+	}
+
+	s.recordDegradedDecision(order, "allowed")
+	s.logger.Error("Portfolio unavailable for risk validation, allowing order under reduced limits (fail-open)",
+		interfaces.Field{Key: "order_id", Value: order.ID},
+		interfaces.Field{Key: "symbol", Value: order.Symbol},
+		interfaces.Field{Key: "order_value", Value: orderValue},
+		interfaces.Field{Key: "max_order_value", Value: s.config.DegradedMaxOrderValue},
+		interfaces.Field{Key: "error", Value: fetchErr},
+	)
+
+	return nil
+}
+
+func (s *RiskService) recordDegradedDecision(order *entities.Order, decision string) {
+	policy := s.config.DegradedPolicy
+	if policy == "" {
+		policy = DegradedPolicyFailClosed
+	}
+
+	s.metrics.IncrementCounter("risk_degraded_decisions", map[string]string{
+		"policy":   string(policy),
+		"decision": decision,
+		"symbol":   string(order.Symbol),
+	})
+}
+
+// EstimateOrderImpact runs every pre-trade risk check against order and reports the
+// projected effect on cash and position. Unlike ValidateOrder it collects all
+// breaches and has no side effects: no alerts, metrics, or persistence.
+func (s *RiskService) EstimateOrderImpact(ctx context.Context, order *entities.Order) (*OrderImpact, error) {
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, order.PortfolioOrDefault())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	orderValue, err := s.estimateOrderValue(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	currentQuantity := 0.0
+	if position, exists := portfolio.GetPosition(order.Symbol); exists {
+		currentQuantity = position.Quantity
+	}
+
+	impact := &OrderImpact{
+		EstimatedCost:           orderValue,
+		PriceStale:              s.isPricedStale(order),
+		CurrentPositionQuantity: currentQuantity,
+		CashAvailable:           portfolio.Cash,
+		Breaches:                []RiskBreach{},
+	}
+
+	if order.Side == entities.OrderSideBuy {
+		impact.ResultingPositionQuantity = currentQuantity + order.Quantity
+		impact.RemainingCash = portfolio.Cash - orderValue
+	} else {
+		impact.ResultingPositionQuantity = currentQuantity - order.Quantity
+		impact.RemainingCash = portfolio.Cash + orderValue
+	}
+
+	checks := []struct {
+		check string
+		run   func() error
+	}{
+		{"trading_halted", func() error {
+			if halted, reason := s.IsHalted(); halted {
+				return fmt.Errorf("%w: %s", entities.ErrTradingHalted, reason)
+			}
+			return nil
+		}},
+		{"short_sale", func() error { return s.checkNoShortSale(portfolio, order) }},
+		{"insufficient_cash", func() error { return s.checkCashBalance(ctx, portfolio, order) }},
+		{"position_size", func() error { return s.checkPositionSize(ctx, portfolio, order) }},
+		{"concentration", func() error { _, err := s.checkConcentration(portfolio); return err }},
+		{"var_limit", func() error { return s.checkVaRLimit(ctx, portfolio) }},
+		{"daily_loss", func() error { return s.checkDailyLossLimit(portfolio) }},
+	}
+	for _, c := range checks {
+		if err := c.run(); err != nil {
+			impact.Breaches = append(impact.Breaches, RiskBreach{Check: c.check, Message: err.Error()})
+		}
+	}
+	impact.WouldPass = len(impact.Breaches) == 0
+
+	return impact, nil
+}
+
 func (s *RiskService) CalculatePortfolioRisk(ctx context.Context, portfolioID string) (*interfaces.PortfolioRisk, error) {
 	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get portfolio: %w", err)
 	}
 
-	var95 := s.calculateVaR(portfolio, 0.95)
-	var99 := s.calculateVaR(portfolio, 0.99)
+	var95 := s.portfolioVaR(ctx, portfolio, 0.95)
+	var99 := s.portfolioVaR(ctx, portfolio, 0.99)
+	es95 := s.calculateExpectedShortfall(ctx, portfolio, 0.95)
 	leverage := s.calculateLeverage(portfolio)
 	concentration := s.calculateConcentration(portfolio)
 	drawdownRisk := s.calculateDrawdownRisk(portfolio)
 
 	portfolioRisk := &interfaces.PortfolioRisk{
-		TotalVaR:      var95,
-		Concentration: concentration,
-		Leverage:      leverage,
-		DrawdownRisk:  drawdownRisk,
+		TotalVaR:          var95,
+		ExpectedShortfall: es95,
+		Concentration:     concentration,
+		Leverage:          leverage,
+		DrawdownRisk:      drawdownRisk,
 	}
 
-	s.updateRiskMetrics(portfolioID, var95, var99, leverage)
+	s.updateRiskMetrics(portfolioID, var95, var99, es95, leverage)
 
 	return portfolioRisk, nil
 }
@@ -124,8 +454,8 @@ func (s *RiskService) CalculatePositionRisk(ctx context.Context, portfolioID str
 		return nil, entities.ErrPositionNotFound
 	}
 
-	var95 := s.calculatePositionVaR(position, 0.95)
-	expectedLoss := s.calculateExpectedLoss(position)
+	var95 := s.calculatePositionVaR(ctx, position, 0.95)
+	expectedLoss := s.calculateExpectedLoss(ctx, position)
 	positionSize := position.MarketValue / portfolio.TotalValue
 	leverageRatio := s.calculatePositionLeverage(position, portfolio)
 
@@ -145,6 +475,12 @@ func (s *RiskService) MonitorRiskLimits(ctx context.Context, portfolioID string)
 		return fmt.Errorf("failed to calculate portfolio risk: %w", err)
 	}
 
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	s.checkDrawdown(ctx, portfolio)
+
 	if portfolioRisk.TotalVaR > s.riskLimits.MaxVaR {
 		s.publishRiskAlert(ctx, "VAR_EXCEEDED", "CRITICAL", "", 
 			fmt.Sprintf("Portfolio VaR (%.4f) exceeds limit (%.4f)", portfolioRisk.TotalVaR, s.riskLimits.MaxVaR))
@@ -166,44 +502,56 @@ func (s *RiskService) MonitorRiskLimits(ctx context.Context, portfolioID string)
 	return nil
 }
 
-func (s *RiskService) validateCashBalance(portfolio *entities.Portfolio, order *entities.Order) error {
+func (s *RiskService) validateCashBalance(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
+	if err := s.checkCashBalance(ctx, portfolio, order); err != nil {
+		s.metrics.IncrementCounter("risk_violations", map[string]string{
+			"type":   "insufficient_cash",
+			"symbol": string(order.Symbol),
+		})
+		return err
+	}
+	return nil
+}
+
+func (s *RiskService) checkCashBalance(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
 	if order.Side != entities.OrderSideBuy {
 		return nil
 	}
 
-	var requiredCash float64
-	if order.Type == entities.OrderTypeMarket {
-		requiredCash = order.Quantity * s.estimateMarketPrice(order.Symbol)
-	} else if order.Price != nil {
-		requiredCash = order.Quantity * (*order.Price)
-	} else {
-		return fmt.Errorf("price is required for limit orders")
+	requiredCash, err := s.estimateOrderValue(ctx, order)
+	if err != nil {
+		return err
 	}
 
-	cashBuffer := requiredCash * 0.1
+	cashBuffer := requiredCash * s.riskLimits.CashBufferPct
 	totalRequired := requiredCash + cashBuffer
 
 	if portfolio.Cash < totalRequired {
+		return fmt.Errorf("insufficient cash: required %.2f, available %.2f", totalRequired, portfolio.Cash)
+	}
+
+	return nil
+}
+
+func (s *RiskService) validatePositionSize(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
+	if err := s.checkPositionSize(ctx, portfolio, order); err != nil {
 		s.metrics.IncrementCounter("risk_violations", map[string]string{
-			"type":   "insufficient_cash",
+			"type":   "position_size",
 			"symbol": string(order.Symbol),
 		})
-		return fmt.Errorf("insufficient cash: required %.2f, available %.2f", totalRequired, portfolio.Cash)
+		return err
 	}
-
 	return nil
 }
 
-func (s *RiskService) validatePositionSize(portfolio *entities.Portfolio, order *entities.Order) error {
+func (s *RiskService) checkPositionSize(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
 	if order.Side != entities.OrderSideBuy {
 		return nil
 	}
 
-	var orderValue float64
-	if order.Type == entities.OrderTypeMarket {
-		orderValue = order.Quantity * s.estimateMarketPrice(order.Symbol)
-	} else if order.Price != nil {
-		orderValue = order.Quantity * (*order.Price)
+	orderValue, err := s.estimateOrderValue(ctx, order)
+	if err != nil {
+		return err
 	}
 
 	currentPosition, exists := portfolio.GetPosition(order.Symbol)
@@ -215,43 +563,104 @@ func (s *RiskService) validatePositionSize(portfolio *entities.Portfolio, order
 	newPositionValue := currentValue + orderValue
 	positionSizeRatio := newPositionValue / portfolio.TotalValue
 
-	if positionSizeRatio > s.riskLimits.MaxPositionSize {
+	limits := s.limitsFor(order.Symbol)
+	if positionSizeRatio > limits.MaxPositionSize {
+		return fmt.Errorf("position size limit exceeded: %.2f%% > %.2f%%", 
+			positionSizeRatio*100, limits.MaxPositionSize*100)
+	}
+
+	return nil
+}
+
+func (s *RiskService) validateConcentration(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
+	symbol, err := s.checkConcentration(portfolio)
+	if err != nil {
 		s.metrics.IncrementCounter("risk_violations", map[string]string{
-			"type":   "position_size",
-			"symbol": string(order.Symbol),
+			"type":   "concentration",
+			"symbol": string(symbol),
 		})
-		return fmt.Errorf("position size limit exceeded: %.2f%% > %.2f%%", 
-			positionSizeRatio*100, s.riskLimits.MaxPositionSize*100)
+		return err
+	}
+	return nil
+}
+
+// checkNoShortSale rejects a sell of more than the portfolio holds. Portfolios
+// only hold longs, so a short could be approved but never booked once filled.
+func (s *RiskService) checkNoShortSale(portfolio *entities.Portfolio, order *entities.Order) error {
+	if order.Side != entities.OrderSideSell {
+		return nil
 	}
 
+	held := 0.0
+	if position, exists := portfolio.GetPosition(order.Symbol); exists {
+		held = position.Quantity
+	}
+	if order.Quantity > held {
+		return fmt.Errorf("%w: selling %g %s exceeds the %g held; short positions are not supported",
+			entities.ErrInsufficientQuantity, order.Quantity, order.Symbol, held)
+	}
+	return nil
+}
+
+func (s *RiskService) validateNoShortSale(portfolio *entities.Portfolio, order *entities.Order) error {
+	if err := s.checkNoShortSale(portfolio, order); err != nil {
+		s.metrics.IncrementCounter("risk_violations", map[string]string{
+			"type":   "short_sale",
+			"symbol": string(order.Symbol),
+		})
+		return err
+	}
 	return nil
 }
 
-func (s *RiskService) validateConcentration(portfolio *entities.Portfolio, order *entities.Order) error {
+func (s *RiskService) checkConcentration(portfolio *entities.Portfolio) (entities.Symbol, error) {
 	concentration := s.calculateConcentration(portfolio)
 	
 	for symbol, ratio := range concentration {
-		if ratio > s.riskLimits.MaxConcentration {
-			s.metrics.IncrementCounter("risk_violations", map[string]string{
-				"type":   "concentration",
-				"symbol": string(symbol),
-			})
-			return fmt.Errorf("concentration limit exceeded for %s: %.2f%% > %.2f%%", 
-				symbol, ratio*100, s.riskLimits.MaxConcentration*100)
+		if limit := s.limitsFor(symbol).MaxConcentration; ratio > limit {
+			return symbol, fmt.Errorf("concentration limit exceeded for %s: %.2f%% > %.2f%%", 
+				symbol, ratio*100, limit*100)
 		}
 	}
 
-	return nil
+	return "", nil
 }
 
-func (s *RiskService) validateVaRLimit(portfolio *entities.Portfolio, order *entities.Order) error {
-	currentVaR := s.calculateVaR(portfolio, s.riskLimits.VaRConfidenceLevel)
-	
-	if currentVaR > s.riskLimits.MaxVaR {
+// limitsFor returns the global risk limits with any SymbolLimits override for
+// symbol applied
+func (s *RiskService) limitsFor(symbol entities.Symbol) interfaces.RiskLimits {
+	limits := *s.riskLimits
+	override, exists := s.config.SymbolLimits[symbol]
+	if !exists {
+		return limits
+	}
+	if override.MaxPositionSize > 0 {
+		limits.MaxPositionSize = override.MaxPositionSize
+	}
+	if override.MaxConcentration > 0 {
+		limits.MaxConcentration = override.MaxConcentration
+	}
+	if override.StopLossThreshold > 0 {
+		limits.StopLossThreshold = override.StopLossThreshold
+	}
+	return limits
+}
+
+func (s *RiskService) validateVaRLimit(ctx context.Context, portfolio *entities.Portfolio, order *entities.Order) error {
+	if err := s.checkVaRLimit(ctx, portfolio); err != nil {
 		s.metrics.IncrementCounter("risk_violations", map[string]string{
 			"type":   "var_limit",
 			"symbol": string(order.Symbol),
 		})
+		return err
+	}
+	return nil
+}
+
+func (s *RiskService) checkVaRLimit(ctx context.Context, portfolio *entities.Portfolio) error {
+	currentVaR := s.calculateVaR(ctx, portfolio, s.riskLimits.VaRConfidenceLevel)
+	
+	if currentVaR > s.riskLimits.MaxVaR {
 		return fmt.Errorf("VaR limit exceeded: %.4f > %.4f", currentVaR, s.riskLimits.MaxVaR)
 	}
 
@@ -259,12 +668,23 @@ func (s *RiskService) validateVaRLimit(portfolio *entities.Portfolio, order *ent
 }
 
 func (s *RiskService) validateDailyLossLimit(portfolio *entities.Portfolio) error {
-	dailyLossRatio := math.Abs(portfolio.DayPnL) / portfolio.TotalValue
-	
-	if dailyLossRatio > s.riskLimits.MaxDailyLoss {
+	if err := s.checkDailyLossLimit(portfolio); err != nil {
 		s.metrics.IncrementCounter("risk_violations", map[string]string{
 			"type": "daily_loss",
 		})
+		return err
+	}
+	return nil
+}
+
+func (s *RiskService) checkDailyLossLimit(portfolio *entities.Portfolio) error {
+	// Only losses count toward the limit; a profitable day never blocks trading
+	if portfolio.DayPnL >= 0 {
+		return nil
+	}
+	dailyLossRatio := -portfolio.DayPnL / portfolio.TotalValue
+	
+	if dailyLossRatio > s.riskLimits.MaxDailyLoss {
 		return fmt.Errorf("daily loss limit exceeded: %.2f%% > %.2f%%", 
 			dailyLossRatio*100, s.riskLimits.MaxDailyLoss*100)
 	}
@@ -272,22 +692,140 @@ func (s *RiskService) validateDailyLossLimit(portfolio *entities.Portfolio) erro
 	return nil
 }
 
-func (s *RiskService) calculateVaR(portfolio *entities.Portfolio, confidenceLevel float64) float64 {
+// checkDrawdown feeds portfolio equity to the drawdown tracker and trips the
+// max-drawdown breaker the first time the limit is exceeded
+func (s *RiskService) checkDrawdown(ctx context.Context, portfolio *entities.Portfolio) {
+	tracker := s.drawdownTracker(portfolio.ID)
+	drawdown := tracker.Observe(portfolio.TotalValue)
+	s.metrics.SetGauge("portfolio_drawdown", drawdown, map[string]string{
+		"portfolio_id": portfolio.ID,
+	})
+
+	if s.riskLimits.MaxDrawdown <= 0 || drawdown <= s.riskLimits.MaxDrawdown {
+		return
+	}
+
+	reason := fmt.Sprintf("portfolio %s drawdown %.2f%% exceeds limit %.2f%% (peak %.2f, equity %.2f)",
+		portfolio.ID, drawdown*100, s.riskLimits.MaxDrawdown*100, tracker.Peak(), portfolio.TotalValue)
+
+	s.haltMu.Lock()
+	alreadyHalted := s.haltReason != ""
+	if !alreadyHalted {
+		s.haltReason = reason
+	}
+	s.haltMu.Unlock()
+	if alreadyHalted {
+		return
+	}
+
+	s.metrics.SetGauge("trading_halted", 1, map[string]string{})
+	s.logger.Error("Max drawdown breaker tripped, trading halted",
+		interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+		interfaces.Field{Key: "drawdown", Value: drawdown},
+		interfaces.Field{Key: "limit", Value: s.riskLimits.MaxDrawdown},
+	)
+	s.publishRiskAlert(ctx, "MAX_DRAWDOWN", "CRITICAL", "", reason)
+
+	if s.config.AutoFlattenOnDrawdown {
+		s.flattenPositions(ctx, portfolio)
+	}
+}
+
+// flattenPositions publishes pre-approved market orders closing every open
+// position. They bypass ValidateOrder because they only ever reduce risk.
+func (s *RiskService) flattenPositions(ctx context.Context, portfolio *entities.Portfolio) []*entities.Order {
+	var orders []*entities.Order
+	for symbol, position := range portfolio.Positions {
+		if position.Quantity == 0 {
+			continue
+		}
+
+		side := entities.OrderSideSell
+		if position.Quantity < 0 {
+			side = entities.OrderSideBuy
+		}
+
+		order := entities.NewOrder(symbol, side, entities.OrderTypeMarket, math.Abs(position.Quantity), nil)
+		order.PortfolioID = portfolio.ID
+		order.Approve()
+
+		if err := s.messageBus.Publish(ctx, "order.approved", order); err != nil {
+			s.logger.Error("Failed to submit flatten order",
+				interfaces.Field{Key: "symbol", Value: symbol},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	s.logger.Warn("Flattened positions after max drawdown",
+		interfaces.Field{Key: "portfolio_id", Value: portfolio.ID},
+		interfaces.Field{Key: "orders", Value: len(orders)},
+	)
+	return orders
+}
+
+// calculateVaR combines position VaRs as sqrt(wᵀ Σ w), where w holds the
+// position VaRs and Σ the correlations between positions
+func (s *RiskService) calculateVaR(ctx context.Context, portfolio *entities.Portfolio, confidenceLevel float64) float64 {
 	if len(portfolio.Positions) == 0 {
 		return 0.0
 	}
 
+	symbols := make([]entities.Symbol, 0, len(portfolio.Positions))
+	weights := make(map[entities.Symbol]float64, len(portfolio.Positions))
+	for symbol, position := range portfolio.Positions {
+		symbols = append(symbols, symbol)
+		weights[symbol] = s.calculatePositionVaR(ctx, position, confidenceLevel)
+	}
+	// A fixed order keeps the floating-point sum repeatable
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	matrix := s.correlationMatrix(ctx, symbols)
 	totalRisk := 0.0
-	for _, position := range portfolio.Positions {
-		positionRisk := s.calculatePositionVaR(position, confidenceLevel)
-		totalRisk += positionRisk * positionRisk
+	for _, a := range symbols {
+		for _, b := range symbols {
+			totalRisk += weights[a] * weights[b] * s.correlation(matrix, a, b)
+		}
+	}
+
+	return math.Sqrt(math.Max(totalRisk, 0))
+}
+
+func (s *RiskService) correlationMatrix(ctx context.Context, symbols []entities.Symbol) map[entities.Symbol]map[entities.Symbol]float64 {
+	if s.correlations == nil || len(symbols) < 2 {
+		return nil
 	}
 
-	return math.Sqrt(totalRisk)
+	matrix, err := s.correlations.GetCorrelations(ctx, symbols)
+	if err != nil {
+		s.logger.Warn("Falling back to default correlation",
+			interfaces.Field{Key: "symbols", Value: len(symbols)},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		return nil
+	}
+	return matrix
 }
 
-func (s *RiskService) calculatePositionVaR(position *entities.Position, confidenceLevel float64) float64 {
-	volatility := s.estimateVolatility(position.Symbol)
+// correlation looks a pair up in either order, using DefaultCorrelation when
+// the matrix has no entry
+func (s *RiskService) correlation(matrix map[entities.Symbol]map[entities.Symbol]float64, a, b entities.Symbol) float64 {
+	if a == b {
+		return 1
+	}
+	if rho, exists := matrix[a][b]; exists {
+		return rho
+	}
+	if rho, exists := matrix[b][a]; exists {
+		return rho
+	}
+	return s.config.DefaultCorrelation
+}
+
+func (s *RiskService) calculatePositionVaR(ctx context.Context, position *entities.Position, confidenceLevel float64) float64 {
+	volatility := s.estimateVolatility(ctx, position.Symbol)
 	zScore := s.getZScore(confidenceLevel)
 	
 	return position.MarketValue * volatility * zScore
@@ -333,8 +871,8 @@ func (s *RiskService) calculateDrawdownRisk(portfolio *entities.Portfolio) float
 	return maxDrawdown
 }
 
-func (s *RiskService) calculateExpectedLoss(position *entities.Position) float64 {
-	volatility := s.estimateVolatility(position.Symbol)
+func (s *RiskService) calculateExpectedLoss(ctx context.Context, position *entities.Position) float64 {
+	volatility := s.estimateVolatility(ctx, position.Symbol)
 	return position.MarketValue * volatility * 0.5
 }
 
@@ -345,50 +883,202 @@ func (s *RiskService) calculatePositionLeverage(position *entities.Position, por
 	return math.Abs(position.MarketValue) / portfolio.TotalValue
 }
 
-func (s *RiskService) estimateMarketPrice(symbol entities.Symbol) float64 {
-	return 100.0
+func (s *RiskService) estimateOrderValue(ctx context.Context, order *entities.Order) (float64, error) {
+	if order.Type == entities.OrderTypeMarket {
+		return order.Quantity * s.estimateMarketPrice(ctx, order), nil
+	}
+	// A stop triggers into a market order, which is expected to fill near the stop
+	if order.Type == entities.OrderTypeStop && order.StopPrice != nil {
+		return order.Quantity * (*order.StopPrice), nil
+	}
+	if order.Price == nil {
+		return 0, fmt.Errorf("price is required for limit orders")
+	}
+	return order.Quantity * (*order.Price), nil
+}
+
+// estimateMarketPrice prices order from the cached quote, then the price
+// provider's latest price, then the order's own limit price, and only then
+// DefaultEstimatedPrice
+func (s *RiskService) estimateMarketPrice(ctx context.Context, order *entities.Order) float64 {
+	if s.priceCache != nil {
+		if quote, exists := s.priceCache.Get(order.Symbol); exists {
+			return quote.Price
+		}
+	}
+
+	if s.prices != nil {
+		data, err := s.prices.GetRealTimePrice(ctx, order.Symbol)
+		if err == nil && data != nil && data.Price > 0 {
+			return data.Price
+		}
+		s.logger.Warn("No latest price for order notional",
+			interfaces.Field{Key: "symbol", Value: order.Symbol},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+
+	if order.Price != nil && *order.Price > 0 {
+		return *order.Price
+	}
+
+	s.metrics.IncrementCounter("risk_default_price_used", map[string]string{
+		"symbol": string(order.Symbol),
+	})
+	return DefaultEstimatedPrice
 }
 
-func (s *RiskService) estimateVolatility(symbol entities.Symbol) float64 {
-	return 0.02
+// isPricedStale reports whether order's value would be estimated from a stale or
+// missing cached quote. Only market orders are priced from the cache.
+func (s *RiskService) isPricedStale(order *entities.Order) bool {
+	if s.priceCache == nil || order.Type != entities.OrderTypeMarket {
+		return false
+	}
+	quote, exists := s.priceCache.Get(order.Symbol)
+	return !exists || quote.Stale
 }
 
-func (s *RiskService) getZScore(confidenceLevel float64) float64 {
-	zScores := map[float64]float64{
-		0.90: 1.28,
-		0.95: 1.645,
-		0.99: 2.33,
+// checkPriceFreshness marks orders priced from a stale quote and rejects them
+// under StalePricePolicyReject
+func (s *RiskService) checkPriceFreshness(order *entities.Order) error {
+	order.PriceStale = s.isPricedStale(order)
+	if !order.PriceStale {
+		return nil
 	}
-	
-	if z, exists := zScores[confidenceLevel]; exists {
-		return z
+
+	policy := s.config.StalePricePolicy
+	if policy == "" {
+		policy = StalePricePolicyAnnotate
+	}
+
+	detail := fmt.Sprintf("no cached price for %s", order.Symbol)
+	if quote, exists := s.priceCache.Get(order.Symbol); exists {
+		detail = fmt.Sprintf("last price for %s is %s old", order.Symbol, quote.Age)
+	}
+
+	s.metrics.IncrementCounter("risk_stale_price_decisions", map[string]string{
+		"policy": string(policy),
+		"symbol": string(order.Symbol),
+	})
+	s.logger.Warn("Risk validation using stale price",
+		interfaces.Field{Key: "order_id", Value: order.ID},
+		interfaces.Field{Key: "symbol", Value: order.Symbol},
+		interfaces.Field{Key: "policy", Value: policy},
+		interfaces.Field{Key: "detail", Value: detail},
+	)
+
+	if policy == StalePricePolicyReject {
+		return fmt.Errorf("%w: %s", entities.ErrStalePrice, detail)
 	}
-	return 1.645
+	return nil
+}
+
+// estimateVolatility returns symbol's daily volatility, converted from the
+// provider's annualized estimate
+func (s *RiskService) estimateVolatility(ctx context.Context, symbol entities.Symbol) float64 {
+	if s.volatility == nil {
+		return s.config.DefaultDailyVolatility
+	}
+
+	annual, err := s.volatility.GetVolatility(ctx, symbol, s.config.VolatilityLookback)
+	if err != nil {
+		s.logger.Warn("Falling back to default volatility",
+			interfaces.Field{Key: "symbol", Value: symbol},
+			interfaces.Field{Key: "error", Value: err},
+		)
+		return s.config.DefaultDailyVolatility
+	}
+	return annual / math.Sqrt(TradingDaysPerYear)
+}
+
+// getZScore returns the standard normal quantile for confidenceLevel, falling
+// back to the 95% quantile outside (0, 1)
+func (s *RiskService) getZScore(confidenceLevel float64) float64 {
+	if confidenceLevel <= 0 || confidenceLevel >= 1 {
+		confidenceLevel = 0.95
+	}
+	return normalQuantile(confidenceLevel)
+}
+
+// normalQuantile is the inverse of the standard normal CDF
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
 }
 
 func (s *RiskService) publishRiskAlert(ctx context.Context, alertType, severity string, symbol entities.Symbol, message string) {
-	alert := RiskAlertMessage{
-		AlertType:   alertType,
-		Severity:    severity,
-		Symbol:      symbol,
-		Message:     message,
-		Timestamp:   time.Now(),
+	s.publishAlert(ctx, RiskAlertMessage{
+		AlertType: alertType,
+		Severity:  severity,
+		Symbol:    symbol,
+		Message:   message,
+	})
+}
+
+// publishOrderRiskAlert raises an alert about order, carrying its stale-price flag
+func (s *RiskService) publishOrderRiskAlert(ctx context.Context, order *entities.Order, alertType, severity, message string) {
+	s.publishAlert(ctx, RiskAlertMessage{
+		AlertType:  alertType,
+		Severity:   severity,
+		Symbol:     order.Symbol,
+		Message:    message,
+		PriceStale: order.PriceStale,
+	})
+}
+
+func (s *RiskService) publishAlert(ctx context.Context, alert RiskAlertMessage) {
+	alertType, severity := alert.AlertType, alert.Severity
+	if s.IsWarmingUp() {
+		s.logger.Info("Risk alert suppressed during warm-up",
+			interfaces.Field{Key: "alert_type", Value: alertType},
+			interfaces.Field{Key: "severity", Value: severity},
+			interfaces.Field{Key: "symbol", Value: alert.Symbol},
+			interfaces.Field{Key: "message", Value: alert.Message},
+			interfaces.Field{Key: "warm_up_remaining", Value: s.config.WarmUpPeriod - s.clock.Now().Sub(s.startedAt)},
+		)
+		s.metrics.IncrementCounter("risk_alerts_suppressed", map[string]string{
+			"alert_type": alertType,
+			"severity":   severity,
+		})
+		return
+	}
+
+	alert.Timestamp = s.clock.Now()
+
+	if s.alertDebouncer == nil || alert.Symbol == "" {
+		s.emitAlert(ctx, alert)
+		return
+	}
+
+	key := riskAlertKey{alertType: alertType, symbol: alert.Symbol}
+	if !s.alertDebouncer.Submit(key, alert) {
+		s.metrics.IncrementCounter("risk_alerts_debounced", map[string]string{
+			"alert_type": alertType,
+			"symbol":     string(alert.Symbol),
+		})
 	}
+}
+
+// riskAlertKey debounces alerts per alert type and symbol
+type riskAlertKey struct {
+	alertType string
+	symbol    entities.Symbol
+}
 
+func (s *RiskService) emitAlert(ctx context.Context, alert RiskAlertMessage) {
 	if err := s.messageBus.Publish(ctx, "risk.alert", alert); err != nil {
 		s.logger.Error("Failed to publish risk alert",
-			interfaces.Field{Key: "alert_type", Value: alertType},
+			interfaces.Field{Key: "alert_type", Value: alert.AlertType},
 			interfaces.Field{Key: "error", Value: err},
 		)
 	}
 
 	s.metrics.IncrementCounter("risk_alerts", map[string]string{
-		"type":     alertType,
-		"severity": severity,
+		"alert_type": alert.AlertType,
+		"severity":   alert.Severity,
 	})
 }
 
-func (s *RiskService) updateRiskMetrics(portfolioID string, var95, var99, leverage float64) {
+func (s *RiskService) updateRiskMetrics(portfolioID string, var95, var99, es95, leverage float64) {
 	s.metrics.SetGauge("portfolio_var_95", var95, map[string]string{
 		"portfolio_id": portfolioID,
 	})
@@ -397,6 +1087,10 @@ func (s *RiskService) updateRiskMetrics(portfolioID string, var95, var99, levera
 		"portfolio_id": portfolioID,
 	})
 
+	s.metrics.SetGauge("portfolio_expected_shortfall", es95, map[string]string{
+		"portfolio_id": portfolioID,
+	})
+
 	s.metrics.SetGauge("portfolio_leverage", leverage, map[string]string{
 		"portfolio_id": portfolioID,
 	})
@@ -407,5 +1101,24 @@ type RiskAlertMessage struct {
 	Severity  string          `json:"severity"`
 	Symbol    entities.Symbol `json:"symbol,omitempty"`
 	Message   string          `json:"message"`
-	Timestamp time.Time       `json:"timestamp"`
-}
\ No newline at end of file
+	// PriceStale is set when the alerted decision was made on a stale quote
+	PriceStale bool            `json:"price_stale,omitempty"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
+
+type OrderImpact struct {
+	EstimatedCost             float64      `json:"estimated_cost"`
+	CurrentPositionQuantity   float64      `json:"current_position_quantity"`
+	ResultingPositionQuantity float64      `json:"resulting_position_quantity"`
+	CashAvailable             float64      `json:"cash_available"`
+	RemainingCash             float64      `json:"remaining_cash"`
+	// PriceStale is set when EstimatedCost was derived from a stale quote
+	PriceStale                bool         `json:"price_stale,omitempty"`
+	Breaches                  []RiskBreach `json:"breaches"`
+	WouldPass                 bool         `json:"would_pass"`
+}
+
+type RiskBreach struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
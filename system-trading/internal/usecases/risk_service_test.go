@@ -0,0 +1,701 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func defaultTestRiskLimits() *interfaces.RiskLimits {
+	return &interfaces.RiskLimits{
+		MaxPositionSize:    0.1,
+		MaxConcentration:   0.2,
+		MaxLeverage:        2.0,
+		MaxDailyLoss:       0.05,
+		MaxVaR:             0.02,
+		VaRConfidenceLevel: 0.95,
+		CashBufferPct:      0.10,
+	}
+}
+
+type riskTestFixture struct {
+	service       *RiskService
+	portfolios    *PortfolioService
+	portfolioRepo *repositories.InMemoryPortfolioRepository
+	bus           *messagebus.MockMessageBus
+	clock         *clock.FakeClock
+}
+
+func setupRiskService(t *testing.T, limits *interfaces.RiskLimits, config RiskServiceConfig) *riskTestFixture {
+	t.Helper()
+
+	testLogger := newTestLogger(t)
+	bus := messagebus.NewMockMessageBus()
+	portfolioRepo := repositories.NewInMemoryPortfolioRepository()
+	fakeClock := clock.NewFakeClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	if config.Clock == nil {
+		config.Clock = fakeClock
+	}
+
+	portfolios := NewPortfolioService(portfolioRepo, bus, testLogger, newTestMetrics("risk"))
+	service := NewRiskService(portfolios, bus, testLogger, newTestMetrics("risk"), limits, config)
+
+	return &riskTestFixture{
+		service:       service,
+		portfolios:    portfolios,
+		portfolioRepo: portfolioRepo,
+		bus:           bus,
+		clock:         fakeClock,
+	}
+}
+
+func seedPortfolio(t *testing.T, repo *repositories.InMemoryPortfolioRepository, id string, cash float64, positions map[entities.Symbol][2]float64) *entities.Portfolio {
+	t.Helper()
+
+	portfolio := entities.NewPortfolio(cash)
+	portfolio.ID = id
+	for symbol, qtyPrice := range positions {
+		portfolio.Cash += qtyPrice[0] * qtyPrice[1]
+		portfolio.AddPosition(symbol, qtyPrice[0], qtyPrice[1])
+	}
+
+	if err := repo.Save(context.Background(), portfolio); err != nil {
+		t.Fatalf("Failed to seed portfolio: %v", err)
+	}
+	return portfolio
+}
+
+func TestRiskService_WarmUpSuppressesAlerts(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{WarmUpPeriod: 5 * time.Minute})
+
+	// A single position at ~50% of the portfolio breaches the 20% concentration limit
+	seedPortfolio(t, f.portfolioRepo, "default", 50000, map[entities.Symbol][2]float64{
+		"AAPL": {500, 100},
+	})
+
+	ctx := context.Background()
+
+	if !f.service.IsWarmingUp() {
+		t.Fatal("Expected service to be warming up right after startup")
+	}
+	if err := f.service.MonitorRiskLimits(ctx, "default"); err != nil {
+		t.Fatalf("MonitorRiskLimits failed: %v", err)
+	}
+	if alerts := f.bus.GetMessagesByTopic("risk.alert"); len(alerts) != 0 {
+		t.Fatalf("Expected alerts to be suppressed during warm-up, got %d", len(alerts))
+	}
+
+	f.clock.Advance(5 * time.Minute)
+
+	if f.service.IsWarmingUp() {
+		t.Fatal("Expected warm-up to have ended")
+	}
+	if err := f.service.MonitorRiskLimits(ctx, "default"); err != nil {
+		t.Fatalf("MonitorRiskLimits failed: %v", err)
+	}
+
+	alerts := f.bus.GetMessagesByTopic("risk.alert")
+	if len(alerts) == 0 {
+		t.Fatal("Expected alerts to be emitted after warm-up")
+	}
+	found := false
+	for _, msg := range alerts {
+		if alert, ok := msg.Message.(RiskAlertMessage); ok && alert.AlertType == "CONCENTRATION_EXCEEDED" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a CONCENTRATION_EXCEEDED alert after warm-up")
+	}
+}
+
+func TestRiskService_DegradedPolicyOnPortfolioFetchError(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      RiskServiceConfig
+		quantity    float64
+		expectAllow bool
+	}{
+		{"fail closed rejects small order", RiskServiceConfig{DegradedPolicy: DegradedPolicyFailClosed, DegradedMaxOrderValue: 1000}, 5, false},
+		{"unset policy fails closed", RiskServiceConfig{DegradedMaxOrderValue: 1000}, 5, false},
+		{"fail open allows order under cap", RiskServiceConfig{DegradedPolicy: DegradedPolicyFailOpenReducedLimits, DegradedMaxOrderValue: 1000}, 5, true},
+		{"fail open rejects order over cap", RiskServiceConfig{DegradedPolicy: DegradedPolicyFailOpenReducedLimits, DegradedMaxOrderValue: 1000}, 50, false},
+		{"fail open without cap fails closed", RiskServiceConfig{DegradedPolicy: DegradedPolicyFailOpenReducedLimits}, 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The portfolio store is down, so every portfolio fetch fails
+			f := setupRiskService(t, defaultTestRiskLimits(), tt.config)
+			f.service.portfolioService = NewPortfolioService(unavailablePortfolioRepository{}, f.bus, newTestLogger(t), newTestMetrics("risk"))
+
+			price := 100.0
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, tt.quantity, &price)
+
+			err := f.service.ValidateOrder(context.Background(), order)
+			if tt.expectAllow && err != nil {
+				t.Fatalf("Expected order to be allowed, got: %v", err)
+			}
+			if !tt.expectAllow && err == nil {
+				t.Fatal("Expected order to be rejected")
+			}
+			if !tt.expectAllow && !errors.Is(err, errPortfolioStoreUnavailable) {
+				t.Errorf("Expected rejection to wrap the fetch error, got: %v", err)
+			}
+
+			if alerts := f.bus.GetMessagesByTopic("risk.alert"); len(alerts) != 1 {
+				t.Errorf("Expected 1 dependency alert, got %d", len(alerts))
+			}
+		})
+	}
+}
+
+var errPortfolioStoreUnavailable = errors.New("portfolio store unavailable")
+
+// unavailablePortfolioRepository fails every call as an unreachable store would
+type unavailablePortfolioRepository struct {
+	interfaces.PortfolioRepository
+}
+
+func (unavailablePortfolioRepository) GetByID(ctx context.Context, id string) (*entities.Portfolio, error) {
+	return nil, errPortfolioStoreUnavailable
+}
+
+func TestRiskService_MissingPortfolioFailsClosedUnderAnyPolicy(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{
+		DegradedPolicy:        DegradedPolicyFailOpenReducedLimits,
+		DegradedMaxOrderValue: 1000,
+	})
+
+	price := 100.0
+	order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, 5, &price)
+	order.PortfolioID = "missing"
+
+	err := f.service.ValidateOrder(context.Background(), order)
+	if !errors.Is(err, entities.ErrPortfolioNotFound) {
+		t.Fatalf("Expected the order to be rejected as ErrPortfolioNotFound, got: %v", err)
+	}
+	if alerts := f.bus.GetMessagesByTopic("risk.alert"); len(alerts) != 0 {
+		t.Errorf("Expected no dependency alert for a missing portfolio, got %d", len(alerts))
+	}
+}
+
+func TestRiskService_MaxDrawdownHaltsTrading(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoFlatten bool
+	}{
+		{"halt only", false},
+		{"halt and flatten", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := defaultTestRiskLimits()
+			limits.MaxDrawdown = 0.1
+			f := setupRiskService(t, limits, RiskServiceConfig{AutoFlattenOnDrawdown: tt.autoFlatten})
+
+			// Equity 100k: 50k cash plus 500 AAPL at 100
+			seedPortfolio(t, f.portfolioRepo, "default", 50000, map[entities.Symbol][2]float64{
+				"AAPL": {500, 100},
+			})
+
+			ctx := context.Background()
+			if err := f.service.MonitorRiskLimits(ctx, "default"); err != nil {
+				t.Fatalf("MonitorRiskLimits failed: %v", err)
+			}
+			if halted, _ := f.service.IsHalted(); halted {
+				t.Fatal("Expected trading to be live at peak equity")
+			}
+
+			// A fall to 70 takes equity to 85k, a 15% drawdown from the 100k peak
+			if err := f.portfolios.UpdatePositionPrices(ctx, "default", &entities.MarketData{Symbol: "AAPL", Price: 70}); err != nil {
+				t.Fatalf("UpdatePositionPrices failed: %v", err)
+			}
+			if err := f.service.MonitorRiskLimits(ctx, "default"); err != nil {
+				t.Fatalf("MonitorRiskLimits failed: %v", err)
+			}
+
+			halted, reason := f.service.IsHalted()
+			if !halted {
+				t.Fatal("Expected max drawdown breaker to halt trading")
+			}
+			if reason == "" {
+				t.Error("Expected a halt reason")
+			}
+
+			drawdownAlerts := 0
+			for _, msg := range f.bus.GetMessagesByTopic("risk.alert") {
+				alert := msg.Message.(RiskAlertMessage)
+				if alert.AlertType == "MAX_DRAWDOWN" {
+					drawdownAlerts++
+					if alert.Severity != "CRITICAL" {
+						t.Errorf("Expected CRITICAL severity, got %s", alert.Severity)
+					}
+				}
+			}
+			if drawdownAlerts != 1 {
+				t.Errorf("Expected exactly 1 MAX_DRAWDOWN alert, got %d", drawdownAlerts)
+			}
+
+			price := 70.0
+			order := entities.NewOrder("MSFT", entities.OrderSideBuy, entities.OrderTypeLimit, 1, &price)
+			if err := f.service.ValidateOrder(ctx, order); !errors.Is(err, entities.ErrTradingHalted) {
+				t.Errorf("Expected ErrTradingHalted, got %v", err)
+			}
+
+			flatten := f.bus.GetMessagesByTopic("order.approved")
+			if !tt.autoFlatten {
+				if len(flatten) != 0 {
+					t.Errorf("Expected no flatten orders without auto-flatten, got %d", len(flatten))
+				}
+				return
+			}
+			if len(flatten) != 1 {
+				t.Fatalf("Expected 1 flatten order, got %d", len(flatten))
+			}
+			flattenOrder := flatten[0].Message.(*entities.Order)
+			if flattenOrder.Symbol != "AAPL" || flattenOrder.Side != entities.OrderSideSell ||
+				flattenOrder.Type != entities.OrderTypeMarket || flattenOrder.Quantity != 500 {
+				t.Errorf("Unexpected flatten order: %+v", flattenOrder)
+			}
+		})
+	}
+}
+
+func TestRiskService_StalePriceAnnotatedOrRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     StalePricePolicy
+		wantReject bool
+	}{
+		{"annotate", StalePricePolicyAnnotate, false},
+		{"reject", StalePricePolicyReject, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{StalePricePolicy: tt.policy})
+			seedPortfolio(t, f.portfolioRepo, "default", 1000000, nil)
+
+			cache := NewPriceCache(f.bus, 5*time.Second, f.clock)
+			cache.Update(&entities.MarketData{Symbol: "AAPL", Price: 150, Timestamp: f.clock.Now()})
+			f.service.SetPriceCache(cache)
+
+			ctx := context.Background()
+			fresh := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 10, nil)
+			if err := f.service.ValidateOrder(ctx, fresh); err != nil {
+				t.Fatalf("Validation on a fresh price failed: %v", err)
+			}
+			if fresh.PriceStale {
+				t.Error("Expected order priced from a fresh quote not to be flagged")
+			}
+
+			f.clock.Advance(10 * time.Second)
+
+			stale := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 10, nil)
+			err := f.service.ValidateOrder(ctx, stale)
+			if !stale.PriceStale {
+				t.Error("Expected order priced from a 10s-old quote to be flagged stale")
+			}
+
+			alerts := f.bus.GetMessagesByTopic("risk.alert")
+			if !tt.wantReject {
+				if err != nil {
+					t.Fatalf("Expected annotate policy to allow the order, got %v", err)
+				}
+				if len(alerts) != 0 {
+					t.Errorf("Expected no alerts under annotate policy, got %d", len(alerts))
+				}
+				return
+			}
+
+			if !errors.Is(err, entities.ErrStalePrice) {
+				t.Fatalf("Expected ErrStalePrice under reject policy, got %v", err)
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("Expected one stale price alert, got %d", len(alerts))
+			}
+			alert := alerts[0].Message.(RiskAlertMessage)
+			if alert.AlertType != "STALE_PRICE" || !alert.PriceStale {
+				t.Errorf("Expected STALE_PRICE alert flagged stale, got %+v", alert)
+			}
+		})
+	}
+}
+
+func TestRiskService_DebouncesRepeatedSymbolAlerts(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{AlertDebounceInterval: time.Minute})
+	defer f.service.Stop()
+
+	seedPortfolio(t, f.portfolioRepo, "default", 50000, map[entities.Symbol][2]float64{
+		"AAPL": {500, 100},
+	})
+
+	concentrationAlerts := func() int {
+		count := 0
+		for _, msg := range f.bus.GetMessagesByTopic("risk.alert") {
+			if alert := msg.Message.(RiskAlertMessage); alert.AlertType == "CONCENTRATION_EXCEEDED" {
+				count++
+			}
+		}
+		return count
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := f.service.MonitorRiskLimits(ctx, "default"); err != nil {
+			t.Fatalf("MonitorRiskLimits failed: %v", err)
+		}
+	}
+	if n := concentrationAlerts(); n != 1 {
+		t.Fatalf("Expected repeated AAPL concentration alerts to collapse to 1, got %d", n)
+	}
+
+	waitFor(t, func() bool { return f.clock.Waiters() == 1 })
+	f.clock.Advance(time.Minute)
+	waitFor(t, func() bool { return concentrationAlerts() == 2 })
+}
+
+func TestRiskService_ReturnsHighestSeverityFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		dayPnL    float64
+		wantAlert string
+		wantErr   string
+	}{
+		// Position size (HIGH), concentration (MEDIUM) and daily loss (CRITICAL) all fail
+		{"critical beats high and medium", -20000, "DAILY_LOSS_LIMIT", "daily loss limit exceeded"},
+		// Position size and VaR are both HIGH; the earlier check wins the tie
+		{"earlier check wins a severity tie", 0, "POSITION_SIZE_LIMIT", "position size limit exceeded"},
+	}
+
+	for _, tt := range tests {
+		for _, concurrency := range []int{1, 8} {
+			t.Run(fmt.Sprintf("%s/concurrency %d", tt.name, concurrency), func(t *testing.T) {
+				f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{MaxConcurrentChecks: concurrency})
+
+				portfolio := seedPortfolio(t, f.portfolioRepo, "default", 50000, map[entities.Symbol][2]float64{
+					"AAPL": {500, 100},
+				})
+				portfolio.DayPnL = tt.dayPnL
+				if err := f.portfolioRepo.Save(context.Background(), portfolio); err != nil {
+					t.Fatalf("Failed to save portfolio: %v", err)
+				}
+
+				price := 100.0
+				order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, 10, &price)
+				err := f.service.ValidateOrder(context.Background(), order)
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected %q, got %v", tt.wantErr, err)
+				}
+
+				alerts := f.bus.GetMessagesByTopic("risk.alert")
+				if len(alerts) != 1 {
+					t.Fatalf("Expected a single alert for the winning failure, got %d", len(alerts))
+				}
+				if alert := alerts[0].Message.(RiskAlertMessage); alert.AlertType != tt.wantAlert {
+					t.Errorf("Expected %s alert, got %s", tt.wantAlert, alert.AlertType)
+				}
+			})
+		}
+	}
+}
+
+type fixedCorrelations map[entities.Symbol]map[entities.Symbol]float64
+
+func (c fixedCorrelations) GetCorrelations(ctx context.Context, symbols []entities.Symbol) (map[entities.Symbol]map[entities.Symbol]float64, error) {
+	return c, nil
+}
+
+func TestRiskService_PortfolioVaRAccountsForCorrelation(t *testing.T) {
+	// Position VaR is market value × 2% daily volatility × the 95% z-score
+	aaplVaR := 10000 * 0.02 * z95
+	msftVaR := 20000 * 0.02 * z95
+
+	tests := []struct {
+		name         string
+		config       RiskServiceConfig
+		correlations interfaces.CorrelationProvider
+		want         float64
+	}{
+		{
+			name:         "perfectly correlated positions add",
+			correlations: fixedCorrelations{"AAPL": {"MSFT": 1}},
+			want:         aaplVaR + msftVaR,
+		},
+		{
+			name: "independent positions add in quadrature",
+			want: math.Sqrt(aaplVaR*aaplVaR + msftVaR*msftVaR),
+		},
+		{
+			name:         "missing pairs use the default correlation",
+			config:       RiskServiceConfig{DefaultCorrelation: 0.3},
+			correlations: fixedCorrelations{},
+			want:         math.Sqrt(aaplVaR*aaplVaR + msftVaR*msftVaR + 2*0.3*aaplVaR*msftVaR),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), tt.config)
+			seedPortfolio(t, f.portfolioRepo, "default", 0, map[entities.Symbol][2]float64{
+				"AAPL": {100, 100},
+				"MSFT": {100, 200},
+			})
+			if tt.correlations != nil {
+				f.service.SetCorrelationProvider(tt.correlations)
+			}
+
+			risk, err := f.service.CalculatePortfolioRisk(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("CalculatePortfolioRisk failed: %v", err)
+			}
+			if math.Abs(risk.TotalVaR-tt.want) > 1e-6 {
+				t.Errorf("Expected portfolio VaR %.4f, got %.4f", tt.want, risk.TotalVaR)
+			}
+		})
+	}
+}
+
+// Standard normal quantiles at 95% and 99%
+const (
+	z95 = 1.6448536269514722
+	z99 = 2.3263478740408408
+)
+
+func TestRiskService_ZScoreMatchesNormalQuantiles(t *testing.T) {
+	tests := []struct {
+		confidence float64
+		want       float64
+	}{
+		{0.90, 1.281552},
+		{0.95, 1.644854},
+		{0.975, 1.959964},
+		{0.99, 2.326348},
+		// Outside (0, 1) falls back to 95%
+		{1, 1.644854},
+	}
+
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	for _, tt := range tests {
+		if z := f.service.getZScore(tt.confidence); math.Abs(z-tt.want) > 1e-3 {
+			t.Errorf("Expected z-score %.6f at %v, got %.6f", tt.want, tt.confidence, z)
+		}
+	}
+}
+
+func TestRiskService_SymbolLimitsOverrideGlobalLimits(t *testing.T) {
+	// Global limits are 10% position size and 20% concentration of a $100,000 portfolio
+	tests := []struct {
+		name      string
+		override  interfaces.RiskLimits
+		position  [2]float64
+		quantity  float64
+		wantCheck string
+	}{
+		{"higher position size lets a 15% order through", interfaces.RiskLimits{MaxPositionSize: 0.25}, [2]float64{}, 150, ""},
+		{"no override rejects the 15% order", interfaces.RiskLimits{}, [2]float64{}, 150, "position size"},
+		{"lower position size rejects an 8% order", interfaces.RiskLimits{MaxPositionSize: 0.05}, [2]float64{}, 80, "position size"},
+		{"higher concentration allows a 30% holding", interfaces.RiskLimits{MaxConcentration: 0.35, MaxPositionSize: 0.5}, [2]float64{300, 100}, 1, ""},
+		{"no override rejects the 30% holding", interfaces.RiskLimits{MaxPositionSize: 0.5}, [2]float64{300, 100}, 1, "concentration"},
+		{"lower concentration rejects a 5% holding", interfaces.RiskLimits{MaxConcentration: 0.03}, [2]float64{50, 100}, 1, "concentration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{
+				SymbolLimits: map[entities.Symbol]interfaces.RiskLimits{"AAPL": tt.override},
+			})
+			positions := map[entities.Symbol][2]float64{}
+			if tt.position[0] > 0 {
+				positions["AAPL"] = tt.position
+			}
+			seedPortfolio(t, f.portfolioRepo, "default", 100000-tt.position[0]*tt.position[1], positions)
+			portfolio, err := f.portfolios.GetPortfolio(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("Failed to get portfolio: %v", err)
+			}
+
+			price := 100.0
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, tt.quantity, &price)
+			sizeErr := f.service.checkPositionSize(context.Background(), portfolio, order)
+			_, concentrationErr := f.service.checkConcentration(portfolio)
+
+			if (sizeErr != nil) != (tt.wantCheck == "position size") {
+				t.Errorf("Unexpected position size result: %v", sizeErr)
+			}
+			if (concentrationErr != nil) != (tt.wantCheck == "concentration") {
+				t.Errorf("Unexpected concentration result: %v", concentrationErr)
+			}
+		})
+	}
+}
+
+func TestRiskService_StopBuyIsSizeCheckedAtItsStopPrice(t *testing.T) {
+	// A 15% stop buy of a $100,000 portfolio against the 10% position size limit
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	seedPortfolio(t, f.portfolioRepo, "default", 100000, nil)
+	portfolio, err := f.portfolios.GetPortfolio(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("Failed to get portfolio: %v", err)
+	}
+
+	stop := 100.0
+	order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeStop, 150, nil)
+	order.StopPrice = &stop
+
+	err = f.service.checkPositionSize(context.Background(), portfolio, order)
+	if err == nil || !strings.Contains(err.Error(), "position size limit exceeded") {
+		t.Errorf("Expected the stop buy to exceed the position size limit, got %v", err)
+	}
+}
+
+// fixedPrices serves a constant latest price for every symbol
+type fixedPrices float64
+
+func (p fixedPrices) GetRealTimePrice(ctx context.Context, symbol entities.Symbol) (*entities.MarketData, error) {
+	return &entities.MarketData{Symbol: symbol, Price: float64(p)}, nil
+}
+
+func TestRiskService_MarketOrderNotionalUsesLatestPrice(t *testing.T) {
+	limit := 120.0
+	tests := []struct {
+		name         string
+		prices       interfaces.LastPriceProvider
+		price        *float64
+		wantRequired float64
+	}{
+		// 100 shares × 250 plus the 10% cash buffer
+		{name: "latest price from the provider", prices: fixedPrices(250), wantRequired: 27500},
+		{name: "limit price without a provider", price: &limit, wantRequired: 13200},
+		{name: "default price as a last resort", wantRequired: 100 * DefaultEstimatedPrice * 1.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+			if tt.prices != nil {
+				f.service.SetPriceProvider(tt.prices)
+			}
+			portfolio := seedPortfolio(t, f.portfolioRepo, "default", 5000, nil)
+
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeMarket, 100, tt.price)
+			err := f.service.checkCashBalance(context.Background(), portfolio, order)
+			if err == nil {
+				t.Fatal("Expected insufficient cash")
+			}
+			if want := fmt.Sprintf("required %.2f", tt.wantRequired); !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected %q in %q", want, err.Error())
+			}
+		})
+	}
+}
+
+func TestRiskService_CashBufferIsConfigurable(t *testing.T) {
+	// A $10,000 limit buy against $10,500 of cash
+	tests := []struct {
+		name    string
+		buffer  float64
+		wantErr bool
+	}{
+		{"10% buffer requires $11,000", 0.10, true},
+		{"no buffer requires only the cost", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := defaultTestRiskLimits()
+			limits.CashBufferPct = tt.buffer
+			f := setupRiskService(t, limits, RiskServiceConfig{})
+			portfolio := seedPortfolio(t, f.portfolioRepo, "default", 10500, nil)
+
+			price := 100.0
+			order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, 100, &price)
+			err := f.service.checkCashBalance(context.Background(), portfolio, order)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected rejection %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRiskService_RejectsSellsOpeningShorts(t *testing.T) {
+	// A $5,000 AAPL long in a $100,000 portfolio; portfolios cannot book shorts
+	tests := []struct {
+		name      string
+		quantity  float64
+		wantShort bool
+	}{
+		{name: "sell within the long", quantity: 50},
+		{name: "sell flipping into a small short", quantity: 51, wantShort: true},
+		{name: "sell flipping into a 15% short", quantity: 200, wantShort: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := defaultTestRiskLimits()
+			limits.MaxVaR = math.Inf(1)
+			f := setupRiskService(t, limits, RiskServiceConfig{})
+			seedPortfolio(t, f.portfolioRepo, "default", 95000, map[entities.Symbol][2]float64{
+				"AAPL": {50, 100},
+			})
+
+			price := 100.0
+			order := entities.NewOrder("AAPL", entities.OrderSideSell, entities.OrderTypeLimit, tt.quantity, &price)
+			err := f.service.ValidateOrder(context.Background(), order)
+
+			if !tt.wantShort {
+				if err != nil {
+					t.Errorf("Expected the sell to pass, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, entities.ErrInsufficientQuantity) {
+				t.Errorf("Expected the short-opening sell to be rejected, got %v", err)
+			}
+
+			impact, err := f.service.EstimateOrderImpact(context.Background(), order)
+			if err != nil {
+				t.Fatalf("EstimateOrderImpact failed: %v", err)
+			}
+			if impact.WouldPass || impact.Breaches[0].Check != "short_sale" {
+				t.Errorf("Expected a short_sale breach, got %+v", impact.Breaches)
+			}
+		})
+	}
+}
+
+func TestRiskService_ValidatesAgainstTheOrdersPortfolio(t *testing.T) {
+	f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	seedPortfolio(t, f.portfolioRepo, "default", 100000, nil)
+	seedPortfolio(t, f.portfolioRepo, "desk-b", 1000, nil)
+
+	// $5,000 is 5% of the default portfolio but more than desk-b's cash
+	tests := []struct {
+		portfolioID string
+		wantErr     bool
+	}{
+		{"", false},
+		{"default", false},
+		{"desk-b", true},
+	}
+	for _, tt := range tests {
+		price := 100.0
+		order := entities.NewOrder("AAPL", entities.OrderSideBuy, entities.OrderTypeLimit, 50, &price)
+		order.PortfolioID = tt.portfolioID
+		if err := f.service.ValidateOrder(context.Background(), order); (err != nil) != tt.wantErr {
+			t.Errorf("Portfolio %q: expected rejection %t, got %v", tt.portfolioID, tt.wantErr, err)
+		}
+	}
+}
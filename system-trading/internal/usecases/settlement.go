@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const AuditTypeSettlement = "settlement"
+
+var ErrAlreadySettled = errors.New("trading day already settled")
+
+type SettlementConfig struct {
+	PortfolioID string
+	Clock       interfaces.Clock
+}
+
+// Settlement closes out each trading day at the session close: it rolls the
+// portfolio's DayPnL into its history, reconciles against the broker, writes a
+// daily summary to the audit store and publishes settlement.complete. Each
+// trading day is settled at most once.
+type Settlement struct {
+	calendar         *TradingCalendar
+	portfolioService *PortfolioService
+	orderRepo        interfaces.OrderRepository
+	auditRepo        interfaces.AuditRepository
+	messageBus       interfaces.MessageBus
+	logger           interfaces.Logger
+	metrics          interfaces.MetricsCollector
+	config           SettlementConfig
+	clock            interfaces.Clock
+	reconciler       *ReconciliationService
+
+	// drawdown follows intraday equity from portfolio updates and is reset at each close
+	drawdown   *DrawdownTracker
+	equityMu   sync.Mutex
+	lastEquity float64
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSettlement(
+	calendar *TradingCalendar,
+	portfolioService *PortfolioService,
+	orderRepo interfaces.OrderRepository,
+	auditRepo interfaces.AuditRepository,
+	messageBus interfaces.MessageBus,
+	logger interfaces.Logger,
+	metrics interfaces.MetricsCollector,
+	config SettlementConfig,
+) *Settlement {
+	if config.PortfolioID == "" {
+		config.PortfolioID = entities.DefaultPortfolioID
+	}
+
+	settlementClock := config.Clock
+	if settlementClock == nil {
+		settlementClock = systemClock{}
+	}
+
+	return &Settlement{
+		calendar:         calendar,
+		portfolioService: portfolioService,
+		orderRepo:        orderRepo,
+		auditRepo:        auditRepo,
+		messageBus:       messageBus,
+		logger:           logger,
+		metrics:          metrics,
+		config:           config,
+		clock:            settlementClock,
+		drawdown:         NewDrawdownTracker(),
+	}
+}
+
+// SetReconciler enables broker reconciliation as part of each settlement
+func (s *Settlement) SetReconciler(reconciler *ReconciliationService) {
+	s.reconciler = reconciler
+}
+
+// Start tracks intraday equity and settles at every session close until Stop
+func (s *Settlement) Start(ctx context.Context) error {
+	if err := s.messageBus.Subscribe(ctx, "portfolio.update", s.handlePortfolioUpdate); err != nil {
+		return fmt.Errorf("failed to subscribe to portfolio.update: %w", err)
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			now := s.clock.Now()
+			sessionClose := s.calendar.NextClose(now)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.clock.After(sessionClose.Sub(now)):
+			}
+
+			if _, err := s.Settle(ctx, sessionClose); err != nil && !errors.Is(err, ErrAlreadySettled) {
+				s.metrics.IncrementCounter("settlement_failures", map[string]string{
+					"portfolio_id": s.config.PortfolioID,
+				})
+				s.logger.Error("End-of-day settlement failed",
+					interfaces.Field{Key: "portfolio_id", Value: s.config.PortfolioID},
+					interfaces.Field{Key: "trading_day", Value: s.calendar.TradingDay(sessionClose)},
+					interfaces.Field{Key: "error", Value: err},
+				)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop halts the settlement loop and waits for any running settlement to finish
+func (s *Settlement) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// Settle settles the trading day whose session closes at sessionClose. It returns
+// ErrAlreadySettled if the audit store already holds that day's summary.
+func (s *Settlement) Settle(ctx context.Context, sessionClose time.Time) (*SettlementSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tradingDay := s.calendar.TradingDay(sessionClose)
+	recordID := settlementRecordID(s.config.PortfolioID, tradingDay)
+
+	settled, err := s.isSettled(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if settled {
+		return nil, ErrAlreadySettled
+	}
+
+	summary := &SettlementSummary{
+		PortfolioID:  s.config.PortfolioID,
+		TradingDay:   tradingDay,
+		SessionClose: sessionClose,
+	}
+
+	if err := s.summarizeTrades(ctx, summary); err != nil {
+		return nil, err
+	}
+
+	if s.reconciler != nil {
+		report, err := s.reconciler.Reconcile(ctx, s.config.PortfolioID)
+		if err != nil {
+			// Settlement still proceeds; a missing report is visible in the summary
+			s.logger.Error("Settlement reconciliation failed",
+				interfaces.Field{Key: "portfolio_id", Value: s.config.PortfolioID},
+				interfaces.Field{Key: "error", Value: err},
+			)
+		}
+		summary.Reconciliation = report
+	}
+
+	settledAt := s.clock.Now()
+	portfolio, day, err := s.portfolioService.RollDay(ctx, s.config.PortfolioID, tradingDay, settledAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll day P&L: %w", err)
+	}
+
+	s.drawdown.Observe(portfolio.TotalValue)
+	summary.PnL = day.PnL
+	summary.ClosingEquity = portfolio.TotalValue
+	summary.MaxDrawdown = s.drawdown.MaxDrawdown()
+	summary.SettledAt = settledAt
+	s.drawdown.Reset(portfolio.TotalValue)
+
+	record := &interfaces.AuditRecord{
+		ID:        recordID,
+		Type:      AuditTypeSettlement,
+		Timestamp: settledAt,
+		Data:      summary,
+	}
+	if err := s.auditRepo.SaveAuditRecord(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to write settlement summary: %w", err)
+	}
+
+	if err := s.messageBus.Publish(ctx, "settlement.complete", summary); err != nil {
+		s.logger.Warn("Failed to publish settlement complete message",
+			interfaces.Field{Key: "trading_day", Value: tradingDay},
+			interfaces.Field{Key: "error", Value: err},
+		)
+	}
+
+	labels := map[string]string{"portfolio_id": s.config.PortfolioID}
+	s.metrics.IncrementCounter("settlements_completed", labels)
+	s.metrics.SetGauge("settlement_day_pnl", summary.PnL, labels)
+
+	s.logger.Info("Trading day settled",
+		interfaces.Field{Key: "portfolio_id", Value: s.config.PortfolioID},
+		interfaces.Field{Key: "trading_day", Value: tradingDay},
+		interfaces.Field{Key: "trades", Value: summary.Trades},
+		interfaces.Field{Key: "pnl", Value: summary.PnL},
+		interfaces.Field{Key: "fees", Value: summary.Fees},
+		interfaces.Field{Key: "max_drawdown", Value: summary.MaxDrawdown},
+	)
+
+	return summary, nil
+}
+
+// summarizeTrades aggregates orders executed on the trading day up to the close
+func (s *Settlement) summarizeTrades(ctx context.Context, summary *SettlementSummary) error {
+	executed := entities.OrderStatusExecuted
+	dayStart := s.calendar.midnight(summary.SessionClose)
+
+	orders, err := s.orderRepo.List(ctx, interfaces.OrderFilters{
+		Status: &executed,
+		DateTo: &summary.SessionClose,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list executed orders: %w", err)
+	}
+
+	for _, order := range orders {
+		if order.ExecutedAt == nil || order.ExecutedAt.Before(dayStart) || order.ExecutedAt.After(summary.SessionClose) {
+			continue
+		}
+		summary.Trades++
+		summary.Fees += order.Fees
+		if order.ExecutedPrice != nil && order.ExecutedQuantity != nil {
+			summary.Volume += *order.ExecutedPrice * *order.ExecutedQuantity
+		}
+	}
+	return nil
+}
+
+func (s *Settlement) isSettled(ctx context.Context, recordID string) (bool, error) {
+	records, err := s.auditRepo.ListAuditRecords(ctx, AuditTypeSettlement, time.Time{})
+	if err != nil {
+		return false, fmt.Errorf("failed to list settlement records: %w", err)
+	}
+	for _, record := range records {
+		if record.ID == recordID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Settlement) handlePortfolioUpdate(ctx context.Context, data []byte) error {
+	var update PortfolioUpdateMessage
+	if err := interfaces.DecodeMessage(ctx, data, &update); err != nil {
+		return fmt.Errorf("invalid portfolio update: %w", err)
+	}
+	if update.PortfolioID != s.config.PortfolioID {
+		return nil
+	}
+
+	s.equityMu.Lock()
+	equity := update.TotalValue
+	if update.Mode == PortfolioUpdateDelta {
+		equity = s.lastEquity + update.TotalValueDelta
+	}
+	s.lastEquity = equity
+	s.equityMu.Unlock()
+
+	s.drawdown.Observe(equity)
+	return nil
+}
+
+func settlementRecordID(portfolioID, tradingDay string) string {
+	return fmt.Sprintf("settlement-%s-%s", portfolioID, tradingDay)
+}
+
+// SettlementSummary is the end-of-day record written to the audit store
+type SettlementSummary struct {
+	PortfolioID  string    `json:"portfolio_id"`
+	TradingDay   string    `json:"trading_day"`
+	SessionClose time.Time `json:"session_close"`
+	Trades       int       `json:"trades"`
+	Volume       float64   `json:"volume"`
+	Fees         float64   `json:"fees"`
+	// PnL is the day's realized P&L net of fees
+	PnL            float64               `json:"pnl"`
+	ClosingEquity  float64               `json:"closing_equity"`
+	MaxDrawdown    float64               `json:"max_drawdown"`
+	Reconciliation *ReconciliationReport `json:"reconciliation,omitempty"`
+	SettledAt      time.Time             `json:"settled_at"`
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/infrastructure/messagebus"
+	"github.com/system-trading/core/internal/infrastructure/repositories"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// executeSettlementOrder fills an order at the given time, applies it to the
+// portfolio and stores it for settlement to aggregate
+func executeSettlementOrder(t *testing.T, service *PortfolioService, orderRepo interfaces.OrderRepository,
+	at time.Time, side entities.OrderSide, quantity, price, fees float64) {
+	t.Helper()
+
+	order := entities.NewOrder("AAPL", side, entities.OrderTypeMarket, quantity, nil)
+	order.Execute(price, quantity)
+	order.ExecutedAt = &at
+	order.CreatedAt = at
+	order.Fees = fees
+
+	if err := service.ProcessOrderExecution(context.Background(), order); err != nil {
+		t.Fatalf("ProcessOrderExecution failed: %v", err)
+	}
+	if err := orderRepo.Create(context.Background(), order); err != nil {
+		t.Fatalf("Failed to store order: %v", err)
+	}
+}
+
+func TestSettlement_SettlesOnceAtSessionClose(t *testing.T) {
+	ctx := context.Background()
+	// Monday afternoon, an hour before the close
+	fakeClock := clock.NewFakeClock(time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC))
+	calendar := NewTradingCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour)
+
+	portfolioService, portfolioRepo := setupPortfolioService(t, 100000)
+	bus := portfolioService.messageBus.(*messagebus.MockMessageBus)
+	orderRepo := repositories.NewInMemoryOrderRepository()
+	auditRepo := repositories.NewInMemoryAuditRepository()
+
+	// Friday's fill belongs to the previous trading day and must not be counted
+	friday := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	stale := entities.NewOrder("MSFT", entities.OrderSideBuy, entities.OrderTypeMarket, 10, nil)
+	stale.Execute(300, 10)
+	stale.ExecutedAt = &friday
+	stale.CreatedAt = friday
+	stale.Fees = 5
+	if err := orderRepo.Create(ctx, stale); err != nil {
+		t.Fatalf("Failed to store order: %v", err)
+	}
+
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 4, hour, minute, 0, 0, time.UTC)
+	}
+	executeSettlementOrder(t, portfolioService, orderRepo, monday(10, 0), entities.OrderSideBuy, 100, 100, 1)
+	executeSettlementOrder(t, portfolioService, orderRepo, monday(11, 0), entities.OrderSideSell, 50, 110, 1)
+	executeSettlementOrder(t, portfolioService, orderRepo, monday(14, 0), entities.OrderSideSell, 50, 95, 1)
+
+	settlement := NewSettlement(calendar, portfolioService, orderRepo, auditRepo, bus,
+		newTestLogger(t), newTestMetrics("settlement"), SettlementConfig{Clock: fakeClock})
+	if err := settlement.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer settlement.Stop()
+
+	// Intraday equity peaks at 101000 and falls to 98980, a 2% drawdown
+	handler := bus.GetHandler("portfolio.update")
+	for _, equity := range []float64{100000, 101000, 98980, 100100} {
+		data, _ := json.Marshal(PortfolioUpdateMessage{PortfolioID: "default", TotalValue: equity})
+		if err := handler(ctx, data); err != nil {
+			t.Fatalf("Portfolio update handler failed: %v", err)
+		}
+	}
+
+	waitFor(t, func() bool { return fakeClock.Waiters() == 1 })
+	fakeClock.Advance(time.Hour)
+	waitFor(t, func() bool { return len(bus.GetMessagesByTopic("settlement.complete")) == 1 })
+
+	// The loop moves on to Tuesday's close; settling Monday again is a no-op
+	waitFor(t, func() bool { return fakeClock.Waiters() == 1 })
+	if _, err := settlement.Settle(ctx, monday(16, 0)); !errors.Is(err, ErrAlreadySettled) {
+		t.Errorf("Expected ErrAlreadySettled on second settlement, got %v", err)
+	}
+
+	records, err := auditRepo.ListAuditRecords(ctx, AuditTypeSettlement, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to list audit records: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected exactly one settlement record, got %d", len(records))
+	}
+	if published := len(bus.GetMessagesByTopic("settlement.complete")); published != 1 {
+		t.Errorf("Expected one settlement.complete event, got %d", published)
+	}
+
+	summary := records[0].Data.(*SettlementSummary)
+	// Realized: 50*(110-100) + 50*(95-100) = 250, less 3 in fees
+	expectedPnL := 247.0
+	if summary.TradingDay != "2024-03-04" || summary.Trades != 3 {
+		t.Errorf("Expected 3 trades on 2024-03-04, got %d on %s", summary.Trades, summary.TradingDay)
+	}
+	if summary.Fees != 3 || summary.Volume != 10000+5500+4750 {
+		t.Errorf("Unexpected fees/volume: %v/%v", summary.Fees, summary.Volume)
+	}
+	if math.Abs(summary.PnL-expectedPnL) > 1e-9 || math.Abs(summary.ClosingEquity-(100000+expectedPnL)) > 1e-9 {
+		t.Errorf("Unexpected P&L/closing equity: %v/%v", summary.PnL, summary.ClosingEquity)
+	}
+	if math.Abs(summary.MaxDrawdown-0.02) > 1e-9 {
+		t.Errorf("Expected 2%% max drawdown, got %v", summary.MaxDrawdown)
+	}
+
+	portfolio, err := portfolioRepo.GetByID(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to load portfolio: %v", err)
+	}
+	if portfolio.DayPnL != 0 {
+		t.Errorf("Expected DayPnL reset after settlement, got %v", portfolio.DayPnL)
+	}
+	if len(portfolio.PnLHistory) != 1 || portfolio.PnLHistory[0].TradingDay != "2024-03-04" ||
+		math.Abs(portfolio.PnLHistory[0].PnL-expectedPnL) > 1e-9 {
+		t.Errorf("Unexpected P&L history: %+v", portfolio.PnLHistory)
+	}
+}
+
+func TestTradingCalendar_NextCloseSkipsClosedDays(t *testing.T) {
+	calendar := NewTradingCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour, "2024-03-11")
+
+	tests := []struct {
+		name string
+		from time.Time
+		want time.Time
+	}{
+		{"before close", time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC), time.Date(2024, 3, 8, 16, 0, 0, 0, time.UTC)},
+		{"at close rolls over weekend and holiday", time.Date(2024, 3, 8, 16, 0, 0, 0, time.UTC), time.Date(2024, 3, 12, 16, 0, 0, 0, time.UTC)},
+		{"from saturday", time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC), time.Date(2024, 3, 12, 16, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.NextClose(tt.from); !got.Equal(tt.want) {
+				t.Errorf("NextClose(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
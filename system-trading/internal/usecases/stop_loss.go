@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// StopLossTriggeredMessage is published on risk.stop_loss_triggered when a
+// position's loss from its average price passes its stop-loss threshold
+type StopLossTriggeredMessage struct {
+	PortfolioID   string          `json:"portfolio_id"`
+	Symbol        entities.Symbol `json:"symbol"`
+	Quantity      float64         `json:"quantity"`
+	AveragePrice  float64         `json:"average_price"`
+	CurrentPrice  float64         `json:"current_price"`
+	UnrealizedPnL float64         `json:"unrealized_pnl"`
+	LossPercent   float64         `json:"loss_percent"`
+	Threshold     float64         `json:"threshold"`
+	// SuggestedOrder is an unapproved market order closing the whole position;
+	// it still has to pass through the normal order flow
+	SuggestedOrder *entities.Order `json:"suggested_order"`
+	Timestamp      time.Time       `json:"timestamp"`
+}
+
+// stopLossKey identifies one position's stop-loss
+type stopLossKey struct {
+	portfolioID string
+	symbol      entities.Symbol
+}
+
+// stopLossLevel is the position and threshold a stop-loss fired at
+type stopLossLevel struct {
+	quantity     float64
+	averagePrice float64
+	threshold    float64
+}
+
+// MonitorStopLosses publishes a stop-loss event for every position whose loss
+// from its average price exceeds the symbol's StopLossThreshold, and returns
+// the events it published. A position's event is published once; it fires
+// again only after the loss recovers past the threshold or the position's
+// size, cost basis or threshold changes.
+func (s *RiskService) MonitorStopLosses(ctx context.Context, portfolioID string) ([]StopLossTriggeredMessage, error) {
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	symbols := make([]entities.Symbol, 0, len(portfolio.Positions))
+	for symbol := range portfolio.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	var triggered []StopLossTriggeredMessage
+	for _, symbol := range symbols {
+		position := portfolio.Positions[symbol]
+		key := stopLossKey{portfolioID: portfolioID, symbol: symbol}
+		threshold := s.limitsFor(symbol).StopLossThreshold
+		if threshold <= 0 || position.Quantity == 0 {
+			s.rearmStopLoss(key)
+			continue
+		}
+
+		loss := positionLossPercent(position)
+		if loss <= threshold {
+			s.rearmStopLoss(key)
+			continue
+		}
+
+		level := stopLossLevel{quantity: position.Quantity, averagePrice: position.AveragePrice, threshold: threshold}
+		if s.stopLossFiredAt(key, level) {
+			continue
+		}
+
+		event := StopLossTriggeredMessage{
+			PortfolioID:    portfolioID,
+			Symbol:         symbol,
+			Quantity:       position.Quantity,
+			AveragePrice:   position.AveragePrice,
+			CurrentPrice:   position.CurrentPrice,
+			UnrealizedPnL:  position.UnrealizedPnL,
+			LossPercent:    loss,
+			Threshold:      threshold,
+			SuggestedOrder: liquidatingOrder(portfolioID, position),
+			Timestamp:      s.clock.Now(),
+		}
+		if err := s.messageBus.Publish(ctx, "risk.stop_loss_triggered", event); err != nil {
+			s.logger.Error("Failed to publish stop-loss trigger",
+				interfaces.Field{Key: "symbol", Value: symbol},
+				interfaces.Field{Key: "error", Value: err},
+			)
+			continue
+		}
+		s.markStopLossFired(key, level)
+
+		s.metrics.IncrementCounter("stop_loss_triggers_total", map[string]string{
+			"symbol": string(symbol),
+		})
+		s.logger.Warn("Position stop-loss triggered",
+			interfaces.Field{Key: "portfolio_id", Value: portfolioID},
+			interfaces.Field{Key: "symbol", Value: symbol},
+			interfaces.Field{Key: "loss_percent", Value: loss},
+			interfaces.Field{Key: "threshold", Value: threshold},
+		)
+		triggered = append(triggered, event)
+	}
+	s.forgetClosedStopLosses(portfolio)
+
+	return triggered, nil
+}
+
+func (s *RiskService) stopLossFiredAt(key stopLossKey, level stopLossLevel) bool {
+	s.stopLossesMu.Lock()
+	defer s.stopLossesMu.Unlock()
+	fired, ok := s.stopLosses[key]
+	return ok && fired == level
+}
+
+func (s *RiskService) markStopLossFired(key stopLossKey, level stopLossLevel) {
+	s.stopLossesMu.Lock()
+	defer s.stopLossesMu.Unlock()
+	s.stopLosses[key] = level
+}
+
+func (s *RiskService) rearmStopLoss(key stopLossKey) {
+	s.stopLossesMu.Lock()
+	defer s.stopLossesMu.Unlock()
+	delete(s.stopLosses, key)
+}
+
+// forgetClosedStopLosses re-arms the stop-losses of positions no longer held
+func (s *RiskService) forgetClosedStopLosses(portfolio *entities.Portfolio) {
+	s.stopLossesMu.Lock()
+	defer s.stopLossesMu.Unlock()
+	for key := range s.stopLosses {
+		if key.portfolioID != portfolio.ID {
+			continue
+		}
+		if _, held := portfolio.Positions[key.symbol]; !held {
+			delete(s.stopLosses, key)
+		}
+	}
+}
+
+// positionLossPercent is the unrealized loss as a fraction of the position's
+// cost basis; gains are negative
+func positionLossPercent(position *entities.Position) float64 {
+	costBasis := position.AveragePrice * math.Abs(position.Quantity)
+	if costBasis == 0 {
+		return 0
+	}
+	return -position.UnrealizedPnL / costBasis
+}
+
+// liquidatingOrder is a market order for the full quantity on the opposite
+// side of position
+func liquidatingOrder(portfolioID string, position *entities.Position) *entities.Order {
+	side := entities.OrderSideSell
+	if position.Quantity < 0 {
+		side = entities.OrderSideBuy
+	}
+	order := entities.NewOrder(position.Symbol, side, entities.OrderTypeMarket, math.Abs(position.Quantity), nil)
+	order.PortfolioID = portfolioID
+	return order
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+func TestRiskService_MonitorStopLossesTriggersPastThreshold(t *testing.T) {
+	tests := []struct {
+		name         string
+		symbolLimit  float64
+		wantTriggers int
+	}{
+		{name: "-8% past the global 5% threshold", wantTriggers: 1},
+		{name: "symbol override of 10% holds", symbolLimit: 0.10, wantTriggers: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := defaultTestRiskLimits()
+			limits.StopLossThreshold = 0.05
+			config := RiskServiceConfig{}
+			if tt.symbolLimit > 0 {
+				config.SymbolLimits = map[entities.Symbol]interfaces.RiskLimits{
+					"AAPL": {StopLossThreshold: tt.symbolLimit},
+				}
+			}
+			f := setupRiskService(t, limits, config)
+
+			portfolio := seedPortfolio(t, f.portfolioRepo, "default", 70000, map[entities.Symbol][2]float64{
+				"AAPL": {150, 100},
+				"MSFT": {50, 300},
+			})
+			portfolio.UpdatePositionPrice("AAPL", 92)
+			portfolio.UpdatePositionPrice("MSFT", 297)
+			if err := f.portfolioRepo.Save(context.Background(), portfolio); err != nil {
+				t.Fatalf("Failed to save portfolio: %v", err)
+			}
+
+			triggered, err := f.service.MonitorStopLosses(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("MonitorStopLosses failed: %v", err)
+			}
+
+			messages := f.bus.GetMessagesByTopic("risk.stop_loss_triggered")
+			if len(messages) != tt.wantTriggers || len(triggered) != tt.wantTriggers {
+				t.Fatalf("Expected %d stop-loss events, got %d published and %d returned",
+					tt.wantTriggers, len(messages), len(triggered))
+			}
+			if tt.wantTriggers == 0 {
+				return
+			}
+
+			event := messages[0].Message.(StopLossTriggeredMessage)
+			if event.Symbol != "AAPL" || event.Threshold != 0.05 {
+				t.Errorf("Expected AAPL at the 5%% threshold, got %s at %.2f", event.Symbol, event.Threshold)
+			}
+			order := event.SuggestedOrder
+			if order == nil {
+				t.Fatal("Expected a suggested liquidating order")
+			}
+			if order.Side != entities.OrderSideSell || order.Type != entities.OrderTypeMarket || order.Quantity != 150 {
+				t.Errorf("Expected a market sell of 150, got %s %s of %.0f", order.Type, order.Side, order.Quantity)
+			}
+		})
+	}
+}
+
+func TestRiskService_MonitorStopLossesPublishesOncePerBreach(t *testing.T) {
+	limits := defaultTestRiskLimits()
+	limits.StopLossThreshold = 0.05
+	f := setupRiskService(t, limits, RiskServiceConfig{})
+
+	portfolio := seedPortfolio(t, f.portfolioRepo, "default", 85000, map[entities.Symbol][2]float64{
+		"AAPL": {150, 100},
+	})
+	setPrice := func(price float64) {
+		t.Helper()
+		portfolio.UpdatePositionPrice("AAPL", price)
+		if err := f.portfolioRepo.Save(context.Background(), portfolio); err != nil {
+			t.Fatalf("Failed to save portfolio: %v", err)
+		}
+	}
+	monitor := func() {
+		t.Helper()
+		if _, err := f.service.MonitorStopLosses(context.Background(), "default"); err != nil {
+			t.Fatalf("MonitorStopLosses failed: %v", err)
+		}
+	}
+
+	setPrice(92)
+	for i := 0; i < 3; i++ {
+		monitor()
+	}
+	setPrice(90)
+	monitor()
+	if got := len(f.bus.GetMessagesByTopic("risk.stop_loss_triggered")); got != 1 {
+		t.Fatalf("Expected one stop-loss event across repeated passes, got %d", got)
+	}
+
+	// Recovering above the stop re-arms it for the next breach
+	setPrice(99)
+	monitor()
+	setPrice(92)
+	monitor()
+	if got := len(f.bus.GetMessagesByTopic("risk.stop_loss_triggered")); got != 2 {
+		t.Errorf("Expected a second event after the position recovered and fell again, got %d", got)
+	}
+}
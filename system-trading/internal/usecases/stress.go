@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// StressScenario is a set of instantaneous price shocks, as fractional returns.
+// SymbolShocks override MarketShock for the listed symbols.
+type StressScenario struct {
+	Name         string                      `json:"name"`
+	Description  string                      `json:"description,omitempty"`
+	MarketShock  float64                     `json:"market_shock"`
+	SymbolShocks map[entities.Symbol]float64 `json:"symbol_shocks,omitempty"`
+}
+
+// FinancialCrisis2008Scenario approximates the 2008 peak-to-trough moves: broad
+// equities down 40%, financials down 55%, and Treasuries and gold rallying as
+// rates fell
+func FinancialCrisis2008Scenario() StressScenario {
+	return StressScenario{
+		Name:        "financial_crisis_2008",
+		Description: "Equities -40%, financials -55%, Treasuries rally on falling rates",
+		MarketShock: -0.40,
+		SymbolShocks: map[entities.Symbol]float64{
+			"XLF": -0.55,
+			"TLT": 0.20,
+			"IEF": 0.10,
+			"GLD": 0.05,
+		},
+	}
+}
+
+// FlashCrashScenario is a sudden market-wide 9% drop, as on 6 May 2010
+func FlashCrashScenario() StressScenario {
+	return StressScenario{
+		Name:        "flash_crash",
+		Description: "Market-wide -9% intraday drop",
+		MarketShock: -0.09,
+	}
+}
+
+// Shock returns the fractional price move the scenario applies to symbol
+func (s StressScenario) Shock(symbol entities.Symbol) float64 {
+	if shock, exists := s.SymbolShocks[symbol]; exists {
+		return shock
+	}
+	return s.MarketShock
+}
+
+// StressBreach is a risk limit the shocked portfolio would exceed
+type StressBreach struct {
+	Limit     string          `json:"limit"`
+	Symbol    entities.Symbol `json:"symbol,omitempty"`
+	Value     float64         `json:"value"`
+	Threshold float64         `json:"threshold"`
+}
+
+type StressResult struct {
+	Scenario       string                      `json:"scenario"`
+	CurrentValue   float64                     `json:"current_value"`
+	ProjectedValue float64                     `json:"projected_value"`
+	PnL            float64                     `json:"pnl"`
+	PositionPnL    map[entities.Symbol]float64 `json:"position_pnl"`
+	Breaches       []StressBreach              `json:"breaches,omitempty"`
+}
+
+// RunStressTest revalues every position under scenario and reports the
+// projected value, P&L and the risk limits the shocked portfolio would breach.
+// Each breach is published as a CRITICAL risk alert. The portfolio itself is
+// not modified.
+func (s *RiskService) RunStressTest(ctx context.Context, portfolioID string, scenario StressScenario) (*StressResult, error) {
+	for symbol, shock := range scenario.SymbolShocks {
+		if shock < -1 {
+			return nil, fmt.Errorf("shock for %s cannot lose more than 100%%, got %v", symbol, shock)
+		}
+	}
+	if scenario.MarketShock < -1 {
+		return nil, fmt.Errorf("market shock cannot lose more than 100%%, got %v", scenario.MarketShock)
+	}
+
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	shocked := &entities.Portfolio{
+		ID:        portfolio.ID,
+		Cash:      portfolio.Cash,
+		Positions: make(map[entities.Symbol]*entities.Position, len(portfolio.Positions)),
+	}
+	result := &StressResult{
+		Scenario:     scenario.Name,
+		CurrentValue: portfolio.TotalValue,
+		PositionPnL:  make(map[entities.Symbol]float64, len(portfolio.Positions)),
+	}
+
+	projected := portfolio.Cash
+	for symbol, position := range portfolio.Positions {
+		shockedPosition := *position
+		shockedPosition.MarketValue = position.MarketValue * (1 + scenario.Shock(symbol))
+		shocked.Positions[symbol] = &shockedPosition
+
+		result.PositionPnL[symbol] = shockedPosition.MarketValue - position.MarketValue
+		result.PnL += result.PositionPnL[symbol]
+		projected += shockedPosition.MarketValue
+	}
+	shocked.TotalValue = projected
+	shocked.DayPnL = portfolio.DayPnL + result.PnL
+	result.ProjectedValue = projected
+
+	result.Breaches = s.stressBreaches(shocked, portfolio.TotalValue)
+	for _, breach := range result.Breaches {
+		s.publishRiskAlert(ctx, "STRESS_TEST_BREACH", "CRITICAL", breach.Symbol,
+			fmt.Sprintf("Scenario %s would breach %s: %.4f > %.4f", scenario.Name, breach.Limit, breach.Value, breach.Threshold))
+	}
+
+	s.metrics.SetGauge("stress_test_pnl", result.PnL, map[string]string{
+		"portfolio_id": portfolioID,
+		"scenario":     scenario.Name,
+	})
+	s.logger.Info("Stress test completed",
+		interfaces.Field{Key: "portfolio_id", Value: portfolioID},
+		interfaces.Field{Key: "scenario", Value: scenario.Name},
+		interfaces.Field{Key: "pnl", Value: result.PnL},
+		interfaces.Field{Key: "breaches", Value: len(result.Breaches)},
+	)
+
+	return result, nil
+}
+
+// stressBreaches checks the shocked portfolio against the daily loss, drawdown,
+// leverage and concentration limits
+func (s *RiskService) stressBreaches(shocked *entities.Portfolio, currentValue float64) []StressBreach {
+	var breaches []StressBreach
+
+	if shocked.DayPnL < 0 && shocked.TotalValue > 0 {
+		if loss := -shocked.DayPnL / shocked.TotalValue; loss > s.riskLimits.MaxDailyLoss {
+			breaches = append(breaches, StressBreach{Limit: "max_daily_loss", Value: loss, Threshold: s.riskLimits.MaxDailyLoss})
+		}
+	}
+
+	if s.riskLimits.MaxDrawdown > 0 {
+		peak := math.Max(s.drawdownTracker(shocked.ID).Peak(), currentValue)
+		if peak > 0 {
+			if drawdown := (peak - shocked.TotalValue) / peak; drawdown > s.riskLimits.MaxDrawdown {
+				breaches = append(breaches, StressBreach{Limit: "max_drawdown", Value: drawdown, Threshold: s.riskLimits.MaxDrawdown})
+			}
+		}
+	}
+
+	if leverage := s.calculateLeverage(shocked); leverage > s.riskLimits.MaxLeverage {
+		breaches = append(breaches, StressBreach{Limit: "max_leverage", Value: leverage, Threshold: s.riskLimits.MaxLeverage})
+	}
+
+	concentration := s.calculateConcentration(shocked)
+	symbols := make([]entities.Symbol, 0, len(concentration))
+	for symbol := range concentration {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+	for _, symbol := range symbols {
+		if limit := s.limitsFor(symbol).MaxConcentration; concentration[symbol] > limit {
+			breaches = append(breaches, StressBreach{Limit: "max_concentration", Symbol: symbol, Value: concentration[symbol], Threshold: limit})
+		}
+	}
+
+	return breaches
+}
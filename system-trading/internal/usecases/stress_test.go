@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+func TestRiskService_RunStressTestProjectsLoss(t *testing.T) {
+	// $10,000 of AAPL and $20,000 of MSFT in a $100,000 portfolio
+	tests := []struct {
+		name         string
+		scenario     StressScenario
+		wantPnL      float64
+		wantBreaches []string
+	}{
+		{
+			name:     "market-wide -10%",
+			scenario: StressScenario{Name: "down_10", MarketShock: -0.10},
+			wantPnL:  -3000,
+		},
+		{
+			// -$1,000 on AAPL and -$12,000 on MSFT is a 14.9% loss of the
+			// projected $87,000, over the 5% daily loss limit
+			name: "symbol shock breaches the daily loss limit",
+			scenario: StressScenario{
+				Name:         "msft_collapse",
+				MarketShock:  -0.10,
+				SymbolShocks: map[entities.Symbol]float64{"MSFT": -0.60},
+			},
+			wantPnL:      -13000,
+			wantBreaches: []string{"max_daily_loss"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+			seedPortfolio(t, f.portfolioRepo, "default", 70000, map[entities.Symbol][2]float64{
+				"AAPL": {100, 100},
+				"MSFT": {100, 200},
+			})
+
+			result, err := f.service.RunStressTest(context.Background(), "default", tt.scenario)
+			if err != nil {
+				t.Fatalf("RunStressTest failed: %v", err)
+			}
+
+			if math.Abs(result.PnL-tt.wantPnL) > 1e-6 {
+				t.Errorf("Expected projected P&L %.2f, got %.2f", tt.wantPnL, result.PnL)
+			}
+			if math.Abs(result.ProjectedValue-(result.CurrentValue+tt.wantPnL)) > 1e-6 {
+				t.Errorf("Expected projected value %.2f, got %.2f", result.CurrentValue+tt.wantPnL, result.ProjectedValue)
+			}
+
+			if len(result.Breaches) != len(tt.wantBreaches) {
+				t.Fatalf("Expected breaches %v, got %+v", tt.wantBreaches, result.Breaches)
+			}
+			for i, limit := range tt.wantBreaches {
+				if result.Breaches[i].Limit != limit {
+					t.Errorf("Expected breach %s, got %s", limit, result.Breaches[i].Limit)
+				}
+			}
+
+			alerts := f.bus.GetMessagesByTopic("risk.alert")
+			if len(alerts) != len(tt.wantBreaches) {
+				t.Fatalf("Expected %d alerts, got %d", len(tt.wantBreaches), len(alerts))
+			}
+			for _, message := range alerts {
+				if alert := message.Message.(RiskAlertMessage); alert.Severity != "CRITICAL" {
+					t.Errorf("Expected CRITICAL stress alerts, got %s", alert.Severity)
+				}
+			}
+
+			// The stored portfolio is left untouched
+			portfolio, err := f.portfolios.GetPortfolio(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("Failed to get portfolio: %v", err)
+			}
+			if portfolio.TotalValue != result.CurrentValue || portfolio.Positions["MSFT"].MarketValue != 20000 {
+				t.Errorf("Expected the stress test not to modify the portfolio, got total %.2f", portfolio.TotalValue)
+			}
+		})
+	}
+}
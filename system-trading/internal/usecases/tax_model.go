@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+)
+
+type TaxTerm string
+
+const (
+	TaxTermShort TaxTerm = "short_term"
+	TaxTermLong  TaxTerm = "long_term"
+)
+
+// DefaultLongTermHoldingPeriod is the common one-year threshold for long-term gains
+const DefaultLongTermHoldingPeriod = 365 * 24 * time.Hour
+
+// TaxModel classifies realized lots for tax reporting
+type TaxModel interface {
+	Classify(lot entities.RealizedLot) TaxTerm
+}
+
+// HoldingPeriodTaxModel treats lots held longer than LongTermThreshold as long-term
+type HoldingPeriodTaxModel struct {
+	LongTermThreshold time.Duration
+}
+
+func NewHoldingPeriodTaxModel(longTermThreshold time.Duration) HoldingPeriodTaxModel {
+	if longTermThreshold <= 0 {
+		longTermThreshold = DefaultLongTermHoldingPeriod
+	}
+	return HoldingPeriodTaxModel{LongTermThreshold: longTermThreshold}
+}
+
+func (m HoldingPeriodTaxModel) Classify(lot entities.RealizedLot) TaxTerm {
+	if lot.HoldingPeriod() > m.LongTermThreshold {
+		return TaxTermLong
+	}
+	return TaxTermShort
+}
+
+// TaxedLot is a realized lot tagged with its tax term
+type TaxedLot struct {
+	entities.RealizedLot
+	Term TaxTerm `json:"term"`
+}
+
+// RealizedGainsByTerm is the split of realized gains across tax terms
+type RealizedGainsByTerm struct {
+	ShortTerm float64    `json:"short_term"`
+	LongTerm  float64    `json:"long_term"`
+	Lots      []TaxedLot `json:"lots"`
+}
+
+func classifyRealizedLots(model TaxModel, lots []entities.RealizedLot) RealizedGainsByTerm {
+	gains := RealizedGainsByTerm{Lots: make([]TaxedLot, 0, len(lots))}
+	for _, lot := range lots {
+		term := model.Classify(lot)
+		switch term {
+		case TaxTermLong:
+			gains.LongTerm += lot.Gain
+		default:
+			gains.ShortTerm += lot.Gain
+		}
+		gains.Lots = append(gains.Lots, TaxedLot{RealizedLot: lot, Term: term})
+	}
+	return gains
+}
@@ -0,0 +1,106 @@
+package usecases
+
+import "time"
+
+const tradingDayLayout = "2006-01-02"
+
+// TradingCalendar knows which days the market trades and when each session
+// opens and closes. Weekends and listed holidays are closed.
+type TradingCalendar struct {
+	location *time.Location
+	open     time.Duration
+	close    time.Duration
+	holidays map[string]bool
+}
+
+// NewTradingCalendar creates a calendar whose sessions run from open to close,
+// given as offsets from midnight in location. Holidays are YYYY-MM-DD dates.
+func NewTradingCalendar(location *time.Location, open, close time.Duration, holidays ...string) *TradingCalendar {
+	if location == nil {
+		location = time.UTC
+	}
+
+	calendar := &TradingCalendar{
+		location: location,
+		open:     open,
+		close:    close,
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[holiday] = true
+	}
+	return calendar
+}
+
+// TradingDay returns the calendar date of t in the exchange's location
+func (c *TradingCalendar) TradingDay(t time.Time) string {
+	return t.In(c.location).Format(tradingDayLayout)
+}
+
+// IsTradingDay reports whether the market has a session on t's date
+func (c *TradingCalendar) IsTradingDay(t time.Time) bool {
+	local := t.In(c.location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.holidays[local.Format(tradingDayLayout)]
+}
+
+// IsOpen reports whether t falls within a trading session
+func (c *TradingCalendar) IsOpen(t time.Time) bool {
+	return c.IsTradingDay(t) && !t.Before(c.SessionOpen(t)) && t.Before(c.SessionClose(t))
+}
+
+// SessionOpen returns when the session on t's date opens
+func (c *TradingCalendar) SessionOpen(t time.Time) time.Time {
+	return c.midnight(t).Add(c.open)
+}
+
+// SessionClose returns when the session on t's date closes
+func (c *TradingCalendar) SessionClose(t time.Time) time.Time {
+	return c.midnight(t).Add(c.close)
+}
+
+// NextClose returns the first session close strictly after t
+func (c *TradingCalendar) NextClose(t time.Time) time.Time {
+	day := c.midnight(t)
+	for {
+		if c.IsTradingDay(day) {
+			if sessionClose := day.Add(c.close); sessionClose.After(t) {
+				return sessionClose
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// NextOpen returns the first session open strictly after t
+func (c *TradingCalendar) NextOpen(t time.Time) time.Time {
+	day := c.midnight(t)
+	for {
+		if c.IsTradingDay(day) {
+			if sessionOpen := day.Add(c.open); sessionOpen.After(t) {
+				return sessionOpen
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// PreviousOpen returns the latest session open at or before t
+func (c *TradingCalendar) PreviousOpen(t time.Time) time.Time {
+	day := c.midnight(t)
+	for {
+		if c.IsTradingDay(day) {
+			if sessionOpen := day.Add(c.open); !sessionOpen.After(t) {
+				return sessionOpen
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+func (c *TradingCalendar) midnight(t time.Time) time.Time {
+	local := t.In(c.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+const (
+	// TradingDaysPerYear annualizes daily volatility
+	TradingDaysPerYear = 252
+	// DefaultAnnualVolatility is used when a symbol lacks enough price history
+	DefaultAnnualVolatility = 0.30
+	// DefaultVolatilityCacheTTL is how long a computed estimate is reused
+	DefaultVolatilityCacheTTL = 5 * time.Minute
+	// DefaultMinVolatilityObservations is the fewest returns an estimate needs
+	DefaultMinVolatilityObservations = 10
+)
+
+type HistoricalVolatilityConfig struct {
+	// DefaultVolatility is returned when fewer than MinObservations returns exist
+	DefaultVolatility float64
+	MinObservations   int
+	// PeriodsPerYear annualizes the per-observation volatility; use
+	// TradingDaysPerYear for daily prices
+	PeriodsPerYear float64
+	CacheTTL       time.Duration
+	Clock          interfaces.Clock
+}
+
+type volatilityCacheKey struct {
+	symbol   entities.Symbol
+	lookback time.Duration
+}
+
+type volatilityCacheEntry struct {
+	volatility float64
+	computedAt time.Time
+}
+
+// HistoricalVolatilityProvider estimates annualized volatility as the sample
+// standard deviation of log returns over the stored price history
+type HistoricalVolatilityProvider struct {
+	repo   interfaces.MarketDataRepository
+	config HistoricalVolatilityConfig
+
+	mu    sync.Mutex
+	cache map[volatilityCacheKey]volatilityCacheEntry
+}
+
+func NewHistoricalVolatilityProvider(repo interfaces.MarketDataRepository, config HistoricalVolatilityConfig) *HistoricalVolatilityProvider {
+	if config.DefaultVolatility <= 0 {
+		config.DefaultVolatility = DefaultAnnualVolatility
+	}
+	if config.MinObservations < 2 {
+		config.MinObservations = DefaultMinVolatilityObservations
+	}
+	if config.PeriodsPerYear <= 0 {
+		config.PeriodsPerYear = TradingDaysPerYear
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultVolatilityCacheTTL
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+
+	return &HistoricalVolatilityProvider{
+		repo:   repo,
+		config: config,
+		cache:  make(map[volatilityCacheKey]volatilityCacheEntry),
+	}
+}
+
+func (p *HistoricalVolatilityProvider) GetVolatility(ctx context.Context, symbol entities.Symbol, lookback time.Duration) (float64, error) {
+	now := p.config.Clock.Now()
+	key := volatilityCacheKey{symbol: symbol, lookback: lookback}
+
+	p.mu.Lock()
+	entry, exists := p.cache[key]
+	p.mu.Unlock()
+	if exists && now.Sub(entry.computedAt) < p.config.CacheTTL {
+		return entry.volatility, nil
+	}
+
+	history, err := p.repo.GetMarketDataHistory(ctx, symbol, now.Add(-lookback), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price history for %s: %w", symbol, err)
+	}
+
+	volatility := p.config.DefaultVolatility
+	if returns := logReturns(history); len(returns) >= p.config.MinObservations {
+		volatility = sampleStdDev(returns) * math.Sqrt(p.config.PeriodsPerYear)
+	}
+
+	p.mu.Lock()
+	p.cache[key] = volatilityCacheEntry{volatility: volatility, computedAt: now}
+	p.mu.Unlock()
+	return volatility, nil
+}
+
+// logReturns returns the log return between consecutive positive prices,
+// assuming history is ordered oldest first
+func logReturns(history []*entities.MarketData) []float64 {
+	var returns []float64
+	previous := 0.0
+	for _, data := range history {
+		if data == nil || data.Price <= 0 {
+			continue
+		}
+		if previous > 0 {
+			returns = append(returns, math.Log(data.Price/previous))
+		}
+		previous = data.Price
+	}
+	return returns
+}
+
+func sampleStdDev(values []float64) float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)-1))
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/system-trading/core/internal/entities"
+	"github.com/system-trading/core/internal/infrastructure/clock"
+	"github.com/system-trading/core/internal/usecases/interfaces"
+)
+
+// historyRepository serves a fixed price series and counts history queries
+type historyRepository struct {
+	interfaces.MarketDataRepository
+	history []*entities.MarketData
+	queries int
+}
+
+func (r *historyRepository) GetMarketDataHistory(ctx context.Context, symbol entities.Symbol, from, to time.Time) ([]*entities.MarketData, error) {
+	r.queries++
+	var result []*entities.MarketData
+	for _, data := range r.history {
+		if data.Symbol == symbol && !data.Timestamp.Before(from) && !data.Timestamp.After(to) {
+			result = append(result, data)
+		}
+	}
+	return result, nil
+}
+
+// dailyPrices builds a daily price series starting at 100 that moves by the
+// given log returns
+func dailyPrices(symbol entities.Symbol, end time.Time, returns []float64) []*entities.MarketData {
+	start := end.Add(-time.Duration(len(returns)) * 24 * time.Hour)
+	price := 100.0
+	history := []*entities.MarketData{{Symbol: symbol, Price: price, Timestamp: start}}
+	for i, r := range returns {
+		price *= math.Exp(r)
+		history = append(history, &entities.MarketData{
+			Symbol:    symbol,
+			Price:     price,
+			Timestamp: start.Add(time.Duration(i+1) * 24 * time.Hour),
+		})
+	}
+	return history
+}
+
+func TestHistoricalVolatilityProvider_AnnualizesSampleStdDev(t *testing.T) {
+	now := time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC)
+	// Ten alternating ±1% log returns: the mean is 0, so the sample variance is
+	// 10 * 0.01² / 9 and the annualized volatility is sqrt(10/9) * 0.01 * sqrt(252)
+	returns := []float64{0.01, -0.01, 0.01, -0.01, 0.01, -0.01, 0.01, -0.01, 0.01, -0.01}
+	expected := math.Sqrt(10.0/9.0) * 0.01 * math.Sqrt(252)
+
+	repo := &historyRepository{history: dailyPrices("AAPL", now, returns)}
+	provider := NewHistoricalVolatilityProvider(repo, HistoricalVolatilityConfig{
+		Clock: clock.NewFakeClock(now),
+	})
+
+	volatility, err := provider.GetVolatility(context.Background(), "AAPL", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetVolatility failed: %v", err)
+	}
+	if math.Abs(volatility-expected) > 1e-9 {
+		t.Errorf("Expected annualized volatility %.10f, got %.10f", expected, volatility)
+	}
+}
+
+func TestHistoricalVolatilityProvider_FallsBackWithoutEnoughHistory(t *testing.T) {
+	now := time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC)
+	repo := &historyRepository{history: dailyPrices("AAPL", now, []float64{0.05, -0.03, 0.02})}
+	provider := NewHistoricalVolatilityProvider(repo, HistoricalVolatilityConfig{
+		DefaultVolatility: 0.25,
+		Clock:             clock.NewFakeClock(now),
+	})
+
+	volatility, err := provider.GetVolatility(context.Background(), "AAPL", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetVolatility failed: %v", err)
+	}
+	if volatility != 0.25 {
+		t.Errorf("Expected the default volatility 0.25, got %v", volatility)
+	}
+}
+
+func TestHistoricalVolatilityProvider_CachesForTTL(t *testing.T) {
+	now := time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	repo := &historyRepository{}
+	provider := NewHistoricalVolatilityProvider(repo, HistoricalVolatilityConfig{
+		CacheTTL: time.Minute,
+		Clock:    fakeClock,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.GetVolatility(ctx, "AAPL", time.Hour); err != nil {
+			t.Fatalf("GetVolatility failed: %v", err)
+		}
+	}
+	if repo.queries != 1 {
+		t.Fatalf("Expected one history query within the TTL, got %d", repo.queries)
+	}
+
+	fakeClock.Advance(time.Minute)
+	if _, err := provider.GetVolatility(ctx, "AAPL", time.Hour); err != nil {
+		t.Fatalf("GetVolatility failed: %v", err)
+	}
+	if repo.queries != 2 {
+		t.Errorf("Expected the estimate to be recomputed after the TTL, got %d queries", repo.queries)
+	}
+}
+
+type fixedVolatility float64
+
+func (v fixedVolatility) GetVolatility(ctx context.Context, symbol entities.Symbol, lookback time.Duration) (float64, error) {
+	return float64(v), nil
+}
+
+func TestRiskService_PositionVaRUsesVolatilityProvider(t *testing.T) {
+	fixture := setupRiskService(t, defaultTestRiskLimits(), RiskServiceConfig{})
+	seedPortfolio(t, fixture.portfolioRepo, "default", 0, map[entities.Symbol][2]float64{
+		"AAPL": {100, 100},
+	})
+	fixture.service.SetVolatilityProvider(fixedVolatility(0.4))
+
+	metrics, err := fixture.service.CalculatePositionRisk(context.Background(), "default", "AAPL")
+	if err != nil {
+		t.Fatalf("CalculatePositionRisk failed: %v", err)
+	}
+
+	expected := 10000 * 0.4 / math.Sqrt(252) * z95
+	if math.Abs(metrics.VaR-expected) > 1e-6 {
+		t.Errorf("Expected VaR %.4f from the provider's volatility, got %.4f", expected, metrics.VaR)
+	}
+}